@@ -0,0 +1,92 @@
+// Referenz-Client für `newpipi --stdio`, z.B. als Baustein einer
+// VS-Code-Erweiterung. Ohne Abhängigkeiten, Node 18+.
+//
+//   const { NewPiPi } = require("./newpipi-client");
+//   const np = new NewPiPi();
+//   await np.hello("vscode");
+//   const templates = await np.request("templates", { type: "Go" });
+//   const { path } = await np.request("create", { type: "Go", name: "demo" },
+//     (e) => console.log(e.message, e.fraction));
+//   np.close();
+//
+// Direkt aufgerufen erstellt er ein Projekt und gibt dessen Pfad aus:
+//
+//   node newpipi-client.js Go demo [ELTERNPFAD] [TEMPLATE]
+
+"use strict";
+
+const { spawn } = require("node:child_process");
+const readline = require("node:readline");
+
+const PROTOCOL = 1;
+
+class NewPiPi {
+  constructor(command = "newpipi") {
+    this.nextId = 1;
+    this.pending = new Map();
+    this.proc = spawn(command, ["--stdio"], { stdio: ["pipe", "pipe", "inherit"] });
+    readline.createInterface({ input: this.proc.stdout }).on("line", (line) => this.receive(line));
+    this.proc.on("exit", () => {
+      for (const { reject } of this.pending.values()) reject(new Error("newpipi beendet"));
+      this.pending.clear();
+    });
+  }
+
+  receive(line) {
+    const msg = JSON.parse(line);
+    const call = this.pending.get(msg.id);
+    if (!call) return;
+    if (msg.event) {
+      if (call.onEvent) call.onEvent(msg);
+      return;
+    }
+    this.pending.delete(msg.id);
+    if (msg.error) call.reject(new Error(msg.error));
+    else call.resolve(msg.result);
+  }
+
+  // request sendet eine Anfrage; onEvent erhält Fortschrittsereignisse.
+  request(method, params, onEvent) {
+    const id = this.nextId++;
+    return new Promise((resolve, reject) => {
+      this.pending.set(id, { resolve, reject, onEvent });
+      this.proc.stdin.write(JSON.stringify({ id, method, params }) + "\n");
+    });
+  }
+
+  // hello prüft, ob newpipi die erwartete Protokollversion spricht.
+  async hello(client) {
+    const info = await this.request("hello", { client, protocol: PROTOCOL });
+    if (info.protocol !== PROTOCOL) {
+      throw new Error(`newpipi spricht Protokoll ${info.protocol}, erwartet ${PROTOCOL}`);
+    }
+    return info;
+  }
+
+  close() {
+    this.proc.stdin.end();
+  }
+}
+
+module.exports = { NewPiPi };
+
+if (require.main === module) {
+  const [type, name, parentPath, template] = process.argv.slice(2);
+  if (!type || !name) {
+    console.error("Verwendung: node newpipi-client.js TYP NAME [ELTERNPFAD] [TEMPLATE]");
+    process.exit(2);
+  }
+  const np = new NewPiPi(process.env.NEWPIPI || "newpipi");
+  (async () => {
+    await np.hello("reference-client");
+    const { path } = await np.request("create", { type, name, parentPath, template }, (e) =>
+      console.error(`${Math.round(e.fraction * 100)}% ${e.message}`),
+    );
+    console.log(path);
+  })()
+    .catch((err) => {
+      console.error(`Fehler: ${err.message}`);
+      process.exitCode = 1;
+    })
+    .finally(() => np.close());
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/alexander-graf/go_pipi/internal/core"
+)
+
+// showEnvDialog bearbeitet die Variablen als Tabelle aus Name und Wert.
+// onSave erhält die Variablen ohne leere Zeilen.
+func showEnvDialog(window fyne.Window, env map[string]string, onSave func(map[string]string)) {
+	type row struct{ name, value *widget.Entry }
+	var rows []row
+	list := container.NewVBox()
+	var addRow func(name, value string)
+	addRow = func(name, value string) {
+		r := row{widget.NewEntry(), widget.NewEntry()}
+		r.name.SetPlaceHolder("NAME")
+		r.name.SetText(name)
+		r.value.SetPlaceHolder("Value")
+		r.value.SetText(value)
+		rows = append(rows, r)
+		var line *fyne.Container
+		remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			rows = slices.DeleteFunc(rows, func(other row) bool { return other == r })
+			list.Remove(line)
+		})
+		line = container.NewBorder(nil, nil, nil, remove, container.NewGridWithColumns(2, r.name, r.value))
+		list.Add(line)
+	}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		addRow(name, env[name])
+	}
+	if len(rows) == 0 {
+		addRow("", "")
+	}
+	add := widget.NewButtonWithIcon("Add Variable", theme.ContentAddIcon(), func() { addRow("", "") })
+	content := container.NewBorder(nil, add, nil, nil, container.NewVScroll(list))
+
+	d := dialog.NewCustomConfirm("Environment Variables", "Save", "Cancel", content, func(ok bool) {
+		if !ok {
+			return
+		}
+		result := make(map[string]string)
+		for _, r := range rows {
+			if name := strings.TrimSpace(r.name.Text); name != "" {
+				result[name] = r.value.Text
+			}
+		}
+		if err := core.ValidateEnv(result); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		onSave(result)
+	}, window)
+	d.Resize(fyne.NewSize(520, 360))
+	d.Show()
+}
+
+// templateSecretWidgets liefert Passwortfelder für die Secrets des Templates
+// und die Wahl des Ablageorts. Leere Felder bleiben Platzhalter.
+func templateSecretWidgets(t *core.Template, values map[string]string, inKeyring *bool) []fyne.CanvasObject {
+	var objects []fyne.CanvasObject
+	for _, s := range t.Secrets {
+		entry := widget.NewPasswordEntry()
+		entry.SetPlaceHolder(s.Description)
+		entry.OnChanged = func(v string) {
+			if v == "" {
+				delete(values, s.Name)
+			} else {
+				values[s.Name] = v
+			}
+		}
+		objects = append(objects, container.NewGridWithColumns(2, widget.NewLabel(s.Name+":"), entry))
+	}
+	if len(objects) > 0 {
+		check := widget.NewCheck("Store secrets in keyring instead of .env", func(on bool) { *inKeyring = on })
+		objects = append(objects, check)
+	}
+	return objects
+}
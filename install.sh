@@ -1,7 +1,7 @@
 #!/bin/bash
 
 # Kompiliere das Programm
-go build -o newpipi
+go build -ldflags "-X github.com/alexander-graf/go_pipi/internal/core.version=$(git describe --tags --always --dirty 2>/dev/null || echo dev)" -o newpipi
 
 # Erstelle Verzeichnisse
 sudo mkdir -p /usr/local/bin
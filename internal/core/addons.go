@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Add-ons sind Templates mit "addon": true, die beim Erstellen zusätzlich zum
+// Template gewählt werden (+docker, +ci, +tests). CreateProject setzt Template
+// und Add-ons vor dem Schreiben wie Mixins zu einem Template zusammen; ohne
+// Template bilden die Add-ons allein die Schicht über dem Grundgerüst.
+
+// addonTemplates sind die eingebauten und eigenen Add-ons (siehe LoadUserTemplates).
+var addonTemplates = builtinAddons
+
+var builtinAddons = []Template{
+	{
+		Name:        "docker",
+		Description: "Mehrstufiges Image mit statischem Binary",
+		Type:        Go,
+		Addon:       true,
+		Files: map[string]string{
+			"Dockerfile":    "FROM golang:1.23 AS build\nWORKDIR /src\nCOPY . .\nRUN CGO_ENABLED=0 go build -o /app .\n\nFROM gcr.io/distroless/static\nCOPY --from=build /app /app\nENTRYPOINT [\"/app\"]\n",
+			".dockerignore": ".git\nbin/\n",
+		},
+	},
+	{
+		Name:        "ci",
+		Description: "GitHub-Actions-Workflow mit vet und Tests",
+		Type:        Go,
+		Addon:       true,
+		Files: map[string]string{
+			".github/workflows/ci.yml": "name: CI\non: [push, pull_request]\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v4\n      - uses: actions/setup-go@v5\n        with:\n          go-version-file: go.mod\n      - run: go vet ./...\n      - run: go test ./...\n",
+		},
+	},
+	{
+		Name:        "tests",
+		Description: "Tabellengetriebener Test als Vorlage",
+		Type:        Go,
+		Addon:       true,
+		Files: map[string]string{
+			"main_test.go": "package main\n\nimport \"testing\"\n\nfunc TestExample(t *testing.T) {\n\ttests := []struct {\n\t\tname     string\n\t\tin, want string\n\t}{\n\t\t{\"empty\", \"\", \"\"},\n\t}\n\tfor _, tt := range tests {\n\t\tt.Run(tt.name, func(t *testing.T) {\n\t\t\tif got := tt.in; got != tt.want {\n\t\t\t\tt.Errorf(\"got %q, want %q\", got, tt.want)\n\t\t\t}\n\t\t})\n\t}\n}\n",
+		},
+	},
+	{
+		Name:        "docker",
+		Description: "Schlankes Python-Image",
+		Type:        Python,
+		Addon:       true,
+		Files: map[string]string{
+			"Dockerfile":    "FROM python:3-slim\nWORKDIR /app\nCOPY requirements.txt .\nRUN pip install --no-cache-dir -r requirements.txt\nCOPY . .\nCMD [\"python\", \"src/main.py\"]\n",
+			".dockerignore": ".git\nvenv/\n__pycache__/\n",
+		},
+	},
+	{
+		Name:        "ci",
+		Description: "GitHub-Actions-Workflow mit pytest",
+		Type:        Python,
+		Addon:       true,
+		Files: map[string]string{
+			".github/workflows/ci.yml": "name: CI\non: [push, pull_request]\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: actions/checkout@v4\n      - uses: actions/setup-python@v5\n        with:\n          python-version: \"3.x\"\n      - run: pip install -r requirements.txt pytest\n      - run: python -m pytest\n",
+		},
+	},
+	{
+		Name:        "tests",
+		Description: "pytest mit src/ im Importpfad",
+		Type:        Python,
+		Addon:       true,
+		Packages:    []string{"pytest"},
+		Files: map[string]string{
+			"pytest.ini":          "[pytest]\npythonpath = src\ntestpaths = tests\n",
+			"tests/test_smoke.py": "def test_smoke():\n    assert True\n",
+		},
+	},
+}
+
+// addonsFor liefert die Add-ons des Projekttyps, nach Namen sortiert.
+func addonsFor(pt ProjectType) []Template {
+	var out []Template
+	for _, t := range addonTemplates {
+		if t.Type == pt {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// AddonNames liefert die Namen der Add-ons des Projekttyps für Auswahllisten.
+func AddonNames(pt ProjectType) []string {
+	var names []string
+	for _, t := range addonsFor(pt) {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// FindAddon findet ein Add-on, auch in der Schreibweise "+docker", und lädt es vollständig.
+func FindAddon(pt ProjectType, name string) *Template {
+	name = strings.TrimPrefix(name, "+")
+	for i := range addonTemplates {
+		if addonTemplates[i].Type == pt && strings.EqualFold(addonTemplates[i].Name, name) {
+			if err := addonTemplates[i].ensureLoaded(); err != nil {
+				return nil
+			}
+			return &addonTemplates[i]
+		}
+	}
+	return nil
+}
+
+// composeAddons setzt das gewählte Template mit den Add-ons zusammen; keiner der
+// Teile wird dabei verändert. Jedes Add-on braucht wie ein Template Vertrauen,
+// bevor es Befehle ausführt; die Zusammensetzung selbst gilt dann als bestätigt.
+func (ps *ProjectSetup) composeAddons() (*Template, error) {
+	var composed Template
+	if ps.Template != nil {
+		composed.merge(*ps.Template)
+		composed.Name, composed.Version = ps.Template.Name, ps.Template.Version
+	}
+	for _, name := range ps.Addons {
+		addon := FindAddon(ps.ProjectType, name)
+		if addon == nil {
+			return nil, fmt.Errorf("add-on %s für %s nicht gefunden", name, ps.ProjectType)
+		}
+		if addon.NeedsTrust(ps.Settings) {
+			return nil, fmt.Errorf("%w: add-on %s führt Befehle aus (newpipi discover trust %q)", errUntrusted, addon.Name, addon.Name)
+		}
+		composed.merge(*addon)
+	}
+	composed.Type = ps.ProjectType
+	composed.trusted = true
+	return &composed, nil
+}
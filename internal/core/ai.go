@@ -0,0 +1,137 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// AISettings konfiguriert die optionale Erzeugung von Startdateien über ein LLM.
+// Ohne Endpoint ist die Funktion abgeschaltet.
+type AISettings struct {
+	// Endpoint ist die Basis-URL einer OpenAI-kompatiblen API, für Ollama z.B.
+	// http://localhost:11434/v1.
+	Endpoint string `json:"endpoint,omitempty"`
+	Model    string `json:"model,omitempty"`
+	APIKey   string `json:"apiKey,omitempty"`
+}
+
+func (a AISettings) Enabled() bool {
+	return a.Endpoint != ""
+}
+
+const aiSystemPrompt = `You generate the starting files for a new %s project named %q.
+Reply with a single JSON object of the form {"files": {"relative/path": "file content"}} and nothing else.
+Only use relative paths inside the project. Do not include dependency directories or lock files.`
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// GenerateFiles lässt das LLM aus einer Beschreibung eine Dateiliste erzeugen.
+// Es wird noch nichts geschrieben; das Ergebnis wird erst nach Freigabe als
+// Template angewendet.
+func GenerateFiles(ctx context.Context, ai AISettings, pt ProjectType, name, description string) (map[string]string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: ai.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: fmt.Sprintf(aiSystemPrompt, pt, name)},
+			{Role: "user", Content: description},
+		},
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(ai.Endpoint, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("ki-anfrage erstellen fehlgeschlagen: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	apiKey, err := aiAPIKey(ai)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ki-endpunkt nicht erreichbar: %v", errNetwork, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ki-endpunkt antwortet mit %s", resp.Status)
+	}
+	var chat chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chat); err != nil {
+		return nil, fmt.Errorf("ki-antwort lesen fehlgeschlagen: %v", err)
+	}
+	if len(chat.Choices) == 0 {
+		return nil, fmt.Errorf("ki-antwort enthält keine nachricht")
+	}
+
+	// Manche Modelle setzen das JSON trotz Anweisung in einen Codeblock
+	content := strings.TrimSpace(chat.Choices[0].Message.Content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.Trim(content, "`\n ")
+	var result struct {
+		Files map[string]string `json:"files"`
+	}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("ki-antwort ist kein gültiges json: %v", err)
+	}
+	if len(result.Files) == 0 {
+		return nil, fmt.Errorf("ki-antwort enthält keine dateien")
+	}
+	for path := range result.Files {
+		if !filepath.IsLocal(path) {
+			return nil, fmt.Errorf("ki-antwort enthält ungültigen pfad: %s", path)
+		}
+	}
+	return result.Files, nil
+}
+
+// AiTemplate verpackt die freigegebenen Dateien als Template, damit sie wie
+// jedes andere Template angewendet werden.
+func AiTemplate(pt ProjectType, description string, files map[string]string) *Template {
+	return &Template{
+		Name:        "AI",
+		Description: description,
+		Type:        pt,
+		Files:       files,
+	}
+}
+
+// previewFiles formatiert die erzeugten Dateien zur Durchsicht.
+func previewFiles(files map[string]string) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var b strings.Builder
+	for _, path := range paths {
+		fmt.Fprintf(&b, "=== %s ===\n%s\n", path, strings.TrimRight(files[path], "\n"))
+	}
+	return b.String()
+}
@@ -0,0 +1,260 @@
+package core
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Template-Archive (.pipi-template) machen ein Template ohne Git-Quelle
+// teilbar, etwa im Chat: ein Zip mit manifest.json, template.json und den
+// Asset-Dateien unter assets/. Exportiert wird das zusammengesetzte Template,
+// Basis und Mixins sind also enthalten. Importierte Templates führen Befehle
+// wie Marktplatz-Templates erst nach Bestätigung aus.
+
+const (
+	ArchiveExtension = ".pipi-template"
+	// archiveFormat ist die Version des Archivformats; neuere lehnt der Import ab.
+	archiveFormat = 1
+	// archiveMaxSize begrenzt den entpackten Inhalt eines Archivs.
+	archiveMaxSize = 64 << 20
+)
+
+// archiveManifest beschreibt den Inhalt eines Archivs.
+type archiveManifest struct {
+	Format      int         `json:"format"`
+	Name        string      `json:"name"`
+	Type        ProjectType `json:"type"`
+	Description string      `json:"description,omitempty"`
+	// Template ist die Template-Datei im Archiv, SHA256 ihre Prüfsumme.
+	Template string    `json:"template"`
+	SHA256   string    `json:"sha256"`
+	Created  time.Time `json:"created"`
+	Version  string    `json:"version"`
+}
+
+// ExportTemplate schreibt das zusammengesetzte Template t als Archiv nach w.
+func ExportTemplate(t *Template, w io.Writer) error {
+	export := *t
+	export.Extends, export.Mixins = "", nil
+	export.Source, export.SourceSHA256 = "", ""
+	export.Assets = maps.Clone(t.Assets)
+
+	zw := zip.NewWriter(w)
+	for dest, src := range export.Assets {
+		if strings.HasPrefix(src, assetBase64Prefix) {
+			continue
+		}
+		content, err := os.ReadFile(src)
+		if err != nil {
+			return fmt.Errorf("asset %s lesen fehlgeschlagen: %v", dest, err)
+		}
+		f, err := zw.Create("assets/" + dest)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(content); err != nil {
+			return err
+		}
+		export.Assets[dest] = dest
+	}
+
+	content, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	manifest, err := json.MarshalIndent(archiveManifest{
+		Format:      archiveFormat,
+		Name:        t.Name,
+		Type:        t.Type,
+		Description: t.Description,
+		Template:    "template.json",
+		SHA256:      fmt.Sprintf("%x", sha256.Sum256(content)),
+		Created:     time.Now().UTC().Truncate(time.Second),
+		Version:     version,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	for name, data := range map[string][]byte{"manifest.json": manifest, "template.json": content} {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// readArchive liest ein Archiv. Asset-Dateien werden base64-kodiert in das
+// Template übernommen, damit es als einzelne Datei gespeichert werden kann.
+func readArchive(path string) (*Template, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("archiv %s öffnen fehlgeschlagen: %v", filepath.Base(path), err)
+	}
+	defer zr.Close()
+	var total uint64
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		total += f.UncompressedSize64
+		files[f.Name] = f
+	}
+	if total > archiveMaxSize {
+		return nil, fmt.Errorf("archiv %s ist entpackt größer als %d MB", filepath.Base(path), archiveMaxSize>>20)
+	}
+	read := func(name string) ([]byte, error) {
+		f, ok := files[name]
+		if !ok {
+			return nil, fmt.Errorf("archiv %s: %s fehlt", filepath.Base(path), name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		// Die Größenangaben im Zip sind nicht vertrauenswürdig
+		return io.ReadAll(io.LimitReader(rc, archiveMaxSize))
+	}
+
+	content, err := read("manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	var manifest archiveManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("archiv %s: manifest ungültig: %v", filepath.Base(path), err)
+	}
+	if manifest.Format > archiveFormat {
+		return nil, fmt.Errorf("archiv %s braucht eine neuere newpipi-version (format %d)", filepath.Base(path), manifest.Format)
+	}
+	if content, err = read(manifest.Template); err != nil {
+		return nil, err
+	}
+	if sum := fmt.Sprintf("%x", sha256.Sum256(content)); !strings.EqualFold(sum, manifest.SHA256) {
+		return nil, fmt.Errorf("archiv %s: prüfsumme von %s stimmt nicht", filepath.Base(path), manifest.Template)
+	}
+	var t Template
+	if err := json.Unmarshal(content, &t); err != nil {
+		return nil, fmt.Errorf("archiv %s: template ungültig: %v", filepath.Base(path), err)
+	}
+	if t.Name == "" || t.Extends != "" || len(t.Mixins) > 0 {
+		return nil, fmt.Errorf("archiv %s: template ohne namen oder mit basis-templates", filepath.Base(path))
+	}
+	if err := t.checkPaths(); err != nil {
+		return nil, err
+	}
+	for dest, src := range t.Assets {
+		if strings.HasPrefix(src, assetBase64Prefix) {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(src)) {
+			return nil, fmt.Errorf("archiv %s: asset %s liegt außerhalb von assets/", filepath.Base(path), src)
+		}
+		data, err := read("assets/" + src)
+		if err != nil {
+			return nil, err
+		}
+		t.Assets[dest] = assetBase64Prefix + base64.StdEncoding.EncodeToString(data)
+	}
+	return &t, nil
+}
+
+// ImportArchive übernimmt ein Archiv ins Template-Verzeichnis, mit name statt
+// des Namens aus dem Archiv, falls gesetzt.
+func ImportArchive(path, name string) (*Template, error) {
+	settings, err := LoadSettings()
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.Policy.remoteTemplates(); err != nil {
+		return nil, err
+	}
+	t, err := readArchive(path)
+	if err != nil {
+		return nil, err
+	}
+	if name != "" {
+		t.Name = name
+	}
+	if FindTemplate(t.Type, t.Name) != nil {
+		return nil, fmt.Errorf("template %s (%s) existiert bereits, mit anderem namen importieren", t.Name, t.Type)
+	}
+	// Wie beim Marktplatz: Befehle laufen erst nach Bestätigung
+	t.Source, t.SourceSHA256 = "archive:"+filepath.Base(path), ""
+	if _, err := saveUserTemplate(settings, t, "Template importiert: "+t.Name); err != nil {
+		return nil, err
+	}
+	LoadUserTemplates()
+	if imported := FindTemplate(t.Type, t.Name); imported != nil {
+		return imported, nil
+	}
+	return nil, fmt.Errorf("template %s nach import nicht geladen", t.Name)
+}
+
+// exportTemplateFile exportiert das Template name in die Datei path.
+func exportTemplateFile(name, path string) error {
+	t := templateByName(name)
+	if t == nil {
+		return fmt.Errorf("template %s nicht gefunden", name)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("archiv anlegen fehlgeschlagen: %v", err)
+	}
+	if err := ExportTemplate(t, f); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("template %s exportieren fehlgeschlagen: %v", name, err)
+	}
+	return f.Close()
+}
+
+// importArchiveCommand ist `newpipi import ARCHIV.pipi-template`.
+func importArchiveCommand(path, name string, trust bool) int {
+	t, err := ImportArchive(path, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+		return ExitCodeFor(err)
+	}
+	fmt.Printf("Template %s (%s) importiert\n", t.Name, t.Type)
+	if t.RunsCommands() && (trust || confirmTrust(t)) {
+		if err := TrustTemplate(t); err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+	}
+	return ExitOK
+}
+
+// setupExport definiert `newpipi export [-o DATEI] NAME`.
+func setupExport(fs *flag.FlagSet) func(args []string) int {
+	output := fs.String("o", "", "Zieldatei (Standard: NAME"+ArchiveExtension+" im aktuellen Verzeichnis)")
+	return func(args []string) int {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi export [-o DATEI] NAME")
+			return exitUsage
+		}
+		path := *output
+		if path == "" {
+			path = strings.TrimSuffix(TemplateFileName(args[0]), ".json") + ArchiveExtension
+		}
+		if err := exportTemplateFile(args[0], path); err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		fmt.Printf("Template %s exportiert: %s\n", args[0], path)
+		return ExitOK
+	}
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	icon := filepath.Join(dir, "icon.png")
+	if err := os.WriteFile(icon, []byte{0x89, 'P', 'N', 'G', 0}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &Template{
+		Name:    "Service",
+		Type:    Go,
+		Extends: "base",
+		Source:  "https://example.com/index.json",
+		Files:   map[string]string{"main.go": "package main\n"},
+		Assets:  map[string]string{"assets/icon.png": icon, "logo.svg": "base64:PHN2Zy8+"},
+		Hooks:   []string{"make setup"},
+	}
+	path := filepath.Join(dir, "service"+ArchiveExtension)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportTemplate(tmpl, f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	got, err := readArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Service" || got.Type != Go || got.Extends != "" || got.Source != "" {
+		t.Errorf("readArchive = %+v, erwartet Service (Go) ohne Basis und Quelle", got)
+	}
+	if got.Files["main.go"] != "package main\n" || len(got.Hooks) != 1 {
+		t.Errorf("Dateien oder Hooks fehlen: %+v", got)
+	}
+	if got.Assets["assets/icon.png"] != "base64:iVBORwA=" || got.Assets["logo.svg"] != "base64:PHN2Zy8+" {
+		t.Errorf("Assets = %v", got.Assets)
+	}
+	if tmpl.Assets["assets/icon.png"] != icon {
+		t.Errorf("exportTemplate hat die Assets des Templates verändert: %v", tmpl.Assets)
+	}
+}
+
+func TestReadArchiveInvalid(t *testing.T) {
+	valid, _ := json.Marshal(Template{Name: "T", Type: Go, Files: map[string]string{"a.txt": "a"}})
+	sum := func(b []byte) string { return fmt.Sprintf("%x", sha256.Sum256(b)) }
+	manifest := func(format int, template, sha string) string {
+		b, _ := json.Marshal(archiveManifest{Format: format, Template: template, SHA256: sha})
+		return string(b)
+	}
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"ohne manifest", map[string]string{"template.json": string(valid)}, "manifest.json fehlt"},
+		{"neueres format", map[string]string{"manifest.json": manifest(archiveFormat+1, "template.json", sum(valid)), "template.json": string(valid)}, "neuere newpipi-version"},
+		{"falsche prüfsumme", map[string]string{"manifest.json": manifest(archiveFormat, "template.json", sum(nil)), "template.json": string(valid)}, "prüfsumme"},
+		{"fehlendes asset", func() map[string]string {
+			b, _ := json.Marshal(Template{Name: "T", Type: Go, Assets: map[string]string{"icon.png": "icon.png"}})
+			return map[string]string{"manifest.json": manifest(archiveFormat, "template.json", sum(b)), "template.json": string(b)}
+		}(), "assets/icon.png fehlt"},
+		{"asset außerhalb", func() map[string]string {
+			b, _ := json.Marshal(Template{Name: "T", Type: Go, Assets: map[string]string{"icon.png": "../icon.png"}})
+			return map[string]string{"manifest.json": manifest(archiveFormat, "template.json", sum(b)), "template.json": string(b)}
+		}(), "außerhalb"},
+		{"mit basis", func() map[string]string {
+			b, _ := json.Marshal(Template{Name: "T", Type: Go, Extends: "base"})
+			return map[string]string{"manifest.json": manifest(archiveFormat, "template.json", sum(b)), "template.json": string(b)}
+		}(), "basis-templates"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, content := range tt.files {
+			w, _ := zw.Create(name)
+			w.Write([]byte(content))
+		}
+		zw.Close()
+		path := filepath.Join(t.TempDir(), "t"+ArchiveExtension)
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readArchive(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: readArchive() = %v, erwartet Fehler mit %q", tt.name, err, tt.want)
+		}
+	}
+}
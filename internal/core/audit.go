@@ -0,0 +1,241 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// auditFinding ist eine gemeldete Schwachstelle einer Abhängigkeit.
+type auditFinding struct {
+	ID       string `json:"id"`
+	Package  string `json:"package"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// AuditReport fasst das Ergebnis des Audit-Werkzeugs zusammen.
+type AuditReport struct {
+	Tool     string         `json:"tool"`
+	Findings []auditFinding `json:"findings"`
+}
+
+// summary ist die einzeilige Zusammenfassung für Fortschrittsanzeigen.
+func (r *AuditReport) summary() string {
+	if len(r.Findings) == 0 {
+		return fmt.Sprintf("%s: keine bekannten Schwachstellen", r.Tool)
+	}
+	counts := make(map[string]int)
+	for _, f := range r.Findings {
+		severity := f.Severity
+		if severity == "" {
+			severity = "unbekannt"
+		}
+		counts[strings.ToLower(severity)]++
+	}
+	var parts []string
+	for severity, n := range counts {
+		parts = append(parts, fmt.Sprintf("%d %s", n, severity))
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("%s: %d Schwachstellen (%s)", r.Tool, len(r.Findings), strings.Join(parts, ", "))
+}
+
+// Details listet die Funde zeilenweise auf.
+func (r *AuditReport) Details() string {
+	var b strings.Builder
+	b.WriteString(r.summary() + "\n")
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "  %s %s", f.Package, f.ID)
+		if f.Severity != "" {
+			fmt.Fprintf(&b, " (%s)", f.Severity)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// auditParsers werten die Ausgabe der Audit-Werkzeuge aus; language.Audit
+// wählt sie über Output.
+var auditParsers = map[string]func([]byte) ([]auditFinding, error){
+	"npm-audit":   parseNpmAudit,
+	"pip-audit":   parsePipAudit,
+	"cargo-audit": parseCargoAudit,
+	"govulncheck": parseGovulncheck,
+	"dotnet":      parseDotnetVulnerable,
+}
+
+// auditDependencies prüft die installierten Abhängigkeiten mit dem Audit-Werkzeug
+// des Ökosystems. Fehlt das Werkzeug, wird der Schritt übersprungen.
+func (ps *ProjectSetup) auditDependencies(projectDir string) (*AuditReport, error) {
+	var tool *projectTool
+	if lang := ps.Language(); lang != nil {
+		tool = lang.Audit
+	}
+	parse := auditParsers[tool.output()]
+	args, ok := ps.toolArgs(projectDir, tool, nil)
+	if parse == nil || !ok {
+		log.Printf("Kein Audit für %s verfügbar", ps.ProjectType)
+		return nil, nil
+	}
+	if missing := tool.missing(); missing != "" {
+		ps.step(fmt.Sprintf("Audit übersprungen: %s nicht installiert", missing))
+		return nil, nil
+	}
+
+	ps.step(fmt.Sprintf("Prüfe Abhängigkeiten mit %s...", args[0]))
+	cmd := ps.command(args[0], args[1:]...)
+	cmd.Dir = projectDir
+	// Die Werkzeuge beenden sich bei Funden mit einem Fehlercode; ausgewertet wird die Ausgabe
+	out, runErr := cmd.Output()
+	findings, err := parse(out)
+	if err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("audit mit %s fehlgeschlagen: %v", args[0], runErr)
+		}
+		return nil, fmt.Errorf("audit-ausgabe von %s nicht lesbar: %v", args[0], err)
+	}
+	report := &AuditReport{Tool: args[0], Findings: findings}
+	ps.step(report.summary())
+	return report, nil
+}
+
+func parseNpmAudit(out []byte) ([]auditFinding, error) {
+	var result struct {
+		Vulnerabilities map[string]struct {
+			Severity string `json:"severity"`
+			Via      []any  `json:"via"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, err
+	}
+	var findings []auditFinding
+	for name, v := range result.Vulnerabilities {
+		id := ""
+		// via enthält Advisories als Objekte oder Namen betroffener Abhängigkeiten
+		for _, via := range v.Via {
+			if advisory, ok := via.(map[string]any); ok {
+				id, _ = advisory["url"].(string)
+				break
+			}
+		}
+		findings = append(findings, auditFinding{ID: id, Package: name, Severity: v.Severity})
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+func parsePipAudit(out []byte) ([]auditFinding, error) {
+	var result struct {
+		Dependencies []struct {
+			Name  string `json:"name"`
+			Vulns []struct {
+				ID string `json:"id"`
+			} `json:"vulns"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, err
+	}
+	var findings []auditFinding
+	for _, dep := range result.Dependencies {
+		for _, v := range dep.Vulns {
+			findings = append(findings, auditFinding{ID: v.ID, Package: dep.Name})
+		}
+	}
+	return findings, nil
+}
+
+func parseCargoAudit(out []byte) ([]auditFinding, error) {
+	var result struct {
+		Vulnerabilities struct {
+			List []struct {
+				Advisory struct {
+					ID string `json:"id"`
+				} `json:"advisory"`
+				Package struct {
+					Name string `json:"name"`
+				} `json:"package"`
+			} `json:"list"`
+		} `json:"vulnerabilities"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, err
+	}
+	var findings []auditFinding
+	for _, v := range result.Vulnerabilities.List {
+		findings = append(findings, auditFinding{ID: v.Advisory.ID, Package: v.Package.Name})
+	}
+	return findings, nil
+}
+
+// parseGovulncheck liest den JSON-Nachrichtenstrom von govulncheck; gezählt
+// werden nur Funde, deren Code tatsächlich aufgerufen wird.
+func parseGovulncheck(out []byte) ([]auditFinding, error) {
+	dec := json.NewDecoder(bytes.NewReader(out))
+	seen := make(map[string]bool)
+	var findings []auditFinding
+	for dec.More() {
+		var msg struct {
+			Finding *struct {
+				OSV   string `json:"osv"`
+				Trace []struct {
+					Module   string `json:"module"`
+					Function string `json:"function"`
+				} `json:"trace"`
+			} `json:"finding"`
+		}
+		if err := dec.Decode(&msg); err != nil {
+			return nil, err
+		}
+		f := msg.Finding
+		if f == nil || len(f.Trace) == 0 || f.Trace[0].Function == "" || seen[f.OSV] {
+			continue
+		}
+		seen[f.OSV] = true
+		findings = append(findings, auditFinding{ID: f.OSV, Package: f.Trace[0].Module})
+	}
+	return findings, nil
+}
+
+func parseDotnetVulnerable(out []byte) ([]auditFinding, error) {
+	// dotnet schreibt vor dem JSON gelegentlich Hinweiszeilen
+	start := bytes.IndexByte(out, '{')
+	if start < 0 {
+		return nil, fmt.Errorf("keine json-ausgabe")
+	}
+	var result struct {
+		Projects []struct {
+			Frameworks []struct {
+				TopLevelPackages []struct {
+					ID              string `json:"id"`
+					Vulnerabilities []struct {
+						Severity    string `json:"severity"`
+						AdvisoryURL string `json:"advisoryurl"`
+					} `json:"vulnerabilities"`
+				} `json:"topLevelPackages"`
+			} `json:"frameworks"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(out[start:], &result); err != nil {
+		return nil, err
+	}
+	var findings []auditFinding
+	for _, p := range result.Projects {
+		for _, fw := range p.Frameworks {
+			for _, pkg := range fw.TopLevelPackages {
+				for _, v := range pkg.Vulnerabilities {
+					findings = append(findings, auditFinding{ID: v.AdvisoryURL, Package: pkg.ID, Severity: v.Severity})
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+func sortFindings(findings []auditFinding) {
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Package < findings[j].Package })
+}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// README-Badges: Sprache, Framework, Lizenz und CI als shields.io-Badges
+// zwischen zwei Markierungen in README.md. Sie entstehen bei der Erstellung
+// und lassen sich mit `newpipi badges` aus Manifest, CI-Dateien und origin
+// neu erzeugen; Text außerhalb der Markierungen bleibt unberührt.
+
+const (
+	badgesStart = "<!-- newpipi:badges -->"
+	badgesEnd   = "<!-- /newpipi:badges -->"
+)
+
+// badge ist ein Bild mit optionalem Link.
+type badge struct {
+	Alt   string
+	Image string
+	Link  string
+}
+
+func (b badge) markdown() string {
+	img := fmt.Sprintf("![%s](%s)", b.Alt, b.Image)
+	if b.Link == "" {
+		return img
+	}
+	return fmt.Sprintf("[%s](%s)", img, b.Link)
+}
+
+// badgeInfo sind die Angaben, aus denen die Badges entstehen.
+type badgeInfo struct {
+	Language string
+	Toolkit  string
+	License  string
+	// Workflow ist die Datei unter .github/workflows, GitLabCI meldet eine
+	// .gitlab-ci.yml.
+	Workflow string
+	GitLabCI bool
+	// Remote ist die Adresse von origin; ohne sie gibt es kein CI-Badge.
+	Remote string
+}
+
+// languageBadges ordnet den eingebauten Sprachen Farbe und Logo (Simple Icons) zu.
+var languageBadges = map[string]struct{ Color, Logo string }{
+	"Python":     {"3776AB", "python"},
+	"Go":         {"00ADD8", "go"},
+	"Rust":       {"000000", "rust"},
+	"JavaScript": {"F7DF1E", "javascript"},
+	"TypeScript": {"3178C6", "typescript"},
+	"C++":        {"00599C", "cplusplus"},
+	"C#":         {"512BD4", "dotnet"},
+	"Java":       {"ED8B00", "openjdk"},
+	"Kotlin":     {"7F52FF", "kotlin"},
+	"Zig":        {"F7A41D", "zig"},
+}
+
+// staticBadge liefert die Adresse eines statischen shields.io-Badges; - und _
+// werden dort verdoppelt.
+func staticBadge(label, message, color, logo string) string {
+	escape := func(s string) string {
+		return url.PathEscape(strings.NewReplacer("-", "--", "_", "__").Replace(s))
+	}
+	u := "https://img.shields.io/badge/" + escape(label) + "-" + escape(message) + "-" + color
+	if logo != "" {
+		u += "?logo=" + url.QueryEscape(logo)
+	}
+	return u
+}
+
+func (info badgeInfo) badges() []badge {
+	var badges []badge
+	if info.Language != "" {
+		style, ok := languageBadges[info.Language]
+		if !ok {
+			style.Color = "informational"
+		}
+		badges = append(badges, badge{Alt: info.Language, Image: staticBadge("language", info.Language, style.Color, style.Logo)})
+	}
+	if info.Toolkit != "" && info.Toolkit != "None" {
+		badges = append(badges, badge{Alt: info.Toolkit, Image: staticBadge("framework", info.Toolkit, "informational", "")})
+	}
+	if info.License != "" {
+		badges = append(badges, badge{Alt: "License: " + info.License, Image: staticBadge("license", info.License, "blue", ""), Link: "LICENSE"})
+	}
+	if ci, ok := info.ciBadge(); ok {
+		badges = append(badges, ci)
+	}
+	return badges
+}
+
+// ciBadge zeigt den Status der Pipeline auf GitHub bzw. GitLab; andere Forges
+// erreicht shields.io nicht.
+func (info badgeInfo) ciBadge() (badge, bool) {
+	if info.Remote == "" {
+		return badge{}, false
+	}
+	host, owner, repo, err := parseRemote(info.Remote)
+	if err != nil {
+		return badge{}, false
+	}
+	switch {
+	case host == GithubHost && info.Workflow != "":
+		return badge{
+			Alt:   "CI",
+			Image: fmt.Sprintf("https://img.shields.io/github/actions/workflow/status/%s/%s/%s", owner, repo, info.Workflow),
+			Link:  fmt.Sprintf("https://github.com/%s/%s/actions/workflows/%s", owner, repo, info.Workflow),
+		}, true
+	case info.GitLabCI:
+		image := "https://img.shields.io/gitlab/pipeline-status/" + url.PathEscape(owner+"/"+repo)
+		if host != "gitlab.com" {
+			image += "?gitlab_url=" + url.QueryEscape("https://"+host)
+		}
+		return badge{Alt: "Pipeline", Image: image, Link: fmt.Sprintf("https://%s/%s/%s/-/pipelines", host, owner, repo)}, true
+	}
+	return badge{}, false
+}
+
+// detectCI sucht die CI-Konfiguration des Projekts; unter mehreren Workflows
+// gewinnt ci.yml, sonst der erste.
+func detectCI(projectDir string) (workflow string, gitlab bool) {
+	entries, _ := os.ReadDir(filepath.Join(projectDir, ".github", "workflows"))
+	var workflows []string
+	for _, e := range entries {
+		if ext := filepath.Ext(e.Name()); !e.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			workflows = append(workflows, e.Name())
+		}
+	}
+	if slices.Contains(workflows, "ci.yml") {
+		workflow = "ci.yml"
+	} else if len(workflows) > 0 {
+		workflow = workflows[0]
+	}
+	_, err := os.Stat(filepath.Join(projectDir, ".gitlab-ci.yml"))
+	return workflow, err == nil
+}
+
+// withBadges setzt die Badges zwischen die Markierungen in content. Ohne
+// Markierungen kommen sie unter die erste Überschrift bzw. an den Anfang;
+// ein leeres README erhält title als Überschrift. Ohne Badges entfällt der
+// Block.
+func withBadges(content, title string, badges []badge) string {
+	var block string
+	if len(badges) > 0 {
+		lines := make([]string, len(badges))
+		for i, b := range badges {
+			lines[i] = b.markdown()
+		}
+		block = badgesStart + "\n" + strings.Join(lines, "\n") + "\n" + badgesEnd
+	}
+	if start := strings.Index(content, badgesStart); start >= 0 {
+		if end := strings.Index(content[start:], badgesEnd); end >= 0 {
+			rest := content[start+end+len(badgesEnd):]
+			if block == "" {
+				rest = strings.TrimPrefix(strings.TrimPrefix(rest, "\n"), "\n")
+			}
+			return content[:start] + block + rest
+		}
+	}
+	if block == "" {
+		return content
+	}
+	if strings.TrimSpace(content) == "" {
+		return "# " + title + "\n\n" + block + "\n"
+	}
+	if heading, rest, ok := strings.Cut(content, "\n"); ok && strings.HasPrefix(heading, "# ") {
+		return heading + "\n\n" + block + "\n\n" + strings.TrimLeft(rest, "\n")
+	}
+	return block + "\n\n" + content
+}
+
+// updateBadges schreibt die Badges in README.md und meldet, ob sich etwas
+// geändert hat. Ohne README und ohne Badges entsteht keine Datei.
+func updateBadges(projectDir, title string, info badgeInfo, modes projectModes) (bool, error) {
+	path := filepath.Join(projectDir, "README.md")
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	updated := withBadges(string(content), title, info.badges())
+	if updated == string(content) {
+		return false, nil
+	}
+	if err := modes.writeFile(path, []byte(updated), modes.File); err != nil {
+		return false, fmt.Errorf("readme schreiben fehlgeschlagen: %v", err)
+	}
+	return true, nil
+}
+
+// writeBadges erzeugt die Badges bei der Erstellung; das CI-Badge gibt es
+// nur mit Push, da origin sonst noch fehlt.
+func (ps *ProjectSetup) writeBadges(projectDir string) error {
+	ps.step("Erzeuge README-Badges...")
+	info := badgeInfo{Language: ps.ProjectType.String(), License: ps.Profile().License}
+	if tk, _ := ps.Toolkit(); tk != nil {
+		info.Toolkit = tk.Name
+	}
+	info.Workflow, info.GitLabCI = detectCI(projectDir)
+	if ps.Push {
+		info.Remote = ps.remoteURL()
+	}
+	_, err := updateBadges(projectDir, ps.ProjectName, info, ps.Settings.Modes())
+	return err
+}
+
+// setupBadges definiert `newpipi badges [-remote URL] [VERZEICHNIS]`.
+func setupBadges(fs *flag.FlagSet) func(args []string) int {
+	remote := fs.String("remote", "", "Adresse des Repositorys für das CI-Badge (Standard: origin)")
+	return func(args []string) int {
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi badges [-remote URL] [VERZEICHNIS]")
+			return exitUsage
+		}
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		manifest, err := readManifest(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		info := badgeInfo{Language: manifest.Type.String(), Toolkit: manifest.Toolkit, License: manifest.License, Remote: *remote}
+		info.Workflow, info.GitLabCI = detectCI(dir)
+		if info.Remote == "" {
+			if out, err := gitOutput(dir, "remote", "get-url", "origin"); err == nil {
+				info.Remote = strings.TrimSpace(out)
+			}
+		}
+		settings, _ := LoadSettings()
+		changed, err := updateBadges(dir, manifest.Name, info, settings.Modes())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		if !changed {
+			fmt.Println("Badges unverändert")
+			return ExitOK
+		}
+		fmt.Println("README.md aktualisiert")
+		return ExitOK
+	}
+}
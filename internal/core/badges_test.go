@@ -0,0 +1,45 @@
+package core
+
+import "testing"
+
+func TestWithBadges(t *testing.T) {
+	b := []badge{{Alt: "Go", Image: "go.svg"}, {Alt: "License", Image: "mit.svg", Link: "LICENSE"}}
+	block := badgesStart + "\n![Go](go.svg)\n[![License](mit.svg)](LICENSE)\n" + badgesEnd
+	tests := []struct {
+		name    string
+		content string
+		badges  []badge
+		want    string
+	}{
+		{"leer", "", b, "# demo\n\n" + block + "\n"},
+		{"überschrift", "# Demo\nText\n", b, "# Demo\n\n" + block + "\n\nText\n"},
+		{"ohne überschrift", "Text\n", b, block + "\n\nText\n"},
+		{"ersetzen", "# Demo\n\n" + badgesStart + "\nalt\n" + badgesEnd + "\n\nText\n", b, "# Demo\n\n" + block + "\n\nText\n"},
+		{"entfernen", "# Demo\n\n" + block + "\n\nText\n", nil, "# Demo\n\nText\n"},
+		{"nichts zu tun", "Text\n", nil, "Text\n"},
+	}
+	for _, tt := range tests {
+		if got := withBadges(tt.content, "demo", tt.badges); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCIBadge(t *testing.T) {
+	tests := []struct {
+		info  badgeInfo
+		image string
+	}{
+		{badgeInfo{Workflow: "ci.yml"}, ""},
+		{badgeInfo{Workflow: "ci.yml", Remote: "git@github.com:me/demo.git"}, "https://img.shields.io/github/actions/workflow/status/me/demo/ci.yml"},
+		{badgeInfo{GitLabCI: true, Remote: "https://gitlab.com/me/demo.git"}, "https://img.shields.io/gitlab/pipeline-status/me%2Fdemo"},
+		{badgeInfo{GitLabCI: true, Remote: "https://git.example.com/me/demo"}, "https://img.shields.io/gitlab/pipeline-status/me%2Fdemo?gitlab_url=https%3A%2F%2Fgit.example.com"},
+		{badgeInfo{Remote: "https://github.com/me/demo"}, ""},
+	}
+	for _, tt := range tests {
+		b, ok := tt.info.ciBadge()
+		if ok != (tt.image != "") || b.Image != tt.image {
+			t.Errorf("%+v: badge = %q, want %q", tt.info, b.Image, tt.image)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Sammelaktionen über mehrere verwaltete Projekte (Tab „Projects“ bzw.
+// `newpipi bulk`). Jedes Projekt ist ein Job der Warteschlange aus jobs.go.
+
+// BulkAction ist eine Aktion, die je Projekt ausgeführt wird.
+type BulkAction struct {
+	Name  string
+	Label string
+	Run   func(ps *ProjectSetup, dir string) error
+}
+
+var BulkActions = []BulkAction{
+	{Name: "pull", Label: "Git Pull", Run: bulkPull},
+	{Name: "clean", Label: "Clean Artifacts", Run: bulkClean},
+	{Name: "install", Label: "Install Dependencies", Run: bulkInstall},
+	{Name: "archive", Label: "Archive", Run: bulkArchive},
+	{Name: "terminal", Label: "Open Terminal", Run: func(ps *ProjectSetup, dir string) error {
+		return ps.launchTerminal(dir, "exec bash")
+	}},
+}
+
+func findBulkAction(name string) (BulkAction, bool) {
+	for _, a := range BulkActions {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return BulkAction{}, false
+}
+
+// dependencyInstallers ordnet einer Datei im Projekt den Befehl zu, der
+// dessen Abhängigkeiten installiert.
+var dependencyInstallers = []struct {
+	Marker  string
+	Command string
+}{
+	{"go.mod", "go mod download"},
+	{"package.json", "npm install"},
+	{"Cargo.toml", "cargo fetch"},
+	{"requirements.txt", "test -d venv || python3 -m venv venv; venv/bin/pip install -r requirements.txt"},
+}
+
+func bulkPull(ps *ProjectSetup, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return fmt.Errorf("kein git-repository")
+	}
+	if out, err := gitOutput(dir, "remote"); err != nil || strings.TrimSpace(out) == "" {
+		return fmt.Errorf("kein remote eingerichtet")
+	}
+	return runInProject(ps, dir, "git pull --ff-only")
+}
+
+func bulkClean(ps *ProjectSetup, dir string) error {
+	for _, k := range ArtifactKinds {
+		if _, err := CleanArtifacts(dir, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bulkInstall(ps *ProjectSetup, dir string) error {
+	found := false
+	for _, inst := range dependencyInstallers {
+		if _, err := os.Stat(filepath.Join(dir, inst.Marker)); err != nil {
+			continue
+		}
+		found = true
+		if err := runInProject(ps, dir, inst.Command); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf("keine bekannte abhängigkeitsdatei")
+	}
+	return nil
+}
+
+// bulkArchive archiviert ein Projekt wie `newpipi cleanup --archive`.
+func bulkArchive(ps *ProjectSetup, dir string) error {
+	created := time.Now()
+	if m, err := readManifest(dir); err == nil {
+		created = m.CreatedAt
+	}
+	_, err := archiveProject(dir, created)
+	return err
+}
+
+// runInProject führt einen Shell-Befehl im Projekt aus, mit den
+// Registry-Spiegeln des Profils.
+func runInProject(ps *ProjectSetup, dir, command string) error {
+	cmd := ps.command("sh", "-c", command)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if output := strings.TrimSpace(string(out)); output != "" {
+			err = fmt.Errorf("%v: %s", err, output)
+		}
+		return fmt.Errorf("%s fehlgeschlagen: %v", command, err)
+	}
+	return nil
+}
+
+// BulkJobs erzeugt je Projekt einen Job; jeder Job bekommt ein eigenes
+// Setup, da ps.command die Befehle mitschreibt.
+func BulkJobs(ps *ProjectSetup, action BulkAction, dirs []string) []job {
+	jobs := make([]job, len(dirs))
+	for i, dir := range dirs {
+		jobs[i] = job{Name: dir, Run: func() error {
+			return action.Run(&ProjectSetup{Settings: ps.Settings, ProfileName: ps.ProfileName}, dir)
+		}}
+	}
+	return jobs
+}
+
+// setupBulk definiert `newpipi bulk [-j N] AKTION [VERZEICHNIS...]`.
+func setupBulk(fs *flag.FlagSet) func(args []string) int {
+	workers := fs.Int("j", MaxJobs, "Höchstens so viele Projekte gleichzeitig")
+	return func(args []string) int {
+		var names []string
+		for _, a := range BulkActions {
+			names = append(names, a.Name)
+		}
+		if len(args) == 0 {
+			fmt.Fprintf(os.Stderr, "Verwendung: newpipi bulk [-j N] %s [VERZEICHNIS...]\n", strings.Join(names, "|"))
+			return exitUsage
+		}
+		action, ok := findBulkAction(args[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Fehler: unbekannte aktion: %s\n", args[0])
+			return exitUsage
+		}
+		dirs := args[1:]
+		if len(dirs) == 0 {
+			dirs = ManagedProjects()
+		}
+		if len(dirs) == 0 {
+			fmt.Println("Keine verwalteten Projekte")
+			return ExitOK
+		}
+		code := ExitOK
+		RunJobs(BulkJobs(NewProjectSetup(), action, dirs), *workers, func(done int, r JobResult) {
+			if r.Err != nil {
+				fmt.Fprintf(os.Stderr, "[%d/%d] Fehler: %s: %v\n", done, len(dirs), r.Name, r.Err)
+				code = exitFailure
+				return
+			}
+			fmt.Printf("[%d/%d] %s (%s)\n", done, len(dirs), r.Name, r.Duration.Round(time.Millisecond))
+		})
+		return code
+	}
+}
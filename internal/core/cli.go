@@ -0,0 +1,641 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Exit-Codes des CLI-Modus, damit Skripte und CI-Wrapper programmatisch reagieren können
+const (
+	ExitOK               = 0
+	exitFailure          = 1
+	exitUsage            = 2
+	exitToolchainMissing = 3
+	exitProjectExists    = 4
+	exitNetwork          = 5
+	exitPolicy           = 6
+)
+
+type stepResult struct {
+	Message    string `json:"message"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// createResult ist die maschinenlesbare Ausgabe von `newpipi create --json`.
+type createResult struct {
+	Success      bool              `json:"success"`
+	Path         string            `json:"path"`
+	Type         ProjectType       `json:"type"`
+	Template     string            `json:"template,omitempty"`
+	Steps        []stepResult      `json:"steps"`
+	DurationMs   int64             `json:"durationMs"`
+	ToolVersions map[string]string `json:"toolVersions,omitempty"`
+	Audit        *AuditReport      `json:"audit,omitempty"`
+	Licenses     *LicenseReport    `json:"licenses,omitempty"`
+	Error        string            `json:"error,omitempty"`
+	Report       string            `json:"report,omitempty"`
+	ExitCode     int               `json:"exitCode"`
+}
+
+// command beschreibt einen Unterbefehl der Kommandozeile.
+type command struct {
+	name    string
+	summary string
+	// setup definiert die Flags des Befehls und liefert die Funktion, die ihn
+	// nach dem Parsen mit den restlichen Argumenten ausführt.
+	setup func(fs *flag.FlagSet) func(args []string) int
+	// values nennt je Flag die Quelle der Shell-Vervollständigung (siehe
+	// completionCandidates); der Schlüssel "" steht für Positionsargumente.
+	values map[string]string
+	hidden bool
+	// plugins lädt vor dem Befehl die Plugins. Das startet jedes Plugin mit
+	// describe und bleibt daher den Befehlen vorbehalten, die Projekte erstellen.
+	plugins bool
+}
+
+// cliCommands liefert den Befehlsbaum der Kommandozeile. Ohne Befehl startet die GUI.
+func cliCommands() []command {
+	return []command{
+		{
+			name:    "create",
+			summary: "Projekt ohne Oberfläche erstellen",
+			setup:   setupCreate,
+			values:  map[string]string{"type": "types", "template": "templates", "path": "paths", "profile": "profiles", "snippet": "snippets", "addon": "addons", "sbom": "sbomFormats"},
+			plugins: true,
+		},
+		{
+			name:    "serve",
+			summary: "Generator als lokale HTTP-API bereitstellen",
+			setup:   setupServe,
+			plugins: true,
+		},
+		{
+			name:    "dbus",
+			summary: "Generator als D-Bus-Dienst (org.newpipi.Creator) bereitstellen",
+			setup:   setupDBus,
+			plugins: true,
+		},
+		{
+			name:    "mcp",
+			summary: "MCP-Server über stdio für KI-Assistenten",
+			setup:   setupMCP,
+			plugins: true,
+		},
+		{
+			name:    "tui",
+			summary: "Assistenten im Terminal starten",
+			setup:   setupTUI,
+			plugins: true,
+		},
+		{
+			name:    "prefetch",
+			summary: "Übliche Abhängigkeiten in die Paket-Caches vorladen",
+			setup:   setupPrefetch,
+			values:  map[string]string{"type": "types"},
+		},
+		{
+			name:    "selftest",
+			summary: "Alle eingebauten Toolkits, Layouts und Templates anlegen und bauen",
+			setup:   setupSelftest,
+			values:  map[string]string{"type": "types"},
+		},
+		{
+			name:    "report",
+			summary: "Lokalen Fehlerbericht für ein GitHub-Issue anlegen",
+			setup:   setupReport,
+		},
+		{
+			name:    "resume",
+			summary: "Unterbrochene Erstellung fortsetzen oder auflisten",
+			setup:   setupResume,
+			values:  map[string]string{"": "paths"},
+			plugins: true,
+		},
+		{
+			name:    "cleanup",
+			summary: "Abgelaufene Wegwerfprojekte anzeigen, archivieren oder löschen",
+			setup:   setupCleanup,
+		},
+		{
+			name:    "sandbox",
+			summary: "Sandbox-Projekte auflisten oder in den Projektpfad übernehmen",
+			setup:   setupSandbox,
+			values:  map[string]string{"": "sandboxActions"},
+		},
+		{
+			name:    "disk",
+			summary: "Speicherbelegung der Projekte anzeigen, Build-Artefakte löschen",
+			setup:   setupDisk,
+			values:  map[string]string{"": "paths", "clean": "artifactKinds"},
+		},
+		{
+			name:    "bulk",
+			summary: "Aktion wie pull, clean oder install für mehrere Projekte ausführen",
+			setup:   setupBulk,
+			values:  map[string]string{"": "bulkActions"},
+		},
+		{
+			name:    "undo",
+			summary: "Zuletzt erstelltes Projekt löschen, sofern unverändert",
+			setup:   setupUndo,
+		},
+		{
+			name:    "updates",
+			summary: "Nach neuen Toolchain- und Template-Versionen suchen",
+			setup:   setupUpdates,
+		},
+		{
+			name:    "stats",
+			summary: "Lokale Nutzungsstatistik anzeigen",
+			setup:   setupStats,
+		},
+		{
+			name:    "quick",
+			summary: "Minimale Eingabe für ein globales Tastenkürzel oder rofi/dmenu",
+			setup:   setupQuick,
+			plugins: true,
+		},
+		{
+			name:    "record",
+			summary: "Manuelle Einrichtung in einer Shell aufzeichnen und als Template-Entwurf speichern",
+			setup:   setupRecord,
+			values:  map[string]string{"type": "types"},
+			plugins: true,
+		},
+		{
+			name:    "save",
+			summary: "Bestehendes Verzeichnis als Template speichern, Projektname als Platzhalter",
+			setup:   setupSave,
+			values:  map[string]string{"type": "types"},
+		},
+		{
+			name:    "watch",
+			summary: "Template bei jedem Speichern neu in ein Sandbox-Verzeichnis rendern",
+			setup:   setupWatch,
+			values:  map[string]string{"": "templates"},
+		},
+		{
+			name:    "badges",
+			summary: "README-Badges aus Manifest, CI-Dateien und origin neu erzeugen",
+			setup:   setupBadges,
+			values:  map[string]string{"": "paths"},
+		},
+		{
+			name:    "verify",
+			summary: "Prüfen, ob ein Projekt noch seinem Erstellungsbericht entspricht",
+			setup:   setupVerify,
+		},
+		{
+			name:    "discover",
+			summary: "Community-Templates aus dem Marktplatz auflisten und installieren",
+			setup:   setupDiscover,
+			values:  map[string]string{"": "discoverActions"},
+		},
+		{
+			name:    "source",
+			summary: "Template-Quellen aus Git-Repositorys verwalten und aktualisieren",
+			setup:   setupSource,
+			values:  map[string]string{"": "sourceActions"},
+		},
+		{
+			name:    "export",
+			summary: "Template als teilbares Archiv (.pipi-template) exportieren",
+			setup:   setupExport,
+			values:  map[string]string{"": "templates"},
+		},
+		{
+			name:    "import",
+			summary: "Cookiecutter-Template oder Template-Archiv übernehmen",
+			setup:   setupImport,
+			values:  map[string]string{"type": "types"},
+		},
+		{
+			name:    "snippet",
+			summary: "Snippets auflisten oder in ein bestehendes Projekt einfügen",
+			setup:   setupSnippet,
+			values:  map[string]string{"": "snippetActions", "type": "types"},
+		},
+		{
+			name:    "secret",
+			summary: "Tokens im Schlüsselbund ablegen, migrieren oder Projekt-Secrets ausgeben",
+			setup:   setupSecret,
+			values:  map[string]string{"": "secretActions", "profile": "profiles"},
+		},
+		{
+			name:    "github",
+			summary: "Projekt aus einem GitHub-Template-Repository erzeugen und klonen",
+			setup:   setupGitHub,
+			values:  map[string]string{"": "githubActions"},
+		},
+		{
+			name:    "forge",
+			summary: "Forge-Richtlinie des Profils auf den Remote eines Projekts anwenden",
+			setup:   setupForge,
+			values:  map[string]string{"": "paths", "profile": "profiles"},
+		},
+		{
+			name:    "config",
+			summary: "Konfigurationsverzeichnis mit Git versionieren und synchronisieren",
+			setup:   setupConfig,
+			values:  map[string]string{"": "configActions"},
+		},
+		{
+			name:    "completion",
+			summary: "Shell-Vervollständigung ausgeben (bash, zsh, fish)",
+			setup:   setupCompletion,
+			values:  map[string]string{"": "shells"},
+		},
+		{
+			name:   "__complete",
+			setup:  setupComplete,
+			hidden: true,
+		},
+	}
+}
+
+// NeedsPlugins meldet, ob der Aufruf mit diesen Argumenten Plugins braucht:
+// die GUI, das stdio-Protokoll und Befehle mit command.plugins.
+func NeedsPlugins(args []string) bool {
+	if len(args) == 0 || args[0] == "--stdio" {
+		return true
+	}
+	c := findCommand(args[0])
+	return c != nil && c.plugins
+}
+
+func findCommand(name string) *command {
+	for _, c := range cliCommands() {
+		if c.name == name {
+			return &c
+		}
+	}
+	return nil
+}
+
+// RunCommand führt den Unterbefehl args[0] aus und liefert den Exit-Code.
+func RunCommand(args []string) int {
+	if args[0] == "--version" || args[0] == "-version" {
+		fmt.Println(VersionString())
+		return ExitOK
+	}
+	if args[0] == "--stdio" {
+		return startStdio()
+	}
+	c := findCommand(args[0])
+	if c == nil {
+		printUsage()
+		return exitUsage
+	}
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	run := c.setup(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	return run(fs.Args())
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Verwendung: newpipi [befehl] [flags]")
+	fmt.Fprintln(os.Stderr, "\nOhne Befehl startet die grafische Oberfläche; --version zeigt die Version,\n--stdio spricht das JSON-Protokoll für Editor-Plugins.\n\nBefehle:")
+	for _, c := range cliCommands() {
+		if !c.hidden {
+			fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.summary)
+		}
+	}
+}
+
+// createOptions sind die Eingaben von `newpipi create`.
+type createOptions struct {
+	typeName     string
+	name         string
+	parentPath   string
+	templateName string
+	toolkit      string
+	layout       string
+	pm           string
+	module       string
+	profile      string
+	options      optionValues
+	snippets     stringList
+	addons       stringList
+	scratch      bool
+	sandbox      bool
+	aiPrompt     string
+	yes          bool
+	noGit        bool
+	vcs          string
+	gitHooks     bool
+	releaseTask  bool
+	sign         bool
+	lfs          bool
+	push         bool
+	audit        bool
+	licenses     bool
+	sbom         string
+	terminal     bool
+	runCommand   string
+	env          optionValues
+	secrets      optionValues
+	keyring      bool
+	jsonOutput   bool
+}
+
+// optionValues sammelt wiederholte --option NAME=WERT Flags.
+type optionValues map[string]string
+
+func (o *optionValues) String() string {
+	return fmt.Sprint(map[string]string(*o))
+}
+
+func (o *optionValues) Set(value string) error {
+	name, v, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("NAME=WERT erwartet, nicht %q", value)
+	}
+	if *o == nil {
+		*o = make(optionValues)
+	}
+	(*o)[name] = v
+	return nil
+}
+
+// stringList sammelt wiederholte Flags mit einfachem Wert.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// setupCreate definiert die Flags von `newpipi create`.
+func setupCreate(fs *flag.FlagSet) func(args []string) int {
+	var opts createOptions
+	fs.StringVar(&opts.typeName, "type", "", "Projekttyp (z.B. Go, Python, Rust)")
+	fs.StringVar(&opts.name, "name", "", "Projektname")
+	fs.StringVar(&opts.parentPath, "path", "", "Elternverzeichnis (Standard: gespeicherter Projektpfad)")
+	fs.StringVar(&opts.templateName, "template", "", "Name des Templates")
+	fs.StringVar(&opts.toolkit, "toolkit", "", "Toolkit bzw. Framework des Projekttyps, z.B. PySide6, FastAPI oder Cobra (Standard: erstes bzw. aus den Einstellungen)")
+	fs.StringVar(&opts.layout, "layout", "", "Projektform des Projekttyps, z.B. Library oder Workspace für Rust (Standard: erste)")
+	fs.StringVar(&opts.pm, "package-manager", "", "Paketmanager für JavaScript und TypeScript: npm, pnpm, yarn oder bun (Standard aus packageManager)")
+	fs.StringVar(&opts.module, "module", "", "Modulpfad eines Go-Projekts, z.B. github.com/user/name (Standard: Projektname bzw. goModulePrefix)")
+	fs.StringVar(&opts.profile, "profile", "", "Profil verwenden (Standard: aktives Profil)")
+	fs.Var(&opts.options, "option", "Template-Option NAME=WERT (mehrfach möglich)")
+	fs.Var(&opts.snippets, "snippet", "Snippet einfügen (mehrfach möglich)")
+	fs.Var(&opts.addons, "addon", "Add-on wie docker, ci oder tests hinzufügen (mehrfach möglich)")
+	fs.StringVar(&opts.aiPrompt, "ai", "", "Startdateien per KI aus dieser Beschreibung erzeugen")
+	fs.BoolVar(&opts.yes, "yes", false, "KI-Dateien ohne Rückfrage übernehmen")
+	fs.BoolVar(&opts.noGit, "no-git", false, "Kein Repository initialisieren, auch nicht mit -vcs")
+	fs.BoolVar(&opts.gitHooks, "git-hooks", false, "Native Git-Hooks für Formatierung und Commit-Nachricht anlegen (Standard aus gitHooks)")
+	fs.BoolVar(&opts.releaseTask, "release-task", false, "Release-Task für Changelog und Tag anlegen: git-cliff, standard-version oder cargo-release (Standard aus releaseTask)")
+	fs.BoolVar(&opts.sign, "sign", false, "Commits per SSH oder GPG signieren (Standard aus signCommits)")
+	fs.StringVar(&opts.vcs, "vcs", "", "Versionskontrolle: git, jj oder hg (Standard aus vcs)")
+	fs.BoolVar(&opts.lfs, "lfs", false, "Große Binärdateien mit Git LFS verwalten (Standard aus gitLFS)")
+	fs.BoolVar(&opts.scratch, "scratch", false, "Als Wegwerfprojekt markieren, das newpipi cleanup später aufräumt")
+	fs.BoolVar(&opts.sandbox, "sandbox", false, "Im Temp-Verzeichnis statt im Projektpfad anlegen (newpipi sandbox promote übernimmt es später)")
+	fs.BoolVar(&opts.push, "push", false, "Nach dem ersten Commit an die remoteURL des Profils pushen")
+	fs.BoolVar(&opts.audit, "audit", false, "Abhängigkeiten vor dem ersten Commit auf Schwachstellen prüfen")
+	fs.BoolVar(&opts.licenses, "licenses", false, "Lizenzen der Abhängigkeiten zusammenfassen und gegen licenseDeny prüfen")
+	fs.StringVar(&opts.sbom, "sbom", "", "SBOM im Projekt ablegen (cyclonedx, spdx)")
+	fs.BoolVar(&opts.terminal, "terminal", false, "Terminal im neuen Projekt öffnen")
+	fs.StringVar(&opts.runCommand, "run", "", "Startbefehl, den das Terminal anzeigt (mit -terminal)")
+	fs.Var(&opts.env, "env", "Umgebungsvariable NAME=WERT für .env, direnv, Compose und VS Code (mehrfach möglich)")
+	fs.Var(&opts.secrets, "secret", "Wert eines Template-Secrets NAME=WERT (mehrfach möglich)")
+	fs.BoolVar(&opts.keyring, "keyring", false, "Secrets im Schlüsselbund statt in .env ablegen")
+	fs.BoolVar(&opts.jsonOutput, "json", false, "Ergebnis als JSON ausgeben")
+	return func(args []string) int {
+		return runCreate(opts)
+	}
+}
+
+// runCreate erstellt ein Projekt ohne GUI und liefert den Exit-Code des Prozesses.
+func runCreate(opts createOptions) int {
+	ps := NewProjectSetup()
+	ps.ProjectName = opts.name
+	ps.skipTerminal = !opts.terminal
+	ps.runCommand = opts.runCommand
+	ps.SkipGit = opts.noGit
+	ps.GitHooks = ps.GitHooks || opts.gitHooks
+	ps.ReleaseTask = ps.ReleaseTask || opts.releaseTask
+	ps.SignCommits = ps.SignCommits || opts.sign
+	ps.GitLFS = ps.GitLFS || opts.lfs
+	ps.Scratch = opts.scratch
+	if opts.vcs != "" {
+		ps.Vcs = opts.vcs
+	}
+	ps.Push = opts.push
+	ps.Audit = opts.audit
+	ps.Licenses = opts.licenses
+	ps.SbomFormat = opts.sbom
+
+	result := createResult{Steps: []stepResult{}}
+	fail := func(code int, err error) int {
+		result.ExitCode = code
+		result.Error = err.Error()
+		var link string
+		if code == exitFailure {
+			result.Report, link = ReportFailure(ps.Settings, os.Args[1:], err)
+		}
+		if opts.jsonOutput {
+			printJSON(result)
+		} else {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			if result.Report != "" {
+				offerIssue(result.Report, link)
+			}
+		}
+		return code
+	}
+
+	if opts.profile != "" {
+		if err := ps.SelectProfile(opts.profile); err != nil {
+			return fail(exitUsage, err)
+		}
+	}
+	if opts.parentPath != "" {
+		ps.ParentPath = opts.parentPath
+	}
+	if opts.sandbox {
+		if err := ps.EnterSandbox(); err != nil {
+			return fail(exitFailure, err)
+		}
+	}
+
+	pt, ok := ParseProjectType(opts.typeName)
+	if !ok {
+		return fail(exitUsage, fmt.Errorf("unbekannter projekttyp: %q", opts.typeName))
+	}
+	ps.ProjectType = pt
+	result.Type = pt
+	if valid, msg := IsValidProjectName(ps.ProjectName); !valid {
+		return fail(exitUsage, errors.New(msg))
+	}
+	ps.ToolkitName = opts.toolkit
+	ps.LayoutName = opts.layout
+	if _, err := ps.Toolkit(); err != nil {
+		return fail(exitUsage, err)
+	}
+	if ps.ModulePath = opts.module; ps.ModulePath != "" {
+		if err := checkModulePath(ps.ModulePath); err != nil {
+			return fail(exitUsage, err)
+		}
+	}
+	if opts.pm != "" {
+		ps.PackageManager = opts.pm
+	}
+	if _, err := FindPackageManager(ps.PackageManager); err != nil {
+		return fail(exitUsage, err)
+	}
+	if opts.templateName != "" {
+		if ps.Template = FindTemplate(pt, opts.templateName); ps.Template == nil {
+			return fail(exitUsage, fmt.Errorf("template nicht gefunden: %s", opts.templateName))
+		}
+		result.Template = ps.Template.Name
+		if missing := ps.Template.MissingOptions(opts.options); len(missing) > 0 && !opts.yes && !opts.jsonOutput && term.IsTerminal(int(os.Stdin.Fd())) {
+			if opts.options == nil {
+				opts.options = make(optionValues)
+			}
+			promptOptions(missing, opts.options)
+		}
+		if _, err := ps.Template.ResolveOptions(opts.options); err != nil {
+			return fail(exitUsage, err)
+		}
+		ps.Options = opts.options
+		if err := ps.Template.validateSecrets(opts.secrets); err != nil {
+			return fail(exitUsage, err)
+		}
+		ps.SecretValues, ps.SecretsInKeyring = opts.secrets, opts.keyring
+	} else if len(opts.options) > 0 {
+		return fail(exitUsage, errors.New("--option benötigt --template"))
+	} else if len(opts.secrets) > 0 {
+		return fail(exitUsage, errors.New("--secret benötigt --template"))
+	}
+	if opts.aiPrompt != "" {
+		if code, err := generateForCreate(ps, opts); err != nil {
+			return fail(code, err)
+		}
+		result.Template = ps.Template.Name
+	}
+	if _, ok := sbomFormats[opts.sbom]; opts.sbom != "" && !ok {
+		return fail(exitUsage, fmt.Errorf("unbekanntes sbom-format: %s", opts.sbom))
+	}
+	for _, name := range opts.snippets {
+		if FindSnippet(pt, name) == nil {
+			return fail(exitUsage, fmt.Errorf("snippet %s für %s nicht gefunden", name, pt))
+		}
+	}
+	ps.Snippets = opts.snippets
+	for _, name := range opts.addons {
+		addon := FindAddon(pt, name)
+		if addon == nil {
+			return fail(exitUsage, fmt.Errorf("add-on %s für %s nicht gefunden", name, pt))
+		}
+		ps.Addons = append(ps.Addons, addon.Name)
+	}
+	if err := ValidateEnv(opts.env); err != nil {
+		return fail(exitUsage, err)
+	}
+	ps.EnvVars = opts.env
+	result.Path = filepath.Join(ps.ParentPath, ps.ProjectName)
+
+	if estimate := ps.LoadEstimate(); estimate != nil {
+		log.Printf("Voraussichtliche Dauer: %s", estimate.total.Round(time.Second))
+	}
+	start := time.Now()
+	err := ps.CreateProject()
+	end := time.Now()
+	result.Steps = append(result.Steps, ps.stepResults(end)...)
+	result.DurationMs = end.Sub(start).Milliseconds()
+	result.ToolVersions = ps.toolVersions
+	result.Audit = ps.auditReport
+	result.Licenses = ps.licenseReport
+
+	if err != nil {
+		return fail(ExitCodeFor(err), err)
+	}
+
+	result.Success = true
+	if opts.jsonOutput {
+		printJSON(result)
+	} else {
+		fmt.Println(result.Path)
+	}
+	return ExitOK
+}
+
+// promptOptions fragt im Terminal nach den Werten fehlender Pflichtoptionen.
+func promptOptions(missing []TemplateOption, values optionValues) {
+	reader := bufio.NewReader(os.Stdin)
+	for _, o := range missing {
+		fmt.Fprintf(os.Stderr, "%s: ", o.DisplayLabel())
+		answer, _ := reader.ReadString('\n')
+		values[o.Name] = strings.TrimSpace(answer)
+	}
+}
+
+// generateForCreate erzeugt die KI-Dateien, zeigt sie an und übernimmt sie
+// nach Bestätigung als Template.
+func generateForCreate(ps *ProjectSetup, opts createOptions) (int, error) {
+	switch {
+	case opts.templateName != "":
+		return exitUsage, errors.New("--ai und --template schließen sich aus")
+	case !ps.Settings.AI.Enabled():
+		return exitUsage, errors.New("ki-endpunkt nicht konfiguriert (NEWPIPI_AI_ENDPOINT)")
+	case opts.jsonOutput && !opts.yes:
+		return exitUsage, errors.New("--ai mit --json benötigt --yes")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	files, err := GenerateFiles(ctx, ps.Settings.AI, ps.ProjectType, ps.ProjectName, opts.aiPrompt)
+	if err != nil {
+		return ExitCodeFor(err), err
+	}
+	if !opts.yes {
+		fmt.Fprint(os.Stderr, previewFiles(files))
+		fmt.Fprint(os.Stderr, "Diese Dateien schreiben? [j/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "j" && a != "y" {
+			return exitFailure, errors.New("abgebrochen")
+		}
+	}
+	ps.Template = AiTemplate(ps.ProjectType, opts.aiPrompt, files)
+	return ExitOK, nil
+}
+
+// ExitCodeFor ordnet einen Fehler der Projekterstellung seinem Exit-Code zu.
+func ExitCodeFor(err error) int {
+	switch {
+	case errors.Is(err, errToolchainMissing):
+		return exitToolchainMissing
+	case errors.Is(err, errProjectExists):
+		return exitProjectExists
+	case errors.Is(err, errNetwork):
+		return exitNetwork
+	case errors.Is(err, errPolicy):
+		return exitPolicy
+	}
+	return exitFailure
+}
+
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Printf("JSON-Ausgabe fehlgeschlagen: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TemplateCommand ist ein Befehl, der nach dem Anlegen der Dateien im Projekt
+// läuft. Anders als Hooks wird er ohne Shell ausgeführt; in Run, Env und Dir
+// werden {name} (Projektname) und {dir} (Projektverzeichnis) ersetzt.
+type TemplateCommand struct {
+	Run []string          `json:"run"`
+	Env map[string]string `json:"env,omitempty"`
+	// Dir ist das Arbeitsverzeichnis relativ zum Projekt (bei Init zum Elternverzeichnis).
+	Dir string `json:"dir,omitempty"`
+	// Step ist die Fortschrittsmeldung, sonst "Führe … aus...".
+	Step string `json:"step,omitempty"`
+	// AllowFailure protokolliert einen Fehlschlag, statt die Erstellung abzubrechen.
+	AllowFailure bool `json:"allowFailure,omitempty"`
+	// Network kennzeichnet Befehle, die Pakete laden; ihr Fehlschlag zählt als Netzwerkfehler.
+	Network bool `json:"network,omitempty"`
+	// Install kennzeichnet die Installation von Abhängigkeiten; sie entfällt,
+	// wenn der Lauf sie überspringt (siehe skipsteps.go).
+	Install bool `json:"install,omitempty"`
+}
+
+// runCommands führt die Befehle nacheinander über ps.command in base aus. Mit
+// sandbox laufen sie wie Hooks mit bereinigter Umgebung und Zeitlimit.
+func (ps *ProjectSetup) runCommands(base string, commands []TemplateCommand, sandbox bool) error {
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	expand := strings.NewReplacer("{name}", ps.ProjectName, "{module}", ps.goModule(), "{dir}", projectDir).Replace
+	for _, c := range commands {
+		if len(c.Run) == 0 {
+			return errors.New("befehl ohne run")
+		}
+		args := make([]string, len(c.Run))
+		for i, arg := range c.Run {
+			args[i] = expand(arg)
+		}
+		dir := base
+		if c.Dir != "" {
+			rel := filepath.FromSlash(expand(c.Dir))
+			if !filepath.IsLocal(rel) {
+				return fmt.Errorf("arbeitsverzeichnis %q liegt außerhalb des projekts", c.Dir)
+			}
+			dir = filepath.Join(base, rel)
+		}
+		step := c.Step
+		if step == "" {
+			step = fmt.Sprintf("Führe %s aus...", strings.Join(args, " "))
+		}
+		ps.step(step)
+
+		ctx, cancel := context.WithCancel(ps.context())
+		if sandbox {
+			cancel()
+			ctx, cancel = context.WithTimeout(ps.context(), hookTimeout)
+		}
+		cmd := ps.commandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = dir
+		if sandbox {
+			cmd.Env = sandboxEnv(ps.ProjectName)
+		} else if cmd.Env == nil && len(c.Env) > 0 {
+			cmd.Env = cmd.Environ()
+		}
+		for key, value := range c.Env {
+			cmd.Env = append(cmd.Env, key+"="+expand(value))
+		}
+		started := time.Now()
+		out, err := cmd.CombinedOutput()
+		ps.trackCommand(cmd, started, c.Network)
+		cancel()
+		if err == nil {
+			continue
+		}
+		if output := strings.TrimSpace(string(out)); output != "" {
+			err = fmt.Errorf("%v: %s", err, output)
+		}
+		err = fmt.Errorf("befehl %q fehlgeschlagen: %v", strings.Join(args, " "), err)
+		if c.AllowFailure {
+			log.Printf("%v (ignoriert)", err)
+			continue
+		}
+		if c.Network {
+			return fmt.Errorf("%w: %v", errNetwork, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// RunsCommands meldet, ob das Template nach dem Anlegen Befehle ausführt.
+// Pakete zählen dazu: Paketmanager führen Installationsskripte der Pakete aus,
+// und das Skript kann die Paketliste frei setzen. Run startet das Terminal.
+func (t *Template) RunsCommands() bool {
+	return len(t.Hooks) > 0 || len(t.Commands) > 0 || t.Generator != "" || len(t.allPackages()) > 0 || t.Script != "" || t.Run != ""
+}
+
+// allPackages sind die Pakete des Templates einschließlich der bedingten.
+func (t *Template) allPackages() []string {
+	packages := slices.Clone(t.Packages)
+	for _, c := range t.Conditional {
+		packages = append(packages, c.Packages...)
+	}
+	return packages
+}
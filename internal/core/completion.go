@@ -0,0 +1,290 @@
+package core
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// completionFlag ist ein Flag eines Befehls, wie es die Vervollständigung benötigt.
+type completionFlag struct {
+	name   string
+	usage  string
+	isBool bool
+	values string
+}
+
+// commandFlags ermittelt die Flags eines Befehls, indem sie auf einem leeren FlagSet definiert werden.
+func commandFlags(c command) []completionFlag {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	c.setup(fs)
+	var flags []completionFlag
+	fs.VisitAll(func(f *flag.Flag) {
+		bf, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, completionFlag{
+			name:   f.Name,
+			usage:  f.Usage,
+			isBool: ok && bf.IsBoolFlag(),
+			values: c.values[f.Name],
+		})
+	})
+	return flags
+}
+
+// completionCandidates liefert die Werte einer Vervollständigungsquelle.
+func completionCandidates(kind string) []string {
+	switch kind {
+	case "types":
+		return ProjectTypeNames
+	case "templates":
+		seen := make(map[string]bool)
+		var names []string
+		for _, t := range Templates {
+			if !seen[t.Name] {
+				seen[t.Name] = true
+				names = append(names, t.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	case "paths":
+		if ps := NewProjectSetup(); ps.ParentPath != "" {
+			return []string{ps.ParentPath}
+		}
+	case "profiles":
+		if settings, err := LoadSettings(); err == nil {
+			return settings.ProfileNames()
+		}
+	case "snippets":
+		seen := make(map[string]bool)
+		var names []string
+		for _, s := range snippets {
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				names = append(names, s.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	case "addons":
+		seen := make(map[string]bool)
+		var names []string
+		for _, t := range addonTemplates {
+			if !seen[t.Name] {
+				seen[t.Name] = true
+				names = append(names, t.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	case "bulkActions":
+		var names []string
+		for _, a := range BulkActions {
+			names = append(names, a.Name)
+		}
+		return names
+	case "artifactKinds":
+		var names []string
+		for _, k := range ArtifactKinds {
+			names = append(names, k.Name)
+		}
+		return names
+	case "sbomFormats":
+		return []string{"cyclonedx", "spdx"}
+	case "discoverActions":
+		return []string{"list", "install", "trust"}
+	case "sourceActions":
+		return []string{"list", "add", "remove", "update"}
+	case "githubActions":
+		return []string{"list", "create"}
+	case "sandboxActions":
+		return []string{"list", "promote"}
+	case "snippetActions":
+		return []string{"list", "add"}
+	case "secretActions":
+		return []string{"set", "migrate", "env"}
+	case "configActions":
+		return []string{"init", "clone", "commit", "pull", "push"}
+	case "shells":
+		return []string{"bash", "zsh", "fish"}
+	}
+	return nil
+}
+
+// setupComplete definiert den internen Befehl, den die Shell-Skripte für dynamische Werte aufrufen.
+func setupComplete(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		if len(args) != 1 {
+			return exitUsage
+		}
+		for _, c := range completionCandidates(args[0]) {
+			fmt.Println(c)
+		}
+		return ExitOK
+	}
+}
+
+func setupCompletion(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi completion bash|zsh|fish")
+			return exitUsage
+		}
+		var script string
+		switch args[0] {
+		case "bash":
+			script = bashCompletion()
+		case "zsh":
+			script = zshCompletion()
+		case "fish":
+			script = fishCompletion()
+		default:
+			fmt.Fprintf(os.Stderr, "Unbekannte Shell: %s\n", args[0])
+			return exitUsage
+		}
+		fmt.Print(script)
+		return ExitOK
+	}
+}
+
+func visibleCommands() []command {
+	var cmds []command
+	for _, c := range cliCommands() {
+		if !c.hidden {
+			cmds = append(cmds, c)
+		}
+	}
+	return cmds
+}
+
+func bashCompletion() string {
+	var b strings.Builder
+	var names []string
+	for _, c := range visibleCommands() {
+		names = append(names, c.name)
+	}
+
+	b.WriteString("# bash-Vervollständigung für newpipi\n")
+	b.WriteString("_newpipi() {\n")
+	b.WriteString("    local cur prev IFS=$'\\n'\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("    if [[ $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W $'%s' -- \"$cur\"))\n", strings.Join(names, "\\n"))
+	b.WriteString("        return\n    fi\n")
+	b.WriteString("    case \"${COMP_WORDS[1]}\" in\n")
+	for _, c := range visibleCommands() {
+		fmt.Fprintf(&b, "    %s)\n", c.name)
+		var flagNames []string
+		var cases strings.Builder
+		for _, f := range commandFlags(c) {
+			flagNames = append(flagNames, "--"+f.name)
+			if f.isBool {
+				continue
+			}
+			fmt.Fprintf(&cases, "        -%s|--%s)\n", f.name, f.name)
+			switch f.values {
+			case "":
+				cases.WriteString("            COMPREPLY=()\n")
+			case "paths":
+				cases.WriteString("            COMPREPLY=($(compgen -d -- \"$cur\") $(compgen -W \"$(newpipi __complete paths)\" -- \"$cur\"))\n")
+			default:
+				fmt.Fprintf(&cases, "            COMPREPLY=($(compgen -W \"$(newpipi __complete %s)\" -- \"$cur\"))\n", f.values)
+			}
+			cases.WriteString("            return;;\n")
+		}
+		if cases.Len() > 0 {
+			b.WriteString("        case \"$prev\" in\n")
+			b.WriteString(cases.String())
+			b.WriteString("        esac\n")
+		}
+		if kind := c.values[""]; kind != "" {
+			fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"$(newpipi __complete %s)\" -- \"$cur\"))\n", kind)
+		}
+		if len(flagNames) > 0 {
+			fmt.Fprintf(&b, "        [[ $cur == -* ]] && COMPREPLY=($(compgen -W $'%s' -- \"$cur\"))\n", strings.Join(flagNames, "\\n"))
+		}
+		b.WriteString("        ;;\n")
+	}
+	b.WriteString("    esac\n}\n")
+	b.WriteString("complete -F _newpipi newpipi\n")
+	return b.String()
+}
+
+func zshCompletion() string {
+	var b strings.Builder
+	b.WriteString("#compdef newpipi\n\n")
+	b.WriteString("_newpipi() {\n")
+	b.WriteString("    local -a commands\n    commands=(\n")
+	for _, c := range visibleCommands() {
+		fmt.Fprintf(&b, "        '%s:%s'\n", c.name, zshEscape(c.summary))
+	}
+	b.WriteString("    )\n")
+	b.WriteString("    if (( CURRENT == 2 )); then\n        _describe 'command' commands\n        return\n    fi\n")
+	b.WriteString("    case $words[2] in\n")
+	for _, c := range visibleCommands() {
+		fmt.Fprintf(&b, "    %s)\n        _arguments \\\n", c.name)
+		for _, f := range commandFlags(c) {
+			desc := zshEscape(f.usage)
+			switch {
+			case f.isBool:
+				fmt.Fprintf(&b, "            '--%s[%s]' \\\n", f.name, desc)
+			case f.values == "paths":
+				fmt.Fprintf(&b, "            '--%s=[%s]:%s:{compadd -- ${(f)\"$(newpipi __complete paths)\"}; _files -/}' \\\n", f.name, desc, f.name)
+			case f.values != "":
+				fmt.Fprintf(&b, "            '--%s=[%s]:%s:{compadd -- ${(f)\"$(newpipi __complete %s)\"}}' \\\n", f.name, desc, f.name, f.values)
+			default:
+				fmt.Fprintf(&b, "            '--%s=[%s]:%s:' \\\n", f.name, desc, f.name)
+			}
+		}
+		if kind := c.values[""]; kind != "" {
+			fmt.Fprintf(&b, "            '1:%s:{compadd -- ${(f)\"$(newpipi __complete %s)\"}}' \\\n", kind, kind)
+		}
+		b.WriteString("            && return\n        ;;\n")
+	}
+	b.WriteString("    esac\n}\n\n")
+	b.WriteString("_newpipi \"$@\"\n")
+	return b.String()
+}
+
+func fishCompletion() string {
+	var b strings.Builder
+	b.WriteString("# fish-Vervollständigung für newpipi\n")
+	b.WriteString("complete -c newpipi -f\n")
+	for _, c := range visibleCommands() {
+		fmt.Fprintf(&b, "complete -c newpipi -n __fish_use_subcommand -a %s -d '%s'\n", c.name, fishEscape(c.summary))
+	}
+	for _, c := range visibleCommands() {
+		cond := fmt.Sprintf("'__fish_seen_subcommand_from %s'", c.name)
+		for _, f := range commandFlags(c) {
+			line := fmt.Sprintf("complete -c newpipi -n %s -l %s -d '%s'", cond, f.name, fishEscape(f.usage))
+			switch {
+			case f.isBool:
+			case f.values == "paths":
+				line += " -r -a '(newpipi __complete paths; __fish_complete_directories)'"
+			case f.values != "":
+				line += fmt.Sprintf(" -r -a '(newpipi __complete %s)'", f.values)
+			default:
+				line += " -r"
+			}
+			b.WriteString(line + "\n")
+		}
+		if kind := c.values[""]; kind != "" {
+			fmt.Fprintf(&b, "complete -c newpipi -n %s -a '(newpipi __complete %s)'\n", cond, kind)
+		}
+	}
+	return b.String()
+}
+
+func zshEscape(s string) string {
+	s = strings.ReplaceAll(s, "'", `'\''`)
+	s = strings.ReplaceAll(s, "[", `\[`)
+	s = strings.ReplaceAll(s, "]", `\]`)
+	return strings.ReplaceAll(s, ":", `\:`)
+}
+
+func fishEscape(s string) string {
+	return strings.ReplaceAll(s, "'", `\'`)
+}
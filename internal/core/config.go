@@ -0,0 +1,332 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const settingsFile = "config.json"
+
+// legacyConfigFile enthielt in älteren Versionen nur den Projektpfad.
+const legacyConfigFile = ".config/newpipi_project_path"
+
+// Settings sind die dauerhaften Einstellungen aus ~/.config/newpipi/config.json.
+type Settings struct {
+	ParentPath string `json:"parentPath,omitempty"`
+	// Terminal ist ein bekannter Terminalname (siehe terminalPresets) oder eine
+	// Befehlsvorlage mit den Platzhaltern {dir} und {script}.
+	Terminal string `json:"terminal,omitempty"`
+	// Editor wird nach der Erstellung mit dem Projektverzeichnis als Argument gestartet.
+	Editor      string `json:"editor,omitempty"`
+	TemplateDir string `json:"templateDir,omitempty"`
+	// TemplateSources sind Git-Repositorys mit geteilten Templates (siehe
+	// templatesources.go).
+	TemplateSources []string `json:"templateSources,omitempty"`
+	// TeamTemplateDir ist ein gemeinsames Template-Verzeichnis, etwa auf einer
+	// Netzwerkfreigabe; eigene Templates haben Vorrang (siehe LoadUserTemplates).
+	TeamTemplateDir string `json:"teamTemplateDir,omitempty"`
+	// Profile ist der Name des aktiven Eintrags aus Profiles.
+	Profile  string             `json:"profile,omitempty"`
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	AI       AISettings         `json:"ai,omitempty"`
+	// MarketplaceURL zeigt auf den JSON-Index der Community-Templates.
+	MarketplaceURL string `json:"marketplaceUrl,omitempty"`
+	// MarketplaceKey ist der öffentliche minisign-Schlüssel, mit dem der Index
+	// signiert sein muss.
+	MarketplaceKey string `json:"marketplaceKey,omitempty"`
+	// TrustedTemplates ordnet Marktplatz-Templates den SHA-256 der Fassung zu,
+	// deren Hooks ausgeführt werden dürfen.
+	TrustedTemplates map[string]string `json:"trustedTemplates,omitempty"`
+	// PrefetchOnStart lädt beim Start der Oberfläche im Hintergrund die
+	// üblichen Abhängigkeiten in die Paket-Caches.
+	PrefetchOnStart bool `json:"prefetchOnStart,omitempty"`
+	// UpdateCheck prüft regelmäßig auf neue Toolchain- und Template-Versionen.
+	UpdateCheck bool `json:"updateCheck,omitempty"`
+	// UIScale vergrößert oder verkleinert die Oberfläche zusätzlich zur
+	// automatischen Skalierung je Bildschirm (wie FYNE_SCALE, das Vorrang hat).
+	UIScale float64 `json:"uiScale,omitempty"`
+	// FontSize ist die Grundschriftgröße der Oberfläche (Fyne-Standard: 14).
+	FontSize float32 `json:"fontSize,omitempty"`
+	// Density ist "compact", "normal" oder "comfortable" und bestimmt die Abstände.
+	Density string `json:"density,omitempty"`
+	// DefaultPackages ersetzt je Projekttyp ("Go", "Python", ...) die
+	// Standardpakete des eingebauten Generators; eine leere Liste installiert keine.
+	DefaultPackages map[string][]string `json:"defaultPackages,omitempty"`
+	// DefaultToolkits wählt je Projekttyp das vorausgewählte Toolkit, etwa {"Python": "Tkinter"}.
+	DefaultToolkits map[string]string `json:"defaultToolkits,omitempty"`
+	// GoModulePrefix steht vor dem Projektnamen im Modulpfad neuer
+	// Go-Projekte, etwa "github.com/alexander-graf" (siehe gomodule.go).
+	GoModulePrefix string `json:"goModulePrefix,omitempty"`
+	// PackageManager ist der Paketmanager neuer JavaScript- und
+	// TypeScript-Projekte: npm (Standard), pnpm, yarn oder bun.
+	PackageManager string `json:"packageManager,omitempty"`
+	// RunCommands ersetzt den im Terminal angezeigten Startbefehl je
+	// Template-Name oder Projekttyp, etwa {"Go": "air"}.
+	RunCommands map[string]string `json:"runCommands,omitempty"`
+	// NoRunShortcuts legt den Startbefehl nicht als Taskfile-Ziel, npm-Skript,
+	// Cargo-Alias und VS-Code-Task im Projekt ab (siehe shortcuts.go).
+	NoRunShortcuts bool `json:"noRunShortcuts,omitempty"`
+	// GitHooks legt native Git-Hooks unter .githooks/ an (siehe githooks.go);
+	// CommitPattern ist ein regulärer Ausdruck für die Betreffzeile, etwa
+	// für Conventional Commits.
+	GitHooks      bool   `json:"gitHooks,omitempty"`
+	CommitPattern string `json:"commitPattern,omitempty"`
+	// ReleaseTask legt einen Release-Task für Changelog und Tag an (siehe release.go).
+	ReleaseTask bool `json:"releaseTask,omitempty"`
+	// ContentLanguage ist die Sprache der Kommentare und Texte in erzeugten
+	// Dateien: "en" (Standard) oder "de" (siehe contentlang.go).
+	ContentLanguage string `json:"contentLanguage,omitempty"`
+	// SignCommits signiert die Commits neuer Repositories (siehe signing.go).
+	SignCommits bool `json:"signCommits,omitempty"`
+	// LicenseDeny sind SPDX-Präfixe, die die Lizenzübersicht markiert, etwa
+	// "GPL" für GPL-2.0-only und GPL-3.0-or-later (siehe licenses.go).
+	LicenseDeny []string `json:"licenseDeny,omitempty"`
+	// VCS ist das Versionskontrollsystem neuer Projekte: "git" (Standard),
+	// "jj" oder "hg" (siehe vcs.go).
+	VCS string `json:"vcs,omitempty"`
+	// GitLFS verwaltet große Binärdateien neuer Repositories mit Git LFS (siehe lfs.go).
+	GitLFS bool `json:"gitLFS,omitempty"`
+	// NoGitAttributes legt mit Git keine .gitattributes an (siehe gitattributes.go).
+	NoGitAttributes bool `json:"noGitAttributes,omitempty"`
+	// NoBadges lässt die shields.io-Badges in README.md weg (siehe badges.go).
+	NoBadges bool `json:"noBadges,omitempty"`
+	// FileMode und DirMode sind die oktalen Rechte neuer Projektdateien und
+	// -verzeichnisse (Standard 0644 und 0755, siehe perms.go).
+	FileMode string `json:"fileMode,omitempty"`
+	DirMode  string `json:"dirMode,omitempty"`
+	// ScratchMaxAgeDays ist das Alter, ab dem Wegwerfprojekte zum Aufräumen
+	// anstehen (Standard 30); ScratchAutoArchive archiviert sie beim Start
+	// der Oberfläche (siehe scratch.go).
+	ScratchMaxAgeDays  int  `json:"scratchMaxAgeDays,omitempty"`
+	ScratchAutoArchive bool `json:"scratchAutoArchive,omitempty"`
+	// ErrorReports schreibt das Protokoll in eine Datei und legt bei Fehlern
+	// einen lokalen Bericht an; gesendet wird nichts (siehe errorreport.go).
+	ErrorReports bool `json:"errorReports,omitempty"`
+
+	// Policy ist die maschinenweite Richtlinie; sie wird nie in die
+	// Konfigurationsdatei geschrieben (siehe policy.go).
+	Policy Policy `json:"-"`
+}
+
+// settingsEnv ordnet jeder Einstellung ihre Umgebungsvariable zu. Gesetzte
+// Variablen haben Vorrang vor der Konfigurationsdatei. Schalter erwarten
+// true/false bzw. 1/0, RunCommands, DefaultPackages und DefaultToolkits ein
+// JSON-Objekt.
+func (s *Settings) settingsEnv() map[string]any {
+	return map[string]any{
+		"NEWPIPI_PARENT_PATH":          &s.ParentPath,
+		"NEWPIPI_TERMINAL":             &s.Terminal,
+		"NEWPIPI_EDITOR":               &s.Editor,
+		"NEWPIPI_TEMPLATE_DIR":         &s.TemplateDir,
+		"NEWPIPI_TEAM_TEMPLATE_DIR":    &s.TeamTemplateDir,
+		"NEWPIPI_TEMPLATE_SOURCES":     &s.TemplateSources,
+		"NEWPIPI_PROFILE":              &s.Profile,
+		"NEWPIPI_AI_ENDPOINT":          &s.AI.Endpoint,
+		"NEWPIPI_AI_MODEL":             &s.AI.Model,
+		"NEWPIPI_AI_API_KEY":           &s.AI.APIKey,
+		"NEWPIPI_MARKETPLACE_URL":      &s.MarketplaceURL,
+		"NEWPIPI_MARKETPLACE_KEY":      &s.MarketplaceKey,
+		"NEWPIPI_PREFETCH":             &s.PrefetchOnStart,
+		"NEWPIPI_UPDATE_CHECK":         &s.UpdateCheck,
+		"NEWPIPI_UI_SCALE":             &s.UIScale,
+		"NEWPIPI_FONT_SIZE":            &s.FontSize,
+		"NEWPIPI_DENSITY":              &s.Density,
+		"NEWPIPI_RUN_COMMANDS":         &s.RunCommands,
+		"NEWPIPI_DEFAULT_PACKAGES":     &s.DefaultPackages,
+		"NEWPIPI_DEFAULT_TOOLKITS":     &s.DefaultToolkits,
+		"NEWPIPI_GO_MODULE_PREFIX":     &s.GoModulePrefix,
+		"NEWPIPI_PACKAGE_MANAGER":      &s.PackageManager,
+		"NEWPIPI_NO_RUN_SHORTCUTS":     &s.NoRunShortcuts,
+		"NEWPIPI_GIT_HOOKS":            &s.GitHooks,
+		"NEWPIPI_COMMIT_PATTERN":       &s.CommitPattern,
+		"NEWPIPI_RELEASE_TASK":         &s.ReleaseTask,
+		"NEWPIPI_CONTENT_LANGUAGE":     &s.ContentLanguage,
+		"NEWPIPI_SIGN_COMMITS":         &s.SignCommits,
+		"NEWPIPI_GIT_LFS":              &s.GitLFS,
+		"NEWPIPI_VCS":                  &s.VCS,
+		"NEWPIPI_LICENSE_DENY":         &s.LicenseDeny,
+		"NEWPIPI_NO_GITATTRIBUTES":     &s.NoGitAttributes,
+		"NEWPIPI_NO_BADGES":            &s.NoBadges,
+		"NEWPIPI_FILE_MODE":            &s.FileMode,
+		"NEWPIPI_DIR_MODE":             &s.DirMode,
+		"NEWPIPI_SCRATCH_MAX_AGE":      &s.ScratchMaxAgeDays,
+		"NEWPIPI_SCRATCH_AUTO_ARCHIVE": &s.ScratchAutoArchive,
+		"NEWPIPI_ERROR_REPORTS":        &s.ErrorReports,
+	}
+}
+
+// applyEnv übernimmt gesetzte Umgebungsvariablen; ungültige Werte werden
+// protokolliert und ignoriert.
+func (s *Settings) applyEnv() {
+	for name, field := range s.settingsEnv() {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		var err error
+		switch field := field.(type) {
+		case *string:
+			*field = value
+		case *bool:
+			var b bool
+			if b, err = strconv.ParseBool(value); err == nil {
+				*field = b
+			}
+		case *int:
+			var i int
+			if i, err = strconv.Atoi(value); err == nil {
+				*field = i
+			}
+		case *float64:
+			var f float64
+			if f, err = strconv.ParseFloat(value, 64); err == nil {
+				*field = f
+			}
+		case *float32:
+			var f float64
+			if f, err = strconv.ParseFloat(value, 32); err == nil {
+				*field = float32(f)
+			}
+		case *[]string:
+			*field = strings.Fields(value)
+		case *map[string]string:
+			var m map[string]string
+			if err = json.Unmarshal([]byte(value), &m); err == nil {
+				*field = m
+			}
+		case *map[string][]string:
+			var m map[string][]string
+			if err = json.Unmarshal([]byte(value), &m); err == nil {
+				*field = m
+			}
+		}
+		if err != nil {
+			log.Printf("Umgebungsvariable %s ungültig, ignoriert: %v", name, err)
+		}
+	}
+}
+
+// configDir liefert das Konfigurationsverzeichnis, überschreibbar mit NEWPIPI_CONFIG_DIR.
+func configDir() (string, error) {
+	if dir := os.Getenv("NEWPIPI_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("home dir nicht gefunden: %v", err)
+	}
+	return filepath.Join(homeDir, ".config", "newpipi"), nil
+}
+
+// StateDir liefert das Verzeichnis für Laufzeitdaten wie Zeitmessungen
+// (XDG_STATE_HOME, sonst ~/.local/state/newpipi).
+func StateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "newpipi"), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("home dir nicht gefunden: %v", err)
+	}
+	return filepath.Join(homeDir, ".local", "state", "newpipi"), nil
+}
+
+// ReadSettingsFile liest nur die Konfigurationsdatei, ohne Umgebungsvariablen.
+func ReadSettingsFile() (Settings, error) {
+	var s Settings
+	dir, err := configDir()
+	if err != nil {
+		return s, err
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, settingsFile))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(content, &s); err != nil {
+			return s, fmt.Errorf("config parsen fehlgeschlagen: %v", err)
+		}
+	case !os.IsNotExist(err):
+		return s, fmt.Errorf("config lesen fehlgeschlagen: %v", err)
+	}
+
+	if s.ParentPath == "" {
+		s.ParentPath = readLegacyProjectPath()
+	}
+	return s, nil
+}
+
+// readLegacyProjectPath übernimmt den Projektpfad aus der alten Konfigurationsdatei.
+func readLegacyProjectPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	content, err := os.ReadFile(filepath.Join(homeDir, legacyConfigFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+// LoadSettings liefert die wirksamen Einstellungen: Datei plus Umgebungsvariablen.
+func LoadSettings() (Settings, error) {
+	s, err := ReadSettingsFile()
+	s.applyEnv()
+	policy, policyErr := loadPolicy()
+	s.Policy = policy
+	return s, errors.Join(err, policyErr)
+}
+
+func writeSettingsFile(s Settings) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("config dir erstellen fehlgeschlagen: %v", err)
+	}
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("config serialisieren fehlgeschlagen: %v", err)
+	}
+	// Die Datei kann Tokens enthalten und ist daher nur für den Benutzer lesbar;
+	// WriteFile setzt die Rechte nur beim Anlegen, ältere Dateien werden verschärft
+	path := filepath.Join(dir, settingsFile)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return fmt.Errorf("config schreiben fehlgeschlagen: %v", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("rechte der config setzen fehlgeschlagen: %v", err)
+	}
+	autoCommitConfig("Einstellungen geändert")
+	return nil
+}
+
+// UpdateSettingsFile ändert die Konfigurationsdatei, ohne Werte aus
+// Umgebungsvariablen dauerhaft zu übernehmen.
+func UpdateSettingsFile(update func(s *Settings)) error {
+	s, err := ReadSettingsFile()
+	if err != nil {
+		return err
+	}
+	update(&s)
+	return writeSettingsFile(s)
+}
+
+// userTemplateDir liefert das Verzeichnis der Benutzer-Templates.
+func (s Settings) userTemplateDir() string {
+	if s.TemplateDir != "" {
+		return s.TemplateDir
+	}
+	dir, err := configDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "templates")
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		get   func(Settings) any
+		want  any
+	}{
+		{"NEWPIPI_EDITOR", "nvim", func(s Settings) any { return s.Editor }, "nvim"},
+		{"NEWPIPI_GIT_HOOKS", "true", func(s Settings) any { return s.GitHooks }, true},
+		{"NEWPIPI_LICENSE_DENY", "GPL AGPL", func(s Settings) any { return s.LicenseDeny }, []string{"GPL", "AGPL"}},
+		{"NEWPIPI_RUN_COMMANDS", `{"Go":"air"}`, func(s Settings) any { return s.RunCommands }, map[string]string{"Go": "air"}},
+		{"NEWPIPI_DEFAULT_PACKAGES", `{"Python":["requests","rich"],"Go":[]}`, func(s Settings) any { return s.DefaultPackages }, map[string][]string{"Python": {"requests", "rich"}, "Go": {}}},
+		{"NEWPIPI_DEFAULT_TOOLKITS", `{"Python":"Tkinter"}`, func(s Settings) any { return s.DefaultToolkits }, map[string]string{"Python": "Tkinter"}},
+		{"NEWPIPI_DEFAULT_PACKAGES", `["requests"]`, func(s Settings) any { return s.DefaultPackages }, map[string][]string(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			var s Settings
+			s.applyEnv()
+			if got := tt.get(s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s=%s: %#v, want %#v", tt.name, tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package core
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Das Konfigurationsverzeichnis kann ein Git-Repository sein, damit Templates,
+// Profile und Einstellungen zwischen Rechnern synchronisiert werden.
+
+const configRepoBranch = "main"
+
+func isConfigRepo() bool {
+	dir, err := configDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// configGit führt einen Git-Befehl im Konfigurationsverzeichnis aus.
+func configGit(args ...string) (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s fehlgeschlagen: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// initConfigRepo macht das Konfigurationsverzeichnis zu einem Git-Repository.
+func initConfigRepo(remote string) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("config dir erstellen fehlgeschlagen: %v", err)
+	}
+	if _, err := configGit("init", "-b", configRepoBranch); err != nil {
+		return err
+	}
+	if remote != "" {
+		if _, err := configGit("remote", "add", "origin", remote); err != nil {
+			return err
+		}
+	}
+	return commitConfig("Konfiguration initialisiert")
+}
+
+// cloneConfigRepo übernimmt eine bestehende Konfiguration auf einen neuen Rechner.
+func cloneConfigRepo(remote string) error {
+	dir, err := configDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, settingsFile)); err == nil {
+		return fmt.Errorf("konfiguration existiert bereits: %s", dir)
+	}
+	out, err := exec.Command("git", "clone", remote, dir).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git clone fehlgeschlagen: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// commitConfig committet alle Änderungen im Konfigurationsverzeichnis, falls es
+// welche gibt. Solange Tokens im Klartext in den Einstellungen stehen, wird
+// nichts committet, damit sie nicht in der Historie und auf dem Remote landen.
+func commitConfig(msg string) error {
+	s, err := ReadSettingsFile()
+	if err != nil {
+		return err
+	}
+	if s.hasPlaintextSecrets() {
+		return errors.New("tokens liegen im klartext in der konfiguration, erst mit newpipi secret migrate in den schlüsselbund verschieben")
+	}
+	if _, err := configGit("add", "-A"); err != nil {
+		return err
+	}
+	status, err := configGit("status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(status) == "" {
+		return nil
+	}
+	_, err = configGit("commit", "-m", msg)
+	return err
+}
+
+// autoCommitConfig committet Änderungen automatisch, wenn die Konfiguration versioniert ist.
+func autoCommitConfig(msg string) {
+	if !isConfigRepo() {
+		return
+	}
+	if err := commitConfig(msg); err != nil {
+		log.Printf("Konfiguration committen fehlgeschlagen: %v", err)
+	}
+}
+
+// setupConfig definiert `newpipi config init|clone|commit|pull|push`.
+func setupConfig(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi config init [REMOTE] | clone REMOTE | commit | pull | push")
+			return exitUsage
+		}
+		var err error
+		switch args[0] {
+		case "init":
+			remote := ""
+			if len(args) > 1 {
+				remote = args[1]
+			}
+			err = initConfigRepo(remote)
+		case "clone":
+			if len(args) < 2 {
+				fmt.Fprintln(os.Stderr, "Verwendung: newpipi config clone REMOTE")
+				return exitUsage
+			}
+			err = cloneConfigRepo(args[1])
+		case "commit":
+			err = commitConfig("Konfiguration aktualisiert")
+		case "pull":
+			_, err = configGit("pull", "--rebase", "--autostash", "origin", configRepoBranch)
+		case "push":
+			// Offene Änderungen mitnehmen; mit Klartext-Tokens wird nicht gepusht
+			if err = commitConfig("Konfiguration aktualisiert"); err == nil {
+				_, err = configGit("push", "-u", "origin", configRepoBranch)
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "Unbekannte Aktion: %s\n", args[0])
+			return exitUsage
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		return ExitOK
+	}
+}
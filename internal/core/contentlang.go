@@ -0,0 +1,87 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Sprache erzeugter Inhalte: Kommentare, Beschreibungen und Meldungen in den
+// Dateien, die newpipi anlegt (Taskfile, Git-Hooks, .gitattributes,
+// Changelog), sind Englisch; mit "contentLanguage": "de" stehen sie auf
+// Deutsch. Übersetzt wird wie bei gettext anhand des englischen Textes, ein
+// fehlender Eintrag bleibt englisch. Templates übersetzen ihre Texte mit
+// {{t "..."}} aus dem eingebauten Katalog und eigenen "messages".
+
+// contentLanguages sind die Sprachen erzeugter Inhalte, Englisch zuerst.
+var contentLanguages = []string{"en", "de"}
+
+// contentCatalogs übersetzen die englischen Texte je Sprache. %s und %d stehen für
+// Werte, die erst beim Rendern feststehen.
+var contentCatalogs = map[string]map[string]string{
+	"de": {
+		"Generated by newpipi": "Von newpipi erzeugt",
+		"Run the project":      "Projekt starten",
+		"Line endings":         "Zeilenenden",
+		"Binary files":         "Binärdateien",
+		"Large binary files, active with Git LFS (git lfs track)":              "Große Binärdateien, aktiv mit Git LFS (git lfs track)",
+		"checks the formatting before every commit.":                           "prüft vor jedem Commit die Formatierung.",
+		"Locally installed tools (npm, venv) take precedence.":                 "Lokal installierte Werkzeuge (npm, venv) haben Vorrang.",
+		"%s not found, formatting not checked":                                 "%s nicht gefunden, Formatierung nicht geprüft",
+		"formatting is off, checked with:":                                     "Formatierung fehlerhaft, geprüft mit:",
+		"checks the subject line of the commit message.":                       "prüft die Betreffzeile der Commit-Nachricht.",
+		"subject line missing":                                                 "Betreffzeile fehlt",
+		"subject line longer than %d characters":                               "Betreffzeile länger als %d Zeichen",
+		"subject line does not match":                                          "Betreffzeile passt nicht zu",
+		"This repository uses Git LFS, but git-lfs is not installed.":          "Dieses Repository nutzt Git LFS, aber git-lfs ist nicht installiert.",
+		"Bump the version, update CHANGELOG.md and tag the release":            "Version erhöhen, CHANGELOG.md fortschreiben und Release taggen",
+		"All notable changes to this project will be documented in this file.": "Alle nennenswerten Änderungen an diesem Projekt werden in dieser Datei festgehalten.",
+	},
+}
+
+// contentLanguage liefert die Sprache erzeugter Inhalte; unbekannte Angaben
+// fallen auf Englisch zurück.
+func (s Settings) contentLanguage() string {
+	if s.ContentLanguage == "" {
+		return contentLanguages[0]
+	}
+	for _, lang := range contentLanguages {
+		if strings.EqualFold(lang, s.ContentLanguage) {
+			return lang
+		}
+	}
+	log.Printf("Unbekannte Sprache für erzeugte Inhalte: %s (%s)", s.ContentLanguage, strings.Join(contentLanguages, ", "))
+	return contentLanguages[0]
+}
+
+// translate übersetzt msg nach lang, zuerst aus messages, dann aus dem
+// eingebauten Katalog, und setzt args für %s ein.
+func translate(messages map[string]map[string]string, lang, msg string, args ...any) string {
+	if text, ok := messages[lang][msg]; ok {
+		msg = text
+	} else if text, ok := contentCatalogs[lang][msg]; ok {
+		msg = text
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// text übersetzt msg in die Sprache erzeugter Inhalte, mit den Übersetzungen
+// des Templates.
+func (ps *ProjectSetup) text(msg string, args ...any) string {
+	var messages map[string]map[string]string
+	if ps.Template != nil {
+		messages = ps.Template.Messages
+	}
+	return translate(messages, ps.Settings.contentLanguage(), msg, args...)
+}
+
+// localize ersetzt in content die englischen Texte msgs durch ihre Übersetzung.
+func (ps *ProjectSetup) localize(content string, msgs ...string) string {
+	for _, msg := range msgs {
+		content = strings.Replace(content, msg, ps.text(msg), 1)
+	}
+	return content
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestContentCatalogs prüft, dass jede Übersetzung dieselben Platzhalter
+// wie der englische Text hat.
+func TestContentCatalogs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	for lang, catalog := range contentCatalogs {
+		if !slices.Contains(contentLanguages, lang) {
+			t.Errorf("katalog %s: sprache fehlt in contentLanguages", lang)
+		}
+		for msg, text := range catalog {
+			if strings.TrimSpace(text) == "" {
+				t.Errorf("%s: %q ist leer übersetzt", lang, msg)
+			}
+			if got, want := verbs.FindAllString(text, -1), verbs.FindAllString(msg, -1); !slices.Equal(got, want) {
+				t.Errorf("%s: %q hat die platzhalter %q, want %q", lang, text, got, want)
+			}
+		}
+	}
+}
+
+func TestGitHooksLanguage(t *testing.T) {
+	t.Setenv("NEWPIPI_CONFIG_DIR", t.TempDir())
+	tests := []struct {
+		lang string
+		want []string
+	}{
+		{"", []string{"# Generated by newpipi: checks the subject line", "subject line longer than 72 characters"}},
+		{"de", []string{"# Von newpipi erzeugt: prüft die Betreffzeile", "Betreffzeile länger als 72 Zeichen"}},
+		{"DE", []string{"Betreffzeile fehlt"}},
+		{"fr", []string{"subject line missing"}},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		ps := &ProjectSetup{Settings: Settings{ContentLanguage: tt.lang}, Template: &Template{}}
+		if err := ps.writeGitHooks(dir); err != nil {
+			t.Fatalf("%s: %v", tt.lang, err)
+		}
+		content, err := os.ReadFile(filepath.Join(dir, gitHooksDir, "commit-msg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s: commit-msg enthält %q nicht:\n%s", tt.lang, want, content)
+			}
+		}
+	}
+}
+
+func TestTemplateMessages(t *testing.T) {
+	tmpl := &Template{Messages: map[string]map[string]string{"de": {"Hello, %s!": "Hallo, %s!"}}}
+	tests := []struct {
+		lang, want string
+	}{
+		{"en", "Hello, demo! Generated by newpipi"},
+		{"de", "Hallo, demo! Von newpipi erzeugt"},
+	}
+	for _, tt := range tests {
+		ps := &ProjectSetup{Settings: Settings{ContentLanguage: tt.lang}, Template: tmpl, ProjectName: "demo"}
+		data := &scriptResult{vars: ps.templateVars(), text: ps.text}
+		got, err := data.render("greeting", `{{t "Hello, %s!" .ProjectName}} {{t "Generated by newpipi"}}`)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q", tt.lang, got, err, tt.want)
+		}
+	}
+}
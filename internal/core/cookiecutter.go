@@ -0,0 +1,499 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Cookiecutter-Templates (cookiecutter.json und ein Verzeichnis
+// {{cookiecutter.project_slug}}/) übernimmt `newpipi import` als newpipi-Template:
+// Variablen werden Optionen, die Jinja-Platzhalter text/template-Ausdrücke, und
+// Dateien samt Pfaden werden bei der Erstellung gerendert (Template.Render).
+// Übersetzt wird der in Cookiecutter-Templates übliche Teil von Jinja:
+// Variablen mit lower, upper, title, strip und replace sowie if/elif/else mit
+// ==, !=, and, or und not. Schleifen, Makros und Cookiecutter-Hooks gibt es
+// nicht; sie werden gemeldet.
+
+// cookiecutterNameVars übernehmen den Projektnamen, den newpipi ohnehin abfragt.
+var cookiecutterNameVars = []string{"project_name", "name", "app_name"}
+
+var (
+	jinjaTag     = regexp.MustCompile(`(?s)\{\{(-?)(.*?)(-?)\}\}|\{%(-?)(.*?)(-?)%\}|\{#.*?#\}`)
+	jinjaRaw     = regexp.MustCompile(`(?s)\{%-?\s*raw\s*-?%\}(.*?)\{%-?\s*endraw\s*-?%\}`)
+	jinjaVar     = regexp.MustCompile(`^cookiecutter(?:\.(\w+)|\[\s*['"]([^'"]+)['"]\s*\])`)
+	jinjaString  = regexp.MustCompile(`^(?:'([^']*)'|"([^"]*)")$`)
+	jinjaFilter  = regexp.MustCompile(`^\s*(?:\.(lower|upper|title|strip)\(\)|\|\s*(lower|upper|title|trim))`)
+	jinjaReplace = regexp.MustCompile(`^\s*(?:\.|\|\s*)replace\(\s*(?:'([^']*)'|"([^"]*)")\s*,\s*(?:'([^']*)'|"([^"]*)")\s*\)`)
+	jinjaCompare = regexp.MustCompile(`^(.+?)\s*(==|!=)\s*(.+)$`)
+	identifier   = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+)
+
+// cookiecutterConverter übersetzt die Jinja-Platzhalter eines Templates.
+type cookiecutterConverter struct {
+	// exprs ordnet jeder Variable ihren text/template-Ausdruck zu.
+	exprs map[string]string
+	// bools sind Variablen mit true/false als Wert.
+	bools map[string]bool
+}
+
+// variable liefert den Ausdruck einer Variable; Optionen mit Zeichen außerhalb
+// von Go-Bezeichnern werden über index angesprochen.
+func variable(name string) string {
+	if identifier.MatchString(name) {
+		return "." + name
+	}
+	return fmt.Sprintf("(index . %q)", name)
+}
+
+// expr übersetzt einen Jinja-Ausdruck wie cookiecutter.name.lower()|replace(' ', '_').
+func (c *cookiecutterConverter) expr(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if m := jinjaString.FindStringSubmatch(s); m != nil {
+		return strconv.Quote(m[1] + m[2]), nil
+	}
+	m := jinjaVar.FindStringSubmatch(s)
+	if m == nil {
+		return "", fmt.Errorf("jinja-ausdruck %q nicht unterstützt", s)
+	}
+	name := m[1] + m[2]
+	out, ok := c.exprs[name]
+	if !ok {
+		return "", fmt.Errorf("unbekannte variable cookiecutter.%s", name)
+	}
+	rest := s[len(m[0]):]
+	for strings.TrimSpace(rest) != "" {
+		if f := jinjaFilter.FindStringSubmatch(rest); f != nil {
+			filter := f[1] + f[2]
+			if filter == "strip" {
+				filter = "trim"
+			}
+			out += " | " + filter
+			rest = rest[len(f[0]):]
+			continue
+		}
+		if r := jinjaReplace.FindStringSubmatch(rest); r != nil {
+			out += fmt.Sprintf(" | replace %q %q", r[1]+r[2], r[3]+r[4])
+			rest = rest[len(r[0]):]
+			continue
+		}
+		return "", fmt.Errorf("jinja-ausdruck %q nicht unterstützt", s)
+	}
+	return out, nil
+}
+
+// cond übersetzt die Bedingung eines if oder elif.
+func (c *cookiecutterConverter) cond(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	for _, op := range []string{" or ", " and "} {
+		if left, right, ok := strings.Cut(s, op); ok {
+			l, err := c.cond(left)
+			if err != nil {
+				return "", err
+			}
+			r, err := c.cond(right)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s (%s) (%s)", strings.TrimSpace(op), l, r), nil
+		}
+	}
+	if rest, ok := strings.CutPrefix(s, "not "); ok {
+		inner, err := c.cond(rest)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("not (%s)", inner), nil
+	}
+	if m := jinjaCompare.FindStringSubmatch(s); m != nil {
+		l, err := c.expr(m[1])
+		if err != nil {
+			return "", err
+		}
+		r, err := c.expr(m[3])
+		if err != nil {
+			return "", err
+		}
+		op := "eq"
+		if m[2] == "!=" {
+			op = "ne"
+		}
+		return fmt.Sprintf("%s (%s) (%s)", op, l, r), nil
+	}
+	e, err := c.expr(s)
+	if err != nil {
+		return "", err
+	}
+	if m := jinjaVar.FindStringSubmatch(s); m != nil && c.bools[m[1]+m[2]] {
+		return fmt.Sprintf("eq (%s) \"true\"", e), nil
+	}
+	return e, nil
+}
+
+// escapeTemplate schützt Text, der unverändert bleiben soll, vor text/template.
+func escapeTemplate(s string) string {
+	return strings.ReplaceAll(s, "{{", `{{"{{"}}`)
+}
+
+// convert übersetzt einen Dateiinhalt oder Pfad von Jinja nach text/template.
+func (c *cookiecutterConverter) convert(s string) (string, error) {
+	// raw-Blöcke vorab schützen, ihr Inhalt darf keine Tags mehr bilden
+	var raws []string
+	s = jinjaRaw.ReplaceAllStringFunc(s, func(block string) string {
+		raws = append(raws, escapeTemplate(jinjaRaw.FindStringSubmatch(block)[1]))
+		return fmt.Sprintf("\x00raw%d\x00", len(raws)-1)
+	})
+
+	var b strings.Builder
+	var err error
+	last := 0
+	for _, loc := range jinjaTag.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(escapeTemplate(s[last:loc[0]]))
+		last = loc[1]
+		group := func(i int) string {
+			if loc[2*i] < 0 {
+				return ""
+			}
+			return s[loc[2*i]:loc[2*i+1]]
+		}
+		open, close := "{{", "}}"
+		if group(1) == "-" || group(4) == "-" {
+			open = "{{- "
+		}
+		if group(3) == "-" || group(6) == "-" {
+			close = " -}}"
+		}
+		switch {
+		case loc[2] >= 0:
+			var e string
+			if e, err = c.expr(group(2)); err != nil {
+				return "", err
+			}
+			b.WriteString(open + e + close)
+		case loc[8] >= 0:
+			stmt := strings.TrimSpace(group(5))
+			keyword, rest, _ := strings.Cut(stmt, " ")
+			var action string
+			switch keyword {
+			case "if", "elif":
+				var cond string
+				if cond, err = c.cond(rest); err != nil {
+					return "", err
+				}
+				action = "if " + cond
+				if keyword == "elif" {
+					action = "else if " + cond
+				}
+			case "else":
+				action = "else"
+			case "endif":
+				action = "end"
+			default:
+				return "", fmt.Errorf("jinja-anweisung %q nicht unterstützt", stmt)
+			}
+			b.WriteString(open + action + close)
+		}
+		// Kommentare entfallen
+	}
+	b.WriteString(escapeTemplate(s[last:]))
+
+	out := b.String()
+	for i, raw := range raws {
+		out = strings.Replace(out, fmt.Sprintf("\x00raw%d\x00", i), raw, 1)
+	}
+	return out, nil
+}
+
+// readCookiecutterContext liest cookiecutter.json in der Reihenfolge der Datei.
+func readCookiecutterContext(content []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("cookiecutter.json ist kein json-objekt")
+	}
+	var keys []string
+	values := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		values[key] = value
+	}
+	return keys, values, nil
+}
+
+// importCookiecutter übersetzt das Cookiecutter-Template in dir. Die
+// Warnungen nennen, was nicht übernommen wurde.
+func importCookiecutter(dir, name string, pt ProjectType, typeGiven bool) (Template, []string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, "cookiecutter.json"))
+	if err != nil {
+		return Template{}, nil, fmt.Errorf("cookiecutter.json lesen fehlgeschlagen: %v", err)
+	}
+	keys, values, err := readCookiecutterContext(content)
+	if err != nil {
+		return Template{}, nil, fmt.Errorf("cookiecutter.json parsen fehlgeschlagen: %v", err)
+	}
+
+	t := Template{Name: name, Render: true, Files: make(map[string]string)}
+	c := &cookiecutterConverter{exprs: make(map[string]string), bools: make(map[string]bool)}
+	var warnings, copyRaw []string
+	nameVar := ""
+	for _, key := range keys {
+		var value any
+		json.Unmarshal(values[key], &value)
+		if key == "_copy_without_render" {
+			for _, pattern := range value.([]any) {
+				if s, ok := pattern.(string); ok {
+					copyRaw = append(copyRaw, s)
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			switch {
+			case strings.Contains(v, "{{"):
+				// Abgeleitete Werte wie project_slug folgen ihrem Ausdruck
+				m := jinjaTag.FindStringSubmatch(v)
+				e := ""
+				if m != nil && m[0] == strings.TrimSpace(v) && m[2] != "" {
+					e, err = c.expr(m[2])
+				}
+				if e == "" || err != nil {
+					warnings = append(warnings, fmt.Sprintf("%s: standardwert %q nicht übersetzbar, als leere option übernommen", key, v))
+					t.Options = append(t.Options, TemplateOption{Name: key, Type: "string"})
+					c.exprs[key] = variable(key)
+					continue
+				}
+				c.exprs[key] = "(" + e + ")"
+			case nameVar == "" && slices.Contains(cookiecutterNameVars, key):
+				nameVar = key
+				c.exprs[key] = ".ProjectName"
+			default:
+				t.Options = append(t.Options, TemplateOption{Name: key, Type: "string", Default: v})
+				c.exprs[key] = variable(key)
+			}
+		case bool:
+			t.Options = append(t.Options, TemplateOption{Name: key, Type: "bool", Default: strconv.FormatBool(v)})
+			c.exprs[key], c.bools[key] = variable(key), true
+		case float64:
+			t.Options = append(t.Options, TemplateOption{Name: key, Type: "string", Default: strconv.FormatFloat(v, 'f', -1, 64)})
+			c.exprs[key] = variable(key)
+		case []any:
+			o := TemplateOption{Name: key, Type: "choice"}
+			for _, choice := range v {
+				o.Choices = append(o.Choices, fmt.Sprint(choice))
+			}
+			t.Options = append(t.Options, o)
+			c.exprs[key] = variable(key)
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s: werte vom typ %T werden nicht übernommen", key, v))
+		}
+	}
+
+	// Das Projektverzeichnis ist das einzige Verzeichnis mit Platzhalter im Namen
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Template{}, nil, err
+	}
+	root := ""
+	for _, e := range entries {
+		if e.IsDir() && strings.Contains(e.Name(), "{{") && strings.Contains(e.Name(), "cookiecutter") {
+			if root != "" {
+				return Template{}, nil, fmt.Errorf("mehrere projektverzeichnisse: %s und %s", root, e.Name())
+			}
+			root = e.Name()
+		}
+		if e.Name() == "hooks" {
+			warnings = append(warnings, "hooks/ nicht übernommen, cookiecutter-hooks laufen nicht")
+		}
+	}
+	if root == "" {
+		return Template{}, nil, fmt.Errorf("kein projektverzeichnis {{cookiecutter...}} gefunden")
+	}
+
+	// Den Projekttyp verraten Dateien wie go.mod im Projektverzeichnis
+	if !typeGiven {
+		var ok bool
+		if pt, ok = DetectProjectType(filepath.Join(dir, root)); !ok {
+			return Template{}, nil, fmt.Errorf("projekttyp nicht erkannt, mit -type angeben")
+		}
+	}
+	err = filepath.WalkDir(filepath.Join(dir, root), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(filepath.Join(dir, root), p)
+		rel = filepath.ToSlash(rel)
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		dest, err := c.convert(rel)
+		if err != nil {
+			return fmt.Errorf("%s: %v", rel, err)
+		}
+		if !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0 {
+			if strings.Contains(rel, "{{") {
+				warnings = append(warnings, rel+": binärdatei mit platzhalter im pfad nicht übernommen")
+				return nil
+			}
+			if t.Assets == nil {
+				t.Assets = make(map[string]string)
+			}
+			t.Assets[rel] = assetBase64Prefix + base64.StdEncoding.EncodeToString(data)
+			return nil
+		}
+		text := escapeTemplate(string(data))
+		if !matchesAny(copyRaw, rel) {
+			if text, err = c.convert(string(data)); err != nil {
+				return fmt.Errorf("%s: %v", rel, err)
+			}
+		}
+		t.Files[dest] = text
+		// Nur Rechte festhalten, die vom Standard abweichen (wie bei record)
+		if info, err := d.Info(); err == nil && info.Mode()&0111 != 0 {
+			if def, _ := t.fileMode(dest, text, defaultModes); def&0111 == 0 {
+				if t.Modes == nil {
+					t.Modes = make(map[string]string)
+				}
+				t.Modes[dest] = "0755"
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Template{}, nil, err
+	}
+	t.Type = pt
+	return t, warnings, nil
+}
+
+// matchesAny prüft die Muster aus _copy_without_render wie Pythons fnmatch:
+// * und ? passen auch auf "/".
+func matchesAny(patterns []string, file string) bool {
+	for _, pattern := range patterns {
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(expr)
+		if ok, _ := regexp.MatchString("^"+expr+"$", file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setupImport definiert `newpipi import [-type TYP] [-name NAME] QUELLE`.
+func setupImport(fs *flag.FlagSet) func(args []string) int {
+	typeName := fs.String("type", "", "Projekttyp, ohne Angabe aus den Dateien erkannt")
+	name := fs.String("name", "", "Name des Templates, Standard ist der Verzeichnisname")
+	trust := fs.Bool("trust", false, "Befehlen eines importierten Archivs ohne Rückfrage vertrauen")
+	return func(args []string) int {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi import [-type TYP] [-name NAME] [-trust] VERZEICHNIS|GIT-URL|ARCHIV"+ArchiveExtension)
+			return exitUsage
+		}
+		if strings.HasSuffix(args[0], ArchiveExtension) {
+			return importArchiveCommand(args[0], *name, *trust)
+		}
+		var pt ProjectType
+		if *typeName != "" {
+			var ok bool
+			if pt, ok = ParseProjectType(*typeName); !ok {
+				fmt.Fprintf(os.Stderr, "Fehler: unbekannter projekttyp: %s\n", *typeName)
+				return exitUsage
+			}
+		}
+		settings, err := LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+
+		dir := args[0]
+		if _, err := os.Stat(dir); err != nil {
+			// Keine lokale Kopie: wie eine Template-Quelle flach klonen
+			if err := settings.Policy.remoteTemplates(); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitPolicy
+			}
+			// gh:owner/repo ist die Cookiecutter-Kurzform für GitHub
+			source, err := parseTemplateSource(strings.TrimPrefix(dir, "gh:"))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitUsage
+			}
+			tmp, err := os.MkdirTemp("", "newpipi-cookiecutter-")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			defer os.RemoveAll(tmp)
+			ctx, cancel := context.WithTimeout(context.Background(), sourceTimeout)
+			defer cancel()
+			clone := []string{"clone", "--quiet", "--depth", "1"}
+			if source.Ref != "" {
+				clone = append(clone, "--branch", source.Ref)
+			}
+			cmd := exec.CommandContext(ctx, "git", append(clone, "--", source.URL, tmp)...)
+			cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %s: %s\n", source, strings.TrimSpace(string(out)))
+				return exitNetwork
+			}
+			dir = tmp
+			if *name == "" {
+				*name = strings.TrimSuffix(path.Base(source.URL), ".git")
+			}
+		}
+		if *name == "" {
+			abs, _ := filepath.Abs(dir)
+			*name = filepath.Base(abs)
+		}
+
+		t, warnings, err := importCookiecutter(dir, *name, pt, *typeName != "")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		t.Description = "Cookiecutter: " + args[0]
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "Warnung: %s\n", w)
+		}
+
+		file, err := saveUserTemplate(settings, &t, "Cookiecutter-Template importiert: "+t.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		fmt.Printf("Template %s (%s) importiert: %s\n", t.Name, t.Type, file)
+		return ExitOK
+	}
+}
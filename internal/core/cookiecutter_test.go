@@ -0,0 +1,46 @@
+package core
+
+import "testing"
+
+func TestCookiecutterConvert(t *testing.T) {
+	c := &cookiecutterConverter{
+		exprs: map[string]string{
+			"project_name": ".ProjectName",
+			"project_slug": `(.ProjectName | lower | replace " " "_")`,
+			"license":      ".license",
+			"use_docker":   ".use_docker",
+			"author name":  `(index . "author name")`,
+		},
+		bools: map[string]bool{"use_docker": true},
+	}
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"{{ cookiecutter.project_slug }}", `{{(.ProjectName | lower | replace " " "_")}}`, true},
+		{"{{cookiecutter.project_name.upper()}}", "{{.ProjectName | upper}}", true},
+		{"{{ cookiecutter.project_name|title|replace('-', ' ') }}", `{{.ProjectName | title | replace "-" " "}}`, true},
+		{"{{ cookiecutter['author name'] }}", `{{(index . "author name")}}`, true},
+		{"{% if cookiecutter.license == 'MIT' %}a{% elif cookiecutter.license != \"BSD\" %}b{% else %}c{% endif %}",
+			`{{if eq (.license) ("MIT")}}a{{else if ne (.license) ("BSD")}}b{{else}}c{{end}}`, true},
+		{"{%- if cookiecutter.use_docker and not cookiecutter.license == 'MIT' -%}x{%- endif %}",
+			`{{- if and (eq (.use_docker) "true") (not (eq (.license) ("MIT"))) -}}x{{- end}}`, true},
+		{"a{# Kommentar #}b", "ab", true},
+		{"{% raw %}{{ x }}{% endraw %}", `{{"{{"}} x }}`, true},
+		{"${{ github.ref }}", "", false},
+		{"{{ cookiecutter.unknown }}", "", false},
+		{"{% for x in cookiecutter.list %}{% endfor %}", "", false},
+		{"{{ cookiecutter.project_name|slugify }}", "", false},
+	}
+	for _, tt := range tests {
+		got, err := c.convert(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("convert(%q): fehler %v, erwartet ok=%v", tt.in, err, tt.ok)
+			continue
+		}
+		if tt.ok && got != tt.want {
+			t.Errorf("convert(%q) = %q, erwartet %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package core
+
+// C++-Buildsysteme: Die Toolkits von C++ wählen CMake, Meson oder ein
+// schlichtes Makefile und binden auf Wunsch vcpkg oder Conan ein. Mit
+// Paketmanager kommt fmt als Beispielabhängigkeit hinzu, damit die Anbindung
+// vom ersten Build an geprüft wird; der Startbefehl baut das Projekt, das
+// Programm liegt danach wie beim reinen CMake unter build/.
+
+// cppToolkits sind die Buildsysteme für C++, CMake ohne Paketmanager zuerst.
+var cppToolkits = []toolkit{
+	{
+		// Startbefehl bleibt das Terminal-Skript des Projekttyps
+		Name:      "CMake",
+		Files:     map[string]string{"CMakeLists.txt": cmakeLists},
+		Toolchain: []toolCheck{cmakeCheck},
+		Check:     "cmake -B build && cmake --build build",
+	},
+	{
+		Name: "CMake+vcpkg",
+		Files: map[string]string{
+			"CMakeLists.txt":    cmakeFmtLists,
+			"CMakePresets.json": vcpkgPresets,
+			"vcpkg.json":        vcpkgManifest,
+			"src/main.cpp":      cppFmtMain,
+		},
+		Toolchain: []toolCheck{cmakeCheck, {[]string{"vcpkg", "version"}, "vcpkg"}},
+		// vcpkg installiert die Abhängigkeiten beim Konfigurieren im Manifest-Modus
+		Run:   "cmake --preset vcpkg && cmake --build build",
+		Check: "cmake --preset vcpkg && cmake --build build",
+	},
+	{
+		Name: "CMake+Conan",
+		Files: map[string]string{
+			"CMakeLists.txt": cmakeFmtLists,
+			"conanfile.txt":  conanCMake,
+			"src/main.cpp":   cppFmtMain,
+			// conan install legt CMakeUserPresets.json neben CMakeLists.txt ab
+			".gitignore": "/build\nCMakeUserPresets.json\n",
+		},
+		Toolchain: []toolCheck{cmakeCheck, conanCheck},
+		Run:       conanCMakeBuild,
+		Check:     conanCMakeBuild,
+	},
+	{
+		Name:      "Meson",
+		Files:     map[string]string{"meson.build": mesonBuild},
+		Toolchain: mesonChecks,
+		Run:       "meson setup build && meson compile -C build",
+		Check:     "meson setup build && meson compile -C build",
+	},
+	{
+		Name: "Meson+Conan",
+		Files: map[string]string{
+			"meson.build":   mesonFmtBuild,
+			"conanfile.txt": conanMeson,
+			"src/main.cpp":  cppFmtMain,
+		},
+		Toolchain: append([]toolCheck{conanCheck}, mesonChecks...),
+		// Die Dateien von Conan liegen unter build/conan, Meson baut in build
+		Run:   conanMesonBuild,
+		Check: conanMesonBuild,
+	},
+	{
+		Name:      "Make",
+		Files:     map[string]string{"Makefile": cppMakefile},
+		Toolchain: []toolCheck{{[]string{"make", "--version"}, "make"}},
+		Run:       "make",
+		Check:     "make",
+	},
+}
+
+var (
+	cmakeCheck  = toolCheck{[]string{"cmake", "--version"}, "cmake"}
+	conanCheck  = toolCheck{[]string{"conan", "--version"}, "conan"}
+	mesonChecks = []toolCheck{{[]string{"meson", "--version"}, "meson"}, {[]string{"ninja", "--version"}, "ninja"}}
+)
+
+// conanCMakeBuild und conanMesonBuild legen bei Bedarf das Standardprofil
+// an, installieren die Abhängigkeiten aus conanfile.txt und bauen.
+const (
+	conanCMakeBuild = "conan profile detect --exist-ok && conan install . --output-folder=build --build=missing && cmake -B build -DCMAKE_TOOLCHAIN_FILE=conan_toolchain.cmake -DCMAKE_BUILD_TYPE=Release && cmake --build build"
+	conanMesonBuild = "conan profile detect --exist-ok && conan install . --output-folder=build/conan --build=missing && meson setup --native-file build/conan/conan_meson_native.ini build && meson compile -C build"
+)
+
+const (
+	cmakeFmtLists = `cmake_minimum_required(VERSION 3.15)
+project({{.ProjectName}} CXX)
+
+set(CMAKE_CXX_STANDARD 17)
+set(CMAKE_CXX_STANDARD_REQUIRED ON)
+
+find_package(fmt CONFIG REQUIRED)
+
+add_executable(${PROJECT_NAME} src/main.cpp)
+target_include_directories(${PROJECT_NAME} PRIVATE include)
+target_link_libraries(${PROJECT_NAME} PRIVATE fmt::fmt)
+`
+
+	cppFmtMain = `#include <fmt/core.h>
+
+int main() {
+    fmt::print("Hello, {}!\n", "C++17");
+    return 0;
+}
+`
+
+	// vcpkgManifest lässt name und version weg, die vcpkg nur für Ports verlangt
+	vcpkgManifest = `{
+  "dependencies": [
+    "fmt"
+  ]
+}
+`
+
+	// vcpkgPresets bindet die Toolchain-Datei aus VCPKG_ROOT ein
+	vcpkgPresets = `{
+  "version": 3,
+  "configurePresets": [
+    {
+      "name": "vcpkg",
+      "binaryDir": "${sourceDir}/build",
+      "toolchainFile": "$env{VCPKG_ROOT}/scripts/buildsystems/vcpkg.cmake",
+      "cacheVariables": {
+        "CMAKE_BUILD_TYPE": "Debug"
+      }
+    }
+  ]
+}
+`
+
+	conanCMake = `[requires]
+fmt/11.0.2
+
+[generators]
+CMakeDeps
+CMakeToolchain
+`
+
+	conanMeson = `[requires]
+fmt/11.0.2
+
+[generators]
+PkgConfigDeps
+MesonToolchain
+`
+
+	mesonBuild = `project('{{.ProjectName}}', 'cpp',
+  version: '0.1.0',
+  default_options: ['cpp_std=c++17', 'warning_level=3'])
+
+executable('{{.ProjectName}}', 'src/main.cpp',
+  include_directories: include_directories('include'))
+`
+
+	mesonFmtBuild = `project('{{.ProjectName}}', 'cpp',
+  version: '0.1.0',
+  default_options: ['cpp_std=c++17', 'warning_level=3'])
+
+fmt_dep = dependency('fmt')
+
+executable('{{.ProjectName}}', 'src/main.cpp',
+  include_directories: include_directories('include'),
+  dependencies: fmt_dep)
+`
+
+	// cppMakefile übersetzt alle Quellen unter src nach build; make verlangt
+	// Tabulatoren vor den Befehlen
+	cppMakefile = `CXXFLAGS ?= -std=c++17 -Wall -Wextra -g
+CPPFLAGS += -Iinclude
+
+BUILD := build
+TARGET := $(BUILD)/{{.ProjectName}}
+SRCS := $(wildcard src/*.cpp)
+OBJS := $(SRCS:src/%.cpp=$(BUILD)/%.o)
+
+all: $(TARGET)
+
+$(TARGET): $(OBJS)
+	$(CXX) $(LDFLAGS) -o $@ $^ $(LDLIBS)
+
+$(BUILD)/%.o: src/%.cpp | $(BUILD)
+	$(CXX) $(CPPFLAGS) $(CXXFLAGS) -MMD -MP -c -o $@ $<
+
+$(BUILD):
+	mkdir -p $@
+
+clean:
+	rm -rf $(BUILD)
+
+-include $(OBJS:.o=.d)
+
+.PHONY: all clean
+`
+)
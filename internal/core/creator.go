@@ -0,0 +1,1026 @@
+// Package core ist der Projektgenerator von newpipi ohne Oberfläche:
+// Projekttypen, Templates, Befehle, die fortsetzbaren Phasen der Erstellung
+// und alle Modi außer der GUI (CLI, TUI, HTTP, D-Bus, stdio, MCP). Das Paket
+// main legt nur die Fyne-Oberfläche darüber.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// Die Erstellungspipeline: ProjectSetup sammelt die Eingaben, CreateProject
+// führt die Phasen aus. Sie kennt keine Oberfläche; GUI (Paket main), CLI, TUI,
+// HTTP, D-Bus, stdio und MCP rufen sie gleichermaßen auf.
+
+// Fehlerklassen, anhand derer der CLI-Modus seine Exit-Codes wählt
+var (
+	errToolchainMissing = errors.New("installation prüfung fehlgeschlagen")
+	errProjectExists    = errors.New("projektverzeichnis existiert bereits")
+	errNetwork          = errors.New("download fehlgeschlagen")
+	errUntrusted        = errors.New("template nicht vertrauenswürdig")
+)
+
+type ProjectType int
+
+const (
+	Python ProjectType = iota
+	Go
+	Rust
+	JavaScript
+	TypeScript
+	CPlusPlus
+	CSharp
+	Java
+	Kotlin
+	Zig
+)
+
+// ProjectTypeNames enthält die Anzeigenamen in der Reihenfolge der ProjectType-Konstanten.
+var ProjectTypeNames = []string{
+	"Python",
+	"Go",
+	"Rust",
+	"JavaScript",
+	"TypeScript",
+	"C++",
+	"C#",
+	"Java",
+	"Kotlin",
+	"Zig",
+}
+
+func (t ProjectType) String() string {
+	if int(t) < 0 || int(t) >= len(ProjectTypeNames) {
+		return fmt.Sprintf("ProjectType(%d)", int(t))
+	}
+	return ProjectTypeNames[t]
+}
+
+// ParseProjectType sucht den Projekttyp zu einem Anzeigenamen (ohne Beachtung der Groß-/Kleinschreibung).
+func ParseProjectType(name string) (ProjectType, bool) {
+	for i, n := range ProjectTypeNames {
+		if strings.EqualFold(n, name) {
+			return ProjectType(i), true
+		}
+	}
+	return 0, false
+}
+
+func (t ProjectType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *ProjectType) UnmarshalText(text []byte) error {
+	pt, ok := ParseProjectType(string(text))
+	if !ok {
+		return fmt.Errorf("unbekannter projekttyp: %s", text)
+	}
+	*t = pt
+	return nil
+}
+
+type ProjectSetup struct {
+	Settings    Settings
+	ProfileName string
+	ParentPath  string
+	ProjectName string
+	ProjectType ProjectType
+	Template    *Template
+	Options     map[string]string
+	// ToolkitName ist das gewählte Toolkit, leer für den Standard (siehe languages.go)
+	ToolkitName string
+	// LayoutName ist das gewählte Layout wie Library, leer für das erste (siehe languages.go)
+	LayoutName string
+	// ModulePath ist der Go-Modulpfad (-module), leer für den Standard (siehe gomodule.go)
+	ModulePath string
+	// PackageManager ist npm, pnpm, yarn oder bun für JavaScript und
+	// TypeScript, vorbelegt aus Settings.PackageManager (siehe packagemanager.go)
+	PackageManager string
+	// runCommand ersetzt für diese Erstellung den Startbefehl (-run)
+	runCommand string
+	// mount sind die Fähigkeiten des Ziel-Dateisystems (siehe mount.go)
+	mount *mountCaps
+	// EnvVars landen in .env und den Dateien, die darauf verweisen (siehe envvars.go)
+	EnvVars map[string]string
+	// SecretValues sind die Werte zu Template.Secrets; mit SecretsInKeyring
+	// landen sie im Schlüsselbund statt in .env. Sie werden nie im
+	// Erstellungsstand gespeichert.
+	SecretValues     map[string]string
+	SecretsInKeyring bool
+	Snippets         []string
+	// Addons sind die Namen der zusätzlich gewählten Add-ons (siehe addons.go).
+	Addons []string
+	// Scratch markiert ein Wegwerfprojekt für `newpipi cleanup` (siehe scratch.go).
+	Scratch     bool
+	Audit       bool
+	auditReport *AuditReport
+	// Licenses sammelt die Lizenzen der Abhängigkeiten (siehe licenses.go)
+	Licenses      bool
+	licenseReport *LicenseReport
+	// SbomFormat ist "cyclonedx" oder "spdx"; leer erzeugt kein SBOM.
+	SbomFormat string
+	// OnAudit zeigt die Audit-Ergebnisse vor dem ersten Commit an und kehrt
+	// zurück, sobald sie bestätigt sind.
+	OnAudit func(r *AuditReport)
+	// OnLicenses zeigt Verstöße gegen die Lizenzrichtlinie vor dem ersten Commit
+	OnLicenses   func(r *LicenseReport)
+	skipTerminal bool
+	// SkipGit legt kein Repository an, auch nicht mit jj oder hg
+	SkipGit bool
+	// skipInstall installiert keine Pakete, skipCI lässt die CI-Dateien von
+	// Templates und Add-ons weg (siehe skipsteps.go)
+	skipInstall bool
+	skipCI      bool
+	// Vcs ist "git", "jj" oder "hg" (siehe vcs.go), vorbelegt aus Settings.VCS
+	Vcs string
+	// GitHooks legt native Git-Hooks an, vorbelegt aus Settings.GitHooks
+	GitHooks bool
+	// ReleaseTask legt einen Release-Task an, vorbelegt aus Settings.ReleaseTask
+	ReleaseTask bool
+	// SignCommits signiert den ersten und alle weiteren Commits, vorbelegt aus Settings.SignCommits
+	SignCommits bool
+	// GitLFS verwaltet große Binärdateien mit Git LFS, vorbelegt aus Settings.GitLFS
+	GitLFS bool
+	// Push trägt Profile.RemoteURL als origin ein und pusht den ersten Commit
+	Push         bool
+	Progress     func(step string)
+	toolVersions map[string]string
+	// Context bricht die Erstellung ab: laufende Befehle werden beendet, keine
+	// weitere Phase beginnt. nil steht für context.Background().
+	Context context.Context
+	// NewCommand erstellt die Befehle der Erstellung anstelle von
+	// exec.CommandContext, etwa für einen Container (siehe scaffold.Runner);
+	// Fixtures gelten dann nicht.
+	NewCommand func(ctx context.Context, name string, args ...string) *exec.Cmd
+	// Preflighted ist gesetzt, wenn die Checkliste vor diesem Lauf schon geprüft wurde
+	Preflighted bool
+	// policyErr ist gesetzt, wenn die Richtlinie nicht geladen werden konnte;
+	// dann wird nichts erstellt
+	policyErr error
+	// stepLog und commandLog sammeln den Ablauf für den Erstellungsbericht
+	stepLog    []stepRecord
+	commandLog []string
+	// fixtureCalls zählt gleiche Befehle für die Fixtures (siehe fixtures.go)
+	fixtureCalls map[string]int
+	// phaseLog misst die Phasen, activePhase ist die laufende (siehe timings.go)
+	phaseLog    []phaseTiming
+	activePhase *phaseTiming
+	// state ist der Stand der laufenden Erstellung für die Wiederaufnahme.
+	state *CreationState
+
+	// pluginTerminalScript ist das vom Plugin gelieferte Terminal-Skript
+	pluginTerminalScript string
+}
+
+type Template struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Type        ProjectType `json:"type"`
+	// Version ist die Fassung des Templates, Changelog beschreibt je Version
+	// die Änderungen (siehe templateversions.go).
+	Version   string            `json:"version,omitempty"`
+	Changelog map[string]string `json:"changelog,omitempty"`
+	Files     map[string]string `json:"files,omitempty"`
+	Packages  []string          `json:"packages,omitempty"`
+	// Modes legt Dateirechte oktal fest ("scripts/setup.sh": "0755"); ohne
+	// Angabe sind Dateien 0644, mit Shebang 0755.
+	Modes map[string]string `json:"modes,omitempty"`
+	// Symlinks legt symbolische Links an: Pfad des Links → relatives Ziel im Projekt.
+	Symlinks map[string]string `json:"symlinks,omitempty"`
+	// LFS sind Muster großer Binärdateien ("*.blend"), die Git LFS verwaltet;
+	// ohne Angabe bleibt LFS aus (siehe lfs.go).
+	LFS []string `json:"lfs,omitempty"`
+	// Assets sind Binärdateien (Icons, Schriften, Bilder): Zielpfad → "base64:DATEN"
+	// oder Pfad im Verzeichnis assets/ neben der Template-Datei. Sie werden nie gerendert.
+	Assets map[string]string `json:"assets,omitempty"`
+	// Extends und Mixins nennen Templates, aus denen dieses zusammengesetzt wird;
+	// Abstract-Templates dienen nur als Baustein und erscheinen nicht in der Auswahl.
+	Extends  string   `json:"extends,omitempty"`
+	Mixins   []string `json:"mixins,omitempty"`
+	Abstract bool     `json:"abstract,omitempty"`
+	// Addon-Templates werden beim Erstellen zu einem Template hinzugewählt
+	// (siehe addons.go) und erscheinen ebenfalls nicht in der Auswahl.
+	Addon       bool               `json:"addon,omitempty"`
+	Options     []TemplateOption   `json:"options,omitempty"`
+	Conditional []ConditionalFiles `json:"conditional,omitempty"`
+	// Secrets sind geheime Variablen des Projekts (siehe envvars.go).
+	Secrets []TemplateSecret `json:"secrets,omitempty"`
+	// Messages übersetzt die englischen Texte des Templates je Sprache für
+	// {{t "..."}}, etwa {"de": {"Hello": "Hallo"}} (siehe contentlang.go).
+	Messages map[string]map[string]string `json:"messages,omitempty"`
+	// Script ist optionaler Starlark-Code mit configure(ctx), siehe templatescript.go.
+	// Mit Skript oder Optionen werden die Dateiinhalte als text/template gerendert.
+	Script string `json:"script,omitempty"`
+	// Generator legt das Projekt mit einem externen Generator an (spring,
+	// quarkus, dotnet oder next) statt mit dem Grundgerüst des Projekttyps;
+	// GeneratorArgs sind dessen Argumente, gerendert mit den Optionen (siehe
+	// generators.go).
+	Generator     string   `json:"generator,omitempty"`
+	GeneratorArgs []string `json:"generatorArgs,omitempty"`
+	// Render rendert Dateiinhalte auch ohne Skript und Optionen; Pfade mit
+	// "{{" werden dann ebenfalls gerendert (siehe cookiecutter.go).
+	Render bool `json:"render,omitempty"`
+	// Commands laufen nach Paketen und vor Hooks im Projekt (siehe commands.go).
+	Commands []TemplateCommand `json:"commands,omitempty"`
+	// Run ersetzt den Startbefehl des Projekttyps im Terminal, etwa "uvicorn app:app --reload".
+	Run string `json:"run,omitempty"`
+	// Check ersetzt den Prüfbefehl des Projekttyps für newpipi selftest.
+	Check string `json:"check,omitempty"`
+	// Hooks sind Shell-Befehle, die nach dem Anlegen im Projekt laufen (siehe trust.go).
+	Hooks []string `json:"hooks,omitempty"`
+	// Source ist die Download-Adresse von Templates aus dem Marktplatz.
+	Source string `json:"source,omitempty"`
+	// SourceSHA256 ist der SHA-256 der heruntergeladenen Fassung, Grundlage der Update-Prüfung.
+	SourceSHA256 string `json:"sourceSha256,omitempty"`
+
+	// checksum ist der SHA-256 der Template-Datei, Grundlage der Vertrauensentscheidung.
+	checksum string
+	// trusted ist für Zusammensetzungen aus bereits geprüften Teilen gesetzt.
+	trusted bool
+	// path ist gesetzt, solange nur die Kopfdaten aus dem Index geladen sind.
+	path string
+	// layer ist die Herkunft (layerUser, layerTeam, ...), leer für eingebaute.
+	layer string
+}
+
+// builtinTemplates sind die mitgelieferten Templates samt denen der externen
+// Generatoren, Templates zusätzlich die des Benutzers (siehe LoadUserTemplates).
+var builtinTemplates = slices.Concat([]Template{
+	{
+		Name:        "CLI App",
+		Description: "Kommandozeilen-Anwendung",
+		Type:        Python,
+		Files: map[string]string{
+			"src/cli.py": `import click
+@click.command()
+def main():
+    click.echo("Hello CLI!")
+
+if __name__ == "__main__":
+    main()`,
+		},
+		Packages: []string{"click"},
+	},
+	// Weitere Templates...
+}, typeScriptTemplates, generatorTemplates)
+
+var Templates = builtinTemplates
+
+// FindTemplate sucht ein Template anhand von Typ und Name und lädt es vollständig.
+func FindTemplate(t ProjectType, name string) *Template {
+	for i := range Templates {
+		if Templates[i].Type == t && strings.EqualFold(Templates[i].Name, name) {
+			if err := Templates[i].ensureLoaded(); err != nil {
+				log.Printf("%v", err)
+				return nil
+			}
+			return &Templates[i]
+		}
+	}
+	return nil
+}
+
+// templateByName sucht ein Template unabhängig vom Typ und lädt es vollständig.
+func templateByName(name string) *Template {
+	for i := range Templates {
+		if strings.EqualFold(Templates[i].Name, name) {
+			return FindTemplate(Templates[i].Type, Templates[i].Name)
+		}
+	}
+	return nil
+}
+
+func NewProjectSetup() *ProjectSetup {
+	ps := &ProjectSetup{}
+	// Ohne gültige Richtlinie wird nichts erstellt, statt sie zu übergehen;
+	// die Frontends melden den Fehler beim Erstellen
+	if _, err := loadPolicy(); err != nil {
+		ps.policyErr = fmt.Errorf("%w: %v", errPolicy, err)
+		log.Printf("Fehler: %v", err)
+	}
+	if err := ps.loadProjectPath(); err != nil {
+		log.Printf("Fehler beim Laden des Projektpfads: %v", err)
+	}
+	ps.GitHooks = ps.Settings.GitHooks
+	ps.ReleaseTask = ps.Settings.ReleaseTask
+	ps.SignCommits = ps.Settings.SignCommits
+	ps.GitLFS = ps.Settings.GitLFS
+	ps.Vcs = ps.Settings.VCS
+	ps.PackageManager = ps.Settings.PackageManager
+	if err := ps.SelectProfile(ps.Settings.Profile); err != nil {
+		log.Printf("Fehler beim Aktivieren des Profils: %v", err)
+	}
+	return ps
+}
+
+func (ps *ProjectSetup) loadProjectPath() error {
+	log.Println("Lade Projektpfad...")
+	settings, err := LoadSettings()
+	ps.Settings = settings
+	if err != nil {
+		return err
+	}
+	if settings.hasPlaintextSecrets() {
+		log.Println("Hinweis: Tokens liegen im Klartext in der Konfiguration, 'newpipi secret migrate' verschiebt sie in den Schlüsselbund")
+	}
+
+	path := settings.ParentPath
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		ps.ParentPath = path
+		log.Printf("Projektpfad geladen: %s", path)
+	}
+	return nil
+}
+
+func (ps *ProjectSetup) SaveProjectPath() error {
+	log.Printf("Speichere Projektpfad: %s", ps.ParentPath)
+	err := UpdateSettingsFile(func(s *Settings) {
+		// Mit aktivem Profil gehört der Pfad zum Profil
+		if profile, ok := s.Profiles[ps.ProfileName]; ok {
+			profile.ParentPath = ps.ParentPath
+			s.Profiles[ps.ProfileName] = profile
+			return
+		}
+		s.ParentPath = ps.ParentPath
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Println("Projektpfad erfolgreich gespeichert")
+	return nil
+}
+
+// step protokolliert einen Arbeitsschritt und meldet ihn an einen registrierten Fortschritts-Callback.
+func (ps *ProjectSetup) step(msg string) {
+	log.Println(msg)
+	ps.stepLog = append(ps.stepLog, stepRecord{message: msg, start: time.Now()})
+	if ps.Progress != nil {
+		ps.Progress(msg)
+	}
+}
+
+func (ps *ProjectSetup) CreateProject() (err error) {
+	log.Println("Starte Projekterstellung...")
+	start := time.Now()
+
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	// ps.state ist nur beim Fortsetzen gesetzt (siehe resume) und gilt für einen Lauf
+	defer func() { ps.state = nil }()
+	if ps.policyErr != nil {
+		return ps.policyErr
+	}
+	if err := ps.context().Err(); err != nil {
+		return err
+	}
+	// Die GUI hat die Checkliste bereits angezeigt (siehe preflight.go)
+	if !ps.Preflighted {
+		checks := ps.Preflight(ps.state != nil)
+		if err := PreflightError(checks); err != nil {
+			return err
+		}
+		for _, c := range checks {
+			if c.Err != nil {
+				log.Printf("Warnung: %v", c.Err)
+			}
+		}
+	}
+	ps.Preflighted = false
+	if ps.Template != nil && ps.Template.NeedsTrust(ps.Settings) {
+		return fmt.Errorf("%w: %s führt Befehle aus (newpipi discover trust %q)", errUntrusted, ps.Template.Name, ps.Template.Name)
+	}
+	// Ab hier zählt der Lauf für die Statistik
+	defer func() { ps.recordUsage(start, err) }()
+
+	if ps.state == nil {
+		ps.state = ps.newCreationState(projectDir)
+	} else if !ps.state.completed("scaffold") {
+		// Ein halb angelegtes Grundgerüst lässt sich nicht fortsetzen, es wird neu erstellt
+		ps.step("Setze unterbrochene Erstellung von vorn fort...")
+		if err := os.RemoveAll(projectDir); err != nil {
+			return err
+		}
+	} else {
+		ps.step(fmt.Sprintf("Setze unterbrochene Erstellung fort (%d Phasen erledigt)...", len(ps.state.Completed)))
+	}
+	if err := ps.state.save(); err != nil {
+		log.Printf("Erstellungsstand speichern fehlgeschlagen: %v", err)
+	}
+	// Add-ons werden vor dem Schreiben zu einem Template zusammengesetzt; der
+	// Erstellungsstand hält Template und Add-ons getrennt fest
+	if len(ps.Addons) > 0 {
+		composed, err := ps.composeAddons()
+		if err != nil {
+			return err
+		}
+		base := ps.Template
+		ps.Template = composed
+		defer func() { ps.Template = base }()
+	}
+
+	// Wenn die Installation-Prüfung erfolgreich war, erstelle das Projekt
+	err = ps.phase("scaffold", func() error {
+		if ps.Template != nil && ps.Template.Generator != "" {
+			return ps.runGenerator()
+		}
+		if lang := ps.Language(); lang != nil {
+			return ps.scaffold(lang)
+		}
+		return ps.createPluginProject()
+	})
+	if err != nil {
+		return err
+	}
+
+	if ps.Template != nil {
+		if err := ps.phase("template", func() error { return ps.applyTemplate(projectDir) }); err != nil {
+			return err
+		}
+	}
+	// Snippets verweigern das Überschreiben und werden daher einzeln festgehalten
+	for _, name := range ps.Snippets {
+		if err := ps.phase("snippet:"+name, func() error { return ps.applySnippet(projectDir, name) }); err != nil {
+			return err
+		}
+	}
+
+	if license := ps.Profile().License; license != "" {
+		err := ps.phase("license", func() error {
+			ps.step("Erstelle LICENSE...")
+			return writeLicense(projectDir, license, ps.Profile().GitName, ps.Settings.Modes())
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(ps.Settings.Policy.ciFiles(ps.ProjectType, ps.ProjectName)) > 0 {
+		if err := ps.phase("policy", func() error { return ps.writeCIFiles(projectDir) }); err != nil {
+			return err
+		}
+	}
+
+	if !ps.Settings.NoBadges {
+		if err := ps.phase("badges", func() error { return ps.writeBadges(projectDir) }); err != nil {
+			return err
+		}
+	}
+
+	if !ps.Settings.NoRunShortcuts {
+		if err := ps.phase("shortcuts", func() error { return ps.writeRunShortcuts(projectDir) }); err != nil {
+			return err
+		}
+	}
+
+	if ps.ReleaseTask && ps.gitRepo() {
+		if err := ps.phase("release", func() error { return ps.writeRelease(projectDir) }); err != nil {
+			return err
+		}
+	}
+
+	if ps.GitHooks && ps.gitRepo() {
+		if err := ps.phase("githooks", func() error { return ps.writeGitHooks(projectDir) }); err != nil {
+			return err
+		}
+	}
+
+	if ps.gitRepo() && (!ps.Settings.NoGitAttributes || ps.lfsTracked() != nil) {
+		if err := ps.phase("gitattributes", func() error { return ps.writeGitAttributes(projectDir) }); err != nil {
+			return err
+		}
+	}
+
+	if len(ps.EnvVars) > 0 {
+		if err := ps.phase("env", func() error { return ps.writeEnv(projectDir) }); err != nil {
+			return err
+		}
+	}
+
+	if ps.Template != nil && len(ps.Template.Secrets) > 0 {
+		if err := ps.phase("secrets", func() error { return ps.writeSecrets(projectDir) }); err != nil {
+			return err
+		}
+	}
+
+	if err := ps.phase("postprocess", ps.runPostProcessors); err != nil {
+		return err
+	}
+
+	if ps.Audit {
+		ps.phase("audit", func() error {
+			report, err := ps.auditDependencies(projectDir)
+			if err != nil {
+				// Ein fehlgeschlagenes Audit soll das Projekt nicht verhindern
+				log.Printf("Audit fehlgeschlagen: %v", err)
+				ps.step("Audit fehlgeschlagen")
+			}
+			ps.auditReport = report
+			if report != nil && len(report.Findings) > 0 && ps.OnAudit != nil {
+				ps.OnAudit(report)
+			}
+			return nil
+		})
+	}
+
+	if ps.Licenses || ps.Settings.Policy.RestrictsLicenses() {
+		ps.phase("licenses", func() error {
+			report, err := ps.collectLicenses(projectDir)
+			if err != nil {
+				// Wie beim Audit soll eine fehlende Übersicht das Projekt nicht verhindern
+				log.Printf("Lizenzübersicht fehlgeschlagen: %v", err)
+				ps.step("Lizenzübersicht fehlgeschlagen")
+			}
+			ps.licenseReport = report
+			if report != nil && len(report.denied()) > 0 && ps.OnLicenses != nil {
+				ps.OnLicenses(report)
+			}
+			return nil
+		})
+	}
+
+	if ps.SbomFormat != "" {
+		if err := ps.phase("sbom", func() error { return ps.writeSBOM(projectDir) }); err != nil {
+			return err
+		}
+	}
+	// Nach dem ersten Commit darf der Bericht das Projekt nicht mehr verändern
+	if err := ps.phase("report", func() error { return ps.writeReport(projectDir, start) }); err != nil {
+		return err
+	}
+
+	if vcs := ps.VersionControl(); !ps.SkipGit && vcs.Name != "git" {
+		err := ps.phase(vcs.Name, func() error {
+			ps.step("Initialisiere " + vcs.Label + "-Repository...")
+			return ps.initVCS(vcs)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if ps.gitRepo() {
+		err := ps.phase("git", func() error {
+			ps.step("Initialisiere Git-Repository...")
+			return ps.initGit()
+		})
+		if err != nil {
+			return err
+		}
+		if ps.Push {
+			if err := ps.phase("push", func() error { return ps.pushInitial(projectDir) }); err != nil {
+				return err
+			}
+		}
+		// Ein Fehler der Forge-API soll das lokale Projekt nicht verhindern
+		if err := ps.phase("forge", func() error {
+			if err := ps.applyForgePolicy(projectDir, false); err != nil {
+				log.Printf("Forge-Richtlinie anwenden fehlgeschlagen: %v", err)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	ps.state.Remove()
+	if err := ps.recordTimings(start, time.Now()); err != nil {
+		log.Printf("Zeitmessung speichern fehlgeschlagen: %v", err)
+	}
+	if err := recordLastCreation(projectDir); err != nil {
+		log.Printf("Letzte Erstellung merken fehlgeschlagen: %v", err)
+	}
+	if err := recordRecent(projectDir); err != nil {
+		log.Printf("Zuletzt erstellte Projekte speichern fehlgeschlagen: %v", err)
+	}
+	if ps.Scratch {
+		if err := recordScratch(projectDir); err != nil {
+			log.Printf("Wegwerfprojekt merken fehlgeschlagen: %v", err)
+		}
+	}
+
+	if ps.skipTerminal {
+		return nil
+	}
+	ps.step("Öffne Terminal...")
+	if err := ps.launchTerminal(projectDir, ps.terminalScript()); err != nil {
+		log.Printf("Terminal öffnen fehlgeschlagen: %v", err)
+	}
+	if ps.Settings.Editor != "" {
+		ps.step("Öffne Editor...")
+		cmd := exec.Command("sh", "-c", ps.Settings.Editor+" "+shellQuote(projectDir))
+		if err := cmd.Start(); err != nil {
+			log.Printf("Editor öffnen fehlgeschlagen: %v", err)
+		}
+	}
+	return nil
+}
+
+// applyTemplate schreibt die Dateien des gewählten Templates und installiert dessen Pakete.
+func (ps *ProjectSetup) applyTemplate(projectDir string) error {
+	if ps.Template.Name == "" {
+		ps.step("Wende Add-ons " + strings.Join(ps.Addons, ", ") + " an...")
+	} else {
+		ps.step(fmt.Sprintf("Wende Template %q an...", ps.Template.Name))
+	}
+	script, err := ps.writeTemplateFiles(projectDir)
+	if err != nil {
+		return err
+	}
+	if ps.skipInstall {
+		if len(script.packages) > 0 {
+			log.Printf("Installation übersprungen: %s", strings.Join(script.packages, " "))
+		}
+	} else if err := ps.installPackages(projectDir, script.packages); err != nil {
+		return err
+	}
+	commands := ps.Template.Commands
+	if ps.skipInstall {
+		commands = withoutInstall(commands)
+	}
+	// Befehle aus dem Marktplatz laufen wie Hooks abgeschottet
+	if err := ps.runCommands(projectDir, ps.npmCommands(commands), ps.Template.Source != ""); err != nil {
+		return err
+	}
+	return ps.runHooks(projectDir)
+}
+
+// writeTemplateFiles schreibt Dateien, Assets und Symlinks des Templates nach
+// projectDir, ohne Pakete, Befehle oder Hooks; die Vorschau (siehe watch.go)
+// braucht nur diesen Teil.
+func (ps *ProjectSetup) writeTemplateFiles(projectDir string) (*scriptResult, error) {
+	options, err := ps.Template.ResolveOptions(ps.Options)
+	if err != nil {
+		return nil, err
+	}
+	ps.Options = options
+	files, packages := ps.Template.expand(options)
+	script, err := ps.Template.runScript(ps, packages)
+	if err != nil {
+		return nil, err
+	}
+	modes := ps.Settings.Modes()
+	for path, content := range files {
+		if script.excluded(path) {
+			continue
+		}
+		if ps.Template.Script != "" || len(ps.Template.Options) > 0 || len(ps.Template.Messages) > 0 || ps.Template.Render {
+			if content, err = script.render(path, content); err != nil {
+				return nil, err
+			}
+		}
+		dest := path
+		if ps.Template.Render && strings.Contains(path, "{{") {
+			if dest, err = script.render(path, path); err != nil {
+				return nil, err
+			}
+		}
+		// Auch lokale Templates und Mixins dürfen nicht aus dem Projekt schreiben
+		if !filepath.IsLocal(filepath.FromSlash(dest)) {
+			return nil, fmt.Errorf("template %s: datei %q liegt außerhalb des projekts", ps.Template.Name, dest)
+		}
+		if ps.skipCI && isCIFile(dest) {
+			log.Printf("CI-Datei %s übersprungen", dest)
+			continue
+		}
+		mode, err := ps.Template.fileMode(path, content, modes)
+		if err != nil {
+			return nil, err
+		}
+		target := filepath.Join(projectDir, dest)
+		if err := modes.mkdirAll(filepath.Dir(target)); err != nil {
+			return nil, fmt.Errorf("verzeichnis für %s erstellen fehlgeschlagen: %v", path, err)
+		}
+		if err := os.WriteFile(target, []byte(content), mode); err != nil {
+			return nil, fmt.Errorf("datei %s erstellen fehlgeschlagen: %v", path, err)
+		}
+		// WriteFile setzt die Rechte nur beim Anlegen; die umask gilt auch hier
+		if err := os.Chmod(target, mode&^umask()); err != nil {
+			return nil, fmt.Errorf("rechte von %s setzen fehlgeschlagen: %v", path, err)
+		}
+	}
+	if err := ps.Template.writeAssets(projectDir, script.excluded, modes); err != nil {
+		return nil, err
+	}
+	if err := ps.Template.writeSymlinks(projectDir, script.excluded, modes); err != nil {
+		return nil, err
+	}
+	return script, nil
+}
+
+// installPackages installiert die Pakete des Templates mit dem Paketmanager des Projekttyps.
+func (ps *ProjectSetup) installPackages(projectDir string, packages []string) error {
+	if len(packages) == 0 {
+		return nil
+	}
+	lang := ps.withPackageManager(ps.Language())
+	if lang == nil || len(lang.Install) == 0 {
+		log.Printf("Paketinstallation für %s nicht unterstützt, überspringe: %v", ps.ProjectType, packages)
+		return nil
+	}
+	install := make([]string, len(lang.Install))
+	for i, arg := range lang.Install {
+		install[i] = ps.expandLocal(arg, false)
+	}
+	if lang.InstallEach {
+		for _, pkg := range packages {
+			cmd := ps.command(install[0], slices.Concat(install[1:], []string{pkg})...)
+			cmd.Dir = projectDir
+			started := time.Now()
+			err := cmd.Run()
+			ps.trackCommand(cmd, started, true)
+			if err != nil {
+				return fmt.Errorf("%w: paket %s installieren fehlgeschlagen: %v", errNetwork, pkg, err)
+			}
+		}
+		return nil
+	}
+	args := slices.Concat(install, packages)
+
+	ps.step("Installiere Template-Pakete...")
+	cmd := ps.command(args[0], args[1:]...)
+	cmd.Dir = projectDir
+	started := time.Now()
+	err := cmd.Run()
+	ps.trackCommand(cmd, started, true)
+	if err != nil {
+		return fmt.Errorf("%w: template-pakete installieren fehlgeschlagen: %v", errNetwork, err)
+	}
+	return nil
+}
+
+// terminalScript liefert das Bash-Skript, das im Terminal des neuen Projekts ausgeführt wird.
+func (ps *ProjectSetup) terminalScript() string {
+	run := ps.StartCommand()
+	lang := ps.Language()
+	if lang != nil {
+		tk, _ := ps.Toolkit()
+		lang = ps.withPackageManager(lang.with(tk))
+	}
+	switch {
+	case run != "" && lang != nil:
+		script := runScript(run)
+		if lang.Hint {
+			script = hintScript(run)
+		}
+		if lang.Activate != "" {
+			script = ps.expandLocal(lang.Activate, true) + " && " + script
+		}
+		return script
+	case run != "":
+		return runScript(run)
+	case lang != nil && lang.Terminal != "":
+		return lang.Terminal
+	case ps.pluginTerminalScript != "":
+		return ps.pluginTerminalScript
+	}
+	return "bash"
+}
+
+// StartCommand liefert den Startbefehl für das Terminal: -run, die
+// Einstellung für das Template bzw. den Projekttyp, sonst den Standard.
+func (ps *ProjectSetup) StartCommand() string {
+	if ps.runCommand != "" {
+		return ps.runCommand
+	}
+	if command, ok := ps.Settings.RunCommands[ps.runCommandKey()]; ok {
+		return command
+	}
+	return ps.defaultStartCommand()
+}
+
+// defaultStartCommand ist der Startbefehl ohne Einstellung unter
+// runCommandKey: der des Templates, die Einstellung für den Projekttyp oder
+// der des Toolkits bzw. Projekttyps.
+func (ps *ProjectSetup) defaultStartCommand() string {
+	if ps.Template != nil {
+		// Ohne Vertrauen bleibt es beim Standard des Projekttyps
+		if ps.Template.Run != "" && !ps.Template.NeedsTrust(ps.Settings) {
+			return ps.npmShell(ps.Template.Run)
+		}
+		if command, ok := ps.Settings.RunCommands[ps.ProjectType.String()]; ok {
+			return command
+		}
+	}
+	if lang := ps.Language(); lang != nil {
+		tk, _ := ps.Toolkit()
+		return ps.withPackageManager(lang.with(tk)).Run
+	}
+	return ""
+}
+
+// runCommandKey ist der Schlüssel in Settings.RunCommands: der Template-Name, sonst der Projekttyp.
+func (ps *ProjectSetup) runCommandKey() string {
+	if ps.Template != nil && ps.Template.Name != "" {
+		return ps.Template.Name
+	}
+	return ps.ProjectType.String()
+}
+
+// RememberRunCommand speichert einen in der GUI geänderten Startbefehl unter
+// runCommandKey; ein leerer oder der Standardbefehl entfernt den Eintrag.
+func (ps *ProjectSetup) RememberRunCommand(command string) error {
+	key := ps.runCommandKey()
+	if command == ps.defaultStartCommand() {
+		command = ""
+	}
+	update := func(s *Settings) {
+		if command == "" {
+			delete(s.RunCommands, key)
+			return
+		}
+		if s.RunCommands == nil {
+			s.RunCommands = make(map[string]string)
+		}
+		s.RunCommands[key] = command
+	}
+	update(&ps.Settings)
+	return UpdateSettingsFile(update)
+}
+
+// hintScript zeigt einen Befehl nur an, etwa für GUI-Programme, die das Terminal blockieren würden.
+func hintScript(command string) string {
+	return fmt.Sprintf(`echo %s && exec bash`, shellQuote(command))
+}
+
+// runScript zeigt einen Befehl an, führt ihn aus und hält das Terminal danach offen.
+func runScript(command string) string {
+	return fmt.Sprintf(`echo %s; %s; exec bash`, shellQuote("Running: "+command), command)
+}
+
+// terminalPresets enthält Befehlsvorlagen für bekannte Terminals.
+var terminalPresets = map[string]string{
+	"wezterm":        "wezterm start --cwd {dir} --always-new-process -- bash -c {script}",
+	"kitty":          "kitty --directory {dir} bash -c {script}",
+	"alacritty":      "alacritty --working-directory {dir} -e bash -c {script}",
+	"gnome-terminal": "gnome-terminal --working-directory={dir} -- bash -c {script}",
+	"konsole":        "konsole --workdir {dir} -e bash -c {script}",
+	"xterm":          "xterm -e bash -c {script}",
+}
+
+// terminalCommand setzt Verzeichnis und Skript in die Vorlage des konfigurierten Terminals ein.
+func (ps *ProjectSetup) terminalCommand(dir, script string) string {
+	tmpl := ps.Settings.Terminal
+	if tmpl == "" {
+		tmpl = "wezterm"
+	}
+	if preset, ok := terminalPresets[tmpl]; ok {
+		tmpl = preset
+	}
+	// Eigene Vorlagen setzen die Platzhalter oft selbst in Anführungszeichen;
+	// zusätzlich zur Maskierung würde das Pfade mit Leerzeichen zerlegen
+	tmpl = strings.NewReplacer(`'{dir}'`, "{dir}", `"{dir}"`, "{dir}", `'{script}'`, "{script}", `"{script}"`, "{script}").Replace(tmpl)
+	return strings.NewReplacer("{dir}", shellQuote(dir), "{script}", shellQuote(script)).Replace(tmpl)
+}
+
+// shellQuote setzt s in einfache Anführungszeichen für sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// launchTerminal öffnet das konfigurierte Terminal im Projektverzeichnis und führt dort das Bash-Skript aus
+func (ps *ProjectSetup) launchTerminal(dir string, script string) error {
+	// Füge eine kleine Verzögerung hinzu
+	time.Sleep(100 * time.Millisecond)
+
+	cmd := exec.Command("sh", "-c", ps.terminalCommand(dir, script))
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("terminal öffnen fehlgeschlagen: %v", err)
+	}
+
+	// Warte kurz nach dem Öffnen
+	time.Sleep(100 * time.Millisecond)
+	return nil
+}
+
+func IsValidProjectName(name string) (bool, string) {
+	if name == "" {
+		return false, "Projektname darf nicht leer sein"
+	}
+	if len(name) > 255 {
+		return false, "Projektname ist zu lang (max 255 Zeichen)"
+	}
+	if strings.ContainsAny(name, "/\\:*?\"<>|$%&# \t\n") {
+		return false, "Projektname darf nur Buchstaben, Zahlen, Unterstriche und Bindestriche enthalten"
+	}
+	return true, ""
+}
+
+// Hilfsfunktionen für Installationsprüfungen
+
+// recordToolVersion führt den Versionsbefehl eines Werkzeugs aus und merkt sich die erste Ausgabezeile.
+func (ps *ProjectSetup) recordToolVersion(name string, args ...string) error {
+	out, err := ps.execCommand(ps.context(), name, args...).CombinedOutput()
+	if err != nil {
+		return err
+	}
+	if ps.toolVersions == nil {
+		ps.toolVersions = make(map[string]string)
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	ps.toolVersions[name] = version
+	return nil
+}
+func (ps *ProjectSetup) showProjectPreview() string {
+	return fmt.Sprintf(
+		"Projektübersicht:\n"+
+			"- Name: %s\n"+
+			"- Typ: %s\n"+
+			"- Pfad: %s\n"+
+			"- Geschätzte Größe: ~%dMB",
+		ps.ProjectName,
+		ps.ProjectType,
+		filepath.Join(ps.ParentPath, ps.ProjectName),
+		ps.estimateProjectSize(),
+	)
+}
+
+func (ps *ProjectSetup) estimateProjectSize() int {
+	if lang := ps.Language(); lang != nil {
+		return lang.SizeMB
+	}
+	return 0
+}
+
+// checkDiskSpace vergleicht den freien Platz mit der geschätzten Projektgröße
+// und liefert den freien Platz zur Anzeige.
+func (ps *ProjectSetup) checkDiskSpace() (string, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(existingAncestor(ps.ParentPath), &stat); err != nil {
+		return "", fmt.Errorf("konnte speicherplatz nicht prüfen: %v", err)
+	}
+
+	// Verfügbarer Speicher in MB
+	available := (stat.Bavail * uint64(stat.Bsize)) / 1024 / 1024
+	needed := uint64(ps.estimateProjectSize())
+
+	if available < needed {
+		return "", fmt.Errorf("nicht genug speicherplatz. benötigt: %dMB, verfügbar: %dMB", needed, available)
+	}
+	return fmt.Sprintf("%d MB frei", available), nil
+}
+
+func (ps *ProjectSetup) initGit() error {
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	cmd := ps.command("git", "init")
+	cmd.Dir = projectDir
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git-initialisierung fehlgeschlagen: %v", err)
+	}
+
+	// Git-Identität des Profils für dieses Repository setzen
+	var commands [][]string
+	if name := ps.Profile().GitName; name != "" {
+		commands = append(commands, []string{"git", "config", "user.name", name})
+	}
+	if email := ps.Profile().GitEmail; email != "" {
+		commands = append(commands, []string{"git", "config", "user.email", email})
+	}
+
+	if ps.SignCommits {
+		signing, err := ps.signingConfig()
+		if err != nil {
+			return err
+		}
+		commands = append(commands, signing...)
+	}
+	commands = append(commands, ps.lfsConfig()...)
+
+	// Erstelle initial commit; nach einem Abbruch dahinter existiert er schon
+	head := ps.command("git", "rev-parse", "--verify", "--quiet", "HEAD")
+	head.Dir = projectDir
+	if head.Run() != nil {
+		ps.warnLargeFiles(projectDir)
+		commands = append(commands, [][]string{
+			{"git", "add", "."},
+			{"git", "commit", "-m", "Initial commit"},
+		}...)
+	}
+
+	// Die Hooks gelten erst nach dem ersten Commit, den sonst etwa ein
+	// Muster für Conventional Commits ablehnen würde
+	if ps.GitHooks {
+		commands = append(commands, []string{"git", "config", "core.hooksPath", gitHooksDir})
+	}
+
+	for _, args := range commands {
+		cmd := ps.command(args[0], args[1:]...)
+		cmd.Dir = projectDir
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("git-befehl fehlgeschlagen: %v", err)
+		}
+	}
+	return nil
+}
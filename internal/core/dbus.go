@@ -0,0 +1,160 @@
+package core
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+// D-Bus-Dienst für Linux-Desktops: Skripte und andere Programme erstellen
+// Projekte über den Session-Bus, ohne die GUI zu starten. CreateProject
+// kehrt sofort mit einer Auftragsnummer zurück, da eine Erstellung länger
+// dauern kann als das übliche Timeout eines Methodenaufrufs; Fortschritt und
+// Ergebnis folgen als Signale mit dieser Nummer.
+
+const (
+	dbusName      = "org.newpipi.Creator"
+	dbusInterface = "org.newpipi.Creator"
+	dbusPath      = dbus.ObjectPath("/org/newpipi/Creator")
+)
+
+const dbusIntrospection = `
+<interface name="` + dbusInterface + `">
+	<method name="CreateProject">
+		<arg name="type" direction="in" type="s"/>
+		<arg name="name" direction="in" type="s"/>
+		<arg name="parentPath" direction="in" type="s"/>
+		<arg name="template" direction="in" type="s"/>
+		<arg name="options" direction="in" type="a{ss}"/>
+		<arg name="job" direction="out" type="u"/>
+	</method>
+	<method name="ListTemplates">
+		<arg name="templates" direction="out" type="a(sss)"/>
+	</method>
+	<signal name="Progress">
+		<arg name="job" type="u"/>
+		<arg name="message" type="s"/>
+		<arg name="fraction" type="d"/>
+	</signal>
+	<signal name="Finished">
+		<arg name="job" type="u"/>
+		<arg name="path" type="s"/>
+	</signal>
+	<signal name="Failed">
+		<arg name="job" type="u"/>
+		<arg name="error" type="s"/>
+	</signal>
+</interface>`
+
+// dbusService implementiert org.newpipi.Creator.
+type dbusService struct {
+	conn *dbus.Conn
+
+	// createMu serialisiert Erstellungen wie im Daemon-Modus.
+	createMu sync.Mutex
+
+	jobMu   sync.Mutex
+	nextJob uint32
+}
+
+// dbusTemplate ist ein Eintrag von ListTemplates: Name, Typ, Beschreibung.
+type dbusTemplate struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+func (d *dbusService) ListTemplates() ([]dbusTemplate, *dbus.Error) {
+	var out []dbusTemplate
+	for _, t := range allTemplates() {
+		if !t.Abstract {
+			out = append(out, dbusTemplate{t.Name, t.Type.String(), t.Description})
+		}
+	}
+	return out, nil
+}
+
+// CreateProject prüft die Eingaben sofort und erstellt das Projekt im Hintergrund.
+func (d *dbusService) CreateProject(typeName, name, parentPath, template string, options map[string]string) (uint32, *dbus.Error) {
+	pt, ok := ParseProjectType(typeName)
+	if !ok {
+		return 0, dbus.MakeFailedError(fmt.Errorf("unbekannter projekttyp: %q", typeName))
+	}
+	ps, _, err := CreateRequest{Type: pt, Name: name, ParentPath: parentPath, Template: template, Options: options}.Prepare()
+	if err != nil {
+		return 0, dbus.MakeFailedError(err)
+	}
+
+	d.jobMu.Lock()
+	d.nextJob++
+	job := d.nextJob
+	d.jobMu.Unlock()
+
+	estimate := ps.LoadEstimate()
+	ps.Progress = func(step string) {
+		fraction, _, _ := estimate.At(step, 0)
+		d.emit("Progress", job, step, fraction)
+	}
+	go func() {
+		d.createMu.Lock()
+		err := ps.CreateProject()
+		d.createMu.Unlock()
+		if err != nil {
+			log.Printf("Fehler bei Projekterstellung über D-Bus: %v", err)
+			d.emit("Failed", job, err.Error())
+			return
+		}
+		d.emit("Finished", job, filepath.Join(ps.ParentPath, ps.ProjectName))
+	}()
+	return job, nil
+}
+
+func (d *dbusService) emit(signal string, args ...any) {
+	if err := d.conn.Emit(dbusPath, dbusInterface+"."+signal, args...); err != nil {
+		log.Printf("D-Bus-Signal %s senden fehlgeschlagen: %v", signal, err)
+	}
+}
+
+// setupDBus definiert `newpipi dbus`.
+func setupDBus(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		if err := runDBus(); err != nil {
+			log.Printf("D-Bus-Dienst fehlgeschlagen: %v", err)
+			return exitFailure
+		}
+		return ExitOK
+	}
+}
+
+func runDBus() error {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("session-bus nicht erreichbar: %v", err)
+	}
+	defer conn.Close()
+
+	d := &dbusService{conn: conn}
+	if err := conn.Export(d, dbusPath, dbusInterface); err != nil {
+		return err
+	}
+	// Die Schnittstelle ist von Hand beschrieben, damit die Argumentnamen erhalten bleiben
+	xml := introspect.IntrospectDeclarationString + "<node>" + introspect.IntrospectDataString + dbusIntrospection + "</node>"
+	if err := conn.Export(introspect.Introspectable(xml), dbusPath, "org.freedesktop.DBus.Introspectable"); err != nil {
+		return err
+	}
+
+	reply, err := conn.RequestName(dbusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return fmt.Errorf("name %s anfordern fehlgeschlagen: %v", dbusName, err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return fmt.Errorf("%s läuft bereits", dbusName)
+	}
+	log.Printf("D-Bus-Dienst %s bereit", dbusName)
+	select {}
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Zeilenbasierter Diff für Dateien, die ein Generator überschreiben würde.
+// Die Eingaben sind Konfigurations- und Quelldateien aus Templates und
+// Snippets, daher genügt die einfache LCS-Tabelle; sehr große Dateien werden
+// als vollständiger Austausch dargestellt.
+
+// diffContext ist die Anzahl unveränderter Zeilen um eine Änderung.
+const diffContext = 3
+
+// diffMaxCells begrenzt die LCS-Tabelle (Zeilen alt × Zeilen neu).
+const diffMaxCells = 4_000_000
+
+// diffOp ist eine Zeile des Diffs: ' ' gleich, '-' entfernt, '+' hinzugefügt.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines berechnet die Bearbeitungsschritte von a nach b.
+func diffLines(a, b []string) []diffOp {
+	if len(a)*len(b) > diffMaxCells {
+		ops := make([]diffOp, 0, len(a)+len(b))
+		for _, l := range a {
+			ops = append(ops, diffOp{'-', l})
+		}
+		for _, l := range b {
+			ops = append(ops, diffOp{'+', l})
+		}
+		return ops
+	}
+	// lcs[i][j] ist die Länge der längsten gemeinsamen Teilfolge von a[i:] und b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// unifiedDiff liefert den Unterschied im Format von `diff -u`, leer bei
+// gleichem Inhalt.
+func unifiedDiff(path, old, new string) string {
+	if old == new {
+		return ""
+	}
+	ops := diffLines(splitLines(old), splitLines(new))
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+
+	// Hunks aus Änderungen samt Kontext bilden; liegen zwischen zwei
+	// Änderungen höchstens 2*diffContext gleiche Zeilen, bilden sie einen Hunk
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].kind == ' ' {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+		from := max(0, i-diffContext)
+		last := i
+		for j := i + 1; j < len(ops) && j-last-1 <= 2*diffContext; j++ {
+			if ops[j].kind != ' ' {
+				last = j
+			}
+		}
+		to := min(len(ops), last+1+diffContext)
+
+		oldStart, newStart := 1, 1
+		for _, op := range ops[:from] {
+			if op.kind != '+' {
+				oldStart++
+			}
+			if op.kind != '-' {
+				newStart++
+			}
+		}
+		oldLen, newLen := 0, 0
+		for _, op := range ops[from:to] {
+			if op.kind != '+' {
+				oldLen++
+			}
+			if op.kind != '-' {
+				newLen++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLen), hunkRange(newStart, newLen))
+		for _, op := range ops[from:to] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = to
+	}
+	return b.String()
+}
+
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	if length == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
+
+// FilePatch ist eine geplante Dateiänderung eines Generators. Accepted
+// entscheidet, ob eine bestehende Datei überschrieben wird; neue Dateien
+// werden immer geschrieben.
+type FilePatch struct {
+	Path     string
+	Old      string
+	New      string
+	Exists   bool
+	Accepted bool
+}
+
+// Conflict meldet, ob die Änderung eine bestehende Datei mit anderem Inhalt ersetzt.
+func (p FilePatch) Conflict() bool {
+	return p.Exists && p.Old != p.New
+}
+
+func (p FilePatch) Diff() string {
+	return unifiedDiff(p.Path, p.Old, p.New)
+}
+
+// planFiles vergleicht die gerenderten Dateien mit dem Stand in dir. Pfade
+// außerhalb von dir, etwa aus einem fremden Snippet, sind ein Fehler.
+func planFiles(dir string, files map[string]string) ([]FilePatch, error) {
+	var patches []FilePatch
+	for path, content := range files {
+		if !filepath.IsLocal(filepath.FromSlash(path)) {
+			return nil, fmt.Errorf("datei %q liegt außerhalb des projekts", path)
+		}
+		p := FilePatch{Path: path, New: content}
+		old, err := os.ReadFile(filepath.Join(dir, path))
+		switch {
+		case err == nil:
+			p.Old, p.Exists = string(old), true
+		case !os.IsNotExist(err):
+			return nil, fmt.Errorf("%s lesen fehlgeschlagen: %v", path, err)
+		}
+		patches = append(patches, p)
+	}
+	sort.Slice(patches, func(i, j int) bool { return patches[i].Path < patches[j].Path })
+	return patches, nil
+}
+
+// WritePatches schreibt neue Dateien und angenommene Änderungen. Abgelehnte
+// und unveränderte Dateien bleiben unberührt.
+func WritePatches(dir string, patches []FilePatch, modes projectModes) error {
+	for _, p := range patches {
+		if p.Exists && (!p.Accepted || p.Old == p.New) {
+			continue
+		}
+		if err := modes.writeFile(filepath.Join(dir, p.Path), []byte(p.New), modes.File); err != nil {
+			return fmt.Errorf("datei %s schreiben fehlgeschlagen: %v", p.Path, err)
+		}
+	}
+	return nil
+}
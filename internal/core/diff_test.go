@@ -0,0 +1,46 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlanFilesPaths(t *testing.T) {
+	tests := []struct {
+		path string
+		ok   bool
+	}{
+		{"main.go", true},
+		{"cmd/app/main.go", true},
+		{"./docs/index.md", true},
+		{"../outside.txt", false},
+		{"cmd/../../outside.txt", false},
+		{"/etc/passwd", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		patches, err := planFiles(dir, map[string]string{tt.path: "x"})
+		if (err == nil) != tt.ok {
+			t.Errorf("planFiles(%q): fehler %v, erwartet ok=%v", tt.path, err, tt.ok)
+			continue
+		}
+		if !tt.ok {
+			continue
+		}
+		if err := WritePatches(dir, patches, defaultModes); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, tt.path)); err != nil {
+			t.Errorf("%s nicht geschrieben: %v", tt.path, err)
+		}
+	}
+}
+
+func TestPlanSnippetRejectsNonLocal(t *testing.T) {
+	s := &Snippet{Name: "evil", Files: map[string]string{"../../.bashrc": "x"}}
+	if _, err := PlanSnippet(t.TempDir(), s); err == nil {
+		t.Error("snippet mit pfad außerhalb des projekts angenommen")
+	}
+}
@@ -0,0 +1,229 @@
+package core
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Speicherbelegung der verwalteten Projekte, also der zuletzt erstellten und
+// der Wegwerfprojekte: je Projekt Quelltext (samt Versionskontrolle) und
+// Build-Artefakte bzw. Abhängigkeiten je Ökosystem. Artefakte lassen sich je
+// Ökosystem löschen; sie entstehen beim nächsten Build oder Install neu.
+
+// artifactKind fasst die Artefaktverzeichnisse eines Ökosystems zusammen;
+// gezählt werden sie nur auf oberster Ebene des Projekts.
+type artifactKind struct {
+	Name string
+	Dirs []string
+	// Restore sagt, wie sich die Verzeichnisse wiederherstellen lassen.
+	Restore string
+}
+
+var ArtifactKinds = []artifactKind{
+	{Name: "node", Dirs: []string{"node_modules"}, Restore: "npm install"},
+	{Name: "rust", Dirs: []string{"target"}, Restore: "cargo build"},
+	{Name: "python", Dirs: []string{"venv", ".venv"}, Restore: "python -m venv venv && venv/bin/pip install -r requirements.txt"},
+	{Name: "build", Dirs: []string{"build", "dist", "bin", "obj"}, Restore: "erneut bauen"},
+}
+
+func findArtifactKind(name string) (artifactKind, bool) {
+	i := slices.IndexFunc(ArtifactKinds, func(k artifactKind) bool { return strings.EqualFold(k.Name, name) })
+	if i < 0 {
+		return artifactKind{}, false
+	}
+	return ArtifactKinds[i], true
+}
+
+// diskUsage ist die Belegung eines Projekts in Bytes.
+type diskUsage struct {
+	Dir    string
+	Source int64
+	// Artifacts ordnet den Namen aus ArtifactKinds die Größe zu; Ökosysteme
+	// ohne Verzeichnisse im Projekt fehlen.
+	Artifacts map[string]int64
+}
+
+func (u diskUsage) Dependencies() int64 {
+	var n int64
+	for _, size := range u.Artifacts {
+		n += size
+	}
+	return n
+}
+
+func (u diskUsage) Total() int64 {
+	return u.Source + u.Dependencies()
+}
+
+// MeasureProject ermittelt die Belegung eines Projektverzeichnisses.
+func MeasureProject(dir string) (diskUsage, error) {
+	u := diskUsage{Dir: dir, Artifacts: make(map[string]int64)}
+	kinds := make(map[string]string)
+	for _, k := range ArtifactKinds {
+		for _, d := range k.Dirs {
+			kinds[d] = k.Name
+		}
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && filepath.Dir(path) == dir {
+			if kind, ok := kinds[d.Name()]; ok {
+				size, err := dirSize(path)
+				if err != nil {
+					return err
+				}
+				u.Artifacts[kind] += size
+				return filepath.SkipDir
+			}
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			u.Source += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return u, fmt.Errorf("%s vermessen fehlgeschlagen: %v", dir, err)
+	}
+	return u, nil
+}
+
+// dirSize summiert die Dateien unter path; Symlinks werden nicht verfolgt.
+func dirSize(path string) (int64, error) {
+	var n int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			n += info.Size()
+		}
+		return nil
+	})
+	return n, err
+}
+
+// CleanArtifacts löscht die Artefaktverzeichnisse eines Ökosystems im Projekt
+// und liefert die freigegebenen Bytes.
+func CleanArtifacts(dir string, kind artifactKind) (int64, error) {
+	var freed int64
+	for _, name := range kind.Dirs {
+		path := filepath.Join(dir, name)
+		info, err := os.Lstat(path)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		size, err := dirSize(path)
+		if err != nil {
+			return freed, err
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return freed, fmt.Errorf("%s löschen fehlgeschlagen: %v", path, err)
+		}
+		freed += size
+	}
+	return freed, nil
+}
+
+// ManagedProjects liefert die zuletzt erstellten und die Wegwerfprojekte.
+func ManagedProjects() []string {
+	dirs := RecentProjects()
+	for _, p := range ScratchProjects() {
+		if !slices.Contains(dirs, p.Dir) {
+			dirs = append(dirs, p.Dir)
+		}
+	}
+	return dirs
+}
+
+// FormatSize gibt eine Größe in B, KB, MB oder GB an.
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, s := range []string{"MB", "GB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, s
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// artifactSummary beschreibt die Artefakte eines Projekts, etwa "node 120.0 MB, build 2.1 MB".
+func (u diskUsage) artifactSummary() string {
+	var parts []string
+	for _, k := range ArtifactKinds {
+		if size, ok := u.Artifacts[k.Name]; ok {
+			parts = append(parts, k.Name+" "+FormatSize(size))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// setupDisk definiert `newpipi disk [-clean ÖKOSYSTEM] [VERZEICHNIS...]`.
+func setupDisk(fs *flag.FlagSet) func(args []string) int {
+	clean := fs.String("clean", "", "Artefakte dieses Ökosystems löschen: node, rust, python oder build")
+	return func(args []string) int {
+		kind, ok := findArtifactKind(*clean)
+		if *clean != "" && !ok {
+			fmt.Fprintf(os.Stderr, "Fehler: unbekanntes ökosystem: %s\n", *clean)
+			return exitUsage
+		}
+		dirs := args
+		if len(dirs) == 0 {
+			dirs = ManagedProjects()
+		}
+		if len(dirs) == 0 {
+			fmt.Println("Keine verwalteten Projekte")
+			return ExitOK
+		}
+		if ok {
+			code := ExitOK
+			for _, dir := range dirs {
+				freed, err := CleanArtifacts(dir, kind)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+					code = exitFailure
+				}
+				if freed > 0 {
+					fmt.Printf("%s: %s freigegeben\n", dir, FormatSize(freed))
+				}
+			}
+			fmt.Printf("Wiederherstellen mit: %s\n", kind.Restore)
+			return code
+		}
+		format := "%-40s %10s %10s %10s  %s\n"
+		fmt.Printf(format, "Project", "Source", "Deps", "Total", "Artifacts")
+		var sum int64
+		code := ExitOK
+		for _, dir := range dirs {
+			u, err := MeasureProject(dir)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				code = exitFailure
+				continue
+			}
+			sum += u.Total()
+			fmt.Printf(format, dir, FormatSize(u.Source), FormatSize(u.Dependencies()), FormatSize(u.Total()), u.artifactSummary())
+		}
+		fmt.Printf("Insgesamt: %s\n", FormatSize(sum))
+		return code
+	}
+}
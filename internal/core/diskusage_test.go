@@ -0,0 +1,82 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMeasureProject(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]int{
+		"main.go":                 10,
+		".git/HEAD":               5,
+		"src/build/keep.txt":      7,
+		"node_modules/x/index.js": 100,
+		"target/debug/app":        200,
+		"venv/bin/python":         30,
+		".venv/lib/a.py":          3,
+		"dist/app.js":             40,
+	}
+	for path, size := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u, err := MeasureProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  int64
+		want int64
+	}{
+		{"source", u.Source, 22},
+		{"node", u.Artifacts["node"], 100},
+		{"rust", u.Artifacts["rust"], 200},
+		{"python", u.Artifacts["python"], 33},
+		{"build", u.Artifacts["build"], 40},
+		{"total", u.Total(), 395},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, erwartet %d", tt.name, tt.got, tt.want)
+		}
+	}
+
+	python, _ := findArtifactKind("Python")
+	freed, err := CleanArtifacts(dir, python)
+	if err != nil || freed != 33 {
+		t.Errorf("cleanArtifacts(python) = %d, %v, erwartet 33", freed, err)
+	}
+	for _, path := range []string{"venv", ".venv"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); !os.IsNotExist(err) {
+			t.Errorf("%s nach cleanArtifacts noch vorhanden", path)
+		}
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{5 << 20, "5.0 MB"},
+		{3 << 30, "3.0 GB"},
+		{2048 << 30, "2048.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.n); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, erwartet %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+// .NET-Vorlagen: Die Toolkits von C# wählen die Vorlage von `dotnet new`
+// (Konsole, Bibliothek, Web-API, Worker, Avalonia, xUnit), das Layout
+// Solution legt das Projekt unter src/ in eine Projektmappe samt
+// Testprojekt unter tests/. Alles entsteht über dotnet new und dotnet sln,
+// newpipi schreibt selbst keine Projektdateien.
+
+// dotnetToolkits sind die Vorlagen von dotnet new. Console nutzt das Init
+// des Projekttyps.
+var dotnetToolkits = []toolkit{
+	{Name: "Console"},
+	dotnetToolkit("Library", "classlib", "dotnet build"),
+	dotnetToolkit("WebAPI", "webapi", ""),
+	dotnetToolkit("Worker", "worker", ""),
+	{
+		Name: "Avalonia",
+		// Die Vorlagen von Avalonia gehören nicht zum SDK; dotnet new install
+		// aktualisiert sie, wenn sie schon installiert sind
+		Init: []TemplateCommand{
+			{Run: []string{"dotnet", "new", "install", "Avalonia.Templates"}, Step: "Installiere Avalonia-Vorlagen...", Network: true},
+			{Run: []string{"dotnet", "new", "avalonia.app", "-n", "{name}"}, Step: "Erstelle Avalonia-Projekt..."},
+		},
+	},
+	dotnetToolkit("xUnit", "xunit", "dotnet test"),
+}
+
+// dotnetLayouts sind die Projektformen: ein einzelnes Projekt oder eine
+// Projektmappe mit Testprojekt.
+var dotnetLayouts = []toolkit{
+	{Name: "Project"},
+	{
+		Name: "Solution",
+		Dir:  "src",
+		Commands: []TemplateCommand{
+			{Run: []string{"dotnet", "new", "sln", "-n", "{name}"}, Step: "Erstelle Projektmappe..."},
+			{Run: []string{"dotnet", "new", "xunit", "-n", "{name}.Tests", "-o", "tests/{name}.Tests"}, Step: "Erstelle Testprojekt..."},
+			{Run: []string{"dotnet", "add", "tests/{name}.Tests/{name}.Tests.csproj", "reference", "src/{name}/{name}.csproj"}, Step: "Verweise auf das Projekt..."},
+			// Ohne Dateinamen findet dotnet sln die Projektmappe selbst, ob .sln oder .slnx
+			{Run: []string{"dotnet", "sln", "add", "src/{name}/{name}.csproj", "tests/{name}.Tests/{name}.Tests.csproj"}, Step: "Füge Projekte zur Projektmappe hinzu..."},
+		},
+		Run:   "dotnet test",
+		Check: "dotnet test",
+	},
+}
+
+// dotnetToolkit legt das Projekt mit der Vorlage short an; run ersetzt
+// "dotnet run", wenn sich die Vorlage nicht starten lässt, und prüft sie dann
+// auch, sofern es dotnet test ist.
+func dotnetToolkit(name, short, run string) toolkit {
+	tk := toolkit{
+		Name: name,
+		Init: []TemplateCommand{
+			{Run: []string{"dotnet", "new", short, "-n", "{name}"}, Step: "Erstelle .NET-Projekt..."},
+		},
+		Run: run,
+	}
+	if run == "dotnet test" {
+		tk.Check = run
+	}
+	return tk
+}
@@ -0,0 +1,283 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Umgebungsvariablen des Projekts stehen in einer einzigen .env; direnv
+// (.envrc), Docker Compose (env_file) und die VS-Code-Startkonfiguration
+// (envFile) verweisen nur darauf, damit die Werte nicht auseinanderlaufen.
+// Secrets eines Templates stehen zusätzlich mit Platzhalter in .env.example;
+// liegen ihre Werte im Schlüsselbund, lädt .envrc sie über `newpipi secret env`.
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// composeFiles sind die Dateinamen, unter denen docker compose sucht.
+var composeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// ValidateEnv prüft die Namen der Variablen.
+func ValidateEnv(env map[string]string) error {
+	for name, value := range env {
+		if !envNamePattern.MatchString(name) {
+			return fmt.Errorf("ungültiger variablenname %q", name)
+		}
+		if strings.ContainsAny(value, "\n\r") {
+			return fmt.Errorf("variable %s: zeilenumbrüche werden nicht unterstützt", name)
+		}
+	}
+	return nil
+}
+
+// writeEnv schreibt die Variablen nach .env und verweist aus .envrc, der
+// Compose-Datei und .vscode/launch.json darauf.
+func (ps *ProjectSetup) writeEnv(projectDir string) error {
+	ps.step("Schreibe Umgebungsvariablen...")
+	modes := ps.Settings.Modes()
+	if err := appendEnvFile(filepath.Join(projectDir, ".env"), ps.EnvVars, modes); err != nil {
+		return fmt.Errorf(".env schreiben fehlgeschlagen: %v", err)
+	}
+	// Werte wie API-Schlüssel gehören nicht in den ersten Commit
+	if err := appendLine(filepath.Join(projectDir, ".gitignore"), ".env", modes); err != nil {
+		return fmt.Errorf(".gitignore schreiben fehlgeschlagen: %v", err)
+	}
+	if err := appendLine(filepath.Join(projectDir, ".envrc"), "dotenv_if_exists", modes); err != nil {
+		return fmt.Errorf(".envrc schreiben fehlgeschlagen: %v", err)
+	}
+	if err := ps.writeComposeEnv(projectDir, modes); err != nil {
+		return err
+	}
+	lang := ps.Language()
+	if lang == nil || lang.Debug == nil {
+		return nil
+	}
+	config := map[string]any{"name": "Launch " + ps.ProjectName, "envFile": "${workspaceFolder}/.env"}
+	for key, value := range lang.Debug {
+		if s, ok := value.(string); ok {
+			value = strings.ReplaceAll(s, "{name}", ps.ProjectName)
+		}
+		config[key] = value
+	}
+	return writeShortcut(projectDir, filepath.Join(".vscode", "launch.json"), marshalCommand(struct {
+		Version        string           `json:"version"`
+		Configurations []map[string]any `json:"configurations"`
+	}{"0.2.0", []map[string]any{config}}, "  "), modes)
+}
+
+// appendEnvFile ergänzt path um die Variablen, die dort noch nicht stehen.
+func appendEnvFile(path string, env map[string]string, modes projectModes) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	defined := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(string(existing)))
+	for scanner.Scan() {
+		line := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "export ")
+		if name, _, ok := strings.Cut(line, "="); ok {
+			defined[strings.TrimSpace(name)] = true
+		}
+	}
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		if defined[name] {
+			log.Printf("%s ist in %s bereits gesetzt, Wert aus der Vorlage bleibt", name, filepath.Base(path))
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", name, quoteEnvValue(env[name]))
+	}
+	return appendContent(path, existing, b.String(), modes)
+}
+
+// quoteEnvValue maskiert einen Wert so, dass direnv, Docker Compose und
+// python-dotenv ihn gleich lesen: einfache Anführungszeichen ersetzen nichts.
+func quoteEnvValue(value string) string {
+	if value != "" && !strings.ContainsFunc(value, func(r rune) bool {
+		return !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-./:@,+", r)
+	}) {
+		return value
+	}
+	if !strings.Contains(value, "'") {
+		return "'" + value + "'"
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`).Replace(value) + `"`
+}
+
+// appendLine hängt line an path an, sofern die Datei sie noch nicht enthält.
+func appendLine(path, line string, modes projectModes) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if slices.Contains(strings.Split(string(existing), "\n"), line) {
+		return nil
+	}
+	return appendContent(path, existing, line+"\n", modes)
+}
+
+// appendContent schreibt existing gefolgt von content; ein fehlender
+// Zeilenumbruch am Ende von existing wird ergänzt.
+func appendContent(path string, existing []byte, content string, modes projectModes) error {
+	if content == "" {
+		return nil
+	}
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		content = "\n" + content
+	}
+	if existing == nil {
+		return modes.writeFile(path, []byte(content), modes.File)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeComposeEnv trägt env_file in jeden Dienst der Compose-Datei ein. Ohne
+// Compose-Datei, aber mit Dockerfile entsteht eine mit einem Dienst.
+func (ps *ProjectSetup) writeComposeEnv(projectDir string, modes projectModes) error {
+	for _, name := range composeFiles {
+		path := filepath.Join(projectDir, name)
+		content, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("%s lesen fehlgeschlagen: %v", name, err)
+		}
+		updated, ok := addComposeEnvFile(string(content))
+		if !ok {
+			log.Printf("Keine Dienste in %s gefunden, env_file nicht eingetragen", name)
+			return nil
+		}
+		if err := os.WriteFile(path, []byte(updated), 0); err != nil {
+			return fmt.Errorf("%s schreiben fehlgeschlagen: %v", name, err)
+		}
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(projectDir, "Dockerfile")); err != nil {
+		return nil
+	}
+	service := strings.ToLower(ps.ProjectName)
+	return writeShortcut(projectDir, "compose.yaml", fmt.Sprintf("services:\n  %s:\n    build: .\n    env_file: .env\n", service), modes)
+}
+
+// addComposeEnvFile fügt nach jedem Dienst unter "services:" die Zeile
+// "env_file: .env" ein, sofern der Dienst noch kein env_file hat. Die Datei
+// wird zeilenweise anhand der Einrückung gelesen, Kommentare bleiben erhalten.
+func addComposeEnvFile(content string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	indent := func(line string) int { return len(line) - len(strings.TrimLeft(line, " ")) }
+	blank := func(line string) bool {
+		trimmed := strings.TrimSpace(line)
+		return trimmed == "" || strings.HasPrefix(trimmed, "#")
+	}
+
+	var out []string
+	inServices, serviceIndent, found := false, -1, false
+	// pending ist die Einrückung für env_file im aktuellen Dienst, -1 ohne Dienst
+	pending, hasEnvFile := -1, false
+	flush := func() {
+		if pending >= 0 && !hasEnvFile {
+			// Hinter die letzte Zeile des Dienstes, vor folgende Leerzeilen
+			at := len(out)
+			for at > 0 && blank(out[at-1]) {
+				at--
+			}
+			out = slices.Insert(out, at, strings.Repeat(" ", pending)+"env_file: .env")
+		}
+		pending, hasEnvFile = -1, false
+	}
+	for _, line := range lines {
+		if blank(line) {
+			out = append(out, line)
+			continue
+		}
+		level := indent(line)
+		switch {
+		case level == 0:
+			flush()
+			inServices = strings.TrimSpace(line) == "services:"
+			serviceIndent = -1
+		case inServices && (serviceIndent < 0 || level == serviceIndent):
+			flush()
+			serviceIndent = level
+			pending, found = level*2, true
+		case inServices && pending >= 0:
+			if level < pending {
+				pending = level
+			}
+			if strings.HasPrefix(strings.TrimSpace(line), "env_file:") {
+				hasEnvFile = true
+			}
+		}
+		out = append(out, line)
+	}
+	flush()
+	return strings.Join(out, "\n"), found
+}
+
+// writeSecrets schreibt .env.example mit Platzhaltern für die Secrets des
+// Templates und legt angegebene Werte in .env oder im Schlüsselbund ab.
+func (ps *ProjectSetup) writeSecrets(projectDir string) error {
+	ps.step("Lege Platzhalter für Secrets an...")
+	modes := ps.Settings.Modes()
+	var example strings.Builder
+	for _, s := range ps.Template.Secrets {
+		placeholder := s.Placeholder
+		if placeholder == "" {
+			placeholder = "changeme"
+		}
+		if s.Description != "" {
+			fmt.Fprintf(&example, "# %s\n", ps.text(s.Description))
+		}
+		fmt.Fprintf(&example, "%s=%s\n", s.Name, quoteEnvValue(placeholder))
+	}
+	if err := writeShortcut(projectDir, ".env.example", example.String(), modes); err != nil {
+		return err
+	}
+	if err := appendLine(filepath.Join(projectDir, ".gitignore"), ".env", modes); err != nil {
+		return fmt.Errorf(".gitignore schreiben fehlgeschlagen: %v", err)
+	}
+	if err := appendLine(filepath.Join(projectDir, ".envrc"), "dotenv_if_exists", modes); err != nil {
+		return fmt.Errorf(".envrc schreiben fehlgeschlagen: %v", err)
+	}
+	if len(ps.SecretValues) == 0 {
+		return nil
+	}
+	if !ps.SecretsInKeyring {
+		if err := appendEnvFile(filepath.Join(projectDir, ".env"), ps.SecretValues, modes); err != nil {
+			return fmt.Errorf(".env schreiben fehlgeschlagen: %v", err)
+		}
+		return nil
+	}
+	for name, value := range ps.SecretValues {
+		if err := keyring.Set(keyringService, projectSecretKey(projectDir, name), value); err != nil {
+			return fmt.Errorf("secret %s in schlüsselbund speichern fehlgeschlagen: %v", name, err)
+		}
+	}
+	return appendLine(filepath.Join(projectDir, ".envrc"), `eval "$(newpipi secret env)"`, modes)
+}
+
+// validateSecrets prüft, ob das Template alle angegebenen Secrets kennt.
+func (t *Template) validateSecrets(values map[string]string) error {
+	for name := range values {
+		if !slices.ContainsFunc(t.Secrets, func(s TemplateSecret) bool { return s.Name == name }) {
+			return fmt.Errorf("template %s hat kein secret %s", t.Name, name)
+		}
+	}
+	return ValidateEnv(values)
+}
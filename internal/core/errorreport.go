@@ -0,0 +1,335 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Fehlerberichte: Mit "errorReports": true schreibt newpipi sein Protokoll
+// zusätzlich nach <state>/newpipi.log und legt bei einem Fehler beim
+// Erstellen oder einem Absturz einen Bericht unter <state>/reports an:
+// Version, System, Befehl, Fehler, die Konfiguration ohne Tokens, Schlüssel
+// und persönliche Angaben sowie das Ende des Protokolls. newpipi sendet
+// nichts; es bietet nur an, ein vorausgefülltes GitHub-Issue im Browser zu
+// öffnen. `newpipi report` erstellt einen Bericht von Hand.
+
+// issuesURL ist die Seite für ein neues Issue.
+const issuesURL = "https://github.com/alexander-graf/go_pipi/issues/new"
+
+// logFileName liegt im Zustandsverzeichnis; ist die Datei beim Start größer
+// als maxLogFileSize, beginnt sie neu.
+const (
+	logFileName    = "newpipi.log"
+	maxLogFileSize = 1 << 20
+)
+
+// reportLogLines ist die Zahl der Protokollzeilen im Bericht, issueBodyLimit
+// begrenzt den Text im Link, damit Browser und GitHub die Adresse annehmen.
+const (
+	reportLogLines = 200
+	issueBodyLimit = 6000
+)
+
+// reportRedactKeys sind Einstellungen, deren Werte nicht in den Bericht
+// gehören; bei Maps wie forgeTokens gilt das für alle Einträge.
+var reportRedactKeys = []string{"apiKey", "marketplaceKey", "forgeTokens", "registryCredentials", "signingKey", "gitName", "gitEmail", "remoteURL"}
+
+// reportRedacted ersetzt entfernte Werte.
+const reportRedacted = "[entfernt]"
+
+// logFilePath liefert den Pfad der Protokolldatei.
+func logFilePath() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, logFileName), nil
+}
+
+// StartLogFile schreibt das Protokoll zusätzlich in die Protokolldatei,
+// sofern Fehlerberichte eingeschaltet sind.
+func StartLogFile() {
+	s, err := LoadSettings()
+	if err != nil || !s.ErrorReports {
+		return
+	}
+	path, err := logFilePath()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Printf("Protokolldatei anlegen fehlgeschlagen: %v", err)
+		return
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogFileSize {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0600)
+	if err != nil {
+		log.Printf("Protokolldatei öffnen fehlgeschlagen: %v", err)
+		return
+	}
+	log.SetOutput(io.MultiWriter(log.Writer(), f))
+}
+
+// errorReport ist ein Fehlerbericht.
+type errorReport struct {
+	Time     time.Time
+	Version  string
+	System   string
+	Desktop  string
+	Command  string
+	Error    string
+	Settings string
+	Log      string
+}
+
+// newErrorReport sammelt die Angaben für einen Bericht über err; args sind
+// die Argumente von newpipi, von denen nur Befehl und Flag-Namen bleiben.
+func newErrorReport(settings Settings, args []string, err error) *errorReport {
+	r := &errorReport{
+		Time:     time.Now(),
+		Version:  VersionString(),
+		System:   fmt.Sprintf("%s, Go %s", osRelease(), strings.TrimPrefix(runtime.Version(), "go")),
+		Desktop:  strings.TrimSpace(os.Getenv("XDG_CURRENT_DESKTOP") + " " + os.Getenv("XDG_SESSION_TYPE")),
+		Command:  reportArgs(args),
+		Settings: redactSettings(settings),
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if path, err := logFilePath(); err == nil {
+		if content, err := os.ReadFile(path); err == nil {
+			r.Log = lastLines(string(content), reportLogLines)
+		}
+	}
+	return r
+}
+
+// osRelease liefert den Namen der Distribution, sonst das Betriebssystem.
+func osRelease() string {
+	content, err := os.ReadFile("/etc/os-release")
+	if err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			if value, ok := strings.CutPrefix(line, "PRETTY_NAME="); ok {
+				return strings.Trim(value, `"`) + " " + runtime.GOARCH
+			}
+		}
+	}
+	return runtime.GOOS + " " + runtime.GOARCH
+}
+
+// reportArgs behält vom Aufruf den Befehl und die Namen der Flags; Werte wie
+// Projektnamen, Pfade oder Secrets fallen weg.
+func reportArgs(args []string) string {
+	var kept []string
+	for i, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "-"):
+			name, _, _ := strings.Cut(arg, "=")
+			kept = append(kept, name)
+		case i == 0:
+			kept = append(kept, arg)
+		}
+	}
+	return strings.Join(slices.Concat([]string{"newpipi"}, kept), " ")
+}
+
+// redactSettings liefert die Einstellungen als JSON ohne die Werte aus
+// reportRedactKeys und ohne Zugangsdaten in Adressen.
+func redactSettings(settings Settings) string {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return ""
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return ""
+	}
+	data, err = json.MarshalIndent(redactValue(v, false), "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func redactValue(v any, redact bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = redactValue(value, redact || slices.ContainsFunc(reportRedactKeys, func(k string) bool { return strings.EqualFold(k, key) }))
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = redactValue(value, redact)
+		}
+		return v
+	case string:
+		if redact && v != "" {
+			return reportRedacted
+		}
+		if u, err := url.Parse(v); err == nil && u.User != nil && u.Host != "" {
+			u.User = url.User(reportRedacted)
+			return u.String()
+		}
+		return v
+	}
+	return v
+}
+
+// markdown gibt den Bericht als Markdown aus; das Home-Verzeichnis erscheint
+// als ~.
+func (r *errorReport) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Fehler\n\n```\n%s\n```\n\n", r.Error)
+	fmt.Fprintf(&b, "## Umgebung\n\n- Version: %s\n- System: %s\n", r.Version, r.System)
+	if r.Desktop != "" {
+		fmt.Fprintf(&b, "- Desktop: %s\n", r.Desktop)
+	}
+	fmt.Fprintf(&b, "- Befehl: `%s`\n- Zeit: %s\n\n", r.Command, r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "## Konfiguration\n\n```json\n%s\n```\n", r.Settings)
+	if r.Log != "" {
+		fmt.Fprintf(&b, "\n## Protokoll\n\n```\n%s\n```\n", r.Log)
+	}
+	text := b.String()
+	if home, err := os.UserHomeDir(); err == nil && home != "/" && home != "" {
+		text = strings.ReplaceAll(text, home, "~")
+	}
+	return text
+}
+
+// save schreibt den Bericht nach <state>/reports.
+func (r *errorReport) save() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "reports")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "error-"+r.Time.Format("20060102-150405")+".md")
+	return path, os.WriteFile(path, []byte(r.markdown()), 0600)
+}
+
+// issueURL liefert den Link auf ein vorausgefülltes Issue; ein zu langer
+// Bericht wird gekürzt, der vollständige liegt in der Datei.
+func (r *errorReport) issueURL() string {
+	title, _, _ := strings.Cut(r.Error, "\n")
+	if title == "" {
+		title = "Fehlerbericht"
+	}
+	if runes := []rune(title); len(runes) > 80 {
+		title = string(runes[:80]) + "…"
+	}
+	body := r.markdown()
+	if len(body) > issueBodyLimit {
+		cut := strings.LastIndex(body[:issueBodyLimit], "\n")
+		if cut < 0 {
+			cut = issueBodyLimit
+		}
+		body = body[:cut] + "\n```\n\n_Gekürzt, bitte den vollständigen Bericht anhängen._\n"
+	}
+	return issuesURL + "?" + url.Values{"title": {title}, "body": {body}}.Encode()
+}
+
+// ReportFailure legt bei eingeschalteten Fehlerberichten einen Bericht über
+// err an und liefert Datei und Issue-Link. Fehlende Werkzeuge, bestehende
+// Verzeichnisse, Netzfehler und Richtlinien sind keine Fehler von newpipi.
+func ReportFailure(settings Settings, args []string, err error) (path, link string) {
+	if !settings.ErrorReports || ExitCodeFor(err) != exitFailure {
+		return "", ""
+	}
+	r := newErrorReport(settings, args, err)
+	path, err = r.save()
+	if err != nil {
+		log.Printf("Fehlerbericht speichern fehlgeschlagen: %v", err)
+		return "", ""
+	}
+	log.Printf("Fehlerbericht gespeichert: %s", path)
+	return path, r.issueURL()
+}
+
+// ReportPanic legt bei einem Absturz einen Bericht an und bricht dann wie
+// gewohnt ab.
+func ReportPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if s, err := LoadSettings(); err == nil {
+		if path, _ := ReportFailure(s, os.Args[1:], fmt.Errorf("panic: %v\n\n%s", r, debug.Stack())); path != "" {
+			fmt.Fprintf(os.Stderr, "Fehlerbericht: %s\n", path)
+		}
+	}
+	panic(r)
+}
+
+// offerIssue fragt im Terminal, ob das Issue im Browser geöffnet werden soll.
+func offerIssue(path, link string) {
+	fmt.Fprintf(os.Stderr, "Fehlerbericht: %s\n", path)
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return
+	}
+	fmt.Fprint(os.Stderr, "Vorausgefülltes GitHub-Issue im Browser öffnen? [j/N] ")
+	var answer string
+	fmt.Scanln(&answer)
+	if strings.EqualFold(answer, "j") || strings.EqualFold(answer, "y") {
+		openIssue(link)
+	}
+}
+
+// openIssue öffnet link im Browser oder gibt ihn aus, wenn das nicht geht.
+func openIssue(link string) {
+	if err := exec.Command("xdg-open", link).Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "Browser öffnen fehlgeschlagen (%v), Link:\n%s\n", err, link)
+	}
+}
+
+// setupReport definiert `newpipi report [--message TEXT] [--open]`.
+func setupReport(fs *flag.FlagSet) func(args []string) int {
+	message := fs.String("message", "", "Beschreibung des Problems")
+	open := fs.Bool("open", false, "Vorausgefülltes GitHub-Issue im Browser öffnen")
+	return func(args []string) int {
+		s, err := LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		var problem error
+		if *message != "" {
+			problem = errors.New(*message)
+		}
+		r := newErrorReport(s, os.Args[1:], problem)
+		path, err := r.save()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		fmt.Println(path)
+		if !s.ErrorReports {
+			log.Printf("Ohne \"errorReports\": true enthält der Bericht kein Protokoll")
+		}
+		if *open {
+			openIssue(r.issueURL())
+		}
+		return ExitOK
+	}
+}
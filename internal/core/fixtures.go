@@ -0,0 +1,295 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Befehls-Fixtures: Mit NEWPIPI_RECORD_FIXTURES=DIR zeichnet newpipi jeden
+// Befehl der Projekterstellung (Werkzeugprüfung, Init, Befehle, Pakete) mit
+// Ausgabe, Exit-Code und den Dateien auf, die er im Projekt anlegt oder
+// ändert; mit NEWPIPI_REPLAY_FIXTURES=DIR spielt es sie ab, statt die
+// Werkzeuge zu starten. Integrationstests der ganzen Pipeline laufen so ohne
+// Netz und Toolchains und liefern immer dasselbe Ergebnis. In beiden Modi
+// startet newpipi sich selbst mit FixtureShimArg anstelle des Befehls, sodass
+// die Aufrufer wie gewohnt Output, CombinedOutput oder Run nutzen.
+
+// FixtureShimArg leitet newpipi als Stellvertreter eines Befehls ein:
+// newpipi FixtureShimArg record|replay DATEI PROJEKT -- BEFEHL ARGUMENTE.
+const FixtureShimArg = "__newpipi-fixture"
+
+// fixtureProject ersetzt das Projektverzeichnis in Argumenten, Ausgaben und
+// Dateien, damit Fixtures nicht vom Zielpfad abhängen.
+const fixtureProject = "{project}"
+
+// fixtureMaxFileSize begrenzt die aufgezeichneten Dateien; größere bleiben
+// wie die Verzeichnisse in fixtureSkipDirs beim Abspielen weg.
+const fixtureMaxFileSize = 1 << 20
+
+// fixtureSkipDirs sind Verzeichnisse mit Abhängigkeiten und Builds, die nicht
+// aufgezeichnet werden.
+var fixtureSkipDirs = []string{".git", "node_modules", "venv", ".venv", "target", ".gradle"}
+
+// commandFixture ist ein aufgezeichneter Befehl.
+type commandFixture struct {
+	Command  []string               `json:"command"`
+	Stdout   string                 `json:"stdout,omitempty"`
+	Stderr   string                 `json:"stderr,omitempty"`
+	ExitCode int                    `json:"exitCode"`
+	Files    map[string]fixtureFile `json:"files,omitempty"`
+	Removed  []string               `json:"removed,omitempty"`
+}
+
+// fixtureFile ist eine angelegte oder geänderte Datei; Text nimmt UTF-8
+// auf, Data alles andere.
+type fixtureFile struct {
+	Mode fs.FileMode `json:"mode"`
+	Text string      `json:"text,omitempty"`
+	Data []byte      `json:"data,omitempty"`
+}
+
+// fixtureMode liefert "record" oder "replay" samt Verzeichnis, leer ohne
+// Fixtures. Abspielen hat Vorrang.
+func fixtureMode() (mode, dir string) {
+	if dir := os.Getenv("NEWPIPI_REPLAY_FIXTURES"); dir != "" {
+		return "replay", dir
+	}
+	if dir := os.Getenv("NEWPIPI_RECORD_FIXTURES"); dir != "" {
+		return "record", dir
+	}
+	return "", ""
+}
+
+// execCommand erstellt einen Befehl über ps.NewCommand, sonst direkt; mit
+// Fixtures startet er newpipi als Stellvertreter, der ihn aufzeichnet oder
+// abspielt.
+func (ps *ProjectSetup) execCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	if ps.NewCommand != nil {
+		return ps.NewCommand(ctx, name, args...)
+	}
+	mode, dir := fixtureMode()
+	if mode == "" {
+		return exec.CommandContext(ctx, name, args...)
+	}
+	self, err := os.Executable()
+	if err == nil {
+		dir, err = filepath.Abs(dir)
+	}
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	if err == nil {
+		projectDir, err = filepath.Abs(projectDir)
+	}
+	if err != nil {
+		log.Printf("Fixtures nicht möglich: %v", err)
+		return exec.CommandContext(ctx, name, args...)
+	}
+	file := filepath.Join(dir, ps.fixtureKey(projectDir, name, args)+".json")
+	return exec.CommandContext(ctx, self, slices.Concat([]string{FixtureShimArg, mode, file, projectDir, "--", name}, args)...)
+}
+
+// fixtureKey benennt die Fixture eines Befehls nach Programm und Argumenten
+// ohne Zielpfad; wiederholte Aufrufe zählen hoch.
+func (ps *ProjectSetup) fixtureKey(projectDir, name string, args []string) string {
+	command := fixtureArgs(projectDir, slices.Concat([]string{name}, args))
+	sum := sha256.Sum256([]byte(strings.Join(command, "\x00")))
+	key := fmt.Sprintf("%s-%s", filepath.Base(name), hex.EncodeToString(sum[:6]))
+	if ps.fixtureCalls == nil {
+		ps.fixtureCalls = make(map[string]int)
+	}
+	ps.fixtureCalls[key]++
+	return fmt.Sprintf("%s-%d", key, ps.fixtureCalls[key])
+}
+
+// fixtureArgs ersetzt Projekt- und Elternverzeichnis in args.
+func fixtureArgs(projectDir string, args []string) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, projectDir, fixtureProject)
+		out[i] = strings.ReplaceAll(arg, filepath.Dir(projectDir), fixtureProject+"/..")
+	}
+	return out
+}
+
+// RunFixtureShim ist der Einstieg des Stellvertreters; args sind
+// MODUS DATEI PROJEKT -- BEFEHL ARGUMENTE, der Exit-Code ist der des Befehls.
+func RunFixtureShim(args []string) int {
+	if len(args) < 5 || args[3] != "--" {
+		fmt.Fprintf(os.Stderr, "Fehler: %s MODUS DATEI PROJEKT -- BEFEHL erwartet\n", FixtureShimArg)
+		return exitUsage
+	}
+	mode, file, projectDir, command := args[0], args[1], args[2], args[4:]
+	var err error
+	code := exitFailure
+	switch mode {
+	case "record":
+		code, err = recordFixture(file, projectDir, command)
+	case "replay":
+		code, err = replayFixture(file, projectDir, command)
+	default:
+		err = fmt.Errorf("unbekannter fixture-modus %q", mode)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+		if code == 0 {
+			code = exitFailure
+		}
+	}
+	return code
+}
+
+// recordFixture führt command aus und speichert Ausgabe, Exit-Code und die
+// geänderten Dateien unter projectDir in file.
+func recordFixture(file, projectDir string, command []string) (int, error) {
+	before := snapshotFiles(projectDir)
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
+	cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	code := 0
+	var exitErr *exec.ExitError
+	if err := cmd.Run(); errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		// Wie die Shell bei einem fehlenden Programm
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(&stderr, err)
+		code = 127
+	}
+
+	fixture := commandFixture{
+		Command:  fixtureArgs(projectDir, command),
+		Stdout:   strings.ReplaceAll(stdout.String(), projectDir, fixtureProject),
+		Stderr:   strings.ReplaceAll(stderr.String(), projectDir, fixtureProject),
+		ExitCode: code,
+		Files:    make(map[string]fixtureFile),
+	}
+	after := snapshotFiles(projectDir)
+	for path, stamp := range after {
+		if old, ok := before[path]; ok && old == stamp {
+			continue
+		}
+		if stamp.size > fixtureMaxFileSize {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(projectDir, filepath.FromSlash(path)))
+		if err != nil {
+			return code, err
+		}
+		f := fixtureFile{Mode: stamp.mode}
+		if utf8.Valid(data) {
+			f.Text = strings.ReplaceAll(string(data), projectDir, fixtureProject)
+		} else {
+			f.Data = data
+		}
+		fixture.Files[path] = f
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			fixture.Removed = append(fixture.Removed, path)
+		}
+	}
+	slices.Sort(fixture.Removed)
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return code, err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return code, err
+	}
+	return code, os.WriteFile(file, append(data, '\n'), 0644)
+}
+
+// replayFixture legt die Dateien aus file unter projectDir an und gibt die
+// aufgezeichnete Ausgabe aus.
+func replayFixture(file, projectDir string, command []string) (int, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 127, fmt.Errorf("keine fixture für %q (%s)", strings.Join(command, " "), filepath.Base(file))
+	}
+	if err != nil {
+		return exitFailure, err
+	}
+	var fixture commandFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return exitFailure, fmt.Errorf("%s: %v", file, err)
+	}
+	for path, f := range fixture.Files {
+		if !filepath.IsLocal(filepath.FromSlash(path)) {
+			return exitFailure, fmt.Errorf("%s: pfad %q liegt außerhalb des projekts", file, path)
+		}
+		target := filepath.Join(projectDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return exitFailure, err
+		}
+		content := f.Data
+		if f.Data == nil {
+			content = []byte(strings.ReplaceAll(f.Text, fixtureProject, projectDir))
+		}
+		if err := os.WriteFile(target, content, f.Mode.Perm()); err != nil {
+			return exitFailure, err
+		}
+	}
+	for _, path := range fixture.Removed {
+		if filepath.IsLocal(filepath.FromSlash(path)) {
+			os.Remove(filepath.Join(projectDir, filepath.FromSlash(path)))
+		}
+	}
+	fmt.Fprint(os.Stdout, strings.ReplaceAll(fixture.Stdout, fixtureProject, projectDir))
+	fmt.Fprint(os.Stderr, strings.ReplaceAll(fixture.Stderr, fixtureProject, projectDir))
+	return fixture.ExitCode, nil
+}
+
+// fileStamp erkennt geänderte Dateien an Größe, Zeitstempel und Rechten.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+	mode    fs.FileMode
+}
+
+// snapshotFiles erfasst die regulären Dateien unter root ohne
+// fixtureSkipDirs; ein fehlendes root ist leer.
+func snapshotFiles(root string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && slices.Contains(fixtureSkipDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		stamps[filepath.ToSlash(rel)] = fileStamp{info.Size(), info.ModTime(), info.Mode().Perm()}
+		return nil
+	})
+	return stamps
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Das Testprogramm ist beim Aufzeichnen und Abspielen der Stellvertreter
+	if len(os.Args) > 1 && os.Args[1] == FixtureShimArg {
+		os.Exit(RunFixtureShim(os.Args[2:]))
+	}
+	os.Exit(m.Run())
+}
+
+// TestReplayFixtures erstellt ein Go-Projekt mit Cobra aus aufgezeichneten
+// Befehlen, ohne dass go installiert sein muss.
+func TestReplayFixtures(t *testing.T) {
+	fixtures, err := filepath.Abs(filepath.Join("testdata", "fixtures", "go-cobra"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NEWPIPI_CONFIG_DIR", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("NEWPIPI_REPLAY_FIXTURES", fixtures)
+	t.Setenv("PATH", "")
+
+	ps := NewProjectSetup()
+	ps.ParentPath = t.TempDir()
+	ps.ProjectName = "demo"
+	ps.ProjectType = Go
+	ps.ToolkitName = "Cobra"
+	ps.skipTerminal, ps.SkipGit = true, true
+	if err := ps.CreateProject(); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	tests := []struct {
+		file, want string
+	}{
+		{"go.mod", "require github.com/spf13/cobra v1.10.2"},
+		{"go.sum", "github.com/spf13/cobra v1.10.2 h1:"},
+		{"cmd/root.go", "cobra.Command"},
+	}
+	for _, tt := range tests {
+		content, err := os.ReadFile(filepath.Join(dir, tt.file))
+		if err != nil {
+			t.Errorf("%s: %v", tt.file, err)
+			continue
+		}
+		if !strings.Contains(string(content), tt.want) {
+			t.Errorf("%s enthält %q nicht:\n%s", tt.file, tt.want, content)
+		}
+	}
+	if got := ps.toolVersions["go"]; got != "go version go1.27.1 linux/amd64" {
+		t.Errorf("toolVersions[go] = %q", got)
+	}
+}
+
+func TestRecordFixtures(t *testing.T) {
+	fixtures := t.TempDir()
+	ps := &ProjectSetup{ParentPath: t.TempDir(), ProjectName: "demo"}
+	dir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	if err := os.MkdirAll(filepath.Join(dir, "alt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "alt", "weg.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	script := "mkdir -p bin && printf '#!/bin/sh\\n' > bin/run && chmod 755 bin/run && rm alt/weg.txt && echo $PWD && exit 3"
+
+	run := func(mode string) (string, int) {
+		t.Setenv("NEWPIPI_RECORD_FIXTURES", "")
+		t.Setenv("NEWPIPI_REPLAY_FIXTURES", "")
+		t.Setenv("NEWPIPI_"+mode+"_FIXTURES", fixtures)
+		ps.fixtureCalls = nil
+		cmd := ps.command("sh", "-c", script)
+		cmd.Dir = dir
+		out, _ := cmd.Output()
+		return string(out), cmd.ProcessState.ExitCode()
+	}
+	recorded, code := run("RECORD")
+	if code != 3 || recorded != dir+"\n" {
+		t.Fatalf("aufgezeichnet: %q, exit %d", recorded, code)
+	}
+
+	// Abspielen in einem neuen Verzeichnis ohne sh
+	ps.ParentPath = t.TempDir()
+	dir = filepath.Join(ps.ParentPath, ps.ProjectName)
+	if err := os.MkdirAll(filepath.Join(dir, "alt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "alt", "weg.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", "")
+	replayed, code := run("REPLAY")
+	if code != 3 || replayed != dir+"\n" {
+		t.Errorf("abgespielt: %q, exit %d, want %q, exit 3", replayed, code, dir+"\n")
+	}
+	if info, err := os.Stat(filepath.Join(dir, "bin", "run")); err != nil || info.Mode().Perm() != 0755 {
+		t.Errorf("bin/run: %v, %v", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "alt", "weg.txt")); !os.IsNotExist(err) {
+		t.Errorf("alt/weg.txt nicht entfernt: %v", err)
+	}
+
+	// Ein nicht aufgezeichneter Befehl scheitert wie ein fehlendes Programm
+	ps.fixtureCalls = nil
+	cmd := ps.command("make")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "keine fixture") {
+		t.Errorf("make ohne fixture: %v, %s", err, out)
+	}
+}
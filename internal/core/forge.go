@@ -0,0 +1,315 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Entfernte Repositories legt newpipi nur für Projekte aus
+// GitHub-Template-Repositorys an (siehe github.go); sonst übernehmen das
+// Template-Hooks (etwa `gh repo create`) oder der Benutzer. Hat das Projekt einen
+// Remote "origin", wendet newpipi die Forge-Richtlinie des Profils über die
+// API an: Standardbranch, Merge-Strategien, Topics, Labels und Branch-Schutz.
+// Unterstützt wird die GitHub-API (github.com und GitHub Enterprise).
+
+const ForgeTimeout = 30 * time.Second
+
+// forgePolicy ist der Inhalt der Richtliniendatei eines Profils.
+type forgePolicy struct {
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+	// MergeStrategies erlaubt "merge", "squash" und "rebase"; die übrigen werden
+	// abgeschaltet. Leer lässt die Einstellung des Repositorys unverändert.
+	MergeStrategies     []string           `json:"mergeStrategies,omitempty"`
+	DeleteBranchOnMerge *bool              `json:"deleteBranchOnMerge,omitempty"`
+	Topics              []string           `json:"topics,omitempty"`
+	Labels              []forgeLabel       `json:"labels,omitempty"`
+	Protection          []branchProtection `json:"protection,omitempty"`
+}
+
+type forgeLabel struct {
+	Name        string `json:"name"`
+	Color       string `json:"color,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// branchProtection schützt einen Branch, ohne Branch den Standardbranch.
+type branchProtection struct {
+	Branch          string   `json:"branch,omitempty"`
+	RequiredReviews int      `json:"requiredReviews,omitempty"`
+	RequiredChecks  []string `json:"requiredChecks,omitempty"`
+	EnforceAdmins   bool     `json:"enforceAdmins,omitempty"`
+}
+
+// forgeRequest ist ein API-Aufruf; Fallback ersetzt ihn, wenn die Ressource
+// schon existiert (Labels).
+type forgeRequest struct {
+	Method   string
+	Path     string
+	Body     any
+	Fallback *forgeRequest
+}
+
+// loadForgePolicy liest die Richtliniendatei; relative Pfade gelten ab dem
+// Konfigurationsverzeichnis.
+func loadForgePolicy(path string) (*forgePolicy, error) {
+	if !filepath.IsAbs(path) {
+		dir, err := configDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("forge-richtlinie lesen fehlgeschlagen: %v", err)
+	}
+	var p forgePolicy
+	if err := json.Unmarshal(content, &p); err != nil {
+		return nil, fmt.Errorf("forge-richtlinie %s ungültig: %v", path, err)
+	}
+	return &p, nil
+}
+
+// requests übersetzt die Richtlinie in API-Aufrufe für das Repository owner/repo.
+func (p *forgePolicy) requests(owner, repo string) ([]forgeRequest, error) {
+	base := "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+	var reqs []forgeRequest
+
+	settings := map[string]any{}
+	if p.DefaultBranch != "" {
+		settings["default_branch"] = p.DefaultBranch
+	}
+	if len(p.MergeStrategies) > 0 {
+		allowed := map[string]bool{}
+		for _, s := range p.MergeStrategies {
+			if s != "merge" && s != "squash" && s != "rebase" {
+				return nil, fmt.Errorf("unbekannte merge-strategie %q", s)
+			}
+			allowed[s] = true
+		}
+		settings["allow_merge_commit"] = allowed["merge"]
+		settings["allow_squash_merge"] = allowed["squash"]
+		settings["allow_rebase_merge"] = allowed["rebase"]
+	}
+	if p.DeleteBranchOnMerge != nil {
+		settings["delete_branch_on_merge"] = *p.DeleteBranchOnMerge
+	}
+	if len(settings) > 0 {
+		reqs = append(reqs, forgeRequest{Method: http.MethodPatch, Path: base, Body: settings})
+	}
+	if len(p.Topics) > 0 {
+		reqs = append(reqs, forgeRequest{Method: http.MethodPut, Path: base + "/topics", Body: map[string]any{"names": p.Topics}})
+	}
+	for _, l := range p.Labels {
+		body := map[string]string{"name": l.Name, "color": strings.TrimPrefix(l.Color, "#"), "description": l.Description}
+		reqs = append(reqs, forgeRequest{
+			Method:   http.MethodPost,
+			Path:     base + "/labels",
+			Body:     body,
+			Fallback: &forgeRequest{Method: http.MethodPatch, Path: base + "/labels/" + url.PathEscape(l.Name), Body: body},
+		})
+	}
+	for _, bp := range p.Protection {
+		branch := bp.Branch
+		if branch == "" {
+			branch = p.DefaultBranch
+		}
+		if branch == "" {
+			return nil, errors.New("branch-schutz ohne branch und ohne defaultBranch")
+		}
+		// GitHub verlangt alle vier Felder, null schaltet die Regel ab
+		body := map[string]any{"enforce_admins": bp.EnforceAdmins, "required_status_checks": nil, "required_pull_request_reviews": nil, "restrictions": nil}
+		if len(bp.RequiredChecks) > 0 {
+			body["required_status_checks"] = map[string]any{"strict": true, "contexts": bp.RequiredChecks}
+		}
+		if bp.RequiredReviews > 0 {
+			body["required_pull_request_reviews"] = map[string]any{"required_approving_review_count": bp.RequiredReviews}
+		}
+		reqs = append(reqs, forgeRequest{Method: http.MethodPut, Path: base + "/branches/" + url.PathEscape(branch) + "/protection", Body: body})
+	}
+	return reqs, nil
+}
+
+// parseRemote zerlegt eine Remote-URL (https, ssh oder scp-artig) in Host,
+// Besitzer und Repository.
+func parseRemote(remote string) (host, owner, repo string, err error) {
+	var path string
+	if u, perr := url.Parse(remote); perr == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remote, ":"); ok && !strings.Contains(at, "/") {
+		// git@github.com:owner/repo.git
+		_, host, _ = strings.Cut(at, "@")
+		if host == "" {
+			host = at
+		}
+		path = rest
+	} else {
+		return "", "", "", fmt.Errorf("remote-url %q nicht erkannt", remote)
+	}
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("remote-url %q nennt kein besitzer/repository", remote)
+	}
+	return host, parts[0], parts[1], nil
+}
+
+// forgeAPIBase liefert die API-Adresse für einen Forge-Host.
+func forgeAPIBase(host string) string {
+	if host == "github.com" {
+		return "https://api.github.com"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// applyForgePolicy wendet die Richtlinie des Profils auf den Remote "origin"
+// des Projekts an. Ohne Richtlinie oder Remote geschieht nichts; mit dryRun
+// werden die Aufrufe nur protokolliert.
+func (ps *ProjectSetup) applyForgePolicy(projectDir string, dryRun bool) error {
+	policyPath := ps.Profile().ForgePolicy
+	if policyPath == "" {
+		return nil
+	}
+	cmd := ps.command("git", "remote", "get-url", "origin")
+	cmd.Dir = projectDir
+	out, err := cmd.Output()
+	if err != nil {
+		log.Printf("Kein Remote origin in %s, Forge-Richtlinie übersprungen", projectDir)
+		return nil
+	}
+	host, owner, repo, err := parseRemote(strings.TrimSpace(string(out)))
+	if err != nil {
+		return err
+	}
+	policy, err := loadForgePolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	reqs, err := policy.requests(owner, repo)
+	if err != nil {
+		return err
+	}
+	ps.step(fmt.Sprintf("Wende Forge-Richtlinie auf %s/%s an...", owner, repo))
+	if dryRun {
+		for _, r := range reqs {
+			body, _ := json.Marshal(r.Body)
+			log.Printf("%s %s %s", r.Method, r.Path, body)
+		}
+		return nil
+	}
+	token, err := ps.forgeToken(host)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fmt.Errorf("kein forge-token für %s im profil %s", host, ps.ProfileName)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ForgeTimeout)
+	defer cancel()
+	for _, r := range reqs {
+		err := forgeCall(ctx, forgeAPIBase(host), token, r)
+		var status forgeStatusError
+		if errors.As(err, &status) && status.code == http.StatusUnprocessableEntity && r.Fallback != nil {
+			err = forgeCall(ctx, forgeAPIBase(host), token, *r.Fallback)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forgeStatusError ist eine Antwort der API außerhalb von 2xx.
+type forgeStatusError struct {
+	code    int
+	request string
+	message string
+}
+
+func (e forgeStatusError) Error() string {
+	return fmt.Sprintf("%s: forge antwortet mit %d: %s", e.request, e.code, e.message)
+}
+
+func forgeCall(ctx context.Context, base, token string, r forgeRequest) error {
+	return forgeDo(ctx, base, token, r, nil)
+}
+
+// forgeDo führt einen API-Aufruf aus und liest die Antwort nach out, sofern gesetzt.
+func forgeDo(ctx context.Context, base, token string, r forgeRequest, out any) error {
+	var body io.Reader
+	if r.Method != http.MethodGet {
+		content, err := json.Marshal(r.Body)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(content)
+	}
+	req, err := http.NewRequestWithContext(ctx, r.Method, base+r.Path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: forge nicht erreichbar: %v", errNetwork, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		if out != nil {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("%s %s: ungültige antwort: %v", r.Method, r.Path, err)
+			}
+		}
+		return nil
+	}
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	json.NewDecoder(resp.Body).Decode(&apiErr)
+	return forgeStatusError{code: resp.StatusCode, request: r.Method + " " + r.Path, message: apiErr.Message}
+}
+
+// setupForge definiert `newpipi forge`, das die Richtlinie nachträglich anwendet.
+func setupForge(fs *flag.FlagSet) func(args []string) int {
+	profile := fs.String("profile", "", "Profil mit forgePolicy (Standard: aktives Profil)")
+	dryRun := fs.Bool("dry-run", false, "API-Aufrufe nur anzeigen")
+	return func(args []string) int {
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi forge [--profile NAME] [--dry-run] [VERZEICHNIS]")
+			return exitUsage
+		}
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		ps := NewProjectSetup()
+		if *profile != "" {
+			if err := ps.SelectProfile(*profile); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitUsage
+			}
+		}
+		if ps.Profile().ForgePolicy == "" {
+			fmt.Fprintln(os.Stderr, "Fehler: profil hat keine forgePolicy")
+			return exitUsage
+		}
+		if err := ps.applyForgePolicy(dir, *dryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return ExitCodeFor(err)
+		}
+		return ExitOK
+	}
+}
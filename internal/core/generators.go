@@ -0,0 +1,317 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Externe Generatoren: Ein Template mit "generator" legt das Projekt nicht
+// mit dem Grundgerüst des Projekttyps an, sondern mit einem bekannten
+// Werkzeug – start.spring.io, der Quarkus-CLI, dotnet new oder
+// create-next-app. Die Optionen des Templates erscheinen wie sonst im
+// Assistenten bzw. als -option und gehen, mit text/template gerendert, als
+// "generatorArgs" an den Generator. Danach läuft die übliche Pipeline weiter:
+// Template-Dateien und -Befehle, Lizenz, CI-Dateien der Richtlinie, Add-ons
+// und Git.
+
+// generatorTimeout begrenzt den Download von Archiven wie start.spring.io.
+const generatorTimeout = 2 * time.Minute
+
+// externalGenerator beschreibt einen Generator.
+type externalGenerator struct {
+	Label     string
+	Toolchain []toolCheck
+	// Init läuft im Elternverzeichnis und legt das Projektverzeichnis an;
+	// das Argument "{args}" steht für die gerenderten generatorArgs.
+	Init []TemplateCommand
+	// Archive ist stattdessen die Adresse eines ZIP-Archivs, das ins
+	// Projektverzeichnis entpackt wird. Query und die generatorArgs, jeweils
+	// NAME=WERT, bilden die Query; Parameter ohne Wert entfallen.
+	Archive string
+	Query   []string
+}
+
+var externalGenerators = map[string]*externalGenerator{
+	"spring": {
+		Label:     "Spring Initializr",
+		Toolchain: []toolCheck{{[]string{"java", "-version"}, "Java"}},
+		Archive:   "https://start.spring.io/starter.zip",
+		Query:     []string{"artifactId={name}", "name={name}"},
+	},
+	"quarkus": {
+		Label: "Quarkus CLI",
+		Toolchain: []toolCheck{
+			{[]string{"quarkus", "--version"}, "quarkus"},
+			{[]string{"java", "-version"}, "Java"},
+		},
+		Init: []TemplateCommand{
+			{Run: []string{"quarkus", "create", "app", "{args}"}, Step: "Erstelle Quarkus-Projekt...", Network: true},
+		},
+	},
+	"dotnet": {
+		Label:     "dotnet new",
+		Toolchain: []toolCheck{{[]string{"dotnet", "--version"}, ".NET SDK"}},
+		Init: []TemplateCommand{
+			{Run: []string{"dotnet", "new", "{args}", "-n", "{name}", "-o", "{name}"}, Step: "Erstelle .NET-Projekt...", Network: true},
+		},
+	},
+	"next": {
+		Label: "create-next-app",
+		Toolchain: []toolCheck{
+			{[]string{"node", "--version"}, "node.js"},
+			{[]string{"npx", "--version"}, "npx"},
+		},
+		// Das Repository legt newpipi selbst an
+		Init: []TemplateCommand{
+			{Run: []string{"npx", "--yes", "create-next-app@latest", "{name}", "{args}", "--use-npm", "--disable-git", "--yes"}, Step: "Erstelle Next.js-Projekt...", Network: true},
+		},
+	},
+}
+
+// generatorNames liefert die bekannten Generatoren, sortiert.
+func generatorNames() []string {
+	names := make([]string, 0, len(externalGenerators))
+	for name := range externalGenerators {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// findGenerator liefert den Generator eines Templates.
+func findGenerator(name string) (*externalGenerator, error) {
+	if g, ok := externalGenerators[name]; ok {
+		return g, nil
+	}
+	return nil, fmt.Errorf("unbekannter generator %q, bekannt sind %s", name, strings.Join(generatorNames(), ", "))
+}
+
+// javaBuildCheck baut und testet mit dem Wrapper, den der Generator anlegt.
+const javaBuildCheck = "if [ -x mvnw ]; then ./mvnw -B verify; else ./gradlew build; fi"
+
+// generatorTemplates sind die eingebauten Templates für die Generatoren.
+var generatorTemplates = []Template{
+	{
+		Name:          "Spring Boot",
+		Description:   "Spring-Boot-Anwendung von start.spring.io",
+		Type:          Java,
+		Generator:     "spring",
+		GeneratorArgs: []string{"type={{.build}}", "language={{.language}}", "javaVersion={{.javaVersion}}", "groupId={{.groupId}}", "dependencies={{.dependencies}}"},
+		Options: []TemplateOption{
+			{Name: "build", Label: "Build", Type: "choice", Choices: []string{"maven-project", "gradle-project", "gradle-project-kotlin"}},
+			{Name: "language", Label: "Language", Type: "choice", Choices: []string{"java", "kotlin", "groovy"}},
+			{Name: "javaVersion", Label: "Java version", Type: "choice", Choices: []string{"21", "17", "24"}},
+			{Name: "groupId", Label: "Group ID", Type: "string", Default: "com.example", Required: true},
+			{Name: "dependencies", Label: "Dependencies (comma-separated)", Type: "string", Default: "web"},
+		},
+		Run:   "if [ -x mvnw ]; then ./mvnw spring-boot:run; else ./gradlew bootRun; fi",
+		Check: javaBuildCheck,
+	},
+	{
+		Name:          "Quarkus",
+		Description:   "Quarkus-Anwendung mit quarkus create",
+		Type:          Java,
+		Generator:     "quarkus",
+		GeneratorArgs: []string{"{{.groupId}}:{{.ProjectName}}", "--{{.build}}", "{{with .extensions}}--extension={{.}}{{end}}"},
+		Options: []TemplateOption{
+			{Name: "build", Label: "Build", Type: "choice", Choices: []string{"maven", "gradle", "gradle-kotlin-dsl"}},
+			{Name: "groupId", Label: "Group ID", Type: "string", Default: "org.acme", Required: true},
+			{Name: "extensions", Label: "Extensions (comma-separated)", Type: "string", Default: "rest"},
+		},
+		Run:   "quarkus dev",
+		Check: javaBuildCheck,
+	},
+	{
+		Name:          ".NET",
+		Description:   "Beliebige Vorlage von dotnet new, etwa webapi, blazor oder classlib",
+		Type:          CSharp,
+		Generator:     "dotnet",
+		GeneratorArgs: []string{"{{.template}}", "{{with .framework}}--framework={{.}}{{end}}"},
+		Options: []TemplateOption{
+			{Name: "template", Label: "Template (dotnet new list)", Type: "string", Default: "console", Required: true},
+			{Name: "framework", Label: "Target framework, e.g. net8.0", Type: "string"},
+		},
+		Commands: []TemplateCommand{
+			{Run: []string{"dotnet", "new", "gitignore"}, Step: "Erstelle .gitignore...", AllowFailure: true},
+		},
+		Run:   "dotnet run",
+		Check: "dotnet build",
+	},
+	{
+		Name:        "Next.js",
+		Description: "Next.js-Anwendung mit create-next-app",
+		Type:        TypeScript,
+		Generator:   "next",
+		GeneratorArgs: []string{
+			`{{if eq .typescript "true"}}--ts{{else}}--js{{end}}`,
+			`{{if eq .tailwind "true"}}--tailwind{{else}}--no-tailwind{{end}}`,
+			`{{if eq .eslint "true"}}--eslint{{else}}--no-eslint{{end}}`,
+			`{{if eq .app "true"}}--app{{else}}--no-app{{end}}`,
+			`{{if eq .srcDir "true"}}--src-dir{{else}}--no-src-dir{{end}}`,
+		},
+		Options: []TemplateOption{
+			{Name: "typescript", Label: "TypeScript", Type: "bool", Default: "true"},
+			{Name: "tailwind", Label: "Tailwind CSS", Type: "bool", Default: "true"},
+			{Name: "eslint", Label: "ESLint", Type: "bool", Default: "true"},
+			{Name: "app", Label: "App Router", Type: "bool", Default: "true"},
+			{Name: "srcDir", Label: "src/ directory", Type: "bool"},
+		},
+		Run:   "npm run dev",
+		Check: "npm run build",
+	},
+}
+
+// generatorArgs rendert die generatorArgs des Templates mit den Optionen;
+// leere Argumente entfallen.
+func (ps *ProjectSetup) generatorArgs() ([]string, error) {
+	data := &scriptResult{vars: ps.templateVars(), text: ps.text}
+	var args []string
+	for i, arg := range ps.Template.GeneratorArgs {
+		rendered, err := data.render(fmt.Sprintf("generatorArgs[%d]", i), arg)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(rendered) != "" {
+			args = append(args, rendered)
+		}
+	}
+	return args, nil
+}
+
+// runGenerator legt das Projektverzeichnis mit dem Generator des Templates an.
+func (ps *ProjectSetup) runGenerator() error {
+	g, err := findGenerator(ps.Template.Generator)
+	if err != nil {
+		return err
+	}
+	options, err := ps.Template.ResolveOptions(ps.Options)
+	if err != nil {
+		return err
+	}
+	ps.Options = options
+	args, err := ps.generatorArgs()
+	if err != nil {
+		return err
+	}
+	ps.step(fmt.Sprintf("Erstelle %s-Projekt mit %s...", ps.ProjectType, g.Label))
+	if g.Archive != "" {
+		return ps.fetchGeneratorArchive(g, args)
+	}
+	return ps.runCommands(ps.ParentPath, expandArgs(g.Init, args), false)
+}
+
+// expandArgs setzt die Argumente für das Argument "{args}" ein.
+func expandArgs(commands []TemplateCommand, args []string) []TemplateCommand {
+	out := make([]TemplateCommand, len(commands))
+	for i, c := range commands {
+		var run []string
+		for _, arg := range c.Run {
+			if arg == "{args}" {
+				run = append(run, args...)
+			} else {
+				run = append(run, arg)
+			}
+		}
+		c.Run = run
+		out[i] = c
+	}
+	return out
+}
+
+// generatorURL setzt die Adresse des Archivs mit seiner Query zusammen.
+func (ps *ProjectSetup) generatorURL(g *externalGenerator, args []string) (string, error) {
+	u, err := url.Parse(g.Archive)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for _, param := range slices.Concat(g.Query, args) {
+		name, value, ok := strings.Cut(strings.ReplaceAll(param, "{name}", ps.ProjectName), "=")
+		if !ok {
+			return "", fmt.Errorf("generator-argument %q ist nicht NAME=WERT", param)
+		}
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// fetchGeneratorArchive lädt das Archiv und entpackt es ins Projektverzeichnis.
+func (ps *ProjectSetup) fetchGeneratorArchive(g *externalGenerator, args []string) error {
+	rawURL, err := ps.generatorURL(g, args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), generatorTimeout)
+	defer cancel()
+	ps.commandLog = append(ps.commandLog, "GET "+rawURL)
+	data, err := fetchURL(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	return unpackZip(data, projectDir, ps.Settings.Modes())
+}
+
+// unpackZip entpackt ein Archiv nach dir; Einträge außerhalb von dir und
+// Symlinks werden abgelehnt, Ausführrechte bleiben erhalten.
+func unpackZip(data []byte, dir string, modes projectModes) error {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("archiv ungültig: %v", err)
+	}
+	if err := modes.mkdirAll(dir); err != nil {
+		return fmt.Errorf("projektverzeichnis erstellen fehlgeschlagen: %v", err)
+	}
+	var total int64
+	for _, f := range zr.File {
+		name := filepath.FromSlash(f.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("archiv: %q liegt außerhalb des projekts", f.Name)
+		}
+		target := filepath.Join(dir, name)
+		if f.FileInfo().IsDir() {
+			if err := modes.mkdirAll(target); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			return fmt.Errorf("archiv: %s ist keine reguläre datei", f.Name)
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		// Die Größenangaben im Zip sind nicht vertrauenswürdig
+		content, err := io.ReadAll(io.LimitReader(rc, archiveMaxSize-total+1))
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("archiv: %s lesen fehlgeschlagen: %v", f.Name, err)
+		}
+		if total += int64(len(content)); total > archiveMaxSize {
+			return fmt.Errorf("archiv ist entpackt größer als %d MB", archiveMaxSize>>20)
+		}
+		if err := modes.mkdirAll(filepath.Dir(target)); err != nil {
+			return err
+		}
+		mode := modes.File
+		if f.Mode()&0111 != 0 {
+			mode |= 0111
+		}
+		if err := modes.writeFile(target, content, mode); err != nil {
+			return fmt.Errorf("datei %s erstellen fehlgeschlagen: %v", f.Name, err)
+		}
+	}
+	return nil
+}
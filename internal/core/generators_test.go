@@ -0,0 +1,99 @@
+package core
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGeneratorArgs(t *testing.T) {
+	tests := []struct {
+		template string
+		options  map[string]string
+		want     []string
+	}{
+		{".NET", nil, []string{"console"}},
+		{".NET", map[string]string{"template": "webapi", "framework": "net8.0"}, []string{"webapi", "--framework=net8.0"}},
+		{"Quarkus", map[string]string{"extensions": ""}, []string{"org.acme:demo", "--maven"}},
+		{"Next.js", map[string]string{"tailwind": "false"}, []string{"--ts", "--no-tailwind", "--eslint", "--app", "--no-src-dir"}},
+	}
+	for _, tt := range tests {
+		i := slices.IndexFunc(generatorTemplates, func(g Template) bool { return g.Name == tt.template })
+		ps := &ProjectSetup{ProjectName: "demo", Template: &generatorTemplates[i]}
+		options, err := ps.Template.ResolveOptions(tt.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ps.Options = options
+		got, err := ps.generatorArgs()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.template, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s %v: args = %q, want %q", tt.template, tt.options, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratorURL(t *testing.T) {
+	ps := &ProjectSetup{ProjectName: "demo"}
+	got, err := ps.generatorURL(externalGenerators["spring"], []string{"dependencies=web,actuator", "bootVersion="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://start.spring.io/starter.zip?artifactId=demo&dependencies=web%2Cactuator&name=demo"
+	if got != want {
+		t.Errorf("url = %s, want %s", got, want)
+	}
+	if _, err := ps.generatorURL(externalGenerators["spring"], []string{"web"}); err == nil {
+		t.Error("argument ohne = angenommen")
+	}
+}
+
+func TestUnpackZip(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]os.FileMode
+		wantErr bool
+	}{
+		{name: "ok", files: map[string]os.FileMode{"pom.xml": 0644, "mvnw": 0755, "src/main/App.java": 0644}},
+		{name: "outside", files: map[string]os.FileMode{"../evil": 0644}, wantErr: true},
+		{name: "absolute", files: map[string]os.FileMode{"/etc/evil": 0644}, wantErr: true},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, mode := range tt.files {
+			h := &zip.FileHeader{Name: name, Method: zip.Deflate}
+			h.SetMode(mode)
+			w, err := zw.CreateHeader(h)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w.Write([]byte(name))
+		}
+		zw.Close()
+		dir := filepath.Join(t.TempDir(), "demo")
+		err := unpackZip(buf.Bytes(), dir, defaultModes)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		for name, mode := range tt.files {
+			info, err := os.Stat(filepath.Join(dir, name))
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+				continue
+			}
+			if exec := info.Mode()&0100 != 0; exec != (mode&0100 != 0) {
+				t.Errorf("%s: %s ausführbar = %v", tt.name, name, exec)
+			}
+		}
+	}
+}
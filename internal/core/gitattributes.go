@@ -0,0 +1,104 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Mit Git legt newpipi eine .gitattributes an: einheitliche Zeilenenden,
+// Binärdateien ohne Textdiff, die Regeln des Projekttyps (language.Attributes)
+// und LFS-Regeln für große Binärformate, auskommentiert ohne Git LFS (siehe
+// lfs.go). Eine .gitattributes aus dem Template hat Vorrang.
+
+// lineEndingAttributes normalisieren Zeilenenden; Windows-Skripte behalten CRLF.
+var lineEndingAttributes = []string{
+	"* text=auto eol=lf",
+	"*.bat text eol=crlf",
+	"*.cmd text eol=crlf",
+	"*.ps1 text eol=crlf",
+}
+
+// binaryPatterns sind übliche Binärformate, die git nicht als Text behandeln soll.
+var binaryPatterns = []string{
+	"*.png", "*.jpg", "*.jpeg", "*.gif", "*.ico", "*.webp",
+	"*.pdf", "*.zip", "*.gz", "*.woff", "*.woff2", "*.ttf", "*.otf",
+}
+
+// lfsPatterns sind große Binärformate aus Spiele- und ML-Projekten, die in
+// Git LFS gehören.
+var lfsPatterns = []string{
+	"*.psd", "*.blend", "*.fbx", "*.wav", "*.mp4",
+	"*.onnx", "*.pt", "*.h5", "*.ckpt", "*.safetensors",
+}
+
+// lfsAttribute ist die Regel, mit der git lfs track ein Muster einträgt.
+func lfsAttribute(pattern string) string {
+	return pattern + " filter=lfs diff=lfs merge=lfs -text"
+}
+
+// gitAttributes liefert den Inhalt der .gitattributes für den Projekttyp.
+func (ps *ProjectSetup) gitAttributes() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n# %s\n", ps.text("Generated by newpipi"), ps.text("Line endings"))
+	for _, line := range lineEndingAttributes {
+		b.WriteString(line + "\n")
+	}
+	fmt.Fprintf(&b, "\n# %s\n", ps.text("Binary files"))
+	for _, pattern := range binaryPatterns {
+		b.WriteString(pattern + " binary\n")
+	}
+	if lang := ps.Language(); lang != nil && len(lang.Attributes) > 0 {
+		fmt.Fprintf(&b, "\n# %s\n", lang.Name)
+		for _, line := range lang.Attributes {
+			b.WriteString(line + "\n")
+		}
+	}
+	if tracked := ps.lfsTracked(); tracked != nil {
+		b.WriteString("\n# Git LFS\n")
+		for _, pattern := range tracked {
+			b.WriteString(lfsAttribute(pattern) + "\n")
+		}
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n# %s\n", ps.text("Large binary files, active with Git LFS (git lfs track)"))
+	for _, pattern := range lfsPatterns {
+		b.WriteString("# " + lfsAttribute(pattern) + "\n")
+	}
+	return b.String()
+}
+
+// writeGitAttributes legt die .gitattributes an, sofern das Template keine
+// mitbringt; einer vorhandenen werden nur fehlende LFS-Regeln angehängt.
+func (ps *ProjectSetup) writeGitAttributes(projectDir string) error {
+	path := filepath.Join(projectDir, ".gitattributes")
+	if content, err := os.ReadFile(path); err == nil {
+		log.Println(".gitattributes aus dem Template bleibt erhalten")
+		var missing []string
+		for _, pattern := range ps.lfsTracked() {
+			if !slices.Contains(strings.Split(string(content), "\n"), lfsAttribute(pattern)) {
+				missing = append(missing, lfsAttribute(pattern))
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		if !strings.HasSuffix(string(content), "\n") {
+			content = append(content, '\n')
+		}
+		content = append(content, strings.Join(missing, "\n")+"\n"...)
+		if err := os.WriteFile(path, content, ps.Settings.Modes().File); err != nil {
+			return fmt.Errorf(".gitattributes ergänzen fehlgeschlagen: %v", err)
+		}
+		return nil
+	}
+	ps.step("Lege .gitattributes an...")
+	modes := ps.Settings.Modes()
+	if err := modes.writeFile(path, []byte(ps.gitAttributes()), modes.File); err != nil {
+		return fmt.Errorf(".gitattributes schreiben fehlgeschlagen: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Native Git-Hooks ersetzen husky und ähnliche Werkzeuge: newpipi legt
+// Shell-Skripte unter .githooks/ an und setzt core.hooksPath darauf, sodass
+// die Hooks mit dem Projekt eingecheckt sind. Die Skripte entstehen aus
+// Hook-Templates (text/template); eine Datei <config>/hooks/<hook> ersetzt
+// das eingebaute Template oder ergänzt einen weiteren Hook wie pre-push.
+
+const gitHooksDir = ".githooks"
+
+// commitSubjectLength ist die maximale Länge der Betreffzeile im commit-msg-Hook.
+const commitSubjectLength = 72
+
+// formatCheck prüft die Formatierung im pre-commit-Hook. Run endet mit einem
+// Fehler, wenn Dateien nicht formatiert sind; fehlt Tool, wird nicht geprüft.
+type formatCheck struct {
+	Tool string `json:"tool"`
+	Run  string `json:"run"`
+}
+
+// hookData sind die Werte für Hook-Templates.
+type hookData struct {
+	ProjectName string
+	Format      formatCheck
+	// CommitPattern ist ein erweiterter regulärer Ausdruck (grep -E) für die Betreffzeile.
+	CommitPattern string
+	SubjectLength int
+}
+
+var builtinHooks = map[string]string{
+	"pre-commit": `#!/bin/sh
+# {{t "Generated by newpipi"}}: {{t "checks the formatting before every commit."}}
+# {{t "Locally installed tools (npm, venv) take precedence."}}
+PATH="$PWD/node_modules/.bin:$PWD/venv/bin:$PATH"
+if ! command -v {{.Format.Tool}} >/dev/null 2>&1; then
+	echo "pre-commit: {{t "%s not found, formatting not checked" .Format.Tool}}" >&2
+	exit 0
+fi
+if ! {{.Format.Run}}; then
+	echo "pre-commit: {{t "formatting is off, checked with:"}}" {{shellQuote .Format.Run}} >&2
+	exit 1
+fi
+`,
+	"commit-msg": `#!/bin/sh
+# {{t "Generated by newpipi"}}: {{t "checks the subject line of the commit message."}}
+subject=$(grep -v '^#' "$1" | head -n 1)
+if [ -z "$subject" ]; then
+	echo "commit-msg: {{t "subject line missing"}}" >&2
+	exit 1
+fi
+if [ "$(printf '%s' "$subject" | wc -m)" -gt {{.SubjectLength}} ]; then
+	echo "commit-msg: {{t "subject line longer than %d characters" .SubjectLength}}" >&2
+	exit 1
+fi
+{{- if .CommitPattern}}
+if ! printf '%s\n' "$subject" | grep -Eq {{shellQuote .CommitPattern}}; then
+	echo "commit-msg: {{t "subject line does not match"}}" {{shellQuote .CommitPattern}} >&2
+	exit 1
+fi
+{{- end}}
+`,
+}
+
+func hooksDir() string {
+	dir, err := configDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "hooks")
+}
+
+// hookTemplates liefert die Hook-Templates nach Hook-Name, eigene vor eingebauten.
+func hookTemplates() map[string]string {
+	hooks := maps.Clone(builtinHooks)
+	entries, err := os.ReadDir(hooksDir())
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("Hook-Templates lesen fehlgeschlagen: %v", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(hooksDir(), e.Name()))
+		if err != nil {
+			log.Printf("Hook-Template %s lesen fehlgeschlagen: %v", e.Name(), err)
+			continue
+		}
+		hooks[e.Name()] = string(content)
+	}
+	return hooks
+}
+
+// writeGitHooks rendert die Hook-Templates nach .githooks/. Der eingebaute
+// pre-commit-Hook entfällt bei Projekttypen ohne Formatierungsprüfung.
+func (ps *ProjectSetup) writeGitHooks(projectDir string) error {
+	ps.step("Lege Git-Hooks an...")
+	data := hookData{ProjectName: ps.ProjectName, CommitPattern: ps.Settings.CommitPattern, SubjectLength: commitSubjectLength}
+	if lang := ps.Language(); lang != nil && lang.Format != nil {
+		data.Format = *lang.Format
+	}
+	hooks := hookTemplates()
+	modes := ps.Settings.Modes()
+	for _, name := range slices.Sorted(maps.Keys(hooks)) {
+		content := hooks[name]
+		if content == builtinHooks["pre-commit"] && data.Format.Run == "" {
+			continue
+		}
+		tmpl, err := template.New(name).Funcs(template.FuncMap{"shellQuote": shellQuote, "t": ps.text}).Option("missingkey=error").Parse(content)
+		if err != nil {
+			return fmt.Errorf("hook-template %s parsen fehlgeschlagen: %v", name, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return fmt.Errorf("hook-template %s rendern fehlgeschlagen: %v", name, err)
+		}
+		if err := modes.writeFile(filepath.Join(projectDir, gitHooksDir, name), []byte(b.String()), modes.executable()); err != nil {
+			return fmt.Errorf("hook %s schreiben fehlgeschlagen: %v", name, err)
+		}
+	}
+	// git lfs install legt seine Hooks nicht in core.hooksPath an (siehe lfs.go)
+	if ps.lfsTracked() == nil {
+		return nil
+	}
+	for _, name := range lfsHooks {
+		if _, ok := hooks[name]; ok {
+			log.Printf("Eigener Hook %s ersetzt den LFS-Hook, dort git lfs %s aufrufen", name, name)
+			continue
+		}
+		if err := modes.writeFile(filepath.Join(projectDir, gitHooksDir, name), []byte(fmt.Sprintf(lfsHook, name, ps.text("Generated by newpipi"), ps.text("This repository uses Git LFS, but git-lfs is not installed."))), modes.executable()); err != nil {
+			return fmt.Errorf("hook %s schreiben fehlgeschlagen: %v", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Template-Repositorys von GitHub: `newpipi github list` zeigt die eigenen
+// Repositorys mit der Einstellung „Template repository“, `newpipi github
+// create` erzeugt daraus über die API ein neues Repository, klont es in den
+// Projektpfad und führt nach Rückfrage das erkannte Bootstrap aus. Angemeldet
+// wird mit dem Forge-Token des Profils (newpipi secret set --forge).
+
+const GithubHost = "github.com"
+
+// GitHub legt den Inhalt eines erzeugten Repositorys im Hintergrund an; der
+// Klon wird daher einige Male wiederholt.
+const (
+	cloneRetries = 5
+	cloneDelay   = 2 * time.Second
+)
+
+// templateRepo ist ein Repository aus der GitHub-API.
+type templateRepo struct {
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	Private     bool   `json:"private"`
+	IsTemplate  bool   `json:"is_template"`
+	CloneURL    string `json:"clone_url"`
+	HTMLURL     string `json:"html_url"`
+}
+
+// GithubToken liefert das Forge-Token des Profils für host oder einen Fehler
+// mit dem Hinweis, wie es sich ablegen lässt.
+func (ps *ProjectSetup) GithubToken(host string) (string, error) {
+	token, err := ps.forgeToken(host)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		profile := ps.ProfileName
+		if profile == "" {
+			profile = "NAME"
+		}
+		return "", fmt.Errorf("kein forge-token für %s: newpipi secret set --profile %s --forge %s", host, profile, host)
+	}
+	return token, nil
+}
+
+// ListTemplateRepos liefert die Template-Repositorys des angemeldeten
+// Benutzers und seiner Organisationen.
+func ListTemplateRepos(ctx context.Context, host, token string) ([]templateRepo, error) {
+	const perPage = 100
+	var templates []templateRepo
+	for page := 1; page <= 10; page++ {
+		var repos []templateRepo
+		r := forgeRequest{Method: http.MethodGet, Path: fmt.Sprintf("/user/repos?affiliation=owner,organization_member&per_page=%d&page=%d", perPage, page)}
+		if err := forgeDo(ctx, forgeAPIBase(host), token, r, &repos); err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			if repo.IsTemplate {
+				templates = append(templates, repo)
+			}
+		}
+		if len(repos) < perPage {
+			break
+		}
+	}
+	return templates, nil
+}
+
+// generateRepo erzeugt aus template ("besitzer/name") das Repository name;
+// ohne owner gehört es dem angemeldeten Benutzer.
+func generateRepo(ctx context.Context, host, token, template, owner, name string, private bool) (templateRepo, error) {
+	templateOwner, templateName, ok := strings.Cut(template, "/")
+	if !ok || templateOwner == "" || templateName == "" || strings.Contains(templateName, "/") {
+		return templateRepo{}, fmt.Errorf("template-repository %q ist nicht besitzer/name", template)
+	}
+	body := map[string]any{"name": name, "private": private}
+	if owner != "" {
+		body["owner"] = owner
+	}
+	var repo templateRepo
+	r := forgeRequest{
+		Method: http.MethodPost,
+		Path:   "/repos/" + url.PathEscape(templateOwner) + "/" + url.PathEscape(templateName) + "/generate",
+		Body:   body,
+	}
+	if err := forgeDo(ctx, forgeAPIBase(host), token, r, &repo); err != nil {
+		return templateRepo{}, err
+	}
+	return repo, nil
+}
+
+// CreateFromTemplateRepo erzeugt aus template ein Repository namens
+// ps.ProjectName, klont es nach ps.ParentPath und liefert das neue Repository.
+func (ps *ProjectSetup) CreateFromTemplateRepo(host, template, owner string, private bool) (templateRepo, error) {
+	if valid, msg := IsValidProjectName(ps.ProjectName); !valid {
+		return templateRepo{}, errors.New(msg)
+	}
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	if _, err := os.Lstat(projectDir); err == nil {
+		return templateRepo{}, fmt.Errorf("%s existiert bereits", projectDir)
+	}
+	token, err := ps.GithubToken(host)
+	if err != nil {
+		return templateRepo{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ForgeTimeout)
+	defer cancel()
+	ps.step(fmt.Sprintf("Erzeuge Repository aus %s...", template))
+	repo, err := generateRepo(ctx, host, token, template, owner, ps.ProjectName, private)
+	if err != nil {
+		return templateRepo{}, err
+	}
+	ps.step(fmt.Sprintf("Klone %s...", repo.FullName))
+	for attempt := 1; ; attempt++ {
+		clone := ps.command("git", "clone", repo.CloneURL, projectDir)
+		withGitToken(clone, token)
+		out, err := clone.CombinedOutput()
+		if err == nil {
+			break
+		}
+		os.RemoveAll(projectDir)
+		if attempt == cloneRetries {
+			// Das Repository bleibt auf GitHub bestehen; undo kennt es nicht
+			return repo, fmt.Errorf("git clone fehlgeschlagen: %v: %s\nrepository %s ist angelegt, aber nicht geklont: erneut klonen oder von hand löschen (%s)", err, strings.TrimSpace(string(out)), repo.FullName, repo.HTMLURL)
+		}
+		time.Sleep(cloneDelay)
+	}
+	if err := recordRecent(projectDir); err != nil {
+		log.Printf("Zuletzt erstellte Projekte speichern fehlgeschlagen: %v", err)
+	}
+	return repo, nil
+}
+
+// bootstrapScripts sind die üblichen Einstiegspunkte eines Repositorys, in
+// dieser Reihenfolge; das erste vorhandene gewinnt.
+var bootstrapScripts = []string{"script/bootstrap", "script/setup", "bootstrap.sh", "setup.sh"}
+
+// makeTarget findet die Ziele bootstrap und setup eines Makefiles.
+var makeTarget = regexp.MustCompile(`(?m)^(bootstrap|setup)\s*:`)
+
+// DetectBootstrap liefert die Befehle, die ein geklontes Repository
+// einrichten: ein Bootstrap-Skript, sonst ein Makefile-Ziel, sonst die
+// Installation der Abhängigkeiten (siehe dependencyInstallers).
+func DetectBootstrap(dir string) []string {
+	for _, script := range bootstrapScripts {
+		if info, err := os.Stat(filepath.Join(dir, script)); err == nil && info.Mode().IsRegular() {
+			if info.Mode()&0111 != 0 {
+				return []string{"./" + script}
+			}
+			return []string{"sh " + script}
+		}
+	}
+	if content, err := os.ReadFile(filepath.Join(dir, "Makefile")); err == nil {
+		if m := makeTarget.FindSubmatch(content); m != nil {
+			return []string{"make " + string(m[1])}
+		}
+	}
+	var commands []string
+	for _, inst := range dependencyInstallers {
+		if _, err := os.Stat(filepath.Join(dir, inst.Marker)); err == nil {
+			commands = append(commands, inst.Command)
+		}
+	}
+	return commands
+}
+
+// RunBootstrap führt die Befehle wie Hooks aus: im Projekt, mit Zeitlimit und
+// ohne Tokens in der Umgebung.
+func (ps *ProjectSetup) RunBootstrap(projectDir string, commands []string) error {
+	steps := make([]TemplateCommand, len(commands))
+	for i, c := range commands {
+		steps[i] = TemplateCommand{Run: []string{"sh", "-c", c}, Step: "Bootstrap: " + c}
+	}
+	return ps.runCommands(projectDir, steps, true)
+}
+
+// setupGitHub definiert `newpipi github list` und `newpipi github create TEMPLATE NAME`.
+func setupGitHub(fs *flag.FlagSet) func(args []string) int {
+	host := fs.String("host", GithubHost, "GitHub-Host, etwa für GitHub Enterprise")
+	profile := fs.String("profile", "", "Profil mit dem Forge-Token (Standard: aktives Profil)")
+	owner := fs.String("owner", "", "Besitzer des neuen Repositorys, etwa eine Organisation (Standard: angemeldeter Benutzer)")
+	private := fs.Bool("private", false, "Neues Repository privat anlegen")
+	parentPath := fs.String("path", "", "Elternverzeichnis des Klons (Standard: gespeicherter Projektpfad)")
+	yes := fs.Bool("yes", false, "Erkanntes Bootstrap ohne Rückfrage ausführen")
+	return func(args []string) int {
+		ps := NewProjectSetup()
+		if *profile != "" {
+			if err := ps.SelectProfile(*profile); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitUsage
+			}
+		}
+		switch {
+		case len(args) == 1 && args[0] == "list":
+			token, err := ps.GithubToken(*host)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitUsage
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), ForgeTimeout)
+			defer cancel()
+			repos, err := ListTemplateRepos(ctx, *host, token)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return ExitCodeFor(err)
+			}
+			for _, repo := range repos {
+				fmt.Printf("%-40s %s\n", repo.FullName, repo.Description)
+			}
+			return ExitOK
+		case len(args) == 3 && args[0] == "create":
+			ps.ProjectName = args[2]
+			if *parentPath != "" {
+				ps.ParentPath = *parentPath
+			}
+			repo, err := ps.CreateFromTemplateRepo(*host, args[1], *owner, *private)
+			if repo.HTMLURL != "" {
+				fmt.Printf("Repository: %s\n", repo.HTMLURL)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return ExitCodeFor(err)
+			}
+			projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+			if commands := DetectBootstrap(projectDir); len(commands) > 0 {
+				run := *yes
+				if !run && term.IsTerminal(int(os.Stdin.Fd())) {
+					fmt.Fprintf(os.Stderr, "Bootstrap ausführen?\n  %s\n[j/N] ", strings.Join(commands, "\n  "))
+					answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+					a := strings.ToLower(strings.TrimSpace(answer))
+					run = a == "j" || a == "y"
+				}
+				if !run {
+					fmt.Fprintf(os.Stderr, "Bootstrap übersprungen: %s\n", strings.Join(commands, "; "))
+				} else if err := ps.RunBootstrap(projectDir, commands); err != nil {
+					fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+					return ExitCodeFor(err)
+				}
+			}
+			fmt.Println(projectDir)
+			return ExitOK
+		}
+		fmt.Fprintln(os.Stderr, "Verwendung: newpipi github list | newpipi github [-owner ORG] [-private] [-path DIR] [-yes] create BESITZER/TEMPLATE NAME")
+		return exitUsage
+	}
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDetectBootstrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]os.FileMode
+		make  string
+		want  []string
+	}{
+		{name: "executable script", files: map[string]os.FileMode{"script/bootstrap": 0755, "go.mod": 0644}, want: []string{"./script/bootstrap"}},
+		{name: "plain script", files: map[string]os.FileMode{"setup.sh": 0644}, want: []string{"sh setup.sh"}},
+		{name: "make target", files: map[string]os.FileMode{"package.json": 0644}, make: "build:\n\tgo build\nsetup: deps\n\tnpm ci\n", want: []string{"make setup"}},
+		{name: "make without target", make: "build:\n\tgo build\n", files: map[string]os.FileMode{"go.mod": 0644}, want: []string{"go mod download"}},
+		{name: "dependencies", files: map[string]os.FileMode{"go.mod": 0644, "package.json": 0644}, want: []string{"go mod download", "npm install"}},
+		{name: "nothing", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for path, mode := range tt.files {
+				full := filepath.Join(dir, path)
+				if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(full, nil, mode); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.make != "" {
+				os.WriteFile(filepath.Join(dir, "Makefile"), []byte(tt.make), 0644)
+			}
+			if got := DetectBootstrap(dir); !slices.Equal(got, tt.want) {
+				t.Errorf("detectBootstrap = %v, erwartet %v", got, tt.want)
+			}
+		})
+	}
+}
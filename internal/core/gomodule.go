@@ -0,0 +1,95 @@
+package core
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"strings"
+)
+
+// Modulpfad von Go-Projekten: Ohne Angabe (-module, Feld „Module path“)
+// entsteht er aus Settings.GoModulePrefix und dem Projektnamen, ohne Präfix
+// ist er der Projektname. In Befehlen steht "{module}" dafür, in Dateien
+// {{.Module}}; {{.Package}} ist der daraus abgeleitete Paketname.
+
+// goModule liefert den Modulpfad des Projekts.
+func (ps *ProjectSetup) goModule() string {
+	if ps.ModulePath != "" {
+		return ps.ModulePath
+	}
+	return ps.DefaultGoModule()
+}
+
+// DefaultGoModule ist der Modulpfad ohne eigene Angabe.
+func (ps *ProjectSetup) DefaultGoModule() string {
+	if prefix := strings.Trim(ps.Settings.GoModulePrefix, "/"); prefix != "" {
+		return path.Join(prefix, ps.ProjectName)
+	}
+	return ps.ProjectName
+}
+
+// checkModulePath prüft die Zeichen eines Modulpfads grob nach den Regeln
+// von `go mod init`; die genaue Prüfung bleibt dem go-Befehl überlassen.
+func checkModulePath(module string) error {
+	if module == "" {
+		return fmt.Errorf("modulpfad darf nicht leer sein")
+	}
+	for _, elem := range strings.Split(module, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return fmt.Errorf("ungültiger modulpfad %q: leeres oder relatives element", module)
+		}
+		if elem[0] == '.' || elem[len(elem)-1] == '.' {
+			return fmt.Errorf("ungültiger modulpfad %q: element beginnt oder endet mit einem punkt", module)
+		}
+		for _, r := range elem {
+			if !isModulePathRune(r) {
+				return fmt.Errorf("ungültiger modulpfad %q: zeichen %q nicht erlaubt", module, r)
+			}
+		}
+	}
+	return nil
+}
+
+func isModulePathRune(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || strings.ContainsRune("-._~", r)
+}
+
+// goPackageName leitet aus dem Modulpfad einen Paketnamen ab: das letzte
+// Element ohne Major-Version (/v2) und ohne Zeichen, die in Bezeichnern nicht
+// vorkommen dürfen.
+func goPackageName(module string) string {
+	elems := strings.Split(module, "/")
+	last := elems[len(elems)-1]
+	if len(elems) > 1 && isMajorVersion(last) {
+		last = elems[len(elems)-2]
+	}
+	last = strings.TrimPrefix(strings.ToLower(last), "go-")
+	name := strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || '0' <= r && r <= '9' || r == '_' {
+			return r
+		}
+		return -1
+	}, last)
+	switch {
+	case name == "":
+		return "lib"
+	case name[0] >= '0' && name[0] <= '9':
+		return "lib" + name
+	case token.IsKeyword(name):
+		return name + "pkg"
+	}
+	return name
+}
+
+// isMajorVersion erkennt Elemente wie v2 am Ende eines Modulpfads.
+func isMajorVersion(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' {
+		return false
+	}
+	for _, r := range elem[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package core
+
+import "testing"
+
+func TestGoPackageName(t *testing.T) {
+	tests := []struct {
+		module string
+		want   string
+	}{
+		{"demo", "demo"},
+		{"github.com/user/my-tool", "mytool"},
+		{"github.com/user/go-yaml", "yaml"},
+		{"example.com/api/v2", "api"},
+		{"example.com/2fa", "lib2fa"},
+		{"example.com/type", "typepkg"},
+		{"example.com/---", "lib"},
+	}
+	for _, tt := range tests {
+		if got := goPackageName(tt.module); got != tt.want {
+			t.Errorf("goPackageName(%q) = %q, want %q", tt.module, got, tt.want)
+		}
+	}
+}
+
+func TestCheckModulePath(t *testing.T) {
+	tests := []struct {
+		module string
+		ok     bool
+	}{
+		{"demo", true},
+		{"github.com/user/name", true},
+		{"example.com/a/v2", true},
+		{"", false},
+		{"/abs/path", false},
+		{"github.com//name", false},
+		{"github.com/user/name/", false},
+		{"example.com/../x", false},
+		{"example.com/.hidden", false},
+		{"example.com/with space", false},
+	}
+	for _, tt := range tests {
+		if err := checkModulePath(tt.module); (err == nil) != tt.ok {
+			t.Errorf("checkModulePath(%q) = %v, want ok=%v", tt.module, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package core
+
+// Java-Buildwerkzeuge: Die Toolkits von Java wählen Maven oder Gradle (Kotlin
+// DSL) im Standardlayout src/main/java und src/test/java samt JUnit-Test,
+// dazu Spring Boot als Starter mit Web und Test für beide Werkzeuge. Den
+// Wrapper (mvnw bzw. gradlew) legt das installierte Werkzeug an, damit das
+// Projekt danach ohne globale Installation baut. javac ist das bisherige
+// Gerüst ohne Buildwerkzeug.
+
+// javaToolkits sind die Buildwerkzeuge für Java, Maven zuerst.
+var javaToolkits = []toolkit{
+	{
+		Name: "Maven",
+		Files: map[string]string{
+			"pom.xml":                     mavenPom,
+			"src/test/java/MainTest.java": javaMainTest,
+			".gitignore":                  "/target\n",
+		},
+		Toolchain: []toolCheck{mavenCheck},
+		Commands:  []TemplateCommand{mavenWrapper},
+		Run:       "./mvnw -q compile exec:java",
+		Check:     "./mvnw -B verify",
+	},
+	{
+		Name: "Gradle",
+		Files: map[string]string{
+			"settings.gradle.kts":         gradleSettings,
+			"build.gradle.kts":            gradleBuild,
+			"src/test/java/MainTest.java": javaMainTest,
+			".gitignore":                  "/.gradle\n/build\n",
+		},
+		Toolchain: []toolCheck{gradleCheck},
+		Commands:  []TemplateCommand{gradleWrapper},
+		Run:       "./gradlew run",
+		Check:     "./gradlew build",
+	},
+	springBootToolkit("Spring Boot+Maven", map[string]string{
+		"pom.xml":    springBootPom,
+		".gitignore": "/target\n",
+	}, mavenCheck, mavenWrapper, "./mvnw spring-boot:run", "./mvnw -B verify"),
+	springBootToolkit("Spring Boot+Gradle", map[string]string{
+		"settings.gradle.kts": gradleSettings,
+		"build.gradle.kts":    springBootGradle,
+		".gitignore":          "/.gradle\n/build\n",
+	}, gradleCheck, gradleWrapper, "./gradlew bootRun", "./gradlew build"),
+	// Startbefehl und Prüfung bleiben die des Projekttyps
+	{Name: "javac"},
+}
+
+var (
+	javacCheck  = toolCheck{[]string{"javac", "-version"}, "Java Development Kit"}
+	mavenCheck  = toolCheck{[]string{"mvn", "--version"}, "Maven"}
+	gradleCheck = toolCheck{[]string{"gradle", "--version"}, "Gradle"}
+
+	mavenWrapper  = TemplateCommand{Run: []string{"mvn", "-B", "-q", "wrapper:wrapper"}, Step: "Erstelle Maven-Wrapper...", Network: true}
+	gradleWrapper = TemplateCommand{Run: []string{"gradle", "-q", "wrapper"}, Step: "Erstelle Gradle-Wrapper...", Network: true}
+)
+
+// springBootToolkit ergänzt buildFiles um die Anwendung im Paket
+// com.example.app; Main.java des Projekttyps entfällt, Spring Boot verlangt
+// ein benanntes Paket.
+func springBootToolkit(name string, buildFiles map[string]string, check toolCheck, wrapper TemplateCommand, run, verify string) toolkit {
+	files := map[string]string{
+		"src/main/java/com/example/app/Application.java":      springBootApplication,
+		"src/main/java/com/example/app/HelloController.java":  springBootController,
+		"src/main/resources/application.properties":           "spring.application.name={{.ProjectName}}\n",
+		"src/test/java/com/example/app/ApplicationTests.java": springBootTest,
+	}
+	for path, content := range buildFiles {
+		files[path] = content
+	}
+	return toolkit{
+		Name:      name,
+		Files:     files,
+		Toolchain: []toolCheck{javacCheck, check},
+		Commands:  []TemplateCommand{wrapper},
+		Run:       run,
+		Check:     verify,
+		Bare:      true,
+	}
+}
+
+const (
+	mavenPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+
+  <groupId>com.example</groupId>
+  <artifactId>{{.ProjectName}}</artifactId>
+  <version>0.1.0-SNAPSHOT</version>
+
+  <properties>
+    <maven.compiler.release>21</maven.compiler.release>
+    <project.build.sourceEncoding>UTF-8</project.build.sourceEncoding>
+    <exec.mainClass>Main</exec.mainClass>
+  </properties>
+
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.junit</groupId>
+        <artifactId>junit-bom</artifactId>
+        <version>5.11.3</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+
+  <dependencies>
+    <dependency>
+      <groupId>org.junit.jupiter</groupId>
+      <artifactId>junit-jupiter</artifactId>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.5.2</version>
+      </plugin>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-jar-plugin</artifactId>
+        <version>3.4.2</version>
+        <configuration>
+          <archive>
+            <manifest>
+              <mainClass>Main</mainClass>
+            </manifest>
+          </archive>
+        </configuration>
+      </plugin>
+      <plugin>
+        <groupId>org.codehaus.mojo</groupId>
+        <artifactId>exec-maven-plugin</artifactId>
+        <version>3.5.0</version>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+
+	gradleSettings = `rootProject.name = "{{.ProjectName}}"
+`
+
+	gradleBuild = `plugins {
+    application
+}
+
+repositories {
+    mavenCentral()
+}
+
+dependencies {
+    testImplementation(platform("org.junit:junit-bom:5.11.3"))
+    testImplementation("org.junit.jupiter:junit-jupiter")
+    testRuntimeOnly("org.junit.platform:junit-platform-launcher")
+}
+
+tasks.withType<JavaCompile> {
+    options.release = 21
+}
+
+application {
+    mainClass = "Main"
+}
+
+tasks.test {
+    useJUnitPlatform()
+}
+`
+
+	javaMainTest = `import static org.junit.jupiter.api.Assertions.assertEquals;
+
+import org.junit.jupiter.api.Test;
+
+class MainTest {
+    @Test
+    void greets() {
+        assertEquals("Hello, Java!", Main.greeting("Java"));
+    }
+}
+`
+
+	springBootPom = `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0"
+         xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+         xsi:schemaLocation="http://maven.apache.org/POM/4.0.0 https://maven.apache.org/xsd/maven-4.0.0.xsd">
+  <modelVersion>4.0.0</modelVersion>
+
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>3.4.0</version>
+    <relativePath/>
+  </parent>
+
+  <groupId>com.example</groupId>
+  <artifactId>{{.ProjectName}}</artifactId>
+  <version>0.1.0-SNAPSHOT</version>
+
+  <properties>
+    <java.version>21</java.version>
+  </properties>
+
+  <dependencies>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-web</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.springframework.boot</groupId>
+      <artifactId>spring-boot-starter-test</artifactId>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.springframework.boot</groupId>
+        <artifactId>spring-boot-maven-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+
+	springBootGradle = `plugins {
+    java
+    id("org.springframework.boot") version "3.4.0"
+    id("io.spring.dependency-management") version "1.1.6"
+}
+
+group = "com.example"
+version = "0.1.0-SNAPSHOT"
+
+repositories {
+    mavenCentral()
+}
+
+tasks.withType<JavaCompile> {
+    options.release = 21
+}
+
+dependencies {
+    implementation("org.springframework.boot:spring-boot-starter-web")
+    testImplementation("org.springframework.boot:spring-boot-starter-test")
+    testRuntimeOnly("org.junit.platform:junit-platform-launcher")
+}
+
+tasks.test {
+    useJUnitPlatform()
+}
+`
+
+	springBootApplication = `package com.example.app;
+
+import org.springframework.boot.SpringApplication;
+import org.springframework.boot.autoconfigure.SpringBootApplication;
+
+@SpringBootApplication
+public class Application {
+    public static void main(String[] args) {
+        SpringApplication.run(Application.class, args);
+    }
+}
+`
+
+	springBootController = `package com.example.app;
+
+import org.springframework.web.bind.annotation.GetMapping;
+import org.springframework.web.bind.annotation.RestController;
+
+@RestController
+public class HelloController {
+    @GetMapping("/")
+    public String hello() {
+        return "Hello, Spring Boot!";
+    }
+}
+`
+
+	springBootTest = `package com.example.app;
+
+import org.junit.jupiter.api.Test;
+import org.springframework.boot.test.context.SpringBootTest;
+
+@SpringBootTest
+class ApplicationTests {
+    @Test
+    void contextLoads() {
+    }
+}
+`
+)
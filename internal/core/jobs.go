@@ -0,0 +1,62 @@
+package core
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Warteschlange für Aufgaben über mehrere Projekte, etwa die Sammelaktionen
+// des Tabs „Projects“: höchstens MaxJobs laufen gleichzeitig, der Rest wartet.
+
+// MaxJobs begrenzt die gleichzeitig laufenden Jobs.
+var MaxJobs = min(runtime.NumCPU(), 4)
+
+// job ist eine Aufgabe der Warteschlange.
+type job struct {
+	// Name beschreibt den Job in Fortschritt und Ergebnis, etwa das Projektverzeichnis.
+	Name string
+	Run  func() error
+}
+
+// JobResult ist das Ergebnis eines abgeschlossenen Jobs.
+type JobResult struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+// RunJobs führt die Jobs mit höchstens workers gleichzeitig aus und liefert
+// die Ergebnisse in der Reihenfolge der Jobs. onDone, falls gesetzt, erfährt
+// jedes Ergebnis sofort; die Aufrufe sind nacheinander, nie gleichzeitig.
+func RunJobs(jobs []job, workers int, onDone func(done int, r JobResult)) []JobResult {
+	results := make([]JobResult, len(jobs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for range max(1, min(workers, len(jobs))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				start := time.Now()
+				err := jobs[i].Run()
+				r := JobResult{Name: jobs[i].Name, Err: err, Duration: time.Since(start)}
+				mu.Lock()
+				results[i] = r
+				done++
+				if onDone != nil {
+					onDone(done, r)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunJobs(t *testing.T) {
+	tests := []struct {
+		jobs, workers int
+	}{
+		{0, 4},
+		{1, 4},
+		{10, 1},
+		{10, 3},
+		{3, 0},
+	}
+	for _, tt := range tests {
+		var running, peak atomic.Int32
+		jobs := make([]job, tt.jobs)
+		for i := range jobs {
+			jobs[i] = job{Name: fmt.Sprint(i), Run: func() error {
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				running.Add(-1)
+				if i%2 == 1 {
+					return errors.New("ungerade")
+				}
+				return nil
+			}}
+		}
+		calls := 0
+		results := RunJobs(jobs, tt.workers, func(done int, r JobResult) {
+			calls++
+			if done != calls {
+				t.Errorf("%d Jobs: done = %d beim %d. Aufruf", tt.jobs, done, calls)
+			}
+		})
+		if calls != tt.jobs || len(results) != tt.jobs {
+			t.Errorf("%d Jobs: %d Aufrufe, %d Ergebnisse", tt.jobs, calls, len(results))
+		}
+		if limit := int32(max(1, tt.workers)); peak.Load() > limit {
+			t.Errorf("%d Jobs, %d Worker: %d gleichzeitig", tt.jobs, tt.workers, peak.Load())
+		}
+		for i, r := range results {
+			if r.Name != fmt.Sprint(i) || (r.Err != nil) != (i%2 == 1) {
+				t.Errorf("Ergebnis %d = %+v", i, r)
+			}
+		}
+	}
+}
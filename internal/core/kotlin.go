@@ -0,0 +1,207 @@
+package core
+
+// Kotlin: Das Grundgerüst ist ein Gradle-Projekt mit Kotlin-DSL
+// (settings.gradle.kts, build.gradle.kts) und Test mit kotlin.test; den
+// Wrapper gradlew legt das installierte Gradle an. Die Toolkits wählen ein
+// Kommandozeilenprogramm mit Clikt, einen Webdienst mit Ktor oder ein
+// Programm ohne Abhängigkeiten; kotlinc übersetzt ohne Gradle direkt.
+
+// kotlinToolkits sind die Presets für Kotlin, Clikt zuerst.
+var kotlinToolkits = []toolkit{
+	kotlinToolkit("CLI", map[string]string{
+		"build.gradle.kts":            kotlinCLIBuild,
+		"src/main/kotlin/Main.kt":     kotlinCLIMain,
+		"src/test/kotlin/MainTest.kt": kotlinCLITest,
+	}, `./gradlew run --args="--name Kotlin"`),
+	kotlinToolkit("Ktor", map[string]string{
+		"build.gradle.kts":            ktorBuild,
+		"src/main/kotlin/Main.kt":     ktorMain,
+		"src/test/kotlin/MainTest.kt": ktorTest,
+	}, ""),
+	// Dateien, Startbefehl und Prüfung des Projekttyps
+	kotlinToolkit("None", nil, ""),
+	{
+		Name:  "kotlinc",
+		Files: map[string]string{"src/main/kotlin/Main.kt": kotlinMain, ".gitignore": "/build\n"},
+		Toolchain: []toolCheck{
+			{[]string{"kotlinc", "-version"}, "Kotlin-Compiler"},
+			{[]string{"java", "-version"}, "Java"},
+		},
+		Run:   "kotlinc src/main/kotlin/Main.kt -include-runtime -d build/main.jar && java -jar build/main.jar",
+		Check: "kotlinc src/main/kotlin/Main.kt -include-runtime -d build/main.jar",
+		Bare:  true,
+	},
+}
+
+// kotlinToolkit ist ein Gradle-Preset; files ersetzen die des Projekttyps,
+// leeres run lässt "./gradlew run".
+func kotlinToolkit(name string, files map[string]string, run string) toolkit {
+	return toolkit{
+		Name:     name,
+		Files:    files,
+		Commands: []TemplateCommand{gradleWrapper},
+		Run:      run,
+	}
+}
+
+const (
+	kotlinBuild = `plugins {
+    kotlin("jvm") version "2.1.0"
+    application
+}
+
+repositories {
+    mavenCentral()
+}
+
+dependencies {
+    testImplementation(kotlin("test"))
+}
+
+application {
+    mainClass = "MainKt"
+}
+
+tasks.test {
+    useJUnitPlatform()
+}
+`
+
+	kotlinMain = `fun greeting(name: String) = "Hello, $name!"
+
+fun main() {
+    println(greeting("Kotlin"))
+}
+`
+
+	kotlinTest = `import kotlin.test.Test
+import kotlin.test.assertEquals
+
+class MainTest {
+    @Test
+    fun greets() {
+        assertEquals("Hello, Kotlin!", greeting("Kotlin"))
+    }
+}
+`
+
+	kotlinCLIBuild = `plugins {
+    kotlin("jvm") version "2.1.0"
+    application
+}
+
+repositories {
+    mavenCentral()
+}
+
+dependencies {
+    implementation("com.github.ajalt.clikt:clikt:5.0.1")
+    testImplementation(kotlin("test"))
+}
+
+application {
+    mainClass = "MainKt"
+}
+
+tasks.test {
+    useJUnitPlatform()
+}
+`
+
+	kotlinCLIMain = `import com.github.ajalt.clikt.core.CliktCommand
+import com.github.ajalt.clikt.core.main
+import com.github.ajalt.clikt.parameters.options.default
+import com.github.ajalt.clikt.parameters.options.option
+
+class Greet : CliktCommand(name = "{{.ProjectName}}") {
+    private val name by option(help = "Name to greet").default("World")
+
+    override fun run() {
+        echo(greeting(name))
+    }
+}
+
+fun greeting(name: String) = "Hello, $name!"
+
+fun main(args: Array<String>) = Greet().main(args)
+`
+
+	kotlinCLITest = `import com.github.ajalt.clikt.testing.test
+import kotlin.test.Test
+import kotlin.test.assertEquals
+
+class MainTest {
+    @Test
+    fun greetsByName() {
+        val result = Greet().test("--name Kotlin")
+        assertEquals("Hello, Kotlin!\n", result.stdout)
+    }
+}
+`
+
+	// Das Ktor-Plugin bringt die BOM mit, die Module brauchen keine Version
+	ktorBuild = `plugins {
+    kotlin("jvm") version "2.1.0"
+    id("io.ktor.plugin") version "3.0.3"
+    application
+}
+
+repositories {
+    mavenCentral()
+}
+
+dependencies {
+    implementation("io.ktor:ktor-server-core-jvm")
+    implementation("io.ktor:ktor-server-netty-jvm")
+    implementation("ch.qos.logback:logback-classic:1.5.12")
+    testImplementation("io.ktor:ktor-server-test-host-jvm")
+    testImplementation(kotlin("test"))
+}
+
+application {
+    mainClass = "MainKt"
+}
+
+tasks.test {
+    useJUnitPlatform()
+}
+`
+
+	ktorMain = `import io.ktor.server.application.Application
+import io.ktor.server.engine.embeddedServer
+import io.ktor.server.netty.Netty
+import io.ktor.server.response.respondText
+import io.ktor.server.routing.get
+import io.ktor.server.routing.routing
+
+fun main() {
+    embeddedServer(Netty, port = 8080, module = Application::module).start(wait = true)
+}
+
+fun Application.module() {
+    routing {
+        get("/") {
+            call.respondText("Hello, Ktor!")
+        }
+    }
+}
+`
+
+	ktorTest = `import io.ktor.client.request.get
+import io.ktor.client.statement.bodyAsText
+import io.ktor.http.HttpStatusCode
+import io.ktor.server.testing.testApplication
+import kotlin.test.Test
+import kotlin.test.assertEquals
+
+class MainTest {
+    @Test
+    fun root() = testApplication {
+        application { module() }
+        val response = client.get("/")
+        assertEquals(HttpStatusCode.OK, response.status)
+        assertEquals("Hello, Ktor!", response.bodyAsText())
+    }
+}
+`
+)
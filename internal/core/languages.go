@@ -0,0 +1,1576 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Projekttypen sind reine Daten: Eine language legt fest, welche Werkzeuge
+// vorhanden sein müssen und wie das Grundgerüst entsteht. CreateProject
+// durchläuft für jeden Typ dieselbe Pipeline (scaffold): Init-Befehle oder
+// Verzeichnis anlegen → Verzeichnisse → Dateien rendern → Befehle. Eigene
+// Sprachen liegen als JSON in <config>/languages und werden wie Plugin-Typen
+// registriert.
+
+// language beschreibt einen Projekttyp.
+type language struct {
+	Name string `json:"name"`
+	// Toolchain wird vor der Erstellung geprüft; die Versionen landen im Bericht.
+	Toolchain []toolCheck `json:"toolchain,omitempty"`
+	// Init legt das Projektverzeichnis selbst an (cargo new, dotnet new) und
+	// läuft im Elternverzeichnis; ohne Init wird das Verzeichnis erstellt.
+	Init  []TemplateCommand `json:"init,omitempty"`
+	Dirs  []string          `json:"dirs,omitempty"`
+	Files map[string]string `json:"files,omitempty"`
+	// Packages sind die Standardabhängigkeiten, ersetzbar über
+	// Settings.DefaultPackages. Ein Argument "{packages}" in Commands steht für
+	// die Liste, in Files {{.Packages}} (eine Zeile je Paket).
+	Packages []string `json:"packages,omitempty"`
+	// WithPackage ersetzt Dateien, solange das Paket zu den Standardpaketen
+	// gehört; Files enthält die Fassung ohne das Paket.
+	WithPackage map[string]map[string]string `json:"withPackage,omitempty"`
+	// Toolkits sind wählbare Varianten wie das GUI-Framework. Ohne Auswahl gilt
+	// Settings.DefaultToolkits, sonst die erste.
+	Toolkits []toolkit `json:"toolkits,omitempty"`
+	// Layouts sind wählbare Projektformen wie Binary oder Library. Sie
+	// ergänzen das Toolkit um Dateien, Pakete und Werkzeuge; Init und Commands
+	// gelten, sofern das Toolkit keine eigenen hat, Run immer. Ohne Auswahl
+	// gilt das erste.
+	Layouts  []toolkit         `json:"layouts,omitempty"`
+	Commands []TemplateCommand `json:"commands,omitempty"`
+	// Install installiert Template-Pakete (Paketnamen werden angehängt);
+	// mit InstallEach einzeln, wenn der Paketmanager nur eines annimmt.
+	Install     []string `json:"install,omitempty"`
+	InstallEach bool     `json:"installEach,omitempty"`
+	// Run ist der Startbefehl, den das Terminal anzeigt und ausführt, mit Hint
+	// nur anzeigt; Activate läuft vorher (etwa das venv). Terminal ist
+	// stattdessen ein vollständiges Bash-Skript.
+	Run      string `json:"run,omitempty"`
+	Hint     bool   `json:"hint,omitempty"`
+	Activate string `json:"activate,omitempty"`
+	Terminal string `json:"terminal,omitempty"`
+	// Check baut und testet ein neues Projekt, etwa "cargo test"; Image ist
+	// das Container-Image dafür (siehe selftest.go).
+	Check string `json:"check,omitempty"`
+	Image string `json:"image,omitempty"`
+	// LocalDir ist ein Verzeichnis mit ausführbaren Dateien (venv), das auf
+	// Netzlaufwerken ohne Ausführrechte oder Symlinks außerhalb des Projekts
+	// entsteht; "{local}" in Commands, Install und Activate steht für seinen
+	// Pfad (siehe mount.go).
+	LocalDir string `json:"localDir,omitempty"`
+	// PathWarning erklärt, warum die Werkzeuge mit Leerzeichen oder
+	// Nicht-ASCII-Zeichen im Pfad scheitern können; preflight warnt dann.
+	PathWarning string `json:"pathWarning,omitempty"`
+	// Shortcuts nennt, wo der Startbefehl zusätzlich zur VS-Code-Task abgelegt
+	// wird: "taskfile", "npm" oder "cargo" (siehe shortcuts.go).
+	Shortcuts []string `json:"shortcuts,omitempty"`
+	// Release ist das Werkzeug des Release-Tasks: "git-cliff",
+	// "standard-version" oder "cargo-release" (siehe release.go).
+	Release string `json:"release,omitempty"`
+	// Format prüft im pre-commit-Hook die Formatierung (siehe githooks.go).
+	Format *formatCheck `json:"format,omitempty"`
+	// Debug ist die Startkonfiguration für .vscode/launch.json, die bei
+	// Umgebungsvariablen entsteht; name und envFile ergänzt newpipi, {name} in
+	// Werten wird ersetzt (siehe envvars.go).
+	Debug map[string]any `json:"debug,omitempty"`
+	// Attributes sind Zeilen für .gitattributes, etwa Diff-Treiber oder
+	// linguist-generated für erzeugten Code (siehe gitattributes.go).
+	Attributes []string `json:"attributes,omitempty"`
+	// SizeMB schätzt den Platzbedarf für die Speicherplatzprüfung.
+	SizeMB int `json:"sizeMB,omitempty"`
+	// Registry ist die Paketquelle, deren Erreichbarkeit vor der Erstellung
+	// geprüft wird; ein Mirror des Profils für Ecosystem hat Vorrang.
+	Registry  string `json:"registry,omitempty"`
+	Ecosystem string `json:"ecosystem,omitempty"`
+	// Audit, Licenses und SBOM untersuchen die installierten Abhängigkeiten
+	// (siehe projecttool.go); Prefetch lädt {packages} in einem leeren
+	// Verzeichnis in die Caches (siehe prefetch.go).
+	Audit    *projectTool      `json:"audit,omitempty"`
+	Licenses *projectTool      `json:"licenses,omitempty"`
+	SBOM     *projectTool      `json:"sbom,omitempty"`
+	Prefetch []TemplateCommand `json:"prefetch,omitempty"`
+}
+
+// toolkit ergänzt Pakete, Dateien und Werkzeuge eines Projekttyps.
+type toolkit struct {
+	Name      string            `json:"name"`
+	Packages  []string          `json:"packages,omitempty"`
+	Files     map[string]string `json:"files,omitempty"`
+	Toolchain []toolCheck       `json:"toolchain,omitempty"`
+	// Init, Commands und Run ersetzen die des Projekttyps, wenn das Toolkit
+	// ein eigenes Werkzeug zum Anlegen mitbringt (wails init).
+	Init     []TemplateCommand `json:"init,omitempty"`
+	Commands []TemplateCommand `json:"commands,omitempty"`
+	Run      string            `json:"run,omitempty"`
+	// Check ersetzt den Prüfbefehl des Projekttyps (siehe selftest.go).
+	Check string `json:"check,omitempty"`
+	// PathWarning ersetzt die des Projekttyps.
+	PathWarning string `json:"pathWarning,omitempty"`
+	// Bare lässt Files, Packages, WithPackage und Toolchain des Projekttyps
+	// weg, wenn das Werkzeug des Toolkits das ganze Gerüst anlegt (create-vite).
+	Bare bool `json:"bare,omitempty"`
+	// Dir lässt Init in diesem Unterverzeichnis des Projekts statt im
+	// Elternverzeichnis laufen, etwa "src" für eine Projektmappe mit
+	// src/{name} (siehe dotnet.go).
+	Dir string `json:"dir,omitempty"`
+}
+
+// toolCheck prüft ein Werkzeug über seinen Versionsbefehl.
+type toolCheck struct {
+	Command []string `json:"command"`
+	// Label nennt das Werkzeug in der Fehlermeldung.
+	Label string `json:"label"`
+}
+
+// languages ordnet den Projekttypen ihre Beschreibung zu.
+var languages = map[ProjectType]*language{
+	Python: {
+		Name:      "Python",
+		Toolchain: []toolCheck{{[]string{"python3", "--version"}, "python3"}},
+		Dirs:      []string{"src", "tests"},
+		Files: map[string]string{
+			"src/__init__.py":   "",
+			"tests/__init__.py": "",
+			"README.md":         "",
+			"requirements.txt":  "{{.Packages}}\n",
+			".gitignore":        "/venv\n__pycache__\n*.pyc\n",
+		},
+		Toolkits: []toolkit{
+			{Name: "PyQt5", Packages: []string{"PyQt5"}, Files: map[string]string{"src/main.py": pyQt5Main}},
+			{Name: "PySide6", Packages: []string{"PySide6"}, Files: map[string]string{"src/main.py": pySide6Main}},
+			{
+				Name:      "Tkinter",
+				Files:     map[string]string{"src/main.py": tkinterMain},
+				Toolchain: []toolCheck{{[]string{"python3", "-c", "import tkinter; print('tkinter', tkinter.TkVersion)"}, "tkinter (python3-tk)"}},
+			},
+			{Name: "Kivy", Packages: []string{"kivy"}, Files: map[string]string{"src/main.py": kivyMain}},
+			{Name: "Flask", Packages: []string{"flask"}, Files: map[string]string{"src/main.py": flaskMain}},
+			{
+				Name:     "FastAPI",
+				Packages: []string{"fastapi", "uvicorn[standard]"},
+				Files:    map[string]string{"src/main.py": fastAPIMain},
+				Run:      "uvicorn src.main:app --reload",
+			},
+			{
+				Name:     "Django",
+				Packages: []string{"django"},
+				Files:    map[string]string{".gitignore": "/venv\n__pycache__\n*.pyc\ndb.sqlite3\n"},
+				// startproject braucht Django aus dem venv, läuft also nach der Installation
+				Commands: slices.Concat(pythonCommands, []TemplateCommand{
+					{Run: []string{"{local}/bin/django-admin", "startproject", "config", "."}, Step: "Erstelle Django-Projekt..."},
+				}),
+				Run:   "python manage.py runserver",
+				Check: "python manage.py check",
+			},
+			{
+				Name:     "Click",
+				Packages: []string{"click"},
+				Files:    map[string]string{"src/main.py": clickMain},
+				Run:      "python src/main.py --help",
+			},
+			{Name: "None", Files: map[string]string{"src/main.py": pythonPlainMain}},
+		},
+		Commands:   pythonCommands,
+		LocalDir:   "venv",
+		Install:    []string{"{local}/bin/pip", "install"},
+		Run:        "python src/main.py",
+		Check:      "python -m compileall -q -x venv .",
+		Image:      "python:3.12-slim",
+		Hint:       true,
+		Activate:   "source {local}/bin/activate",
+		Shortcuts:  []string{"taskfile"},
+		Release:    "git-cliff",
+		Format:     &formatCheck{"ruff", "ruff format --check ."},
+		Debug:      map[string]any{"type": "debugpy", "request": "launch", "program": "${workspaceFolder}/src/main.py"},
+		Attributes: []string{"*.py diff=python", "*.ipynb linguist-documentation"},
+		SizeMB:     50,
+		Registry:   "https://pypi.org/simple/",
+		Ecosystem:  "pip",
+		Audit:      &projectTool{Run: []string{"pip-audit", "--format", "json", "--path", "{local}/lib/python*/site-packages"}, Output: "pip-audit"},
+		Licenses:   &projectTool{Run: []string{"pip-licenses", "--format", "json", "--python", "{local}/bin/python"}, Output: "pip-licenses"},
+		SBOM:       &projectTool{Run: []string{"cyclonedx-py", "environment", "{local}", "--output-file", "{file}"}, Formats: []string{"cyclonedx"}},
+		Prefetch: []TemplateCommand{
+			{Run: []string{"python3", "-m", "pip", "download", "--quiet", "--dest", ".", "{packages}"}, Network: true},
+		},
+	},
+	Go: {
+		Name:      "Go",
+		Toolchain: []toolCheck{{[]string{"go", "version"}, "go"}},
+		// Fyne, Gio und die Qt-Bindings brauchen cgo
+		Toolkits: []toolkit{
+			{
+				Name:        "Fyne",
+				Packages:    []string{"fyne.io/fyne/v2"},
+				Files:       map[string]string{"main.go": goFyneMain},
+				Toolchain:   []toolCheck{{[]string{"gcc", "--version"}, "gcc"}},
+				PathWarning: cgoPathWarning,
+			},
+			{
+				Name:        "Gio",
+				Packages:    []string{"gioui.org"},
+				Files:       map[string]string{"main.go": goGioMain},
+				Toolchain:   []toolCheck{{[]string{"gcc", "--version"}, "gcc"}},
+				PathWarning: cgoPathWarning,
+			},
+			{
+				Name: "Wails",
+				Toolchain: []toolCheck{
+					{[]string{"wails", "version"}, "wails"},
+					{[]string{"node", "--version"}, "node.js"},
+					{[]string{"npm", "--version"}, "npm"},
+				},
+				// wails init legt go.mod und das Frontend selbst an
+				Init: []TemplateCommand{
+					{Run: []string{"wails", "init", "-n", "{name}", "-t", "vanilla"}, Step: "Erstelle Wails-Projekt..."},
+				},
+				Commands: []TemplateCommand{
+					{Run: []string{"go", "mod", "tidy"}, Step: "Führe go mod tidy aus...", Network: true, Install: true},
+				},
+				Run:   "wails dev",
+				Check: "wails build",
+			},
+			{
+				Name:     "Qt",
+				Packages: []string{"github.com/mappu/miqt/qt"},
+				Files:    map[string]string{"main.go": goQtMain},
+				Toolchain: []toolCheck{
+					{[]string{"gcc", "--version"}, "gcc"},
+					{[]string{"pkg-config", "--modversion", "Qt5Widgets"}, "Qt 5 (qtbase5-dev)"},
+				},
+				PathWarning: cgoPathWarning,
+			},
+			{
+				Name:     "Cobra",
+				Packages: []string{"github.com/spf13/cobra"},
+				Files:    map[string]string{"main.go": goCobraMain, "cmd/root.go": goCobraRoot},
+				Run:      "go run . --help",
+			},
+			{Name: "Gin", Packages: []string{"github.com/gin-gonic/gin"}, Files: map[string]string{"main.go": goGinMain}},
+			{Name: "Echo", Packages: []string{"github.com/labstack/echo/v4"}, Files: map[string]string{"main.go": goEchoMain}},
+			{
+				Name:     "gRPC",
+				Packages: []string{"google.golang.org/grpc"},
+				Files:    map[string]string{"main.go": goGRPCMain, "proto/service.proto": goGRPCProto},
+			},
+			{
+				Name:  "Library",
+				Files: map[string]string{"lib.go": goLibrary, "lib_test.go": goLibraryTest},
+				Run:   "go test ./...",
+			},
+			{Name: "None", Files: map[string]string{"main.go": goPlainMain}},
+		},
+		Commands: []TemplateCommand{
+			{Run: []string{"go", "mod", "init", "{module}"}, Step: "Initialisiere Go-Modul..."},
+			// tidy vor get, sonst entfernt es noch nicht importierte Standardpakete
+			{Run: []string{"go", "mod", "tidy"}, Step: "Führe go mod tidy aus...", Network: true, Install: true},
+			{Run: []string{"go", "get", "{packages}"}, Step: "Installiere Abhängigkeiten...", Network: true, Install: true},
+		},
+		Install:    []string{"go", "get"},
+		Run:        "go run .",
+		Check:      "go build ./... && go test ./...",
+		Image:      "golang:1.23",
+		Hint:       true,
+		Shortcuts:  []string{"taskfile"},
+		Release:    "git-cliff",
+		Format:     &formatCheck{"gofmt", `test -z "$(gofmt -l .)"`},
+		Debug:      map[string]any{"type": "go", "request": "launch", "mode": "auto", "program": "${workspaceFolder}"},
+		Attributes: []string{"*.go diff=golang", "*.pb.go linguist-generated", "*_gen.go linguist-generated", "go.sum linguist-generated", "vendor/** linguist-vendored"},
+		SizeMB:     30,
+		Registry:   "https://proxy.golang.org/",
+		Ecosystem:  "go",
+		Audit:      &projectTool{Run: []string{"govulncheck", "-format", "json", "./..."}, Output: "govulncheck"},
+		// Das eigene Modul hat noch keine Lizenz, go-licenses bräche sonst ab
+		Licenses: &projectTool{Run: []string{"go-licenses", "report", "--ignore", "{name}", "./..."}, Output: "go-licenses"},
+		SBOM:     &projectTool{Run: []string{"cyclonedx-gomod", "mod", "-json", "-output", "{file}"}, Formats: []string{"cyclonedx"}},
+		// Außerhalb eines Moduls braucht go mod download Versionen, siehe prefetchList
+		Prefetch: []TemplateCommand{
+			{Run: []string{"go", "mod", "download", "{packages}"}, Network: true},
+		},
+	},
+	Rust: {
+		Name:      "Rust",
+		Toolchain: []toolCheck{{[]string{"rustc", "--version"}, "rust"}},
+		Init: []TemplateCommand{
+			{Run: []string{"cargo", "new", "{name}"}, Step: "Erstelle Cargo-Projekt..."},
+		},
+		// Versionen sind festgelegt, weil sich die APIs zwischen Minor-Versionen
+		// ändern; None behält das Hello World von cargo new
+		Toolkits: []toolkit{
+			{Name: "egui", Packages: []string{"eframe@0.31"}, Files: map[string]string{"src/main.rs": rustEguiMain}},
+			{Name: "iced", Packages: []string{"iced@0.13"}, Files: map[string]string{"src/main.rs": rustIcedMain}},
+			{Name: "Slint", Packages: []string{"slint@1"}, Files: map[string]string{"src/main.rs": rustSlintMain}},
+			{
+				Name:      "gtk-rs",
+				Packages:  []string{"gtk4@0.9"},
+				Files:     map[string]string{"src/main.rs": rustGtkMain},
+				Toolchain: []toolCheck{{[]string{"pkg-config", "--modversion", "gtk4"}, "GTK 4 (libgtk-4-dev)"}},
+			},
+			{
+				Name: "Tauri",
+				Toolchain: []toolCheck{
+					{[]string{"cargo", "create-tauri-app", "--version"}, "create-tauri-app (cargo install create-tauri-app)"},
+					{[]string{"cargo", "tauri", "--version"}, "tauri-cli (cargo install tauri-cli)"},
+				},
+				// create-tauri-app legt Frontend und src-tauri/ selbst an
+				Init: []TemplateCommand{
+					{Run: []string{"cargo", "create-tauri-app", "{name}", "--manager", "cargo", "--template", "vanilla", "--yes"}, Step: "Erstelle Tauri-Projekt...", Network: true},
+				},
+				Run:   "cargo tauri dev",
+				Check: "cargo test --manifest-path src-tauri/Cargo.toml",
+			},
+			{Name: "actix-web", Packages: []string{"actix-web@4"}, Files: map[string]string{"src/main.rs": rustActixMain}},
+			{Name: "clap", Packages: []string{"clap@4"}, Files: map[string]string{"src/main.rs": rustClapMain}, Run: "cargo run -- --help"},
+			{Name: "None"},
+		},
+		Layouts: []toolkit{
+			{Name: "Binary"},
+			{
+				Name: "Library",
+				Init: []TemplateCommand{
+					{Run: []string{"cargo", "new", "--lib", "{name}"}, Step: "Erstelle Cargo-Bibliothek..."},
+				},
+				Run: "cargo test",
+			},
+			{
+				// Das Hauptpaket ist zugleich Wurzel des Workspaces, cargo add wirkt also darauf
+				Name: "Workspace",
+				Files: map[string]string{
+					"Cargo.toml":             rustWorkspaceToml,
+					"crates/core/Cargo.toml": rustCoreToml,
+					"crates/core/src/lib.rs": rustCoreLib,
+				},
+			},
+		},
+		Commands: []TemplateCommand{
+			{Run: []string{"cargo", "add", "{packages}"}, Step: "Füge Abhängigkeiten hinzu...", Network: true, Install: true},
+		},
+		Install:    []string{"cargo", "add"},
+		Run:        "cargo run",
+		Check:      "cargo test",
+		Image:      "rust:1",
+		Hint:       true,
+		Shortcuts:  []string{"cargo"},
+		Release:    "cargo-release",
+		Format:     &formatCheck{"cargo", "cargo fmt --check"},
+		Debug:      map[string]any{"type": "lldb", "request": "launch", "cargo": map[string]any{"args": []string{"build"}}},
+		Attributes: []string{"*.rs diff=rust", "Cargo.lock linguist-generated"},
+		SizeMB:     100,
+		Registry:   "https://index.crates.io/config.json",
+		Audit: &projectTool{
+			Run:      []string{"cargo", "audit", "--json"},
+			Requires: &toolCheck{[]string{"cargo", "audit", "--version"}, "cargo-audit"},
+			Output:   "cargo-audit",
+		},
+		Licenses: &projectTool{
+			Run:      []string{"cargo", "license", "--json"},
+			Requires: &toolCheck{[]string{"cargo", "license", "--version"}, "cargo-license"},
+			Output:   "cargo-license",
+		},
+		SBOM: &projectTool{
+			Run:      []string{"cargo", "cyclonedx", "--format", "json", "--override-filename", "sbom.cdx"},
+			Requires: &toolCheck{[]string{"cargo", "cyclonedx", "--version"}, "cargo-cyclonedx"},
+			Formats:  []string{"cyclonedx"},
+		},
+		// cargo lädt nur für ein Paket; ein leeres Hilfsprojekt genügt
+		Prefetch: []TemplateCommand{
+			{Run: []string{"cargo", "init", "--lib", "--vcs", "none", "--name", "prefetch"}},
+			{Run: []string{"cargo", "add", "{packages}"}, Network: true},
+			{Run: []string{"cargo", "fetch"}, Network: true},
+		},
+	},
+	JavaScript: {
+		Name:        "JavaScript",
+		Toolchain:   []toolCheck{{[]string{"node", "--version"}, "node.js"}},
+		Files:       map[string]string{"app.js": javaScriptPlainApp},
+		Packages:    []string{"express"},
+		WithPackage: map[string]map[string]string{"express": {"app.js": javaScriptApp}},
+		Toolkits:    slices.Concat([]toolkit{{Name: "Node"}}, viteToolkits("")),
+		Commands: []TemplateCommand{
+			{Run: []string{"npm", "init", "-y"}, Step: "Initialisiere npm..."},
+			{Run: []string{"npm", "install", "{packages}"}, Step: "Installiere Abhängigkeiten...", Network: true, Install: true},
+		},
+		Install:    []string{"npm", "install"},
+		Run:        "node app.js",
+		Check:      "node --check app.js",
+		Image:      "node:22",
+		Shortcuts:  []string{"npm"},
+		Release:    "standard-version",
+		Format:     &formatCheck{"prettier", "prettier --check ."},
+		Debug:      map[string]any{"type": "node", "request": "launch", "program": "${workspaceFolder}/app.js"},
+		Attributes: npmAttributes,
+		Registry:   "https://registry.npmjs.org/",
+		Ecosystem:  "npm",
+		Audit:      npmAudit,
+		Licenses:   npmLicenses,
+		SBOM:       npmSBOM,
+		Prefetch:   npmPrefetch,
+	},
+	TypeScript: {
+		Name: "TypeScript",
+		Toolchain: []toolCheck{
+			{[]string{"node", "--version"}, "node.js"},
+			{[]string{"tsc", "--version"}, "typescript"},
+		},
+		Files:    map[string]string{"src/index.ts": typeScriptIndex},
+		Packages: []string{"typescript", "@types/node"},
+		Toolkits: slices.Concat([]toolkit{{Name: "Node"}}, viteToolkits("-ts")),
+		Commands: []TemplateCommand{
+			{Run: []string{"npm", "init", "-y"}},
+			{Run: []string{"npm", "install", "--save-dev", "{packages}"}, Network: true, Install: true},
+			{Run: []string{"npx", "tsc", "--init"}},
+		},
+		Install:    []string{"npm", "install"},
+		Run:        "npx tsc && node dist/index.js",
+		Check:      "npx tsc --noEmit",
+		Image:      "node:22",
+		Shortcuts:  []string{"npm"},
+		Release:    "standard-version",
+		Format:     &formatCheck{"prettier", "prettier --check src"},
+		Debug:      map[string]any{"type": "node", "request": "launch", "program": "${workspaceFolder}/dist/index.js"},
+		Attributes: npmAttributes,
+		Registry:   "https://registry.npmjs.org/",
+		Ecosystem:  "npm",
+		Audit:      npmAudit,
+		Licenses:   npmLicenses,
+		SBOM:       npmSBOM,
+		Prefetch:   npmPrefetch,
+	},
+	CPlusPlus: {
+		Name:      "C++",
+		Toolchain: []toolCheck{{[]string{"g++", "--version"}, "g++"}},
+		Dirs:      []string{"src", "include", "build"},
+		Files: map[string]string{
+			"src/main.cpp": cppMain,
+			".gitignore":   "/build\n",
+		},
+		Toolkits:    cppToolkits,
+		Terminal:    `cd build && cmake .. && make && echo "Build abgeschlossen." && bash`,
+		PathWarning: "make und viele CMake-Skripte trennen Pfade an Leerzeichen auf",
+		Format:      &formatCheck{"clang-format", "clang-format --dry-run --Werror src/*.cpp"},
+		Debug:       map[string]any{"type": "cppdbg", "request": "launch", "program": "${workspaceFolder}/build/{name}", "cwd": "${workspaceFolder}"},
+		Attributes:  []string{"*.cpp diff=cpp", "*.h diff=cpp", "*.hpp diff=cpp", "third_party/** linguist-vendored"},
+		Release:     "git-cliff",
+	},
+	CSharp: {
+		Name:      "C#",
+		Toolchain: []toolCheck{{[]string{"dotnet", "--version"}, ".NET SDK"}},
+		Init: []TemplateCommand{
+			{Run: []string{"dotnet", "new", "console", "-n", "{name}"}, Step: "Erstelle .NET-Projekt..."},
+		},
+		Toolkits:    dotnetToolkits,
+		Layouts:     dotnetLayouts,
+		Install:     []string{"dotnet", "add", "package"},
+		InstallEach: true,
+		Run:         "dotnet run",
+		Check:       "dotnet build",
+		Image:       "mcr.microsoft.com/dotnet/sdk:9.0",
+		Format:      &formatCheck{"dotnet", "dotnet format --verify-no-changes"},
+		// Visual Studio erwartet Projektmappen mit CRLF
+		Attributes: []string{"*.cs diff=csharp", "*.sln text eol=crlf", "*.Designer.cs linguist-generated"},
+		Release:    "git-cliff",
+		Audit:      &projectTool{Run: []string{"dotnet", "list", "package", "--vulnerable", "--format", "json"}, Output: "dotnet"},
+	},
+	Java: {
+		Name:       "Java",
+		Toolchain:  []toolCheck{javacCheck},
+		Files:      map[string]string{"src/main/java/Main.java": javaMain},
+		Toolkits:   javaToolkits,
+		Run:        "javac src/main/java/Main.java && java -cp src/main/java Main",
+		Check:      "javac -d build src/main/java/Main.java",
+		Image:      "eclipse-temurin:21-jdk",
+		Debug:      map[string]any{"type": "java", "request": "launch", "mainClass": "Main"},
+		Attributes: []string{"*.java diff=java", "mvnw text eol=lf", "gradlew text eol=lf", "*.jar binary"},
+		Release:    "git-cliff",
+	},
+	Kotlin: {
+		Name: "Kotlin",
+		// Gradle lädt den Kotlin-Compiler selbst, braucht aber ein JDK
+		Toolchain: []toolCheck{javacCheck, gradleCheck},
+		Files: map[string]string{
+			"settings.gradle.kts":         gradleSettings,
+			"build.gradle.kts":            kotlinBuild,
+			"src/main/kotlin/Main.kt":     kotlinMain,
+			"src/test/kotlin/MainTest.kt": kotlinTest,
+			".gitignore":                  "/.gradle\n/.kotlin\n/build\n",
+		},
+		Toolkits:   kotlinToolkits,
+		Run:        "./gradlew run",
+		Check:      "./gradlew build",
+		Image:      "gradle:8-jdk21",
+		Format:     &formatCheck{"ktlint", "ktlint"},
+		Debug:      map[string]any{"type": "kotlin", "request": "launch", "mainClass": "MainKt"},
+		Attributes: []string{"gradlew text eol=lf", "*.jar binary"},
+		Release:    "git-cliff",
+	},
+	Zig: {
+		Name:      "Zig",
+		Toolchain: []toolCheck{{[]string{"zig", "version"}, "Zig"}},
+		Init: []TemplateCommand{
+			{Run: []string{"zig", "init", "--minimal"}, Step: "Erstelle Zig-Projekt..."},
+		},
+		Files:   map[string]string{".gitignore": "/.zig-cache\n/zig-out\n"},
+		Layouts: zigLayouts,
+		// zig fetch nimmt nur eine Adresse je Aufruf
+		Install:     []string{"zig", "fetch", "--save"},
+		InstallEach: true,
+		Run:         "zig build run",
+		Check:       "zig build test",
+		Format:      &formatCheck{"zig", "zig fmt --check ."},
+		Debug:       map[string]any{"type": "lldb", "request": "launch", "program": "${workspaceFolder}/zig-out/bin/{name}", "cwd": "${workspaceFolder}"},
+		Attributes:  []string{"*.zig text eol=lf", "*.zon text eol=lf"},
+		Release:     "git-cliff",
+		SizeMB:      20,
+	},
+}
+
+// JavaScript und TypeScript untersuchen ihre Abhängigkeiten mit npm und
+// license-checker.
+var (
+	npmAudit    = &projectTool{Run: []string{"npm", "audit", "--json"}, Output: "npm-audit"}
+	npmLicenses = &projectTool{Run: []string{"license-checker", "--json", "--excludePrivatePackages"}, Output: "license-checker"}
+	npmSBOM     = &projectTool{Run: []string{"npm", "sbom", "--sbom-format", "{format}"}, Formats: []string{"cyclonedx", "spdx"}, Stdout: true}
+	npmPrefetch = []TemplateCommand{{Run: []string{"npm", "cache", "add", "{packages}"}, Network: true}}
+)
+
+// viteToolkits sind die Frontend-Gerüste von create-vite; suffix wählt die
+// TypeScript-Fassung der Vorlagen.
+func viteToolkits(suffix string) []toolkit {
+	var toolkits []toolkit
+	for _, name := range []string{"React", "Vue", "Svelte", "Vanilla"} {
+		toolkits = append(toolkits, toolkit{
+			Name:      name,
+			Bare:      true,
+			Toolchain: []toolCheck{{[]string{"node", "--version"}, "node.js"}},
+			Init: []TemplateCommand{
+				{Run: []string{"npm", "create", "vite@latest", "{name}", "--", "--template", strings.ToLower(name) + suffix, "--no-interactive"}, Step: "Erstelle Vite-Projekt...", Network: true},
+			},
+			Commands: []TemplateCommand{
+				{Run: []string{"npm", "install"}, Step: "Installiere Abhängigkeiten...", Network: true, Install: true},
+			},
+			Run:   "npm run dev",
+			Check: "npm run build",
+		})
+	}
+	return toolkits
+}
+
+// npmAttributes markieren die Lockfiles aller Paketmanager und den Build.
+var npmAttributes = []string{
+	"package-lock.json linguist-generated -diff",
+	"pnpm-lock.yaml linguist-generated -diff",
+	"yarn.lock linguist-generated -diff",
+	"bun.lock linguist-generated -diff",
+	"dist/** linguist-generated",
+}
+
+// pythonCommands legen das venv an und installieren die Pakete.
+var pythonCommands = []TemplateCommand{
+	{Run: []string{"python3", "-m", "venv", "{local}"}, Step: "Erstelle virtuelle Umgebung..."},
+	{Run: []string{"{local}/bin/pip", "install", "--upgrade", "pip"}, Step: "Aktualisiere pip...", Network: true, Install: true},
+	{Run: []string{"{local}/bin/pip", "install", "{packages}"}, Step: "Installiere Pakete...", Network: true, Install: true},
+}
+
+// cgoPathWarning gilt für Toolkits, die C-Code über cgo übersetzen.
+const cgoPathWarning = "cgo reicht Pfade in Compiler-Flags nicht zuverlässig maskiert weiter"
+
+// Dateiinhalte der eingebauten Projekttypen
+const (
+	goPlainMain = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("Hello, Go!")
+}
+`
+
+	goCobraMain = `package main
+
+import "{{.Module}}/cmd"
+
+func main() {
+	cmd.Execute()
+}
+`
+
+	goCobraRoot = `package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "{{.ProjectName}}",
+	Short: "Says hello",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		fmt.Printf("Hello, %s!\n", name)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.Flags().String("name", "Cobra", "who to greet")
+}
+
+// Execute runs the root command.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+`
+
+	goGinMain = `package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	r := gin.Default()
+	r.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "Hello Gin!"})
+	})
+	r.Run(":8080")
+}
+`
+
+	goEchoMain = `package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func main() {
+	e := echo.New()
+	e.Use(middleware.Logger())
+	e.GET("/", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"message": "Hello Echo!"})
+	})
+	e.Logger.Fatal(e.Start(":8080"))
+}
+`
+
+	goGRPCMain = `package main
+
+import (
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Register your own services from proto/service.proto next to the health
+// service once the code is generated with protoc-gen-go-grpc.
+func main() {
+	lis, err := net.Listen("tcp", ":50051")
+	if err != nil {
+		log.Fatal(err)
+	}
+	s := grpc.NewServer()
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	reflection.Register(s)
+	log.Printf("listening on %v", lis.Addr())
+	if err := s.Serve(lis); err != nil {
+		log.Fatal(err)
+	}
+}
+`
+
+	goGRPCProto = `syntax = "proto3";
+
+package {{.Package}};
+
+option go_package = "{{.Module}}/proto";
+
+service Greeter {
+  rpc SayHello (HelloRequest) returns (HelloReply);
+}
+
+message HelloRequest {
+  string name = 1;
+}
+
+message HelloReply {
+  string message = 1;
+}
+`
+
+	goLibrary = `// Package {{.Package}} says hello.
+package {{.Package}}
+
+// Hello returns a greeting for name.
+func Hello(name string) string {
+	return "Hello, " + name + "!"
+}
+`
+
+	goLibraryTest = `package {{.Package}}
+
+import "testing"
+
+func TestHello(t *testing.T) {
+	if got := Hello("Go"); got != "Hello, Go!" {
+		t.Errorf("Hello(\"Go\") = %q", got)
+	}
+}
+`
+
+	javaScriptPlainApp = `console.log('Hello, JavaScript!');
+`
+
+	pyQt5Main = `import sys
+from PyQt5.QtWidgets import QApplication, QMainWindow, QPushButton, QVBoxLayout, QWidget
+
+class MainWindow(QMainWindow):
+    def __init__(self):
+        super().__init__()
+        self.setWindowTitle("PyQt5 Boilerplate")
+        self.setGeometry(100, 100, 300, 200)
+
+        layout = QVBoxLayout()
+        
+        button = QPushButton("Click me!")
+        button.clicked.connect(self.button_clicked)
+        layout.addWidget(button)
+
+        central_widget = QWidget()
+        central_widget.setLayout(layout)
+        self.setCentralWidget(central_widget)
+
+    def button_clicked(self):
+        print("Button clicked!")
+
+def main():
+    app = QApplication(sys.argv)
+    window = MainWindow()
+    window.show()
+    sys.exit(app.exec_())
+
+if __name__ == "__main__":
+    main()`
+
+	pySide6Main = `import sys
+from PySide6.QtWidgets import QApplication, QMainWindow, QPushButton, QVBoxLayout, QWidget
+
+class MainWindow(QMainWindow):
+    def __init__(self):
+        super().__init__()
+        self.setWindowTitle("PySide6 Boilerplate")
+        self.resize(300, 200)
+
+        layout = QVBoxLayout()
+
+        button = QPushButton("Click me!")
+        button.clicked.connect(self.button_clicked)
+        layout.addWidget(button)
+
+        central_widget = QWidget()
+        central_widget.setLayout(layout)
+        self.setCentralWidget(central_widget)
+
+    def button_clicked(self):
+        print("Button clicked!")
+
+def main():
+    app = QApplication(sys.argv)
+    window = MainWindow()
+    window.show()
+    sys.exit(app.exec())
+
+if __name__ == "__main__":
+    main()
+`
+
+	tkinterMain = `import tkinter as tk
+from tkinter import ttk
+
+def main():
+    root = tk.Tk()
+    root.title("Tkinter Boilerplate")
+    root.geometry("300x200")
+
+    label = ttk.Label(root, text="Hello Tkinter!")
+    label.pack(pady=20)
+
+    def button_clicked():
+        label.config(text="Button clicked!")
+
+    ttk.Button(root, text="Click me!", command=button_clicked).pack()
+    root.mainloop()
+
+if __name__ == "__main__":
+    main()
+`
+
+	kivyMain = `from kivy.app import App
+from kivy.uix.boxlayout import BoxLayout
+from kivy.uix.button import Button
+from kivy.uix.label import Label
+
+class MainApp(App):
+    title = "Kivy Boilerplate"
+
+    def build(self):
+        layout = BoxLayout(orientation="vertical", padding=20)
+        self.label = Label(text="Hello Kivy!")
+        button = Button(text="Click me!", size_hint=(1, 0.3))
+        button.bind(on_press=self.button_clicked)
+        layout.add_widget(self.label)
+        layout.add_widget(button)
+        return layout
+
+    def button_clicked(self, _):
+        self.label.text = "Button clicked!"
+
+if __name__ == "__main__":
+    MainApp().run()
+`
+
+	flaskMain = `from flask import Flask
+
+app = Flask(__name__)
+
+@app.route("/")
+def index():
+    return {"message": "Hello Flask!"}
+
+if __name__ == "__main__":
+    app.run(debug=True)
+`
+
+	fastAPIMain = `from fastapi import FastAPI
+
+app = FastAPI()
+
+@app.get("/")
+def index():
+    return {"message": "Hello FastAPI!"}
+`
+
+	clickMain = `import click
+
+@click.command()
+@click.option("--name", default="World", help="Who to greet.")
+def main(name):
+    click.echo(f"Hello, {name}!")
+
+if __name__ == "__main__":
+    main()
+`
+
+	pythonPlainMain = `def main():
+    print("Hello, Python!")
+
+if __name__ == "__main__":
+    main()
+`
+
+	goFyneMain = `package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+func main() {
+	myApp := app.New()
+	myWindow := myApp.NewWindow("Hello")
+	myWindow.Resize(fyne.NewSize(600, 400))
+
+	hello := widget.NewLabel("Hello Fyne!")
+	content := container.New(layout.NewVBoxLayout(),
+		hello,
+		widget.NewButton("Hi!", func() {
+			hello.SetText("Welcome :)")
+		}),
+	)
+	leftAligned := container.New(layout.NewHBoxLayout(), content, layout.NewSpacer())
+
+	myWindow.SetContent(leftAligned)
+	myWindow.ShowAndRun()
+}`
+
+	goGioMain = `package main
+
+import (
+	"log"
+	"os"
+
+	"gioui.org/app"
+	"gioui.org/op"
+	"gioui.org/text"
+	"gioui.org/widget/material"
+)
+
+func main() {
+	go func() {
+		w := new(app.Window)
+		w.Option(app.Title("Hello"))
+		if err := run(w); err != nil {
+			log.Fatal(err)
+		}
+		os.Exit(0)
+	}()
+	app.Main()
+}
+
+func run(w *app.Window) error {
+	th := material.NewTheme()
+	var ops op.Ops
+	for {
+		switch e := w.Event().(type) {
+		case app.DestroyEvent:
+			return e.Err
+		case app.FrameEvent:
+			gtx := app.NewContext(&ops, e)
+			title := material.H1(th, "Hello Gio!")
+			title.Alignment = text.Middle
+			title.Layout(gtx)
+			e.Frame(gtx.Ops)
+		}
+	}
+}
+`
+
+	goQtMain = `package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mappu/miqt/qt"
+)
+
+func main() {
+	qt.NewQApplication(os.Args)
+
+	btn := qt.NewQPushButton3("Hello Qt!")
+	btn.SetFixedWidth(320)
+
+	counter := 0
+	btn.OnPressed(func() {
+		counter++
+		btn.SetText(fmt.Sprintf("Clicked %d time(s)", counter))
+	})
+	btn.Show()
+
+	qt.QApplication_Exec()
+}
+`
+
+	rustEguiMain = `use eframe::egui;
+
+fn main() -> eframe::Result {
+    let options = eframe::NativeOptions {
+        viewport: egui::ViewportBuilder::default().with_inner_size([320.0, 240.0]),
+        ..Default::default()
+    };
+    eframe::run_native(
+        "Hello egui",
+        options,
+        Box::new(|_cc| Ok(Box::<App>::default())),
+    )
+}
+
+#[derive(Default)]
+struct App {
+    count: u32,
+}
+
+impl eframe::App for App {
+    fn update(&mut self, ctx: &egui::Context, _frame: &mut eframe::Frame) {
+        egui::CentralPanel::default().show(ctx, |ui| {
+            ui.heading("Hello egui!");
+            ui.label(format!("Count: {}", self.count));
+            if ui.button("Increment").clicked() {
+                self.count += 1;
+            }
+        });
+    }
+}
+`
+
+	rustIcedMain = `use iced::widget::{button, column, text, Column};
+
+fn main() -> iced::Result {
+    iced::run("Hello iced", Counter::update, Counter::view)
+}
+
+#[derive(Default)]
+struct Counter {
+    count: u32,
+}
+
+#[derive(Debug, Clone, Copy)]
+enum Message {
+    Increment,
+}
+
+impl Counter {
+    fn update(&mut self, message: Message) {
+        match message {
+            Message::Increment => self.count += 1,
+        }
+    }
+
+    fn view(&self) -> Column<Message> {
+        column![
+            text("Hello iced!"),
+            text(format!("Count: {}", self.count)),
+            button("Increment").on_press(Message::Increment),
+        ]
+        .padding(10)
+        .spacing(5)
+    }
+}
+`
+
+	rustSlintMain = `slint::slint! {
+    import { Button, VerticalBox } from "std-widgets.slint";
+
+    export component MainWindow inherits Window {
+        title: "Hello Slint";
+        in-out property <int> count: 0;
+
+        VerticalBox {
+            Text { text: "Hello Slint!"; }
+            Text { text: "Count: \{root.count}"; }
+            Button {
+                text: "Increment";
+                clicked => { root.count += 1; }
+            }
+        }
+    }
+}
+
+fn main() -> Result<(), slint::PlatformError> {
+    MainWindow::new()?.run()
+}
+`
+
+	rustGtkMain = `use gtk4 as gtk;
+use gtk::prelude::*;
+use gtk::{glib, Application, ApplicationWindow, Button};
+
+fn main() -> glib::ExitCode {
+    let app = Application::builder()
+        .application_id("org.example.HelloGtk")
+        .build();
+    app.connect_activate(build_ui);
+    app.run()
+}
+
+fn build_ui(app: &Application) {
+    let button = Button::builder()
+        .label("Hello gtk-rs!")
+        .margin_top(12)
+        .margin_bottom(12)
+        .margin_start(12)
+        .margin_end(12)
+        .build();
+    button.connect_clicked(|button| button.set_label("Hello again!"));
+
+    let window = ApplicationWindow::builder()
+        .application(app)
+        .title("Hello gtk-rs")
+        .child(&button)
+        .build();
+    window.present();
+}
+`
+
+	rustActixMain = `use actix_web::{get, App, HttpServer, Responder};
+
+#[get("/")]
+async fn index() -> impl Responder {
+    "Hello actix-web!"
+}
+
+#[actix_web::main]
+async fn main() -> std::io::Result<()> {
+    HttpServer::new(|| App::new().service(index))
+        .bind(("127.0.0.1", 8080))?
+        .run()
+        .await
+}
+`
+
+	rustClapMain = `use clap::{Arg, Command};
+
+fn main() {
+    let matches = Command::new("{{.ProjectName}}")
+        .about("Says hello")
+        .arg(Arg::new("name").long("name").default_value("World").help("Who to greet"))
+        .get_matches();
+    let name = matches.get_one::<String>("name").unwrap();
+    println!("Hello, {name}!");
+}
+`
+
+	rustWorkspaceToml = `[package]
+name = "{{.ProjectName}}"
+version = "0.1.0"
+edition = "2021"
+
+[dependencies]
+
+[workspace]
+members = ["crates/*"]
+`
+
+	rustCoreToml = `[package]
+name = "{{.ProjectName}}-core"
+version = "0.1.0"
+edition = "2021"
+
+[dependencies]
+`
+
+	rustCoreLib = `pub fn greeting(name: &str) -> String {
+    format!("Hello, {name}!")
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn greets() {
+        assert_eq!(greeting("Rust"), "Hello, Rust!");
+    }
+}
+`
+
+	javaScriptApp = `const express = require('express');
+const app = express();
+const port = 3000;
+
+app.get('/', (req, res) => {
+    res.send('Hello World!');
+});
+
+app.listen(port, () => {
+    console.log('Server running at http://localhost:' + port);
+});`
+
+	typeScriptIndex = `class Greeter {
+    constructor(private name: string) {}
+
+    greet(): void {
+        console.log("Hello, " + this.name + "!");
+    }
+}
+
+const greeter = new Greeter("World");
+greeter.greet();`
+
+	cmakeLists = `cmake_minimum_required(VERSION 3.10)
+project({{.ProjectName}})
+
+set(CMAKE_CXX_STANDARD 17)
+set(CMAKE_CXX_STANDARD_REQUIRED ON)
+
+add_executable(${PROJECT_NAME} src/main.cpp)
+target_include_directories(${PROJECT_NAME} PRIVATE include)`
+
+	cppMain = `#include <iostream>
+
+int main() {
+    std::cout << "Hello, C++17!" << std::endl;
+    return 0;
+}`
+
+	javaMain = `public class Main {
+    static String greeting(String name) {
+        return "Hello, " + name + "!";
+    }
+
+    public static void main(String[] args) {
+        System.out.println(greeting("Java"));
+    }
+}`
+)
+
+// languageDir ist das Verzeichnis eigener Sprachdefinitionen.
+func languageDir() string {
+	dir, err := configDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "languages")
+}
+
+// LoadLanguages registriert die Sprachdefinitionen aus languageDir als
+// zusätzliche Projekttypen.
+func LoadLanguages() {
+	paths, err := filepath.Glob(filepath.Join(languageDir(), "*.json"))
+	if err != nil {
+		log.Printf("Sprachdefinitionen suchen fehlgeschlagen: %v", err)
+		return
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Sprachdefinition %s lesen fehlgeschlagen: %v", path, err)
+			continue
+		}
+		lang := &language{}
+		if err := json.Unmarshal(data, lang); err != nil {
+			log.Printf("Sprachdefinition %s ungültig: %v", path, err)
+			continue
+		}
+		if lang.Name == "" {
+			log.Printf("Sprachdefinition %s ohne name, überspringe", path)
+			continue
+		}
+		if _, exists := ParseProjectType(lang.Name); exists {
+			log.Printf("Sprache %s: Projekttyp existiert bereits, überspringe", lang.Name)
+			continue
+		}
+		pt := ProjectType(len(ProjectTypeNames))
+		ProjectTypeNames = append(ProjectTypeNames, lang.Name)
+		languages[pt] = lang
+		log.Printf("Sprache geladen: %s", lang.Name)
+	}
+}
+
+// packages liefert die Standardpakete, bevorzugt die aus den Einstellungen.
+func (lang *language) packages(settings Settings) []string {
+	for name, packages := range settings.DefaultPackages {
+		if strings.EqualFold(name, lang.Name) {
+			return packages
+		}
+	}
+	return lang.Packages
+}
+
+// files liefert die Dateien passend zu den gewählten Paketen und zum Toolkit.
+func (lang *language) files(packages []string, tk *toolkit) map[string]string {
+	if tk != nil && tk.Bare {
+		return maps.Clone(tk.Files)
+	}
+	files := maps.Clone(lang.Files)
+	if files == nil {
+		files = make(map[string]string)
+	}
+	for pkg, override := range lang.WithPackage {
+		if slices.Contains(packages, pkg) {
+			maps.Copy(files, override)
+		}
+	}
+	if tk != nil {
+		maps.Copy(files, tk.Files)
+	}
+	return files
+}
+
+func (lang *language) ToolkitNames() []string {
+	names := make([]string, len(lang.Toolkits))
+	for i, tk := range lang.Toolkits {
+		names[i] = tk.Name
+	}
+	return names
+}
+
+func (lang *language) LayoutNames() []string {
+	names := make([]string, len(lang.Layouts))
+	for i, l := range lang.Layouts {
+		names[i] = l.Name
+	}
+	return names
+}
+
+// with liefert den Projekttyp mit den Befehlen des Toolkits, sofern es eigene hat.
+func (lang *language) with(tk *toolkit) *language {
+	if tk == nil {
+		return lang
+	}
+	l := *lang
+	if len(tk.Init) > 0 {
+		l.Init = tk.Init
+	}
+	if len(tk.Commands) > 0 {
+		l.Commands = tk.Commands
+	}
+	if tk.Run != "" {
+		l.Run, l.Terminal = tk.Run, ""
+	}
+	if tk.Check != "" {
+		l.Check = tk.Check
+	}
+	return &l
+}
+
+// Toolkit liefert das gewählte Toolkit samt Layout, nil bei Projekttypen
+// ohne Toolkits und Layouts.
+func (ps *ProjectSetup) Toolkit() (*toolkit, error) {
+	tk, err := ps.selectedToolkit()
+	if err != nil {
+		return nil, err
+	}
+	layout, err := ps.layout()
+	if err != nil || layout == nil {
+		return tk, err
+	}
+	return withLayout(tk, layout)
+}
+
+// layout liefert das gewählte Layout, nil bei Projekttypen ohne Layouts.
+func (ps *ProjectSetup) layout() (*toolkit, error) {
+	lang := ps.Language()
+	if lang == nil || len(lang.Layouts) == 0 {
+		if ps.LayoutName != "" {
+			return nil, fmt.Errorf("projekttyp %s hat keine layouts", ps.ProjectType)
+		}
+		return nil, nil
+	}
+	if ps.LayoutName == "" {
+		return &lang.Layouts[0], nil
+	}
+	for i, l := range lang.Layouts {
+		if strings.EqualFold(l.Name, ps.LayoutName) {
+			return &lang.Layouts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unbekanntes layout %q für %s (%s)", ps.LayoutName, lang.Name, strings.Join(lang.LayoutNames(), ", "))
+}
+
+// withLayout ergänzt tk um layout. Ein Toolkit, das das Projekt selbst
+// anlegt (create-tauri-app), verträgt kein Layout mit Init oder Dateien.
+func withLayout(tk, layout *toolkit) (*toolkit, error) {
+	var combined toolkit
+	if tk != nil {
+		combined = *tk
+	}
+	if len(combined.Init) > 0 && (len(layout.Init) > 0 || len(layout.Files) > 0) {
+		return nil, fmt.Errorf("toolkit %s legt das projekt selbst an, layout %s ist damit nicht möglich", combined.Name, layout.Name)
+	}
+	combined.Files = maps.Clone(combined.Files)
+	if combined.Files == nil {
+		combined.Files = make(map[string]string)
+	}
+	maps.Copy(combined.Files, layout.Files)
+	combined.Packages = slices.Concat(combined.Packages, layout.Packages)
+	combined.Toolchain = slices.Concat(combined.Toolchain, layout.Toolchain)
+	if len(combined.Init) == 0 {
+		combined.Init = layout.Init
+	}
+	if len(combined.Commands) == 0 {
+		combined.Commands = layout.Commands
+	}
+	if layout.Run != "" {
+		combined.Run = layout.Run
+	}
+	if layout.Check != "" {
+		combined.Check = layout.Check
+	}
+	if layout.Dir != "" {
+		combined.Dir = layout.Dir
+	}
+	return &combined, nil
+}
+
+// selectedToolkit liefert das gewählte Toolkit ohne Layout.
+func (ps *ProjectSetup) selectedToolkit() (*toolkit, error) {
+	lang := ps.Language()
+	if lang == nil || len(lang.Toolkits) == 0 {
+		if ps.ToolkitName != "" {
+			return nil, fmt.Errorf("projekttyp %s hat keine toolkits", ps.ProjectType)
+		}
+		return nil, nil
+	}
+	name := ps.ToolkitName
+	if name == "" {
+		for typeName, tk := range ps.Settings.DefaultToolkits {
+			if strings.EqualFold(typeName, lang.Name) {
+				name = tk
+			}
+		}
+	}
+	if name == "" {
+		return &lang.Toolkits[0], nil
+	}
+	for i, tk := range lang.Toolkits {
+		if strings.EqualFold(tk.Name, name) {
+			return &lang.Toolkits[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unbekanntes toolkit %q für %s (%s)", name, lang.Name, strings.Join(lang.ToolkitNames(), ", "))
+}
+
+// scaffoldPackages sind die Standardpakete samt denen des Toolkits.
+func (ps *ProjectSetup) scaffoldPackages(lang *language, tk *toolkit) []string {
+	if tk != nil && tk.Bare {
+		return tk.Packages
+	}
+	packages := lang.packages(ps.Settings)
+	if tk != nil {
+		packages = slices.Concat(packages, tk.Packages)
+	}
+	return packages
+}
+
+// Language liefert die Beschreibung des gewählten Projekttyps, nil bei Plugin-Typen.
+func (ps *ProjectSetup) Language() *language {
+	return languages[ps.ProjectType]
+}
+
+// checkToolchain prüft die Werkzeuge des Projekttyps.
+func (ps *ProjectSetup) checkToolchain() error {
+	// Mit externem Generator braucht es dessen Werkzeuge statt des Grundgerüsts
+	if ps.Template != nil && ps.Template.Generator != "" {
+		g, err := findGenerator(ps.Template.Generator)
+		if err != nil {
+			return err
+		}
+		return ps.checkTools(g.Toolchain)
+	}
+	lang := ps.Language()
+	if lang == nil {
+		return nil
+	}
+	tk, err := ps.Toolkit()
+	if err != nil {
+		return err
+	}
+	tools := lang.Toolchain
+	switch {
+	case tk != nil && tk.Bare:
+		tools = tk.Toolchain
+	case tk != nil:
+		tools = slices.Concat(tools, tk.Toolchain)
+	}
+	return ps.checkTools(slices.Concat(tools, ps.packageManagerTools()))
+}
+
+// checkTools prüft die Werkzeuge über ihre Versionsbefehle.
+func (ps *ProjectSetup) checkTools(tools []toolCheck) error {
+	for _, tool := range tools {
+		if len(tool.Command) == 0 {
+			continue
+		}
+		if err := ps.recordToolVersion(tool.Command[0], tool.Command[1:]...); err != nil {
+			return fmt.Errorf("%s ist nicht installiert: %v", tool.Label, err)
+		}
+	}
+	return nil
+}
+
+// scaffold legt das Grundgerüst des Projekttyps an.
+func (ps *ProjectSetup) scaffold(lang *language) error {
+	tk, err := ps.Toolkit()
+	if err != nil {
+		return err
+	}
+	if tk != nil && tk.Name != "" {
+		ps.step(fmt.Sprintf("Erstelle %s-Projekt mit %s...", lang.Name, tk.Name))
+	} else {
+		ps.step(fmt.Sprintf("Erstelle %s-Projekt...", lang.Name))
+	}
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	modes := ps.Settings.Modes()
+	packages := ps.scaffoldPackages(lang, tk)
+	files := lang.files(packages, tk)
+	lang = ps.withPackageManager(lang.with(tk))
+
+	initDir := ps.ParentPath
+	if tk != nil && tk.Dir != "" {
+		if !filepath.IsLocal(filepath.FromSlash(tk.Dir)) {
+			return fmt.Errorf("verzeichnis %q liegt außerhalb des projekts", tk.Dir)
+		}
+		initDir = filepath.Join(projectDir, tk.Dir)
+		if err := modes.mkdirAll(initDir); err != nil {
+			return fmt.Errorf("projektverzeichnis erstellen fehlgeschlagen: %v", err)
+		}
+	}
+	if len(lang.Init) > 0 {
+		if err := ps.runCommands(initDir, lang.Init, false); err != nil {
+			return err
+		}
+	} else if err := modes.mkdirAll(projectDir); err != nil {
+		return fmt.Errorf("projektverzeichnis erstellen fehlgeschlagen: %v", err)
+	}
+
+	if len(lang.Dirs) > 0 {
+		ps.step("Erstelle Projektstruktur...")
+	}
+	for _, dir := range lang.Dirs {
+		if !filepath.IsLocal(filepath.FromSlash(dir)) {
+			return fmt.Errorf("verzeichnis %q liegt außerhalb des projekts", dir)
+		}
+		if err := modes.mkdirAll(filepath.Join(projectDir, dir)); err != nil {
+			return fmt.Errorf("verzeichnis %s erstellen fehlgeschlagen: %v", dir, err)
+		}
+	}
+
+	data := &scriptResult{vars: map[string]string{
+		"ProjectName": ps.ProjectName,
+		"Packages":    strings.Join(packages, "\n"),
+		"Module":      ps.goModule(),
+		"Package":     goPackageName(ps.goModule()),
+	}, text: ps.text}
+	for path, content := range files {
+		if !filepath.IsLocal(filepath.FromSlash(path)) {
+			return fmt.Errorf("datei %q liegt außerhalb des projekts", path)
+		}
+		content, err := data.render(path, content)
+		if err != nil {
+			return err
+		}
+		if err := modes.writeFile(filepath.Join(projectDir, path), []byte(content), modes.File); err != nil {
+			return fmt.Errorf("datei %s erstellen fehlgeschlagen: %v", path, err)
+		}
+	}
+
+	if err := ps.linkLocalDir(projectDir); err != nil {
+		return err
+	}
+	// Ohne Installation entfallen die Installationsbefehle und die mit
+	// {packages} (eigene Sprachen), die Paketliste in den Dateien bleibt
+	commands := lang.Commands
+	if ps.skipInstall {
+		commands, packages = withoutInstall(commands), nil
+	}
+	return ps.runCommands(projectDir, ps.expandLocalCommands(expandPackages(commands, packages)), false)
+}
+
+// expandPackages setzt die Paketliste für das Argument "{packages}" ein.
+// Befehle, deren Liste leer bliebe, entfallen.
+func expandPackages(commands []TemplateCommand, packages []string) []TemplateCommand {
+	var out []TemplateCommand
+	for _, c := range commands {
+		i := slices.Index(c.Run, "{packages}")
+		if i < 0 {
+			out = append(out, c)
+			continue
+		}
+		if len(packages) == 0 {
+			continue
+		}
+		c.Run = slices.Concat(c.Run[:i], packages, c.Run[i+1:])
+		out = append(out, c)
+	}
+	return out
+}
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestToolkitLayout(t *testing.T) {
+	tests := []struct {
+		toolkit, layout string
+		wantInit        string
+		wantRun         string
+		wantFiles       []string
+		wantErr         bool
+	}{
+		{"clap", "", "cargo new {name}", "cargo run -- --help", []string{"src/main.rs"}, false},
+		{"clap", "library", "cargo new --lib {name}", "cargo test", []string{"src/main.rs"}, false},
+		{"actix-web", "Workspace", "cargo new {name}", "cargo run", []string{"Cargo.toml", "crates/core/Cargo.toml", "crates/core/src/lib.rs", "src/main.rs"}, false},
+		{"Tauri", "Binary", "cargo create-tauri-app {name} --manager cargo --template vanilla --yes", "cargo tauri dev", nil, false},
+		{"Tauri", "Library", "", "", nil, true},
+		{"None", "Monorepo", "", "", nil, true},
+	}
+	for _, tt := range tests {
+		ps := &ProjectSetup{ProjectType: Rust, ToolkitName: tt.toolkit, LayoutName: tt.layout}
+		tk, err := ps.Toolkit()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s/%s: err = %v, wantErr %v", tt.toolkit, tt.layout, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		lang := ps.Language().with(tk)
+		if got := strings.Join(lang.Init[0].Run, " "); got != tt.wantInit {
+			t.Errorf("%s/%s: init = %q, want %q", tt.toolkit, tt.layout, got, tt.wantInit)
+		}
+		if lang.Run != tt.wantRun {
+			t.Errorf("%s/%s: run = %q, want %q", tt.toolkit, tt.layout, lang.Run, tt.wantRun)
+		}
+		var files []string
+		for name := range tk.Files {
+			files = append(files, name)
+		}
+		slices.Sort(files)
+		if !slices.Equal(files, tt.wantFiles) {
+			t.Errorf("%s/%s: files = %q, want %q", tt.toolkit, tt.layout, files, tt.wantFiles)
+		}
+	}
+}
+
+func TestCppToolkits(t *testing.T) {
+	tests := []struct {
+		toolkit    string
+		buildFile  string
+		generators string
+		wantFmt    bool
+	}{
+		{"CMake", "CMakeLists.txt", "", false},
+		{"cmake+vcpkg", "CMakeLists.txt", "", true},
+		{"CMake+Conan", "CMakeLists.txt", "CMakeDeps\nCMakeToolchain", true},
+		{"Meson", "meson.build", "", false},
+		{"Meson+Conan", "meson.build", "PkgConfigDeps\nMesonToolchain", true},
+		{"Make", "Makefile", "", false},
+	}
+	data := &scriptResult{vars: map[string]string{"ProjectName": "demo"}}
+	for _, tt := range tests {
+		ps := &ProjectSetup{ProjectType: CPlusPlus, ToolkitName: tt.toolkit}
+		tk, err := ps.Toolkit()
+		if err != nil {
+			t.Errorf("%s: %v", tt.toolkit, err)
+			continue
+		}
+		files := ps.Language().files(nil, tk)
+		for _, name := range []string{tt.buildFile, "src/main.cpp"} {
+			if _, ok := files[name]; !ok {
+				t.Errorf("%s: %s fehlt", tt.toolkit, name)
+			}
+		}
+		for name, content := range files {
+			if _, err := data.render(name, content); err != nil {
+				t.Errorf("%s: %v", tt.toolkit, err)
+			}
+		}
+		if got := strings.Contains(files["src/main.cpp"], "fmt::print"); got != tt.wantFmt {
+			t.Errorf("%s: fmt in main.cpp = %v, want %v", tt.toolkit, got, tt.wantFmt)
+		}
+		if tt.generators != "" && !strings.HasSuffix(files["conanfile.txt"], "[generators]\n"+tt.generators+"\n") {
+			t.Errorf("%s: conanfile.txt = %q, want generators %q", tt.toolkit, files["conanfile.txt"], tt.generators)
+		}
+	}
+}
+
+func TestJavaToolkits(t *testing.T) {
+	tests := []struct {
+		toolkit     string
+		wantRun     string
+		wantFile    string
+		wantMain    bool
+		wantCommand string
+	}{
+		{"", "./mvnw -q compile exec:java", "pom.xml", true, "mvn -B -q wrapper:wrapper"},
+		{"Gradle", "./gradlew run", "build.gradle.kts", true, "gradle -q wrapper"},
+		{"Spring Boot+Maven", "./mvnw spring-boot:run", "src/main/java/com/example/app/Application.java", false, "mvn -B -q wrapper:wrapper"},
+		{"spring boot+gradle", "./gradlew bootRun", "build.gradle.kts", false, "gradle -q wrapper"},
+		{"javac", "javac src/main/java/Main.java && java -cp src/main/java Main", "src/main/java/Main.java", true, ""},
+	}
+	for _, tt := range tests {
+		ps := &ProjectSetup{ProjectType: Java, ToolkitName: tt.toolkit}
+		tk, err := ps.Toolkit()
+		if err != nil {
+			t.Errorf("%s: %v", tt.toolkit, err)
+			continue
+		}
+		lang := ps.Language().with(tk)
+		files := lang.files(nil, tk)
+		if _, ok := files[tt.wantFile]; !ok {
+			t.Errorf("%s: %s fehlt", tt.toolkit, tt.wantFile)
+		}
+		// Spring Boot verlangt ein benanntes Paket, Main.java entfällt
+		if _, ok := files["src/main/java/Main.java"]; ok != tt.wantMain {
+			t.Errorf("%s: Main.java vorhanden = %v, want %v", tt.toolkit, ok, tt.wantMain)
+		}
+		var command string
+		if len(lang.Commands) > 0 {
+			command = strings.Join(lang.Commands[0].Run, " ")
+		}
+		if lang.Run != tt.wantRun || command != tt.wantCommand {
+			t.Errorf("%s: run %q, command %q, want %q, %q", tt.toolkit, lang.Run, command, tt.wantRun, tt.wantCommand)
+		}
+	}
+}
+
+func TestKotlinToolkits(t *testing.T) {
+	tests := []struct {
+		toolkit     string
+		wantRun     string
+		wantGradle  bool
+		wantContent string
+	}{
+		{"", `./gradlew run --args="--name Kotlin"`, true, "CliktCommand"},
+		{"Ktor", "./gradlew run", true, "embeddedServer"},
+		{"None", "./gradlew run", true, `println(greeting("Kotlin"))`},
+		{"kotlinc", "kotlinc src/main/kotlin/Main.kt -include-runtime -d build/main.jar && java -jar build/main.jar", false, `println(greeting("Kotlin"))`},
+	}
+	for _, tt := range tests {
+		ps := &ProjectSetup{ProjectType: Kotlin, ToolkitName: tt.toolkit}
+		tk, err := ps.Toolkit()
+		if err != nil {
+			t.Errorf("%s: %v", tt.toolkit, err)
+			continue
+		}
+		lang := ps.Language().with(tk)
+		files := lang.files(nil, tk)
+		if !strings.Contains(files["src/main/kotlin/Main.kt"], tt.wantContent) {
+			t.Errorf("%s: Main.kt enthält %q nicht", tt.toolkit, tt.wantContent)
+		}
+		// Nur mit Gradle entsteht der Wrapper
+		_, gradle := files["settings.gradle.kts"]
+		if lang.Run != tt.wantRun || gradle != tt.wantGradle || (len(lang.Commands) > 0) != tt.wantGradle {
+			t.Errorf("%s: run %q, gradle %v, %d commands, want %q, %v", tt.toolkit, lang.Run, gradle, len(lang.Commands), tt.wantRun, tt.wantGradle)
+		}
+	}
+}
+
+func TestZigLayouts(t *testing.T) {
+	tests := []struct {
+		layout    string
+		wantRun   string
+		wantBuild string
+		wantFile  string
+	}{
+		{"", "zig build run", "b.addExecutable", "src/main.zig"},
+		{"Library", "zig build test", "b.addLibrary", "src/root.zig"},
+	}
+	for _, tt := range tests {
+		ps := &ProjectSetup{ProjectType: Zig, LayoutName: tt.layout}
+		tk, err := ps.Toolkit()
+		if err != nil {
+			t.Errorf("%s: %v", tt.layout, err)
+			continue
+		}
+		lang := ps.Language().with(tk)
+		files := lang.files(nil, tk)
+		// zig init legt das Projekt im Projektverzeichnis an
+		if tk.Dir != "." || len(lang.Init) != 1 {
+			t.Errorf("%s: dir %q, %d init", tt.layout, tk.Dir, len(lang.Init))
+		}
+		if lang.Run != tt.wantRun || !strings.Contains(files["build.zig"], tt.wantBuild) || files[tt.wantFile] == "" {
+			t.Errorf("%s: run %q, build.zig ohne %s oder %s fehlt", tt.layout, lang.Run, tt.wantBuild, tt.wantFile)
+		}
+	}
+}
+
+func TestDotnetToolkits(t *testing.T) {
+	tests := []struct {
+		toolkit, layout string
+		wantInit        string
+		wantRun         string
+		wantDir         string
+		wantCommands    int
+	}{
+		{"", "", "dotnet new console -n {name}", "dotnet run", "", 0},
+		{"WebAPI", "Project", "dotnet new webapi -n {name}", "dotnet run", "", 0},
+		{"Library", "", "dotnet new classlib -n {name}", "dotnet build", "", 0},
+		{"Console", "Solution", "dotnet new console -n {name}", "dotnet test", "src", 4},
+		{"Avalonia", "solution", "dotnet new avalonia.app -n {name}", "dotnet test", "src", 4},
+	}
+	for _, tt := range tests {
+		ps := &ProjectSetup{ProjectType: CSharp, ToolkitName: tt.toolkit, LayoutName: tt.layout}
+		tk, err := ps.Toolkit()
+		if err != nil {
+			t.Errorf("%s/%s: %v", tt.toolkit, tt.layout, err)
+			continue
+		}
+		lang := ps.Language().with(tk)
+		if got := strings.Join(lang.Init[len(lang.Init)-1].Run, " "); got != tt.wantInit {
+			t.Errorf("%s/%s: init = %q, want %q", tt.toolkit, tt.layout, got, tt.wantInit)
+		}
+		if lang.Run != tt.wantRun || tk.Dir != tt.wantDir || len(lang.Commands) != tt.wantCommands {
+			t.Errorf("%s/%s: run %q, dir %q, %d commands, want %q, %q, %d", tt.toolkit, tt.layout, lang.Run, tk.Dir, len(lang.Commands), tt.wantRun, tt.wantDir, tt.wantCommands)
+		}
+	}
+}
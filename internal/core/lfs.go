@@ -0,0 +1,103 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Git LFS für Projekte mit großen Binärdateien (Spiele, ML-Modelle): Die
+// Muster aus Template.LFS oder, mit ps.GitLFS, lfsPatterns stehen als aktive
+// LFS-Regeln in der .gitattributes, und vor dem ersten Commit richtet `git lfs
+// install --local` die Filter ein. Große Dateien, die trotzdem direkt im Repository
+// landen würden, meldet newpipi vor dem Commit.
+
+// lfsWarnSize ist die Größe, ab der eine Datei ohne LFS gemeldet wird.
+const lfsWarnSize = 10 << 20
+
+// lfsHooks sind die Hooks, die git lfs install anlegt. Mit core.hooksPath auf
+// .githooks (siehe githooks.go) gehören sie dorthin.
+var lfsHooks = []string{"pre-push", "post-checkout", "post-commit", "post-merge"}
+
+// lfsHook ruft den gleichnamigen Befehl von git lfs auf, wie es git lfs
+// install tut; %[1]s ist der Hook-Name, %[2]s und %[3]s sind die Texte in der
+// Sprache erzeugter Inhalte.
+const lfsHook = `#!/bin/sh
+# %[2]s: Git LFS
+command -v git-lfs >/dev/null 2>&1 || { echo "%[3]s" >&2; exit 2; }
+git lfs %[1]s "$@"
+`
+
+// lfsTracked liefert die LFS-Muster des Projekts, ohne LFS nil. Templates mit
+// LFS-Mustern schalten LFS immer ein.
+func (ps *ProjectSetup) lfsTracked() []string {
+	switch {
+	case !ps.gitRepo():
+		return nil
+	case ps.Template != nil && len(ps.Template.LFS) > 0:
+		return ps.Template.LFS
+	case ps.GitLFS:
+		return lfsPatterns
+	}
+	return nil
+}
+
+// hasGitLFS meldet, ob git-lfs installiert ist.
+func hasGitLFS() bool {
+	return exec.Command("git", "lfs", "version").Run() == nil
+}
+
+// checkLFS warnt vorab, wenn git-lfs fehlt; die Erstellung läuft dann ohne LFS.
+func (ps *ProjectSetup) checkLFS() PreflightCheck {
+	c := PreflightCheck{Name: "Git LFS", Detail: strings.Join(ps.lfsTracked(), " ")}
+	if !hasGitLFS() {
+		c.Err = fmt.Errorf("%w: git-lfs nicht installiert, große binärdateien landen direkt im repository", errToolchainMissing)
+		c.Warning = true
+	}
+	return c
+}
+
+// lfsConfig sind die Befehle, die LFS im neuen Repository einrichten.
+func (ps *ProjectSetup) lfsConfig() [][]string {
+	if ps.lfsTracked() == nil {
+		return nil
+	}
+	if !hasGitLFS() {
+		log.Println("git-lfs nicht installiert, LFS übersprungen")
+		return nil
+	}
+	// Mit eigenen Hooks liegen die LFS-Hooks schon in .githooks
+	args := []string{"git", "lfs", "install", "--local"}
+	if ps.GitHooks {
+		args = append(args, "--skip-repo")
+	}
+	return [][]string{args}
+}
+
+// warnLargeFiles meldet vor dem ersten Commit Dateien ab lfsWarnSize, die
+// git add übernehmen würde, ohne dass ein LFS-Muster sie erfasst.
+func (ps *ProjectSetup) warnLargeFiles(projectDir string) {
+	out, err := gitOutput(projectDir, "ls-files", "-z", "--others", "--exclude-standard")
+	if err != nil {
+		log.Printf("Große Dateien suchen fehlgeschlagen: %v", err)
+		return
+	}
+	tracked := ps.lfsTracked()
+files:
+	for _, rel := range strings.Split(out, "\x00") {
+		info, err := os.Stat(filepath.Join(projectDir, rel))
+		if rel == "" || err != nil || info.Size() < lfsWarnSize {
+			continue
+		}
+		for _, pattern := range tracked {
+			if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+				continue files
+			}
+		}
+		ps.step(fmt.Sprintf("Warnung: %s (%d MB) landet ohne Git LFS im Repository", rel, info.Size()>>20))
+	}
+}
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLFSTracked(t *testing.T) {
+	tests := []struct {
+		name     string
+		gitLFS   bool
+		skipGit  bool
+		template *Template
+		want     []string
+	}{
+		{"aus", false, false, nil, nil},
+		{"standardmuster", true, false, nil, lfsPatterns},
+		{"template", false, false, &Template{LFS: []string{"*.blend"}}, []string{"*.blend"}},
+		{"template ohne muster", true, false, &Template{}, lfsPatterns},
+		{"ohne git", true, true, &Template{LFS: []string{"*.blend"}}, nil},
+	}
+	for _, tt := range tests {
+		ps := &ProjectSetup{GitLFS: tt.gitLFS, SkipGit: tt.skipGit, Template: tt.template}
+		if got := ps.lfsTracked(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: lfsTracked() = %v, erwartet %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Nach der Paketinstallation sammelt newpipi auf Wunsch die Lizenzen der
+// Abhängigkeiten mit dem Werkzeug des Ökosystems und markiert Lizenzen, die
+// Settings.LicenseDeny (ohne Angabe copyleftLicenses) nennt oder die die
+// Richtlinie (siehe policy.go) nicht erlaubt.
+
+// copyleftLicenses sind SPDX-Präfixe der Copyleft-Lizenzen, die ohne eigene
+// Richtlinie markiert werden.
+var copyleftLicenses = []string{"GPL", "AGPL", "LGPL", "SSPL", "EUPL", "OSL"}
+
+// licensePackage ist eine Abhängigkeit mit ihrer Lizenz.
+type licensePackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	License string `json:"license"`
+	// Denied ist gesetzt, wenn die Lizenz gegen die Richtlinie verstößt.
+	Denied bool `json:"denied,omitempty"`
+}
+
+// LicenseReport fasst die Lizenzen der Abhängigkeiten zusammen.
+type LicenseReport struct {
+	Tool     string           `json:"tool"`
+	Packages []licensePackage `json:"packages"`
+}
+
+// denied liefert die Pakete, deren Lizenz gegen die Richtlinie verstößt.
+func (r *LicenseReport) denied() []licensePackage {
+	var out []licensePackage
+	for _, p := range r.Packages {
+		if p.Denied {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// summary ist die einzeilige Zusammenfassung für Fortschrittsanzeigen.
+func (r *LicenseReport) summary() string {
+	counts := make(map[string]int)
+	for _, p := range r.Packages {
+		counts[p.License]++
+	}
+	var parts []string
+	for license, n := range counts {
+		parts = append(parts, fmt.Sprintf("%d %s", n, license))
+	}
+	sort.Strings(parts)
+	s := fmt.Sprintf("%s: %d Pakete (%s)", r.Tool, len(r.Packages), strings.Join(parts, ", "))
+	if denied := r.denied(); len(denied) > 0 {
+		s += fmt.Sprintf(", %d gegen die Lizenzrichtlinie", len(denied))
+	}
+	return s
+}
+
+// Details listet zuerst die markierten Pakete, dann alle übrigen.
+func (r *LicenseReport) Details() string {
+	var b strings.Builder
+	b.WriteString(r.summary() + "\n")
+	for _, denied := range []bool{true, false} {
+		for _, p := range r.Packages {
+			if p.Denied != denied {
+				continue
+			}
+			mark := " "
+			if p.Denied {
+				mark = "!"
+			}
+			fmt.Fprintf(&b, "%s %s %s: %s\n", mark, p.Name, p.Version, p.License)
+		}
+	}
+	return b.String()
+}
+
+// licenseDenied prüft einen SPDX-Ausdruck gegen die Präfixe aus deny.
+func licenseDenied(expression string, deny []string) bool {
+	return licenseExpressionDenied(expression, func(id string) bool {
+		id = strings.ToUpper(id)
+		for _, prefix := range deny {
+			if p := strings.ToUpper(prefix); id == p || strings.HasPrefix(id, p+"-") {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// licenseExpressionDenied wertet einen SPDX-Ausdruck aus. Bei "OR" genügt
+// eine erlaubte Alternative, bei "AND" muss jeder Teil erlaubt sein.
+func licenseExpressionDenied(expression string, deniedID func(id string) bool) bool {
+	// Werkzeuge trennen Alternativen auch mit "/" oder ";"
+	expression = strings.NewReplacer("/", " OR ", ";", " OR ", " or ", " OR ", " and ", " AND ").Replace(expression)
+	for _, alternative := range strings.Split(expression, " OR ") {
+		allowed := true
+		for _, id := range strings.Split(alternative, " AND ") {
+			if deniedID(strings.Trim(id, "() ")) {
+				allowed = false
+			}
+		}
+		if allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// licenseParsers werten die Ausgabe der Lizenz-Werkzeuge aus; language.Licenses
+// wählt sie über Output. project ist der Name des Projekts selbst.
+var licenseParsers = map[string]func(out []byte, project string) ([]licensePackage, error){
+	"license-checker": func(out []byte, _ string) ([]licensePackage, error) { return parseLicenseChecker(out) },
+	"pip-licenses":    func(out []byte, _ string) ([]licensePackage, error) { return parsePipLicenses(out) },
+	"cargo-license":   parseCargoLicense,
+	"go-licenses":     func(out []byte, _ string) ([]licensePackage, error) { return parseGoLicenses(out) },
+}
+
+// collectLicenses sammelt die Lizenzen der installierten Abhängigkeiten. Fehlt
+// das Werkzeug, wird der Schritt übersprungen.
+func (ps *ProjectSetup) collectLicenses(projectDir string) (*LicenseReport, error) {
+	var tool *projectTool
+	if lang := ps.Language(); lang != nil {
+		tool = lang.Licenses
+	}
+	parse := licenseParsers[tool.output()]
+	args, ok := ps.toolArgs(projectDir, tool, nil)
+	if parse == nil || !ok {
+		log.Printf("Keine Lizenzübersicht für %s verfügbar", ps.ProjectType)
+		return nil, nil
+	}
+	if missing := tool.missing(); missing != "" {
+		ps.step(fmt.Sprintf("Lizenzübersicht übersprungen: %s nicht installiert", missing))
+		return nil, nil
+	}
+
+	ps.step(fmt.Sprintf("Sammle Lizenzen mit %s...", args[0]))
+	cmd := ps.command(args[0], args[1:]...)
+	cmd.Dir = projectDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("lizenzen mit %s sammeln fehlgeschlagen: %v", args[0], err)
+	}
+	packages, err := parse(out, ps.ProjectName)
+	if err != nil {
+		return nil, fmt.Errorf("ausgabe von %s nicht lesbar: %v", args[0], err)
+	}
+	deny := ps.Settings.LicenseDeny
+	if len(deny) == 0 {
+		deny = copyleftLicenses
+	}
+	policy := ps.Settings.Policy
+	deny = append(slices.Clone(deny), policy.DeniedLicenses...)
+	for i := range packages {
+		if packages[i].License == "" {
+			packages[i].License = "unbekannt"
+		}
+		packages[i].Denied = licenseDenied(packages[i].License, deny) || !policy.licenseAllowed(packages[i].License)
+	}
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+	report := &LicenseReport{Tool: args[0], Packages: packages}
+	ps.step(report.summary())
+	return report, nil
+}
+
+func parseLicenseChecker(out []byte) ([]licensePackage, error) {
+	var result map[string]struct {
+		// licenses ist ein String oder bei mehreren Lizenzen eine Liste
+		Licenses any `json:"licenses"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, err
+	}
+	var packages []licensePackage
+	for key, p := range result {
+		// Schlüssel sind "name@version", bei Scopes "@scope/name@version"
+		at := strings.LastIndex(key, "@")
+		if at <= 0 {
+			at = len(key)
+		}
+		license := ""
+		switch l := p.Licenses.(type) {
+		case string:
+			license = l
+		case []any:
+			var ids []string
+			for _, id := range l {
+				if s, ok := id.(string); ok {
+					ids = append(ids, s)
+				}
+			}
+			license = strings.Join(ids, " OR ")
+		}
+		packages = append(packages, licensePackage{Name: key[:at], Version: strings.TrimPrefix(key[at:], "@"), License: license})
+	}
+	return packages, nil
+}
+
+func parsePipLicenses(out []byte) ([]licensePackage, error) {
+	var result []struct {
+		Name    string `json:"Name"`
+		Version string `json:"Version"`
+		License string `json:"License"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, err
+	}
+	var packages []licensePackage
+	for _, p := range result {
+		packages = append(packages, licensePackage{Name: p.Name, Version: p.Version, License: p.License})
+	}
+	return packages, nil
+}
+
+// parseCargoLicense liest cargo license --json; das Projekt selbst zählt nicht.
+func parseCargoLicense(out []byte, crate string) ([]licensePackage, error) {
+	var result []struct {
+		Name    string  `json:"name"`
+		Version string  `json:"version"`
+		License *string `json:"license"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, err
+	}
+	var packages []licensePackage
+	for _, p := range result {
+		if p.Name == crate {
+			continue
+		}
+		license := ""
+		if p.License != nil {
+			license = *p.License
+		}
+		packages = append(packages, licensePackage{Name: p.Name, Version: p.Version, License: license})
+	}
+	return packages, nil
+}
+
+// parseGoLicenses liest den CSV-Bericht von go-licenses: Modul, URL, Lizenz.
+func parseGoLicenses(out []byte) ([]licensePackage, error) {
+	r := csv.NewReader(strings.NewReader(string(out)))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var packages []licensePackage
+	for _, rec := range records {
+		if len(rec) < 3 {
+			continue
+		}
+		packages = append(packages, licensePackage{Name: rec[0], License: rec[2]})
+	}
+	return packages, nil
+}
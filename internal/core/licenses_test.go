@@ -0,0 +1,56 @@
+package core
+
+import "testing"
+
+func TestLicenseDenied(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"MIT", false},
+		{"Apache-2.0", false},
+		{"GPL-3.0-only", true},
+		{"GPL-2.0-or-later", true},
+		{"LGPL-2.1", true},
+		{"AGPL-3.0", true},
+		{"GPL", true},
+		{"GPLv3", false},
+		{"MIT OR GPL-3.0", false},
+		{"GPL-2.0 OR LGPL-3.0", true},
+		{"(MIT AND GPL-3.0)", true},
+		{"MIT/Apache-2.0", false},
+		{"Apache-2.0 WITH LLVM-exception", false},
+		{"unbekannt", false},
+	}
+	for _, tt := range tests {
+		if got := licenseDenied(tt.expression, copyleftLicenses); got != tt.want {
+			t.Errorf("licenseDenied(%q) = %v, erwartet %v", tt.expression, got, tt.want)
+		}
+	}
+}
+
+func TestParseLicenses(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func([]byte) ([]licensePackage, error)
+		out   string
+		want  licensePackage
+	}{
+		{"license-checker", parseLicenseChecker, `{"@scope/pkg@1.2.3": {"licenses": ["MIT", "Apache-2.0"]}}`, licensePackage{Name: "@scope/pkg", Version: "1.2.3", License: "MIT OR Apache-2.0"}},
+		{"pip-licenses", parsePipLicenses, `[{"Name": "click", "Version": "8.1.7", "License": "BSD License"}]`, licensePackage{Name: "click", Version: "8.1.7", License: "BSD License"}},
+		{"cargo-license", func(out []byte) ([]licensePackage, error) { return parseCargoLicense(out, "demo") },
+			`[{"name": "demo", "version": "0.1.0", "license": null}, {"name": "serde", "version": "1.0.0", "license": "MIT OR Apache-2.0"}]`,
+			licensePackage{Name: "serde", Version: "1.0.0", License: "MIT OR Apache-2.0"}},
+		{"go-licenses", parseGoLicenses, "github.com/spf13/cobra,https://github.com/spf13/cobra/blob/HEAD/LICENSE.txt,Apache-2.0\n", licensePackage{Name: "github.com/spf13/cobra", License: "Apache-2.0"}},
+	}
+	for _, tt := range tests {
+		got, err := tt.parse([]byte(tt.out))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("%s: %+v, erwartet %+v", tt.name, got, tt.want)
+		}
+	}
+}
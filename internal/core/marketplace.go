@@ -0,0 +1,272 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Der Marktplatz ist ein JSON-Index mit Community-Templates unter einer
+// konfigurierbaren URL:
+//
+//	{"templates": [{"name": "FastAPI", "type": "Python", "description": "...",
+//	  "author": "...", "rating": 4.5, "url": "fastapi.json"}]}
+//
+// Relative URLs der Einträge beziehen sich auf die Adresse des Index. Ist ein
+// minisign-Schlüssel konfiguriert, muss der Index unter <url>.minisig signiert
+// sein und jeder Eintrag den SHA-256 seiner Template-Datei nennen.
+
+// marketEntry ist ein Template im Marktplatz-Index.
+type marketEntry struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Description string  `json:"description"`
+	Author      string  `json:"author,omitempty"`
+	Rating      float64 `json:"rating,omitempty"`
+	URL         string  `json:"url"`
+	SHA256      string  `json:"sha256,omitempty"`
+}
+
+type MarketIndex struct {
+	Templates []marketEntry `json:"templates"`
+}
+
+// MarketTimeout begrenzt Index- und Template-Downloads.
+const MarketTimeout = 30 * time.Second
+
+// fetchURL lädt eine Ressource des Marktplatzes.
+func fetchURL(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s laden: %v", errNetwork, rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s antwortet mit %s", errNetwork, rawURL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// FetchMarketIndex lädt den Marktplatz-Index und prüft seine Signatur.
+func FetchMarketIndex(ctx context.Context, settings Settings) (*MarketIndex, error) {
+	indexURL := settings.MarketplaceURL
+	if indexURL == "" {
+		return nil, fmt.Errorf("keine marktplatz-url konfiguriert (NEWPIPI_MARKETPLACE_URL)")
+	}
+	content, err := fetchURL(ctx, indexURL)
+	if err != nil {
+		return nil, err
+	}
+	if settings.MarketplaceKey != "" {
+		key, err := parseMinisignKey(settings.MarketplaceKey)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := fetchURL(ctx, indexURL+".minisig")
+		if err != nil {
+			return nil, err
+		}
+		if err := key.verify(content, sig); err != nil {
+			return nil, fmt.Errorf("marktplatz-index: %v", err)
+		}
+	}
+	var index MarketIndex
+	if err := json.Unmarshal(content, &index); err != nil {
+		return nil, fmt.Errorf("marktplatz-index parsen fehlgeschlagen: %v", err)
+	}
+	return &index, nil
+}
+
+// find sucht einen Eintrag ohne Beachtung der Groß-/Kleinschreibung.
+func (idx *MarketIndex) find(name string) *marketEntry {
+	for i := range idx.Templates {
+		if strings.EqualFold(idx.Templates[i].Name, name) {
+			return &idx.Templates[i]
+		}
+	}
+	return nil
+}
+
+// InstallMarketTemplate lädt das Template eines Eintrags in das
+// Template-Verzeichnis des Benutzers und liefert es zurück. Vertraut wird ihm
+// dadurch noch nicht.
+func InstallMarketTemplate(ctx context.Context, settings Settings, entry *marketEntry) (*Template, error) {
+	if err := settings.Policy.remoteTemplates(); err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(settings.MarketplaceURL)
+	if err != nil {
+		return nil, fmt.Errorf("marktplatz-url ungültig: %v", err)
+	}
+	ref, err := url.Parse(entry.URL)
+	if err != nil {
+		return nil, fmt.Errorf("template-url ungültig: %v", err)
+	}
+	source := base.ResolveReference(ref).String()
+	content, err := fetchURL(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	sum := fmt.Sprintf("%x", sha256.Sum256(content))
+	switch {
+	case entry.SHA256 != "" && !strings.EqualFold(entry.SHA256, sum):
+		return nil, fmt.Errorf("prüfsumme von %s stimmt nicht: erwartet %s, erhalten %s", entry.Name, entry.SHA256, sum)
+	case entry.SHA256 == "" && settings.MarketplaceKey != "":
+		return nil, fmt.Errorf("signierter index nennt keine prüfsumme für %s", entry.Name)
+	}
+	var t Template
+	if err := json.Unmarshal(content, &t); err != nil {
+		return nil, fmt.Errorf("template %s ist ungültig: %v", entry.Name, err)
+	}
+	if err := t.checkPaths(); err != nil {
+		return nil, err
+	}
+	t.Source, t.SourceSHA256 = source, sum
+
+	dir := settings.userTemplateDir()
+	if dir == "" {
+		return nil, fmt.Errorf("kein template-verzeichnis")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("template-verzeichnis erstellen fehlgeschlagen: %v", err)
+	}
+	content, err = json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, TemplateFileName(entry.Name)), content, 0644); err != nil {
+		return nil, fmt.Errorf("template speichern fehlgeschlagen: %v", err)
+	}
+	autoCommitConfig("Template installiert: " + entry.Name)
+	LoadUserTemplates()
+	if installed := FindTemplate(t.Type, t.Name); installed != nil {
+		return installed, nil
+	}
+	return nil, fmt.Errorf("template %s nach installation nicht geladen", t.Name)
+}
+
+// confirmTrust fragt auf der Konsole, ob den Hooks des Templates vertraut wird.
+func confirmTrust(t *Template) bool {
+	fmt.Fprint(os.Stderr, t.TrustSummary())
+	fmt.Fprint(os.Stderr, "Diesem Template vertrauen? [j/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	a := strings.ToLower(strings.TrimSpace(answer))
+	return a == "j" || a == "y"
+}
+
+// TemplateFileName bildet aus einem Template-Namen einen Dateinamen.
+func TemplateFileName(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, name)
+	return strings.Trim(slug, "-") + ".json"
+}
+
+// setupDiscover definiert `newpipi discover [list|install NAME|trust NAME]`.
+func setupDiscover(fs *flag.FlagSet) func(args []string) int {
+	trust := fs.Bool("trust", false, "Hooks des installierten Templates ohne Rückfrage vertrauen")
+	return func(args []string) int {
+		settings, err := LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		if len(args) == 2 && args[0] == "trust" {
+			return trustInstalled(args[1])
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), MarketTimeout)
+		defer cancel()
+		index, err := FetchMarketIndex(ctx, settings)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return ExitCodeFor(err)
+		}
+
+		if len(args) == 0 || args[0] == "list" {
+			for _, e := range index.Templates {
+				fmt.Printf("%-20s %-10s %s %s\n", e.Name, e.Type, RatingStars(e.Rating), e.Description)
+			}
+			return ExitOK
+		}
+		if args[0] != "install" || len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi discover [list | install NAME | trust NAME]")
+			return exitUsage
+		}
+		entry := index.find(args[1])
+		if entry == nil {
+			fmt.Fprintf(os.Stderr, "Fehler: template %s nicht im marktplatz\n", args[1])
+			return exitUsage
+		}
+		t, err := InstallMarketTemplate(ctx, settings, entry)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return ExitCodeFor(err)
+		}
+		fmt.Printf("Template installiert: %s\n", t.Name)
+		if t.RunsCommands() && (*trust || confirmTrust(t)) {
+			if err := TrustTemplate(t); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+		}
+		return ExitOK
+	}
+}
+
+// trustInstalled bestätigt ein bereits installiertes Template nachträglich.
+func trustInstalled(name string) int {
+	// Add-ons brauchen wie Templates Vertrauen
+	for _, list := range [][]Template{Templates, addonTemplates} {
+		for i := range list {
+			t := &list[i]
+			if !strings.EqualFold(t.Name, name) {
+				continue
+			}
+			if err := t.ensureLoaded(); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			if !t.RunsCommands() {
+				fmt.Printf("Template %s führt keine Befehle aus\n", t.Name)
+				return ExitOK
+			}
+			if !confirmTrust(t) {
+				return exitFailure
+			}
+			if err := TrustTemplate(t); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			return ExitOK
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Fehler: template %s nicht gefunden\n", name)
+	return exitUsage
+}
+
+// RatingStars stellt eine Bewertung von 0 bis 5 als Sterne dar.
+func RatingStars(rating float64) string {
+	full := int(rating + 0.5)
+	full = max(0, min(full, 5))
+	return strings.Repeat("★", full) + strings.Repeat("☆", 5-full)
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+// MCP-Server (Model Context Protocol) über stdio für KI-Assistenten: Sie
+// erstellen Projekte über dieselbe geprüfte Pipeline samt Erstellungsbericht
+// wie alle anderen Schnittstellen, statt Befehle von Hand auszuführen.
+// Unterstützt werden nur Tools, keine Ressourcen oder Prompts.
+
+// mcpProtocolVersion ist die neueste unterstützte Protokollversion.
+const mcpProtocolVersion = "2025-06-18"
+
+// mcpSupportedVersions nennt die Versionen, deren Tool-Aufrufe identisch sind.
+var mcpSupportedVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+
+type mcpMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *mcpError       `json:"error,omitempty"`
+}
+
+type mcpError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// JSON-RPC-Fehlercodes
+const (
+	mcpParseError     = -32700
+	mcpMethodNotFound = -32601
+	mcpInvalidParams  = -32602
+)
+
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+// mcpTools beschreibt die angebotenen Tools. Die Beschreibungen richten sich
+// an das Sprachmodell und sind daher englisch.
+func mcpTools() []mcpTool {
+	str := func(desc string) map[string]any { return map[string]any{"type": "string", "description": desc} }
+	return []mcpTool{
+		{
+			Name:        "list_project_types",
+			Description: "List the project types newpipi can scaffold.",
+			InputSchema: map[string]any{"type": "object", "properties": map[string]any{}},
+		},
+		{
+			Name:        "list_templates",
+			Description: "List the available project templates with their options.",
+			InputSchema: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"type": str("Only list templates of this project type")},
+			},
+		},
+		{
+			Name:        "create_project",
+			Description: "Scaffold a new project (toolchain setup, template, git init, creation manifest). Returns the project path.",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"type":           map[string]any{"type": "string", "enum": ProjectTypeNames, "description": "Project type"},
+					"name":           str("Project directory name (letters, digits, _ and -)"),
+					"parentPath":     str("Parent directory; defaults to the configured project path"),
+					"template":       str("Template name from list_templates"),
+					"toolkit":        str("GUI toolkit or framework of the project type, e.g. PySide6, Flask or FastAPI for Python, Cobra or Gin for Go"),
+					"layout":         str("Project layout of the project type, e.g. Binary, Library or Workspace for Rust"),
+					"packageManager": map[string]any{"type": "string", "enum": PackageManagerNames(), "description": "Package manager for JavaScript and TypeScript projects"},
+					"module":         str("Go module path, e.g. github.com/user/name; defaults to the project name"),
+					"options":        map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}, "description": "Template option values"},
+					"snippets":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					"addons":         map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "Add-ons merged into the template, e.g. docker, ci, tests"},
+					"scratch":        map[string]any{"type": "boolean", "description": "Mark as throwaway project; listed by newpipi cleanup once older than the configured age"},
+					"sandbox":        map[string]any{"type": "boolean", "description": "Create in a fresh temp directory instead of parentPath; newpipi sandbox promote moves it later"},
+					"env":            map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}, "description": "Environment variables written to .env, .envrc, compose file and VS Code launch config"},
+					"skipGit":        map[string]any{"type": "boolean"},
+					"gitHooks":       map[string]any{"type": "boolean", "description": "Install native git hooks for formatting and commit-message checks"},
+					"releaseTask":    map[string]any{"type": "boolean", "description": "Add a release task that updates CHANGELOG.md and tags the next version"},
+					"sign":           map[string]any{"type": "boolean", "description": "Sign commits with the SSH or GPG key of the profile"},
+					"lfs":            map[string]any{"type": "boolean", "description": "Track large binary assets with Git LFS"},
+					"vcs":            map[string]any{"type": "string", "enum": []string{"git", "jj", "hg"}, "description": "Version control system of the new repository"},
+				},
+				"required": []string{"type", "name"},
+			},
+		},
+	}
+}
+
+// setupMCP definiert `newpipi mcp`.
+func setupMCP(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		// stdout gehört allein dem Protokoll
+		out := os.Stdout
+		os.Stdout = os.Stderr
+		return runMCP(os.Stdin, out)
+	}
+}
+
+func runMCP(in io.Reader, out io.Writer) int {
+	enc := json.NewEncoder(out)
+	send := func(m mcpMessage) {
+		m.JSONRPC = "2.0"
+		if err := enc.Encode(m); err != nil {
+			log.Printf("Antwort schreiben fehlgeschlagen: %v", err)
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var req mcpMessage
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			send(mcpMessage{ID: json.RawMessage("null"), Error: &mcpError{mcpParseError, err.Error()}})
+			continue
+		}
+		// Benachrichtigungen (ohne id) brauchen keine Antwort
+		if len(req.ID) == 0 {
+			continue
+		}
+		result, rpcErr := handleMCP(req, send)
+		if rpcErr != nil {
+			send(mcpMessage{ID: req.ID, Error: rpcErr})
+			continue
+		}
+		send(mcpMessage{ID: req.ID, Result: result})
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Eingabe lesen fehlgeschlagen: %v", err)
+		return exitFailure
+	}
+	return ExitOK
+}
+
+func handleMCP(req mcpMessage, send func(mcpMessage)) (any, *mcpError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(req.Params, &params)
+		version := mcpProtocolVersion
+		for _, v := range mcpSupportedVersions {
+			if v == params.ProtocolVersion {
+				version = v
+			}
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": "newpipi", "version": VersionString()},
+		}, nil
+	case "ping":
+		return map[string]any{}, nil
+	case "tools/list":
+		return map[string]any{"tools": mcpTools()}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+			Meta      struct {
+				ProgressToken any `json:"progressToken"`
+			} `json:"_meta"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &mcpError{mcpInvalidParams, err.Error()}
+		}
+		progress := func(message string, fraction float64) {
+			if params.Meta.ProgressToken == nil {
+				return
+			}
+			payload, _ := json.Marshal(map[string]any{
+				"progressToken": params.Meta.ProgressToken,
+				"progress":      fraction,
+				"total":         1,
+				"message":       message,
+			})
+			send(mcpMessage{Method: "notifications/progress", Params: payload})
+		}
+		result, err := callMCPTool(params.Name, params.Arguments, progress)
+		if err != nil {
+			// Fehler des Tools sieht das Modell als Ergebnis, nicht als Protokollfehler
+			return map[string]any{
+				"content": []map[string]string{{"type": "text", "text": err.Error()}},
+				"isError": true,
+			}, nil
+		}
+		text, _ := json.MarshalIndent(result, "", "  ")
+		return map[string]any{
+			"content": []map[string]string{{"type": "text", "text": string(text)}},
+		}, nil
+	}
+	return nil, &mcpError{mcpMethodNotFound, fmt.Sprintf("unbekannte methode: %s", req.Method)}
+}
+
+// callMCPTool führt ein Tool aus; die Logik teilt es mit dem stdio-Protokoll.
+func callMCPTool(name string, args json.RawMessage, progress func(message string, fraction float64)) (any, error) {
+	switch name {
+	case "list_project_types":
+		return handleStdio(stdioRequest{Method: "types"}, progress)
+	case "list_templates":
+		return handleStdio(stdioRequest{Method: "templates", Params: args}, progress)
+	case "create_project":
+		return handleStdio(stdioRequest{Method: "create", Params: args}, progress)
+	}
+	return nil, fmt.Errorf("unbekanntes tool: %s", name)
+}
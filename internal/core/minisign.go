@@ -0,0 +1,69 @@
+package core
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisign-Signaturen: Schlüssel und Signatur beginnen mit dem Algorithmus
+// ("Ed" für die Daten selbst, "ED" für ihren BLAKE2b-512-Hash) und der
+// 8-Byte-Schlüssel-ID. Die globale Signatur deckt zusätzlich den
+// vertrauenswürdigen Kommentar ab.
+
+type minisignKey struct {
+	id  []byte
+	key ed25519.PublicKey
+}
+
+// parseMinisignKey liest einen öffentlichen Schlüssel in Base64 (die zweite
+// Zeile einer minisign.pub).
+func parseMinisignKey(s string) (*minisignKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != "Ed" {
+		return nil, fmt.Errorf("ungültiger minisign-schlüssel")
+	}
+	return &minisignKey{id: raw[2:10], key: ed25519.PublicKey(raw[10:])}, nil
+}
+
+// verify prüft eine .minisig-Datei für content.
+func (k *minisignKey) verify(content, sigFile []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(sigFile)), "\n")
+	if len(lines) != 4 {
+		return fmt.Errorf("ungültige minisign-signatur")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("ungültige minisign-signatur")
+	}
+	if !bytes.Equal(sig[2:10], k.id) {
+		return fmt.Errorf("signatur stammt von einem anderen schlüssel")
+	}
+
+	message := content
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		sum := blake2b.Sum512(content)
+		message = sum[:]
+	default:
+		return fmt.Errorf("unbekannter signaturalgorithmus %q", sig[:2])
+	}
+	if !ed25519.Verify(k.key, message, sig[10:]) {
+		return fmt.Errorf("signatur ungültig")
+	}
+
+	comment, ok := strings.CutPrefix(strings.TrimRight(lines[2], "\r"), "trusted comment: ")
+	if !ok {
+		return fmt.Errorf("ungültige minisign-signatur")
+	}
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || !ed25519.Verify(k.key, append(sig[10:], comment...), global) {
+		return fmt.Errorf("globale signatur ungültig")
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignFixture erzeugt einen Schlüssel im minisign-Format und signiert damit.
+type minisignFixture struct {
+	id      []byte
+	private ed25519.PrivateKey
+	public  string
+}
+
+func newMinisignFixture(t *testing.T) *minisignFixture {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := []byte("12345678")
+	raw := append(append([]byte("Ed"), id...), pub...)
+	return &minisignFixture{id: id, private: priv, public: base64.StdEncoding.EncodeToString(raw)}
+}
+
+// sign liefert eine .minisig-Datei; algorithm ist "Ed" oder "ED" (vorab gehasht).
+func (f *minisignFixture) sign(content []byte, algorithm, comment string) []byte {
+	message := content
+	if algorithm == "ED" {
+		sum := blake2b.Sum512(content)
+		message = sum[:]
+	}
+	sig := ed25519.Sign(f.private, message)
+	global := ed25519.Sign(f.private, append(append([]byte{}, sig...), comment...))
+	raw := append(append([]byte(algorithm), f.id...), sig...)
+	return []byte(fmt.Sprintf("untrusted comment: test\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(raw), comment, base64.StdEncoding.EncodeToString(global)))
+}
+
+func TestMinisignVerify(t *testing.T) {
+	f := newMinisignFixture(t)
+	other := newMinisignFixture(t)
+	other.id = []byte("87654321")
+	content := []byte(`{"templates":[]}`)
+
+	tests := []struct {
+		name    string
+		content []byte
+		sig     []byte
+		wantErr string
+	}{
+		{"Ed", content, f.sign(content, "Ed", "index"), ""},
+		{"ED vorab gehasht", content, f.sign(content, "ED", "index"), ""},
+		{"Inhalt verändert", []byte(`{"templates":[1]}`), f.sign(content, "ED", "index"), "signatur ungültig"},
+		{"anderer Schlüssel", content, other.sign(content, "ED", "index"), "anderen schlüssel"},
+		{"vertrauenswürdiger Kommentar verändert", content,
+			[]byte(strings.Replace(string(f.sign(content, "ED", "index")), "trusted comment: index", "trusted comment: evil", 1)), "globale signatur"},
+		{"unbekannter Algorithmus", content, f.sign(content, "Xx", "index"), "signaturalgorithmus"},
+		{"zu wenige Zeilen", content, []byte("untrusted comment: test\n"), "ungültige minisign-signatur"},
+	}
+	key, err := parseMinisignKey(f.public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := key.verify(tt.content, tt.sig)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unerwarteter Fehler: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("Fehler %v, erwartet %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseMinisignKey(t *testing.T) {
+	f := newMinisignFixture(t)
+	tests := []struct {
+		name string
+		key  string
+		ok   bool
+	}{
+		{"gültig", f.public, true},
+		{"mit Zeilenumbruch", f.public + "\n", true},
+		{"kein Base64", "nicht base64!", false},
+		{"zu kurz", base64.StdEncoding.EncodeToString([]byte("Ed1234")), false},
+		{"falscher Algorithmus", base64.StdEncoding.EncodeToString(append([]byte("XX12345678"), make([]byte, ed25519.PublicKeySize)...)), false},
+	}
+	for _, tt := range tests {
+		if _, err := parseMinisignKey(tt.key); (err == nil) != tt.ok {
+			t.Errorf("%s: Fehler %v", tt.name, err)
+		}
+	}
+}
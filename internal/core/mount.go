@@ -0,0 +1,235 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Netzlaufwerke (NFS, SMB, sshfs) unterstützen oft keine Symlinks,
+// Ausführrechte, Dateisperren oder Hardlinks, woran einfache Gerüste
+// scheitern. Vor der Erstellung auf einem solchen Laufwerk prüft newpipi
+// diese Fähigkeiten in einem temporären Verzeichnis und passt die Erstellung
+// an: Das LocalDir des Projekttyps (venv) entsteht lokal, Paketmanager
+// kopieren statt Hardlinks zu setzen. Fehlende Sperren werden nur gemeldet.
+
+// networkFilesystems sind die Dateisystemtypen aus /proc/self/mountinfo, die geprüft werden.
+var networkFilesystems = []string{
+	"nfs", "nfs4", "cifs", "smb3", "smbfs", "fuse.sshfs", "9p", "afs", "ceph",
+	"glusterfs", "fuse.glusterfs", "davfs", "fuse.rclone",
+}
+
+// mountCaps sind die Fähigkeiten des Dateisystems unter dem Zielpfad.
+type mountCaps struct {
+	// dir ist das geprüfte Verzeichnis, siehe ProjectSetup.mountCaps
+	dir       string
+	FSType    string
+	Network   bool
+	Symlinks  bool
+	Exec      bool
+	Locking   bool
+	Hardlinks bool
+}
+
+// mountCaps prüft das Dateisystem unter dem Elternpfad. Das Ergebnis gilt,
+// bis sich der Pfad ändert.
+func (ps *ProjectSetup) mountCaps() *mountCaps {
+	dir := existingAncestor(ps.ParentPath)
+	if ps.mount != nil && ps.mount.dir == dir {
+		return ps.mount
+	}
+	caps, err := probeMount(dir)
+	if err != nil {
+		log.Printf("Dateisystem von %s prüfen fehlgeschlagen: %v", dir, err)
+	}
+	ps.mount = caps
+	return caps
+}
+
+// probeMount ermittelt den Mount von dir und prüft Netzlaufwerke mit
+// Probedateien. Lokale Dateisysteme gelten ohne Prüfung als vollständig.
+func probeMount(dir string) (*mountCaps, error) {
+	caps := &mountCaps{dir: dir, Symlinks: true, Exec: true, Locking: true, Hardlinks: true}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return caps, err
+	}
+	mount, err := findMount(resolved)
+	if err != nil || mount == nil {
+		return caps, err
+	}
+	caps.FSType = mount.fsType
+	if !slices.Contains(networkFilesystems, mount.fsType) {
+		return caps, nil
+	}
+	caps.Network = true
+
+	tmp, err := os.MkdirTemp(dir, ".newpipi-probe-")
+	if err != nil {
+		return caps, err
+	}
+	defer os.RemoveAll(tmp)
+	file := filepath.Join(tmp, "probe")
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_RDWR, 0o755)
+	if err != nil {
+		return caps, err
+	}
+	caps.Locking = unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB) == nil
+	f.Close()
+	caps.Symlinks = os.Symlink("probe", filepath.Join(tmp, "symlink")) == nil
+	caps.Hardlinks = os.Link(file, filepath.Join(tmp, "hardlink")) == nil
+	// Manche Mounts ignorieren chmod oder verbieten die Ausführung ganz
+	caps.Exec = !slices.Contains(mount.options, "noexec")
+	if err := os.Chmod(file, 0o755); err != nil {
+		caps.Exec = false
+	} else if info, err := os.Stat(file); err != nil || info.Mode()&0o100 == 0 {
+		caps.Exec = false
+	}
+	return caps, nil
+}
+
+type mountEntry struct {
+	point   string
+	fsType  string
+	options []string
+}
+
+// findMount liefert den Eintrag aus /proc/self/mountinfo mit dem längsten
+// Einhängepunkt, unter dem path liegt.
+func findMount(path string) (*mountEntry, error) {
+	data, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	var best *mountEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		// ID Eltern-ID Major:Minor Wurzel Einhängepunkt Optionen [Felder...] - Typ Quelle Superblock-Optionen
+		fields := strings.Fields(line)
+		sep := slices.Index(fields, "-")
+		if sep < 6 || sep+1 >= len(fields) {
+			continue
+		}
+		point := unescapeMountinfo(fields[4])
+		if point != "/" && path != point && !strings.HasPrefix(path, point+"/") {
+			continue
+		}
+		if best == nil || len(point) >= len(best.point) {
+			best = &mountEntry{point: point, fsType: fields[sep+1], options: strings.Split(fields[5], ",")}
+		}
+	}
+	return best, nil
+}
+
+// unescapeMountinfo ersetzt die oktalen Escapes (\040 für Leerzeichen) der mountinfo.
+func unescapeMountinfo(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// relocateLocalDir meldet, ob das LocalDir außerhalb des Projekts entsteht:
+// Ein venv braucht Ausführrechte und Symlinks.
+func (c *mountCaps) relocateLocalDir() bool {
+	return c.Network && (!c.Exec || !c.Symlinks)
+}
+
+// missing nennt die fehlenden Fähigkeiten für Meldungen.
+func (c *mountCaps) missing() []string {
+	var missing []string
+	for _, m := range []struct {
+		ok   bool
+		name string
+	}{{c.Symlinks, "Symlinks"}, {c.Exec, "Ausführrechte"}, {c.Locking, "Dateisperren"}, {c.Hardlinks, "Hardlinks"}} {
+		if !m.ok {
+			missing = append(missing, m.name)
+		}
+	}
+	return missing
+}
+
+// localDir liefert den Pfad für "{local}": das LocalDir im Projekt oder, auf
+// Netzlaufwerken ohne Ausführrechte oder Symlinks, ein Verzeichnis im
+// Zustandsverzeichnis. Leer bei Projekttypen ohne LocalDir.
+func (ps *ProjectSetup) localDir() string {
+	lang := ps.Language()
+	if lang == nil || lang.LocalDir == "" {
+		return ""
+	}
+	if !ps.mountCaps().relocateLocalDir() {
+		return lang.LocalDir
+	}
+	dir, err := StateDir()
+	if err != nil {
+		log.Printf("Zustandsverzeichnis nicht verfügbar, %s bleibt im Projekt: %v", lang.LocalDir, err)
+		return lang.LocalDir
+	}
+	// Gleichnamige Projekte in verschiedenen Verzeichnissen dürfen sich nicht stören
+	sum := sha256.Sum256([]byte(filepath.Join(ps.ParentPath, ps.ProjectName)))
+	return filepath.Join(dir, lang.LocalDir+"s", ps.ProjectName+"-"+hex.EncodeToString(sum[:4]))
+}
+
+// expandLocal setzt localDir für "{local}" ein; quote maskiert ausgelagerte
+// Pfade für die Shell.
+func (ps *ProjectSetup) expandLocal(s string, quote bool) string {
+	if !strings.Contains(s, "{local}") {
+		return s
+	}
+	dir := ps.localDir()
+	if quote && filepath.IsAbs(dir) {
+		dir = shellQuote(dir)
+	}
+	return strings.ReplaceAll(s, "{local}", dir)
+}
+
+// expandLocalCommands setzt localDir in die Argumente der Befehle ein.
+func (ps *ProjectSetup) expandLocalCommands(commands []TemplateCommand) []TemplateCommand {
+	out := make([]TemplateCommand, len(commands))
+	for i, c := range commands {
+		c.Run = slices.Clone(c.Run)
+		for j, arg := range c.Run {
+			c.Run[j] = ps.expandLocal(arg, false)
+		}
+		out[i] = c
+	}
+	return out
+}
+
+// linkLocalDir verlinkt ein ausgelagertes LocalDir ins Projekt, sofern das
+// Laufwerk Symlinks kann, damit etwa `source venv/bin/activate` weiter funktioniert.
+func (ps *ProjectSetup) linkLocalDir(projectDir string) error {
+	lang := ps.Language()
+	local := ps.localDir()
+	if lang == nil || local == lang.LocalDir || !ps.mountCaps().Symlinks {
+		return nil
+	}
+	if err := os.Symlink(local, filepath.Join(projectDir, lang.LocalDir)); err != nil {
+		return fmt.Errorf("%s verlinken fehlgeschlagen: %v", lang.LocalDir, err)
+	}
+	return nil
+}
+
+// mountEnv sind Umgebungsvariablen für Befehle auf Laufwerken ohne
+// Hardlinks: pnpm und uv kopieren dann aus ihren Caches.
+func (ps *ProjectSetup) mountEnv() []string {
+	if ps.mount == nil || ps.mount.Hardlinks {
+		return nil
+	}
+	return []string{"npm_config_package_import_method=copy", "UV_LINK_MODE=copy"}
+}
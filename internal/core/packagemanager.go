@@ -0,0 +1,236 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Paketmanager für das npm-Ökosystem: Die eingebauten Projekttypen schreiben
+// ihre Befehle in npm-Form (npm init, npm install, npx, npm create, npm run);
+// für pnpm, yarn oder bun werden Init, Commands, Install und der Startbefehl
+// übersetzt, sodass derselbe Paketmanager durchgängig verwendet wird.
+
+// packageManager beschreibt die Entsprechungen der npm-Befehle.
+type packageManager struct {
+	Name string
+	// Init legt package.json an (npm init -y).
+	Init []string
+	// Install installiert die Abhängigkeiten aus package.json (npm install).
+	Install []string
+	// Add und AddDev fügen Pakete hinzu (npm install [--save-dev] PAKET).
+	Add    []string
+	AddDev []string
+	// Exec startet ein lokal installiertes Programm (npx PROGRAMM), Dlx
+	// lädt es bei Bedarf (npx --yes PAKET).
+	Exec []string
+	Dlx  []string
+	// Create startet einen Generator (npm create PAKET).
+	Create []string
+	// Run führt ein Skript aus package.json aus (npm run SKRIPT).
+	Run []string
+}
+
+var packageManagers = []packageManager{
+	{
+		Name:    "npm",
+		Init:    []string{"npm", "init", "-y"},
+		Install: []string{"npm", "install"},
+		Add:     []string{"npm", "install"},
+		AddDev:  []string{"npm", "install", "--save-dev"},
+		Exec:    []string{"npx"},
+		Dlx:     []string{"npx", "--yes"},
+		Create:  []string{"npm", "create"},
+		Run:     []string{"npm", "run"},
+	},
+	{
+		Name:    "pnpm",
+		Init:    []string{"pnpm", "init"},
+		Install: []string{"pnpm", "install"},
+		Add:     []string{"pnpm", "add"},
+		AddDev:  []string{"pnpm", "add", "-D"},
+		Exec:    []string{"pnpm", "exec"},
+		Dlx:     []string{"pnpm", "dlx"},
+		Create:  []string{"pnpm", "create"},
+		Run:     []string{"pnpm", "run"},
+	},
+	{
+		Name:    "yarn",
+		Init:    []string{"yarn", "init", "-y"},
+		Install: []string{"yarn", "install"},
+		Add:     []string{"yarn", "add"},
+		AddDev:  []string{"yarn", "add", "-D"},
+		Exec:    []string{"yarn"},
+		// yarn 1 kennt kein dlx
+		Dlx:    []string{"npx", "--yes"},
+		Create: []string{"yarn", "create"},
+		Run:    []string{"yarn", "run"},
+	},
+	{
+		Name: "bun",
+		// bun init legt zusätzlich index.ts und tsconfig.json an; package.json
+		// kommt daher von npm, das mit Node.js ohnehin installiert ist
+		Init:    []string{"npm", "init", "-y"},
+		Install: []string{"bun", "install"},
+		Add:     []string{"bun", "add"},
+		AddDev:  []string{"bun", "add", "-d"},
+		Exec:    []string{"bunx"},
+		Dlx:     []string{"bunx"},
+		Create:  []string{"bun", "create"},
+		Run:     []string{"bun", "run"},
+	},
+}
+
+func PackageManagerNames() []string {
+	names := make([]string, len(packageManagers))
+	for i, pm := range packageManagers {
+		names[i] = pm.Name
+	}
+	return names
+}
+
+// FindPackageManager sucht einen Paketmanager; leer steht für npm.
+func FindPackageManager(name string) (*packageManager, error) {
+	if name == "" {
+		return &packageManagers[0], nil
+	}
+	for i, pm := range packageManagers {
+		if strings.EqualFold(pm.Name, name) {
+			return &packageManagers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unbekannter paketmanager %q (%s)", name, strings.Join(PackageManagerNames(), ", "))
+}
+
+// NpmProject meldet, ob der Projekttyp Pakete aus dem npm-Ökosystem bezieht.
+func (ps *ProjectSetup) NpmProject() bool {
+	lang := ps.Language()
+	return lang != nil && lang.Ecosystem == "npm"
+}
+
+// withPackageManager übersetzt die npm-Befehle von lang für den gewählten
+// Paketmanager; bei npm und außerhalb des npm-Ökosystems bleibt lang unverändert.
+func (ps *ProjectSetup) withPackageManager(lang *language) *language {
+	if lang == nil || lang.Ecosystem != "npm" {
+		return lang
+	}
+	pm, err := FindPackageManager(ps.PackageManager)
+	if err != nil || pm.Name == "npm" {
+		return lang
+	}
+	l := *lang
+	l.Init = pm.commands(lang.Init)
+	l.Commands = pm.commands(lang.Commands)
+	if len(lang.Install) > 0 {
+		l.Install = pm.args(lang.Install)
+	}
+	l.Run = pm.shell(lang.Run)
+	l.Check = pm.shell(lang.Check)
+	return &l
+}
+
+// selectedPackageManager liefert den gewählten Paketmanager, nil bei npm,
+// außerhalb des npm-Ökosystems und bei Templates mit externem Generator, der
+// seinen Paketmanager selbst wählt.
+func (ps *ProjectSetup) selectedPackageManager() *packageManager {
+	pm, err := FindPackageManager(ps.PackageManager)
+	if err != nil || pm.Name == "npm" || !ps.NpmProject() {
+		return nil
+	}
+	if ps.Template != nil && ps.Template.Generator != "" {
+		return nil
+	}
+	return pm
+}
+
+// npmCommands übersetzt die Befehle eines Templates wie die des Projekttyps.
+func (ps *ProjectSetup) npmCommands(commands []TemplateCommand) []TemplateCommand {
+	if pm := ps.selectedPackageManager(); pm != nil {
+		return pm.commands(commands)
+	}
+	return commands
+}
+
+// npmShell übersetzt einen Startbefehl wie "npm run dev".
+func (ps *ProjectSetup) npmShell(command string) string {
+	if pm := ps.selectedPackageManager(); pm != nil {
+		return pm.shell(command)
+	}
+	return command
+}
+
+// packageManagerTools prüft den gewählten Paketmanager, sofern es nicht npm
+// ist, das mit Node.js kommt.
+func (ps *ProjectSetup) packageManagerTools() []toolCheck {
+	pm := ps.selectedPackageManager()
+	if pm == nil {
+		return nil
+	}
+	return []toolCheck{{[]string{pm.Name, "--version"}, pm.Name}}
+}
+
+func (pm *packageManager) commands(commands []TemplateCommand) []TemplateCommand {
+	out := make([]TemplateCommand, len(commands))
+	for i, c := range commands {
+		c.Run = pm.args(c.Run)
+		out[i] = c
+	}
+	return out
+}
+
+// args übersetzt einen npm- oder npx-Aufruf; andere Befehle bleiben unverändert.
+func (pm *packageManager) args(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+	if args[0] == "npx" {
+		if len(args) > 1 && args[1] == "--yes" {
+			return slices.Concat(pm.Dlx, args[2:])
+		}
+		return slices.Concat(pm.Exec, args[1:])
+	}
+	if args[0] != "npm" || len(args) < 2 {
+		return args
+	}
+	rest := args[2:]
+	switch args[1] {
+	case "init":
+		return slices.Clone(pm.Init)
+	case "install", "i":
+		if !slices.ContainsFunc(rest, func(arg string) bool { return !strings.HasPrefix(arg, "-") }) {
+			return slices.Clone(pm.Install)
+		}
+		dev := slices.ContainsFunc(rest, func(arg string) bool { return arg == "--save-dev" || arg == "-D" })
+		rest = slices.DeleteFunc(slices.Clone(rest), func(arg string) bool { return arg == "--save-dev" || arg == "-D" })
+		if dev {
+			return slices.Concat(pm.AddDev, rest)
+		}
+		return slices.Concat(pm.Add, rest)
+	case "create":
+		// npm reicht Argumente erst nach -- an den Generator weiter, die
+		// anderen direkt; die Version bestimmen sie selbst
+		rest = slices.DeleteFunc(slices.Clone(rest), func(arg string) bool { return arg == "--" })
+		if len(rest) > 0 {
+			rest[0] = strings.TrimSuffix(rest[0], "@latest")
+		}
+		return slices.Concat(pm.Create, rest)
+	case "run", "run-script":
+		return slices.Concat(pm.Run, rest)
+	case "start", "test":
+		return slices.Concat(pm.Run, args[1:])
+	}
+	return args
+}
+
+// shell übersetzt die mit && verketteten Befehle einer Befehlszeile wie
+// "npx tsc && node dist/index.js".
+func (pm *packageManager) shell(command string) string {
+	parts := strings.Split(command, " && ")
+	for i, part := range parts {
+		fields := strings.Fields(part)
+		if len(fields) > 0 && (fields[0] == "npm" || fields[0] == "npx") {
+			parts[i] = strings.Join(pm.args(fields), " ")
+		}
+	}
+	return strings.Join(parts, " && ")
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackageManagerArgs(t *testing.T) {
+	tests := []struct {
+		pm   string
+		args string
+		want string
+	}{
+		{"npm", "npm install --save-dev {packages}", "npm install --save-dev {packages}"},
+		{"pnpm", "npm init -y", "pnpm init"},
+		{"pnpm", "npm install", "pnpm install"},
+		{"pnpm", "npm install {packages}", "pnpm add {packages}"},
+		{"yarn", "npm install --save-dev {packages}", "yarn add -D {packages}"},
+		{"bun", "npm install --save-dev {packages}", "bun add -d {packages}"},
+		{"pnpm", "npx tsc --init", "pnpm exec tsc --init"},
+		{"yarn", "npx --yes standard-version", "npx --yes standard-version"},
+		{"bun", "npx --yes standard-version", "bunx standard-version"},
+		{"pnpm", "npm create vite@latest demo -- --template react --no-interactive", "pnpm create vite demo --template react --no-interactive"},
+		{"yarn", "npm run dev", "yarn run dev"},
+		{"bun", "npm start", "bun run start"},
+		{"pnpm", "node app.js", "node app.js"},
+	}
+	for _, tt := range tests {
+		pm, err := FindPackageManager(tt.pm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(pm.args(strings.Fields(tt.args)), " "); got != tt.want {
+			t.Errorf("%s %q = %q, want %q", tt.pm, tt.args, got, tt.want)
+		}
+	}
+	if _, err := FindPackageManager("deno"); err == nil {
+		t.Error("unbekannter paketmanager angenommen")
+	}
+}
+
+func TestWithPackageManager(t *testing.T) {
+	ps := &ProjectSetup{ProjectType: TypeScript, PackageManager: "pnpm"}
+	lang := ps.withPackageManager(ps.Language())
+	if lang.Run != "pnpm exec tsc && node dist/index.js" {
+		t.Errorf("run = %q", lang.Run)
+	}
+	if tools := ps.packageManagerTools(); len(tools) != 1 || tools[0].Label != "pnpm" {
+		t.Errorf("werkzeuge = %v", tools)
+	}
+	// Die eingebaute Beschreibung bleibt unverändert
+	if languages[TypeScript].Run != "npx tsc && node dist/index.js" {
+		t.Errorf("projekttyp verändert: %q", languages[TypeScript].Run)
+	}
+	ps.ProjectType = Go
+	if ps.withPackageManager(ps.Language()) != languages[Go] || ps.packageManagerTools() != nil {
+		t.Error("go-projekt übersetzt")
+	}
+}
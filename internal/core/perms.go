@@ -0,0 +1,132 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// Rechte der Dateien und Verzeichnisse, die newpipi in Projekten anlegt.
+// Standard sind 0644 und 0755; Settings.FileMode und DirMode ändern das, etwa
+// 0664 und 2775 für gemeinsam genutzte, gruppenbeschreibbare Projekte. Die
+// umask des Prozesses gilt immer, auch für ausdrücklich gesetzte Rechte.
+
+// projectModes sind die Rechte für neue Dateien und Verzeichnisse.
+type projectModes struct {
+	File os.FileMode
+	Dir  os.FileMode
+}
+
+var defaultModes = projectModes{File: 0o644, Dir: 0o755}
+
+// Modes liefert die Rechte aus den Einstellungen; ungültige Angaben meldet
+// der Pre-flight-Check, hier gilt dann der Standard.
+func (s Settings) Modes() projectModes {
+	m, err := s.parseModes()
+	if err != nil {
+		log.Printf("Rechte aus den Einstellungen ungültig, verwende Standard: %v", err)
+		return defaultModes
+	}
+	return m
+}
+
+func (s Settings) parseModes() (projectModes, error) {
+	m := defaultModes
+	var err error
+	if s.FileMode != "" {
+		if m.File, err = parseMode(s.FileMode); err != nil {
+			return defaultModes, fmt.Errorf("fileMode: %v", err)
+		}
+	}
+	if s.DirMode != "" {
+		if m.Dir, err = parseMode(s.DirMode); err != nil {
+			return defaultModes, fmt.Errorf("dirMode: %v", err)
+		}
+	}
+	return m, nil
+}
+
+// parseMode liest oktale Rechte wie "0664" oder "2775" samt setgid, setuid und sticky.
+func parseMode(text string) (os.FileMode, error) {
+	bits, err := strconv.ParseUint(text, 8, 32)
+	if err != nil || bits > 0o7777 {
+		return 0, fmt.Errorf("ungültige rechte %q", text)
+	}
+	mode := os.FileMode(bits & 0o777)
+	if bits&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if bits&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if bits&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode, nil
+}
+
+// executable ergänzt Ausführrechte überall dort, wo Leserechte bestehen.
+func (m projectModes) executable() os.FileMode {
+	return m.File | m.File&0o444>>2
+}
+
+// umask liefert die umask des Prozesses. Sie lässt sich nur durch Setzen
+// lesen und wird daher einmal ermittelt.
+var umask = sync.OnceValue(func() os.FileMode {
+	old := unix.Umask(0)
+	unix.Umask(old)
+	return os.FileMode(old)
+})
+
+// mkdirAll legt path samt fehlender Oberverzeichnisse an. Sonderrechte wie
+// setgid übernimmt mkdir nicht, sie werden für neue Verzeichnisse nachgetragen.
+func (m projectModes) mkdirAll(path string) error {
+	var created []string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if _, err := os.Lstat(dir); err == nil || filepath.Dir(dir) == dir {
+			break
+		}
+		created = append(created, dir)
+	}
+	if err := os.MkdirAll(path, m.Dir.Perm()); err != nil {
+		return err
+	}
+	if m.Dir&(os.ModeSetuid|os.ModeSetgid|os.ModeSticky) == 0 {
+		return nil
+	}
+	for _, dir := range created {
+		if err := os.Chmod(dir, m.Dir&^umask()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile schreibt eine Datei samt fehlender Verzeichnisse. Rechte gelten nur
+// für neue Dateien, bestehende behalten ihre.
+func (m projectModes) writeFile(path string, content []byte, mode os.FileMode) error {
+	if err := m.mkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, mode)
+}
+
+// formatMode schreibt Rechte oktal wie in chmod.
+func formatMode(mode os.FileMode) string {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 0o1000
+	}
+	return fmt.Sprintf("%04o", bits)
+}
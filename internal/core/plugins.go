@@ -0,0 +1,191 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Plugins sind ausführbare Dateien in <config>/plugins, die über JSON auf
+// stdin/stdout angesprochen werden:
+//
+//	plugin describe     → pluginInfo als JSON
+//	plugin create       ← pluginRequest, → pluginMessage je Zeile
+//	plugin postprocess  ← pluginRequest, → pluginMessage je Zeile
+//
+// So lassen sich neue Projekttypen und Nachbearbeitungsschritte ergänzen,
+// ohne die Anwendung zu ändern.
+
+// pluginInfo beschreibt, was ein Plugin beiträgt.
+type pluginInfo struct {
+	Name         string   `json:"name"`
+	ProjectTypes []string `json:"projectTypes,omitempty"`
+	PostProcess  bool     `json:"postProcess,omitempty"`
+}
+
+// pluginRequest wird dem Plugin bei create und postprocess auf stdin übergeben.
+type pluginRequest struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	ParentPath string `json:"parentPath"`
+	ProjectDir string `json:"projectDir"`
+	Template   string `json:"template,omitempty"`
+}
+
+// pluginMessage ist eine Ausgabezeile des Plugins: "step" meldet Fortschritt,
+// "result" schließt erfolgreich ab, "error" bricht ab.
+type pluginMessage struct {
+	Type           string `json:"type"`
+	Message        string `json:"message,omitempty"`
+	TerminalScript string `json:"terminalScript,omitempty"`
+}
+
+type plugin struct {
+	path string
+	info pluginInfo
+}
+
+var (
+	plugins     []*plugin
+	pluginTypes = make(map[ProjectType]*plugin)
+)
+
+func pluginDir() string {
+	dir, err := configDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "plugins")
+}
+
+// LoadPlugins fragt alle ausführbaren Dateien im Plugin-Verzeichnis nach ihren
+// Fähigkeiten und registriert ihre Projekttypen.
+func LoadPlugins() {
+	dir := pluginDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Plugin-Verzeichnis lesen fehlgeschlagen: %v", err)
+		}
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		p := &plugin{path: filepath.Join(dir, entry.Name())}
+		out, err := exec.Command(p.path, "describe").Output()
+		if err != nil {
+			log.Printf("Plugin %s beschreiben fehlgeschlagen: %v", entry.Name(), err)
+			continue
+		}
+		if err := json.Unmarshal(out, &p.info); err != nil {
+			log.Printf("Plugin %s liefert ungültige Beschreibung: %v", entry.Name(), err)
+			continue
+		}
+		plugins = append(plugins, p)
+		for _, name := range p.info.ProjectTypes {
+			if _, exists := ParseProjectType(name); exists {
+				log.Printf("Plugin %s: Projekttyp %s existiert bereits, überspringe", p.info.Name, name)
+				continue
+			}
+			pt := ProjectType(len(ProjectTypeNames))
+			ProjectTypeNames = append(ProjectTypeNames, name)
+			pluginTypes[pt] = p
+		}
+		log.Printf("Plugin geladen: %s", p.info.Name)
+	}
+}
+
+// run führt eine Plugin-Aktion aus, meldet "step"-Zeilen als Fortschritt und
+// liefert die abschließende "result"-Nachricht.
+func (p *plugin) run(ps *ProjectSetup, action string) (pluginMessage, error) {
+	req := pluginRequest{
+		Type:       ps.ProjectType.String(),
+		Name:       ps.ProjectName,
+		ParentPath: ps.ParentPath,
+		ProjectDir: filepath.Join(ps.ParentPath, ps.ProjectName),
+	}
+	if ps.Template != nil {
+		req.Template = ps.Template.Name
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return pluginMessage{}, err
+	}
+
+	cmd := ps.command(p.path, action)
+	cmd.Dir = ps.ParentPath
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return pluginMessage{}, err
+	}
+	if err := cmd.Start(); err != nil {
+		return pluginMessage{}, fmt.Errorf("plugin %s starten fehlgeschlagen: %v", p.info.Name, err)
+	}
+
+	var result *pluginMessage
+	var pluginErr error
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		var msg pluginMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			log.Printf("Plugin %s: ungültige Ausgabe: %s", p.info.Name, scanner.Text())
+			continue
+		}
+		switch msg.Type {
+		case "step":
+			ps.step(msg.Message)
+		case "result":
+			result = &msg
+		case "error":
+			pluginErr = fmt.Errorf("plugin %s: %s", p.info.Name, msg.Message)
+		}
+	}
+	if err := cmd.Wait(); err != nil && pluginErr == nil {
+		pluginErr = fmt.Errorf("plugin %s fehlgeschlagen: %v", p.info.Name, err)
+	}
+	if pluginErr != nil {
+		return pluginMessage{}, pluginErr
+	}
+	if result == nil {
+		return pluginMessage{}, fmt.Errorf("plugin %s lieferte kein ergebnis", p.info.Name)
+	}
+	return *result, nil
+}
+
+// createPluginProject überlässt die Erstellung dem Plugin, das den Projekttyp registriert hat.
+func (ps *ProjectSetup) createPluginProject() error {
+	p, ok := pluginTypes[ps.ProjectType]
+	if !ok {
+		return fmt.Errorf("kein generator für projekttyp %s", ps.ProjectType)
+	}
+	result, err := p.run(ps, "create")
+	if err != nil {
+		return err
+	}
+	ps.pluginTerminalScript = result.TerminalScript
+	return nil
+}
+
+// runPostProcessors lässt alle Nachbearbeitungs-Plugins über das neue Projekt laufen.
+func (ps *ProjectSetup) runPostProcessors() error {
+	for _, p := range plugins {
+		if !p.info.PostProcess {
+			continue
+		}
+		ps.step(fmt.Sprintf("Führe Plugin %s aus...", p.info.Name))
+		if _, err := p.run(ps, "postprocess"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
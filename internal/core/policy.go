@@ -0,0 +1,158 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Eine maschinenweite Richtlinie (/etc/newpipi/policy.yaml) legt für alle
+// Benutzer eines Rechners fest, welche Lizenzen erlaubt sind, welche Registries
+// gelten, welche CI-Dateien jedes Projekt bekommt und ob Templates aus dem Netz
+// installiert werden dürfen. Sie hat Vorrang vor Konfiguration und Profil;
+// eine unlesbare Richtlinie verhindert den Start, statt still ignoriert zu werden.
+
+// policyFile ist der Standardpfad der Richtlinie, NEWPIPI_POLICY ersetzt ihn.
+const policyFile = "/etc/newpipi/policy.yaml"
+
+var errPolicy = errors.New("verstoß gegen die richtlinie")
+
+// Policy ist der Inhalt der Richtliniendatei.
+type Policy struct {
+	// AllowedLicenses sind die SPDX-Bezeichner, die Projekte und ihre
+	// Abhängigkeiten haben dürfen; leer erlaubt alle.
+	AllowedLicenses []string `yaml:"allowedLicenses"`
+	// DeniedLicenses ergänzt Settings.LicenseDeny um Präfixe, die immer markiert werden.
+	DeniedLicenses []string `yaml:"deniedLicenses"`
+	// Registries ersetzt die Registry-Mirrors der Profile je Ökosystem (npm, pip, go).
+	Registries map[string]string `yaml:"registries"`
+	// CIFiles legt Dateien an, die jedes Projekt haben muss: Projekttyp oder
+	// "*" → Pfad → Inhalt, {name} steht für den Projektnamen.
+	CIFiles map[string]map[string]string `yaml:"ciFiles"`
+	// NoRemoteTemplates verbietet Marktplatz, Template-Quellen und den Import
+	// von Templates aus dem Netz oder aus Archiven.
+	NoRemoteTemplates bool `yaml:"noRemoteTemplates"`
+}
+
+// policyPath liefert den Pfad der Richtliniendatei.
+func policyPath() string {
+	if path := os.Getenv("NEWPIPI_POLICY"); path != "" {
+		return path
+	}
+	return policyFile
+}
+
+// loadPolicy liest die Richtlinie; ohne Datei gilt keine. Unbekannte Felder
+// sind ein Fehler, damit Tippfehler nicht zu einer wirkungslosen Regel führen.
+func loadPolicy() (Policy, error) {
+	var p Policy
+	content, err := os.ReadFile(policyPath())
+	if os.IsNotExist(err) {
+		return p, nil
+	} else if err != nil {
+		return p, fmt.Errorf("richtlinie lesen fehlgeschlagen: %v", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && err != io.EOF {
+		return p, fmt.Errorf("richtlinie %s parsen fehlgeschlagen: %v", policyPath(), err)
+	}
+	for ecosystem := range p.Registries {
+		if _, ok := registryEnv[ecosystem]; !ok {
+			return p, fmt.Errorf("richtlinie %s: unbekanntes ökosystem %q für registries", policyPath(), ecosystem)
+		}
+	}
+	for pt, files := range p.CIFiles {
+		if _, ok := ParseProjectType(pt); !ok && pt != "*" {
+			return p, fmt.Errorf("richtlinie %s: unbekannter projekttyp %q für ciFiles", policyPath(), pt)
+		}
+		for path := range files {
+			if !filepath.IsLocal(filepath.FromSlash(path)) {
+				return p, fmt.Errorf("richtlinie %s: datei %q liegt außerhalb des projekts", policyPath(), path)
+			}
+		}
+	}
+	return p, nil
+}
+
+// RestrictsLicenses meldet, ob die Richtlinie Lizenzen regelt; die
+// Lizenzübersicht läuft dann bei jeder Erstellung.
+func (p Policy) RestrictsLicenses() bool {
+	return len(p.AllowedLicenses) > 0 || len(p.DeniedLicenses) > 0
+}
+
+// licenseAllowed prüft einen SPDX-Ausdruck gegen AllowedLicenses: Bei "OR"
+// genügt eine erlaubte Alternative, bei "AND" muss jeder Teil erlaubt sein.
+func (p Policy) licenseAllowed(expression string) bool {
+	if len(p.AllowedLicenses) == 0 {
+		return true
+	}
+	return !licenseExpressionDenied(expression, func(id string) bool {
+		return !slices.ContainsFunc(p.AllowedLicenses, func(allowed string) bool { return strings.EqualFold(allowed, id) })
+	})
+}
+
+// remoteTemplates meldet, ob Templates aus dem Netz installiert werden dürfen.
+func (p Policy) remoteTemplates() error {
+	if p.NoRemoteTemplates {
+		return fmt.Errorf("%w: templates aus dem netz sind deaktiviert (%s)", errPolicy, policyPath())
+	}
+	return nil
+}
+
+// ciFiles liefert die vorgeschriebenen Dateien des Projekttyps; typbezogene
+// Einträge überschreiben gleichnamige aus "*".
+func (p Policy) ciFiles(pt ProjectType, projectName string) map[string]string {
+	files := make(map[string]string)
+	for _, key := range []string{"*", pt.String()} {
+		for path, content := range p.CIFiles[key] {
+			files[path] = strings.ReplaceAll(content, "{name}", projectName)
+		}
+	}
+	return files
+}
+
+// checkPolicy prüft vorab die Lizenz des Profils gegen die Richtlinie; eine
+// ungültige Richtlinie blockiert die Erstellung.
+func (ps *ProjectSetup) checkPolicy() PreflightCheck {
+	if ps.policyErr != nil {
+		return PreflightCheck{Name: "Policy", Detail: policyPath(), Err: ps.policyErr}
+	}
+	license := ps.Profile().License
+	c := PreflightCheck{Name: "License policy", Detail: license}
+	if license != "" && !ps.Settings.Policy.licenseAllowed(license) {
+		c.Err = fmt.Errorf("%w: lizenz %s nicht erlaubt (erlaubt: %s)", errPolicy, license, strings.Join(ps.Settings.Policy.AllowedLicenses, ", "))
+	}
+	return c
+}
+
+// writeCIFiles legt die vorgeschriebenen CI-Dateien an. Sie ersetzen
+// gleichnamige Dateien aus dem Template.
+func (ps *ProjectSetup) writeCIFiles(projectDir string) error {
+	files := ps.Settings.Policy.ciFiles(ps.ProjectType, ps.ProjectName)
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	modes := ps.Settings.Modes()
+	for _, path := range paths {
+		ps.step("Lege " + path + " laut Richtlinie an...")
+		target := filepath.Join(projectDir, filepath.FromSlash(path))
+		if err := modes.mkdirAll(filepath.Dir(target)); err != nil {
+			return fmt.Errorf("verzeichnis für %s erstellen fehlgeschlagen: %v", path, err)
+		}
+		if err := modes.writeFile(target, []byte(files[path]), modes.File); err != nil {
+			return fmt.Errorf("%s erstellen fehlgeschlagen: %v", path, err)
+		}
+	}
+	return nil
+}
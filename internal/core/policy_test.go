@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicyLicenseAllowed(t *testing.T) {
+	p := Policy{AllowedLicenses: []string{"MIT", "Apache-2.0"}}
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{"MIT", true},
+		{"mit", true},
+		{"GPL-3.0-only", false},
+		{"MIT OR GPL-3.0-only", true},
+		{"(MIT AND BSD-3-Clause)", false},
+		{"Apache-2.0 AND MIT", true},
+		{"unbekannt", false},
+	}
+	for _, tt := range tests {
+		if got := p.licenseAllowed(tt.expression); got != tt.want {
+			t.Errorf("licenseAllowed(%q) = %v, erwartet %v", tt.expression, got, tt.want)
+		}
+	}
+	if !(Policy{}).licenseAllowed("GPL-3.0-only") {
+		t.Error("ohne allowedLicenses muss jede Lizenz erlaubt sein")
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		ok      bool
+	}{
+		{"leer", "", true},
+		{"gültig", "allowedLicenses: [MIT]\nregistries: {npm: https://npm.example.com}\nciFiles: {Go: {.gitlab-ci.yml: x}}\n", true},
+		{"tippfehler", "allowedLicence: [MIT]\n", false},
+		{"ökosystem", "registries: {maven: https://repo.example.com}\n", false},
+		{"projekttyp", "ciFiles: {Cobol: {ci.yml: x}}\n", false},
+		{"pfad", "ciFiles: {\"*\": {../ci.yml: x}}\n", false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "policy.yaml")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("NEWPIPI_POLICY", path)
+		if _, err := loadPolicy(); (err == nil) != tt.ok {
+			t.Errorf("%s: fehler %v, erwartet ok=%v", tt.name, err, tt.ok)
+		}
+	}
+	t.Setenv("NEWPIPI_POLICY", filepath.Join(t.TempDir(), "fehlt.yaml"))
+	if _, err := loadPolicy(); err != nil {
+		t.Errorf("ohne datei: %v", err)
+	}
+}
+
+func TestInvalidPolicyBlocksCreation(t *testing.T) {
+	t.Setenv("NEWPIPI_CONFIG_DIR", t.TempDir())
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte("allowedLicence: [MIT]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NEWPIPI_POLICY", path)
+	ps := NewProjectSetup()
+	ps.ProjectName, ps.ProjectType, ps.ParentPath = "demo", Go, t.TempDir()
+	if err := ps.CreateProject(); !errors.Is(err, errPolicy) || ExitCodeFor(err) != exitPolicy {
+		t.Fatalf("createProject() = %v, erwartet errPolicy", err)
+	}
+	if _, err := os.Stat(filepath.Join(ps.ParentPath, "demo")); !os.IsNotExist(err) {
+		t.Error("projekt trotz ungültiger richtlinie angelegt")
+	}
+}
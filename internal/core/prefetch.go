@@ -0,0 +1,104 @@
+package core
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// prefetchList liefert die vorzuladenden Pakete eines Projekttyps: die
+// Standardpakete des eingebauten Generators samt Standard-Toolkit und die
+// Pakete seiner Templates.
+func prefetchList(settings Settings, pt ProjectType) []string {
+	var packages []string
+	ps := &ProjectSetup{ProjectType: pt, Settings: settings}
+	if lang := ps.Language(); lang != nil {
+		tk, _ := ps.Toolkit()
+		packages = slices.Clone(ps.scaffoldPackages(lang, tk))
+	}
+	for _, t := range allTemplates() {
+		if t.Type == pt {
+			packages = append(packages, t.Packages...)
+		}
+	}
+	// Außerhalb eines Moduls braucht go mod download Versionen
+	for i, p := range packages {
+		if pt == Go && !strings.Contains(p, "@") {
+			packages[i] = p + "@latest"
+		}
+	}
+	return packages
+}
+
+// Prefetch lädt die Abhängigkeiten in die Caches der Paketmanager, damit
+// spätere Projekterstellungen ohne langsame Downloads auskommen. Typen ohne
+// installierte Werkzeuge werden übersprungen.
+func (ps *ProjectSetup) Prefetch(types []ProjectType) error {
+	var errs []error
+	for _, pt := range types {
+		packages := prefetchList(ps.Settings, pt)
+		if len(packages) == 0 {
+			continue
+		}
+		if err := ps.prefetchType(pt, packages); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pt, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// prefetchType führt die Prefetch-Befehle des Projekttyps in einem leeren
+// Verzeichnis aus; fehlt das Werkzeug des ersten Befehls, entfällt der Typ.
+func (ps *ProjectSetup) prefetchType(pt ProjectType, packages []string) error {
+	lang := languages[pt]
+	if lang == nil || len(lang.Prefetch) == 0 || len(lang.Prefetch[0].Run) == 0 {
+		return nil
+	}
+	if _, err := exec.LookPath(lang.Prefetch[0].Run[0]); err != nil {
+		log.Printf("Vorladen für %s übersprungen: %s nicht installiert", pt, lang.Prefetch[0].Run[0])
+		return nil
+	}
+	tmp, err := os.MkdirTemp("", "newpipi-prefetch-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	ps.step(fmt.Sprintf("Lade %s-Abhängigkeiten vor...", pt))
+	return ps.runCommands(tmp, expandPackages(lang.Prefetch, packages), false)
+}
+
+// setupPrefetch definiert `newpipi prefetch [--type TYP]`.
+func setupPrefetch(fs *flag.FlagSet) func(args []string) int {
+	typeName := fs.String("type", "", "Nur diesen Projekttyp vorladen")
+	return func(args []string) int {
+		types := AllProjectTypes()
+		if *typeName != "" {
+			pt, ok := ParseProjectType(*typeName)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Fehler: unbekannter projekttyp: %q\n", *typeName)
+				return exitUsage
+			}
+			types = []ProjectType{pt}
+		}
+		ps := NewProjectSetup()
+		if err := ps.Prefetch(types); err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return ExitCodeFor(err)
+		}
+		return ExitOK
+	}
+}
+
+func AllProjectTypes() []ProjectType {
+	types := make([]ProjectType, len(ProjectTypeNames))
+	for i := range types {
+		types[i] = ProjectType(i)
+	}
+	return types
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/sys/unix"
+)
+
+// Vor jeder Erstellung läuft eine Prüfung aller Voraussetzungen, die sich
+// ohne Änderungen feststellen lassen. CreateProject bricht beim ersten
+// blockierenden Fehler ab; die GUI zeigt vorher die ganze Liste an.
+
+// networkCheckTimeout begrenzt die Prüfung der Paketquelle.
+const networkCheckTimeout = 3 * time.Second
+
+// PreflightCheck ist ein Punkt der Checkliste. Name ist eine UI-Beschriftung.
+type PreflightCheck struct {
+	Name   string
+	Detail string
+	Err    error
+	// Warning lässt die Erstellung trotz Err zu, etwa ohne Netz bei gefüllten Caches.
+	Warning bool
+}
+
+func (c PreflightCheck) Blocking() bool {
+	return c.Err != nil && !c.Warning
+}
+
+// Preflight prüft Name, Zielpfad, Namenskonflikt, Speicherplatz, Werkzeuge
+// und bei Bedarf die Anmeldung für den Push und die Paketquelle. Beim Fortsetzen darf das Verzeichnis bestehen.
+func (ps *ProjectSetup) Preflight(resuming bool) []PreflightCheck {
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	checks := []PreflightCheck{ps.checkName()}
+	if ps.ProjectType == Go && ps.Template == nil {
+		checks = append(checks, ps.checkModule())
+	}
+
+	path := ps.checkParentPath()
+	checks = append(checks, path, ps.checkPathCharacters(), ps.checkModes())
+	if path.Err == nil {
+		if caps := ps.mountCaps(); caps.Network {
+			checks = append(checks, ps.checkMount(caps))
+		}
+	}
+
+	conflict := PreflightCheck{Name: "Name conflict", Detail: projectDir}
+	if _, err := os.Stat(projectDir); !resuming && !os.IsNotExist(err) {
+		conflict.Err = fmt.Errorf("%w: %s", errProjectExists, projectDir)
+		if findPendingState(projectDir) != nil {
+			conflict.Err = fmt.Errorf("%w: %s (unterbrochene erstellung, fortsetzen mit newpipi resume %s)", errProjectExists, projectDir, shellQuote(projectDir))
+		}
+	}
+	checks = append(checks, conflict)
+
+	disk := PreflightCheck{Name: "Disk space"}
+	if path.Err == nil {
+		disk.Detail, disk.Err = ps.checkDiskSpace()
+	} else {
+		disk.Err, disk.Warning = errors.New("übersprungen, zielpfad ungültig"), true
+	}
+	checks = append(checks, disk)
+
+	toolchain := PreflightCheck{Name: "Toolchain"}
+	if err := ps.checkToolchain(); err != nil {
+		toolchain.Err = fmt.Errorf("%w: %v", errToolchainMissing, err)
+	}
+	var versions []string
+	for _, v := range ps.toolVersions {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+	toolchain.Detail = strings.Join(versions, ", ")
+	checks = append(checks, toolchain)
+
+	if !ps.SkipGit {
+		checks = append(checks, ps.checkVCS())
+	}
+
+	if ps.policyErr != nil || len(ps.Settings.Policy.AllowedLicenses) > 0 {
+		checks = append(checks, ps.checkPolicy())
+	}
+
+	if ps.SignCommits && ps.gitRepo() {
+		checks = append(checks, ps.checkSigning())
+	}
+
+	if ps.lfsTracked() != nil {
+		checks = append(checks, ps.checkLFS())
+	}
+
+	if ps.Push && ps.gitRepo() {
+		push := ps.checkPush()
+		push.Warning = errors.Is(push.Err, errNetwork)
+		checks = append(checks, push)
+	}
+
+	if registry := ps.registryURL(); registry != "" && ps.needsNetwork() {
+		network := PreflightCheck{Name: "Network", Detail: registry}
+		if err := checkReachable(registry); err != nil {
+			network.Err = fmt.Errorf("%w: %s nicht erreichbar: %v", errNetwork, registry, err)
+			network.Warning = true
+		}
+		checks = append(checks, network)
+	}
+	return checks
+}
+
+// PreflightError liefert den ersten blockierenden Fehler der Checkliste.
+func PreflightError(checks []PreflightCheck) error {
+	for _, c := range checks {
+		if c.Blocking() {
+			return c.Err
+		}
+	}
+	return nil
+}
+
+func (ps *ProjectSetup) checkName() PreflightCheck {
+	c := PreflightCheck{Name: "Project name", Detail: ps.ProjectName}
+	if valid, msg := IsValidProjectName(ps.ProjectName); !valid {
+		c.Err = errors.New(msg)
+	}
+	return c
+}
+
+// checkModule prüft den Modulpfad eines Go-Projekts (siehe gomodule.go).
+func (ps *ProjectSetup) checkModule() PreflightCheck {
+	c := PreflightCheck{Name: "Module path", Detail: ps.goModule()}
+	c.Err = checkModulePath(c.Detail)
+	return c
+}
+
+// checkParentPath prüft, ob im Elternverzeichnis geschrieben werden kann.
+// Fehlt es, muss sein nächstes vorhandenes Oberverzeichnis beschreibbar sein.
+func (ps *ProjectSetup) checkParentPath() PreflightCheck {
+	c := PreflightCheck{Name: "Target directory", Detail: ps.ParentPath}
+	if ps.ParentPath == "" {
+		c.Err = errors.New("elternpfad darf nicht leer sein")
+		return c
+	}
+	dir := existingAncestor(ps.ParentPath)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		c.Err = fmt.Errorf("%s ist kein verzeichnis", dir)
+		return c
+	}
+	if err := unix.Access(dir, unix.W_OK); err != nil {
+		c.Err = fmt.Errorf("%s ist nicht beschreibbar: %v", dir, err)
+		return c
+	}
+	if dir != ps.ParentPath {
+		c.Detail += " (wird angelegt)"
+	}
+	return c
+}
+
+// checkPathCharacters prüft den Elternpfad auf Zeichen, die Skripte oder
+// Werkzeuge stören. Terminal und Befehle maskieren Pfade selbst, gewarnt wird
+// nur, wenn die Werkzeuge des Projekttyps daran scheitern können.
+func (ps *ProjectSetup) checkPathCharacters() PreflightCheck {
+	c := PreflightCheck{Name: "Path characters", Detail: "ASCII ohne Leerzeichen"}
+	if strings.ContainsFunc(ps.ParentPath, unicode.IsControl) {
+		c.Err = errors.New("pfad enthält steuerzeichen")
+		return c
+	}
+	var found []string
+	if strings.ContainsFunc(ps.ParentPath, unicode.IsSpace) {
+		found = append(found, "Leerzeichen")
+	}
+	if strings.ContainsFunc(ps.ParentPath, func(r rune) bool { return r > unicode.MaxASCII }) || !utf8.ValidString(ps.ParentPath) {
+		found = append(found, "Nicht-ASCII-Zeichen")
+	}
+	if len(found) == 0 {
+		return c
+	}
+	c.Detail = fmt.Sprintf("enthält %s (wird maskiert)", strings.Join(found, " und "))
+	if warning := ps.pathWarning(); warning != "" {
+		c.Err = fmt.Errorf("pfad enthält %s: %s", strings.Join(found, " und "), warning)
+		c.Warning = true
+	}
+	return c
+}
+
+// checkModes prüft die Rechte aus den Einstellungen und ob die umask
+// eingestellte Rechte wieder entfernt.
+func (ps *ProjectSetup) checkModes() PreflightCheck {
+	c := PreflightCheck{Name: "Permissions"}
+	m, err := ps.Settings.parseModes()
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	mask := umask()
+	c.Detail = fmt.Sprintf("Dateien %s, Verzeichnisse %s (umask %s)", formatMode(m.File&^mask), formatMode(m.Dir&^mask), formatMode(mask))
+	configured := ps.Settings.FileMode != "" || ps.Settings.DirMode != ""
+	if lost := (m.File | m.Dir).Perm() & mask; configured && lost != 0 {
+		c.Err = fmt.Errorf("umask %s entfernt eingestellte rechte (%s), für gemeinsam genutzte projekte etwa umask 0002 setzen", formatMode(mask), formatMode(lost))
+		c.Warning = true
+	}
+	return c
+}
+
+// checkMount meldet, welche Fähigkeiten dem Netzlaufwerk fehlen und wie die
+// Erstellung darauf reagiert. Blockiert wird nie.
+func (ps *ProjectSetup) checkMount(caps *mountCaps) PreflightCheck {
+	c := PreflightCheck{Name: "Network mount", Detail: caps.FSType + ": Symlinks, Ausführrechte, Dateisperren und Hardlinks verfügbar"}
+	missing := caps.missing()
+	if len(missing) == 0 {
+		return c
+	}
+	var adjust []string
+	if lang := ps.Language(); lang != nil && lang.LocalDir != "" && caps.relocateLocalDir() {
+		adjust = append(adjust, fmt.Sprintf("%s entsteht unter %s", lang.LocalDir, ps.localDir()))
+	}
+	if !caps.Hardlinks {
+		adjust = append(adjust, "paketmanager kopieren statt hardlinks")
+	}
+	if !caps.Locking {
+		adjust = append(adjust, "ohne dateisperren können cargo und git hängen")
+	}
+	c.Err = fmt.Errorf("%s ohne %s", caps.FSType, strings.Join(missing, ", "))
+	if len(adjust) > 0 {
+		c.Err = fmt.Errorf("%v: %s", c.Err, strings.Join(adjust, "; "))
+	}
+	c.Warning = true
+	return c
+}
+
+// pathWarning liefert den Hinweis des Toolkits oder Projekttyps zu heiklen Pfaden.
+func (ps *ProjectSetup) pathWarning() string {
+	lang := ps.Language()
+	if lang == nil {
+		return ""
+	}
+	if tk, _ := ps.Toolkit(); tk != nil && tk.PathWarning != "" {
+		return tk.PathWarning
+	}
+	return lang.PathWarning
+}
+
+// existingAncestor liefert path oder sein nächstes vorhandenes Oberverzeichnis.
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Lstat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// needsNetwork meldet, ob die Erstellung Pakete lädt.
+func (ps *ProjectSetup) needsNetwork() bool {
+	if ps.Template != nil && (len(ps.Template.Packages) > 0 || slices.ContainsFunc(ps.Template.Commands, func(c TemplateCommand) bool { return c.Network })) {
+		return true
+	}
+	lang := ps.Language()
+	if lang == nil {
+		return false
+	}
+	tk, _ := ps.Toolkit()
+	return slices.ContainsFunc(expandPackages(lang.with(tk).Commands, ps.scaffoldPackages(lang, tk)), func(c TemplateCommand) bool { return c.Network })
+}
+
+// registryURL ist die Paketquelle des Projekttyps, bevorzugt der Mirror aus
+// Richtlinie oder Profil.
+func (ps *ProjectSetup) registryURL() string {
+	lang := ps.Language()
+	if lang == nil {
+		return ""
+	}
+	if mirror := ps.registryMirrors()[lang.Ecosystem]; lang.Ecosystem != "" && mirror != "" {
+		// GOPROXY kann eine Liste sein
+		first, _, _ := strings.Cut(mirror, ",")
+		return first
+	}
+	return lang.Registry
+}
+
+// checkReachable prüft, ob der Server antwortet; der Statuscode spielt keine Rolle.
+func checkReachable(url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), networkCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
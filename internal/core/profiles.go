@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Profile bündelt Einstellungen für einen Arbeitskontext, z.B. "work" oder "personal".
+type Profile struct {
+	ParentPath string `json:"parentPath,omitempty"`
+	GitName    string `json:"gitName,omitempty"`
+	GitEmail   string `json:"gitEmail,omitempty"`
+	// SigningKey signiert Commits: ein öffentlicher SSH-Schlüssel, eine
+	// .pub-Datei oder eine GPG-Schlüssel-ID (siehe signing.go).
+	SigningKey string `json:"signingKey,omitempty"`
+	// ForgeTokens ordnet Forge-Hosts (z.B. github.com) ihr API-Token zu; der Wert
+	// "keyring" verweist auf den System-Schlüsselbund (siehe secrets.go).
+	ForgeTokens map[string]string `json:"forgeTokens,omitempty"`
+	// RemoteURL ist die Adresse für den ersten Push, {name} steht für den
+	// Projektnamen, etwa "git@github.com:me/{name}.git" (siehe push.go).
+	RemoteURL string `json:"remoteURL,omitempty"`
+	// ForgePolicy ist eine JSON-Datei mit Repository-Einstellungen, die nach
+	// der Erstellung auf den Remote origin angewendet werden (siehe forge.go).
+	ForgePolicy string `json:"forgePolicy,omitempty"`
+	// License ist der SPDX-Bezeichner der Standardlizenz neuer Projekte.
+	License string `json:"license,omitempty"`
+	// RegistryMirrors ordnet den Ökosystemen npm, pip und go eine Registry-URL zu.
+	RegistryMirrors map[string]string `json:"registryMirrors,omitempty"`
+	// RegistryCredentials enthält je Ökosystem "user:passwort" oder ein Token.
+	RegistryCredentials map[string]string `json:"registryCredentials,omitempty"`
+}
+
+// registryEnv nennt je Ökosystem die Umgebungsvariable, über die der Mirror gesetzt wird.
+var registryEnv = map[string]string{
+	"npm": "npm_config_registry",
+	"pip": "PIP_INDEX_URL",
+	"go":  "GOPROXY",
+}
+
+func (s Settings) ProfileNames() []string {
+	names := make([]string, 0, len(s.Profiles))
+	for name := range s.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectProfile aktiviert ein Profil; ein leerer Name deaktiviert Profile.
+func (ps *ProjectSetup) SelectProfile(name string) error {
+	if name == "" {
+		ps.ProfileName = ""
+		return nil
+	}
+	profile, ok := ps.Settings.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profil nicht gefunden: %s", name)
+	}
+	ps.ProfileName = name
+	// NEWPIPI_PARENT_PATH hat Vorrang vor dem Pfad des Profils
+	if _, ok := os.LookupEnv("NEWPIPI_PARENT_PATH"); !ok && profile.ParentPath != "" {
+		ps.ParentPath = profile.ParentPath
+	}
+	return nil
+}
+
+func (ps *ProjectSetup) Profile() Profile {
+	return ps.Settings.Profiles[ps.ProfileName]
+}
+
+// registryMirrors liefert die Mirrors des Profils; die Registries der
+// Richtlinie haben Vorrang.
+func (ps *ProjectSetup) registryMirrors() map[string]string {
+	if len(ps.Settings.Policy.Registries) == 0 {
+		return ps.Profile().RegistryMirrors
+	}
+	mirrors := make(map[string]string)
+	for ecosystem, url := range ps.Profile().RegistryMirrors {
+		mirrors[ecosystem] = url
+	}
+	for ecosystem, url := range ps.Settings.Policy.Registries {
+		mirrors[ecosystem] = url
+	}
+	return mirrors
+}
+
+// command erstellt einen Befehl für die Projekterstellung, der die
+// Registry-Mirrors des aktiven Profils über seine Umgebung erhält.
+func (ps *ProjectSetup) command(name string, args ...string) *exec.Cmd {
+	return ps.commandContext(ps.context(), name, args...)
+}
+
+// context liefert den Kontext der Erstellung.
+func (ps *ProjectSetup) context() context.Context {
+	if ps.Context == nil {
+		return context.Background()
+	}
+	return ps.Context
+}
+
+// commandContext ist command mit Kontext, etwa für ein Zeitlimit.
+func (ps *ProjectSetup) commandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := ps.execCommand(ctx, name, args...)
+	ps.commandLog = append(ps.commandLog, strings.Join(slices.Concat([]string{name}, args), " "))
+	mirrors := ps.registryMirrors()
+	env := ps.mountEnv()
+	if len(mirrors) == 0 && len(env) == 0 {
+		return cmd
+	}
+	cmd.Env = append(os.Environ(), env...)
+	for ecosystem, url := range mirrors {
+		env, ok := registryEnv[ecosystem]
+		if !ok {
+			continue
+		}
+		cmd.Env = append(cmd.Env, env+"="+url)
+		credential, err := ps.registryCredential(ecosystem)
+		if err != nil {
+			log.Printf("Registry-Zugangsdaten für %s nicht verfügbar: %v", ecosystem, err)
+			continue
+		}
+		cmd.Env = append(cmd.Env, registryEnvValue(ecosystem, url, credential)...)
+	}
+	return cmd
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// projectTool ist ein Werkzeug, das die Abhängigkeiten eines fertigen
+// Projekts untersucht: Audit, Lizenzen oder SBOM eines Projekttyps. Run läuft
+// im Projektverzeichnis; {name}, {local}, {file} und {format} werden
+// ersetzt. Argumente mit * löst newpipi im Projekt auf, findet das Muster
+// nichts (etwa ohne venv), entfällt der Schritt.
+type projectTool struct {
+	Run []string `json:"run"`
+	// Requires prüft Werkzeuge, die nicht als eigener Befehl im PATH liegen,
+	// etwa cargo-Plugins; sonst genügt Run[0].
+	Requires *toolCheck `json:"requires,omitempty"`
+	// Output wählt die Auswertung der Ausgabe (siehe auditParsers und
+	// licenseParsers).
+	Output string `json:"output,omitempty"`
+	// Formats sind die SBOM-Formate des Werkzeugs; mit Stdout schreibt
+	// newpipi die Ausgabe selbst in die Datei.
+	Formats []string `json:"formats,omitempty"`
+	Stdout  bool     `json:"stdout,omitempty"`
+}
+
+// output liefert die Auswertung der Ausgabe; ohne Werkzeug "".
+func (t *projectTool) output() string {
+	if t == nil {
+		return ""
+	}
+	return t.Output
+}
+
+// missing liefert den Namen des fehlenden Werkzeugs, sonst "".
+func (t *projectTool) missing() string {
+	if t.Requires != nil && len(t.Requires.Command) > 0 {
+		if exec.Command(t.Requires.Command[0], t.Requires.Command[1:]...).Run() != nil {
+			return t.Requires.Label
+		}
+		return ""
+	}
+	if _, err := exec.LookPath(t.Run[0]); err != nil {
+		return t.Run[0]
+	}
+	return ""
+}
+
+// toolArgs setzt die Platzhalter in t.Run ein; ok ist false ohne Werkzeug
+// oder wenn ein Muster im Projekt nichts findet.
+func (ps *ProjectSetup) toolArgs(projectDir string, t *projectTool, vars map[string]string) (args []string, ok bool) {
+	if t == nil || len(t.Run) == 0 {
+		return nil, false
+	}
+	replace := []string{"{name}", ps.ProjectName}
+	for key, value := range vars {
+		replace = append(replace, key, value)
+	}
+	expand := strings.NewReplacer(replace...).Replace
+	for _, arg := range t.Run {
+		arg = expand(ps.expandLocal(arg, false))
+		if strings.Contains(arg, "*") {
+			pattern := arg
+			if !filepath.IsAbs(pattern) {
+				pattern = filepath.Join(projectDir, pattern)
+			}
+			matches, _ := filepath.Glob(pattern)
+			if len(matches) == 0 {
+				return nil, false
+			}
+			arg = matches[0]
+		}
+		args = append(args, arg)
+	}
+	return args, true
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestToolArgs(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "venv", "lib", "python3.12", "site-packages"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ps := &ProjectSetup{ProjectName: "demo", ProjectType: Python}
+	tests := []struct {
+		name string
+		run  []string
+		want []string
+		ok   bool
+	}{
+		{"name", []string{"go-licenses", "--ignore", "{name}"}, []string{"go-licenses", "--ignore", "demo"}, true},
+		{"format", []string{"npm", "sbom", "--sbom-format", "{format}", "{file}"}, []string{"npm", "sbom", "--sbom-format", "spdx", "sbom.spdx.json"}, true},
+		{"local", []string{"pip-licenses", "--python", "{local}/bin/python"}, []string{"pip-licenses", "--python", "venv/bin/python"}, true},
+		{"muster", []string{"pip-audit", "--path", "{local}/lib/python*/site-packages"}, []string{"pip-audit", "--path", filepath.Join(dir, "venv", "lib", "python3.12", "site-packages")}, true},
+		{"ohne treffer", []string{"pip-audit", "--path", "env/lib/python*"}, nil, false},
+		{"leer", nil, nil, false},
+	}
+	for _, tt := range tests {
+		args, ok := ps.toolArgs(dir, &projectTool{Run: tt.run}, map[string]string{"{format}": "spdx", "{file}": "sbom.spdx.json"})
+		if ok != tt.ok || !slices.Equal(args, tt.want) {
+			t.Errorf("%s: toolArgs = %q, %v, want %q, %v", tt.name, args, ok, tt.want, tt.ok)
+		}
+	}
+	if _, ok := ps.toolArgs(dir, nil, nil); ok {
+		t.Error("toolArgs ohne werkzeug angenommen")
+	}
+}
+
+func TestLanguageTools(t *testing.T) {
+	tests := []struct {
+		pt                    ProjectType
+		audit, licenses, sbom bool
+		prefetch              bool
+	}{
+		{Python, true, true, true, true},
+		{Go, true, true, true, true},
+		{Rust, true, true, true, true},
+		{JavaScript, true, true, true, true},
+		{TypeScript, true, true, true, true},
+		{CSharp, true, false, false, false},
+		{Zig, false, false, false, false},
+	}
+	for _, tt := range tests {
+		lang := languages[tt.pt]
+		if got := auditParsers[lang.Audit.output()] != nil; got != tt.audit {
+			t.Errorf("%s: audit = %v, want %v", tt.pt, got, tt.audit)
+		}
+		if got := licenseParsers[lang.Licenses.output()] != nil; got != tt.licenses {
+			t.Errorf("%s: licenses = %v, want %v", tt.pt, got, tt.licenses)
+		}
+		if got := lang.SBOM != nil && slices.Contains(lang.SBOM.Formats, "cyclonedx"); got != tt.sbom {
+			t.Errorf("%s: sbom = %v, want %v", tt.pt, got, tt.sbom)
+		}
+		if got := slices.ContainsFunc(lang.Prefetch, func(c TemplateCommand) bool { return slices.Contains(c.Run, "{packages}") }); got != tt.prefetch {
+			t.Errorf("%s: prefetch = %v, want %v", tt.pt, got, tt.prefetch)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Mit Profile.RemoteURL trägt newpipi nach dem ersten Commit den Remote origin
+// ein und pusht auf Wunsch. Ob der Push klappen kann, prüft preflight vorher:
+// per ssh beim Forge anmelden bzw. gh oder das Forge-Token fragen. So scheitert
+// die Erstellung nicht erst am Ende mit einer Git-Fehlermeldung.
+
+// pushCheckTimeout begrenzt die Anmeldung per ssh und die Token-Prüfung.
+const pushCheckTimeout = 10 * time.Second
+
+// remoteURL liefert die Remote-URL des Profils mit eingesetztem Projektnamen.
+func (ps *ProjectSetup) remoteURL() string {
+	return strings.ReplaceAll(ps.Profile().RemoteURL, "{name}", ps.ProjectName)
+}
+
+// isSSHRemote erkennt ssh://- und scp-artige Adressen wie git@github.com:o/r.git.
+func isSSHRemote(remote string) bool {
+	if strings.HasPrefix(remote, "ssh://") {
+		return true
+	}
+	if strings.Contains(remote, "://") {
+		return false
+	}
+	at, _, ok := strings.Cut(remote, ":")
+	return ok && !strings.Contains(at, "/")
+}
+
+// checkPush prüft die Anmeldung für den ersten Push und nennt bei Fehlern,
+// wie sie sich beheben lässt.
+func (ps *ProjectSetup) checkPush() PreflightCheck {
+	remote := ps.remoteURL()
+	c := PreflightCheck{Name: "Git push", Detail: remote}
+	if remote == "" {
+		c.Err = fmt.Errorf("profil %s hat keine remoteURL", ps.ProfileName)
+		return c
+	}
+	host, _, _, err := parseRemote(remote)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pushCheckTimeout)
+	defer cancel()
+	if isSSHRemote(remote) {
+		if err := checkSSHAuth(ctx, remote, host); err != nil {
+			c.Err = err
+		}
+		return c
+	}
+	if err := ps.checkHTTPSAuth(ctx, host); err != nil {
+		c.Err = err
+	}
+	return c
+}
+
+// checkSSHAuth meldet sich ohne Rückfragen beim Forge an. GitHub, GitLab und
+// Gitea beenden die Sitzung danach mit Exit-Code 1; 255 heißt, ssh selbst ist
+// gescheitert. Ein Abbruch durch den Timeout zählt als Fehler.
+func checkSSHAuth(ctx context.Context, remote, host string) error {
+	user := "git"
+	if at, _, ok := strings.Cut(strings.TrimPrefix(remote, "ssh://"), "@"); ok && !strings.ContainsAny(at, "/:") {
+		user = at
+	}
+	cmd := exec.CommandContext(ctx, "ssh", "-T", "-o", "BatchMode=yes", "-o", "ConnectTimeout=5", "-o", "StrictHostKeyChecking=accept-new", user+"@"+host)
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%w: ssh-anmeldung bei %s nach %v abgebrochen", errNetwork, host, pushCheckTimeout)
+	}
+	var exit *exec.ExitError
+	if err == nil || errors.As(err, &exit) && exit.ExitCode() != 255 {
+		return nil
+	}
+	output := strings.TrimSpace(string(out))
+	switch {
+	case strings.Contains(output, "Permission denied"):
+		// ssh-add -l: 0 = Schlüssel geladen, 1 = Agent ohne Schlüssel, 2 = kein Agent
+		hint := "geladene ssh-schlüssel (ssh-add -l) sind beim forge nicht hinterlegt"
+		var agentExit *exec.ExitError
+		if err := exec.Command("ssh-add", "-l").Run(); errors.As(err, &agentExit) && agentExit.ExitCode() == 1 {
+			hint = "ssh-agent hat keine schlüssel, mit ssh-add laden"
+		} else if err != nil {
+			hint = "ssh-agent starten (eval \"$(ssh-agent)\") und schlüssel mit ssh-add laden"
+		}
+		return fmt.Errorf("ssh-anmeldung bei %s abgelehnt: %s; fehlt ein schlüssel, mit ssh-keygen -t ed25519 erzeugen und den öffentlichen schlüssel beim forge hinterlegen – oder ohne push erstellen", host, hint)
+	case strings.Contains(output, "Host key verification failed"):
+		return fmt.Errorf("hostschlüssel von %s passt nicht zu ~/.ssh/known_hosts, eintrag prüfen (ssh-keygen -R %s) oder ohne push erstellen", host, host)
+	case output == "":
+		output = err.Error()
+	}
+	return fmt.Errorf("ssh-verbindung zu %s fehlgeschlagen: %s", host, output)
+}
+
+// checkHTTPSAuth prüft das Forge-Token des Profils an der API, sonst die
+// Anmeldung von gh.
+func (ps *ProjectSetup) checkHTTPSAuth(ctx context.Context, host string) error {
+	token, err := ps.forgeToken(host)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		if _, err := exec.LookPath("gh"); err != nil {
+			return fmt.Errorf("keine anmeldung für %s: forge-token mit newpipi secret set --profile %s --forge %s ablegen oder gh installieren und gh auth login --hostname %s ausführen – oder ohne push erstellen", host, ps.ProfileName, host, host)
+		}
+		if out, err := exec.CommandContext(ctx, "gh", "auth", "status", "--hostname", host).CombinedOutput(); err != nil {
+			return fmt.Errorf("gh ist für %s nicht angemeldet (%s): gh auth login --hostname %s ausführen – oder ohne push erstellen", host, strings.TrimSpace(string(out)), host)
+		}
+		return nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, forgeAPIBase(host)+"/user", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %s nicht erreichbar: %v", errNetwork, host, err)
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("forge-token für %s ist ungültig oder abgelaufen: neues token mit newpipi secret set --profile %s --forge %s ablegen – oder ohne push erstellen", host, ps.ProfileName, host)
+	case http.StatusForbidden:
+		return fmt.Errorf("forge-token für %s hat keine berechtigung (fehlende scopes oder sso-freigabe): token mit schreibrecht für repositories ablegen – oder ohne push erstellen", host)
+	}
+	return nil
+}
+
+// withGitToken gibt git das Forge-Token per Umgebung mit, damit es nicht im
+// protokollierten Befehl und damit im Erstellungsbericht steht.
+func withGitToken(cmd *exec.Cmd, token string) {
+	if cmd.Env == nil {
+		cmd.Env = cmd.Environ()
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	cmd.Env = append(cmd.Env, "GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: Basic "+auth)
+}
+
+// pushInitial trägt origin ein und pusht den ersten Commit. Über https erhält
+// git das Forge-Token des Profils per Umgebung (withGitToken). Existiert
+// origin schon (Fortsetzen nach gescheitertem Push), wird die URL ersetzt.
+func (ps *ProjectSetup) pushInitial(projectDir string) error {
+	remote := ps.remoteURL()
+	ps.step(fmt.Sprintf("Pushe nach %s...", remote))
+	verb := "add"
+	existing := ps.command("git", "remote", "get-url", "origin")
+	existing.Dir = projectDir
+	if existing.Run() == nil {
+		verb = "set-url"
+	}
+	add := ps.command("git", "remote", verb, "origin", remote)
+	add.Dir = projectDir
+	if out, err := add.CombinedOutput(); err != nil {
+		return fmt.Errorf("remote origin eintragen fehlgeschlagen: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	push := ps.command("git", "push", "-u", "origin", "HEAD")
+	push.Dir = projectDir
+	if !isSSHRemote(remote) {
+		if host, _, _, err := parseRemote(remote); err == nil {
+			if token, err := ps.forgeToken(host); err == nil && token != "" {
+				withGitToken(push, token)
+			}
+		}
+	}
+	if out, err := push.CombinedOutput(); err != nil {
+		return fmt.Errorf("git push fehlgeschlagen: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
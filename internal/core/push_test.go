@@ -0,0 +1,26 @@
+package core
+
+import "testing"
+
+func TestIsSSHRemote(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   bool
+	}{
+		{"git@github.com:owner/repo.git", true},
+		{"github.com:owner/repo", true},
+		{"ssh://git@github.com/owner/repo.git", true},
+		{"ssh://git@gitlab.example.com:2222/owner/repo.git", true},
+		{"https://github.com/owner/repo.git", false},
+		{"http://gitea.local/owner/repo", false},
+		{"git://github.com/owner/repo.git", false},
+		{"/srv/git/repo.git", false},
+		{"./relative/path:with-colon", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isSSHRemote(tt.remote); got != tt.want {
+			t.Errorf("isSSHRemote(%q) = %v, erwartet %v", tt.remote, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Schnellstart: `newpipi quick` fragt nur Typ/Template und Namen ab und ist
+// dafür gedacht, im Desktop (GNOME, KDE, sxhkd, i3 ...) auf ein globales
+// Tastenkürzel gelegt zu werden. Einen eigenen globalen Hotkey registriert
+// newpipi nicht, das bleibt Sache der Desktop-Umgebung.
+//
+// Listenprotokoll für rofi/dmenu: `quick --list` gibt je Zeile eine Auswahl
+// („Typ“ oder „Typ/Template“) aus, `quick --choice AUSWAHL --name NAME`
+// erstellt das Projekt. `quick --dmenu BEFEHL` erledigt beides mit einem
+// dmenu-kompatiblen Programm.
+
+// QuickChoices liefert alle Auswahlmöglichkeiten, zuletzt genutzte zuerst.
+// Die Schreibweise entspricht den Schlüsseln der Nutzungsstatistik.
+func QuickChoices() []string {
+	choices := append([]string{}, ProjectTypeNames...)
+	for _, t := range allTemplates() {
+		if !t.Abstract {
+			choices = append(choices, t.Type.String()+"/"+t.Name)
+		}
+	}
+	stats := readUsageStats()
+	sort.SliceStable(choices, func(i, j int) bool {
+		return stats[choices[i]].LastUsed.After(stats[choices[j]].LastUsed)
+	})
+	return choices
+}
+
+// QuickSetup bereitet die Erstellung für eine Auswahl aus QuickChoices vor.
+// Das Terminal öffnet sich wie in der GUI im neuen Projekt.
+func QuickSetup(choice, name string) (*ProjectSetup, error) {
+	typeName, templateName, _ := strings.Cut(choice, "/")
+	pt, ok := ParseProjectType(typeName)
+	if !ok {
+		return nil, fmt.Errorf("unbekannter projekttyp: %q", typeName)
+	}
+	if valid, msg := IsValidProjectName(name); !valid {
+		return nil, errors.New(msg)
+	}
+	ps := NewProjectSetup()
+	ps.ProjectType = pt
+	ps.ProjectName = name
+	if templateName != "" {
+		if ps.Template = FindTemplate(pt, templateName); ps.Template == nil {
+			return nil, fmt.Errorf("template nicht gefunden: %s", templateName)
+		}
+	}
+	return ps, nil
+}
+
+// dmenu zeigt die Zeilen mit einem dmenu-kompatiblen Befehl an und liefert die
+// Auswahl bzw. Eingabe. Abbruch (Escape) ergibt einen leeren String.
+func dmenu(command string, lines []string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n"))
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s fehlgeschlagen: %v", command, err)
+	}
+	return string(bytes.TrimSpace(out)), nil
+}
+
+// QuickWindow öffnet das Schnellstart-Fenster; die Oberfläche setzt es beim
+// Start. Ohne Oberfläche braucht `newpipi quick` --list, --choice oder --dmenu.
+var QuickWindow func() int
+
+// setupQuick definiert `newpipi quick`.
+func setupQuick(fs *flag.FlagSet) func(args []string) int {
+	list := fs.Bool("list", false, "Auswahlmöglichkeiten zeilenweise ausgeben")
+	choice := fs.String("choice", "", "Auswahl aus --list (Typ oder Typ/Template)")
+	name := fs.String("name", "", "Projektname zu --choice")
+	menu := fs.String("dmenu", "", "dmenu-kompatiblen Befehl verwenden (z.B. \"rofi -dmenu -p newpipi\")")
+	return func(args []string) int {
+		switch {
+		case *list:
+			for _, c := range QuickChoices() {
+				fmt.Println(c)
+			}
+			return ExitOK
+		case *choice != "":
+			return quickCreate(*choice, *name)
+		case *menu != "":
+			c, err := dmenu(*menu, QuickChoices())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			if c == "" {
+				return ExitOK
+			}
+			n, err := dmenu(*menu, nil)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			if n == "" {
+				return ExitOK
+			}
+			return quickCreate(c, n)
+		}
+		if QuickWindow == nil {
+			fmt.Fprintln(os.Stderr, "Fehler: keine Oberfläche, --list, --choice oder --dmenu angeben")
+			return exitUsage
+		}
+		return QuickWindow()
+	}
+}
+
+func quickCreate(choice, name string) int {
+	ps, err := QuickSetup(choice, name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+		return exitUsage
+	}
+	if err := ps.CreateProject(); err != nil {
+		fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+		return ExitCodeFor(err)
+	}
+	return ExitOK
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+)
+
+// maxRecent begrenzt die Liste der zuletzt erstellten Projekte.
+const maxRecent = 20
+
+func recentPath() string {
+	dir, err := StateDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "recent.json")
+}
+
+// RecentProjects liefert die zuletzt erstellten, noch vorhandenen Projekte.
+func RecentProjects() []string {
+	var dirs []string
+	if content, err := os.ReadFile(recentPath()); err == nil {
+		json.Unmarshal(content, &dirs)
+	}
+	return slices.DeleteFunc(dirs, func(dir string) bool {
+		_, err := os.Stat(dir)
+		return err != nil
+	})
+}
+
+// recordRecent setzt ein Projekt an den Anfang der Liste.
+func recordRecent(projectDir string) error {
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+	dirs := slices.DeleteFunc(RecentProjects(), func(dir string) bool { return dir == abs })
+	dirs = append([]string{abs}, dirs...)
+	if len(dirs) > maxRecent {
+		dirs = dirs[:maxRecent]
+	}
+	content, err := json.Marshal(dirs)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(recentPath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(recentPath(), content, 0644)
+}
+
+// OpenProject öffnet ein bestehendes Projekt im Terminal und, falls
+// konfiguriert, im Editor.
+func (ps *ProjectSetup) OpenProject(dir string) error {
+	if err := ps.launchTerminal(dir, "exec bash"); err != nil {
+		return err
+	}
+	if ps.Settings.Editor != "" {
+		if err := exec.Command("sh", "-c", ps.Settings.Editor+" "+shellQuote(dir)).Start(); err != nil {
+			log.Printf("Editor öffnen fehlgeschlagen: %v", err)
+		}
+	}
+	return nil
+}
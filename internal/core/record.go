@@ -0,0 +1,395 @@
+package core
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Aufzeichnung: `newpipi record` startet eine Shell im Verzeichnis, beobachtet
+// per fsnotify, welche Dateien entstehen oder sich ändern, und liest die
+// eingegebenen Befehle aus der Shell-History. Daraus entsteht ein
+// Template-Entwurf: geänderte Textdateien als Files, Paketinstallationen des
+// Projekttyps als Packages, übrige Befehle als Hooks.
+
+// recordIgnoredDirs sind Verzeichnisse mit Build-Ergebnissen und Abhängigkeiten.
+var recordIgnoredDirs = []string{".git", ".jj", ".hg", "node_modules", "target", "venv", ".venv", "__pycache__", "dist", "build", "bin", "obj"}
+
+// recordIgnoredFiles entstehen durch die Paketinstallation ohnehin neu.
+var recordIgnoredFiles = []string{"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.lock"}
+
+// recordMaxFileSize begrenzt übernommene Dateien; größere sind kaum Vorlagen.
+const recordMaxFileSize = 256 * 1024
+
+// recordSkippedCommands verändern nichts oder sind über die Dateien erfasst.
+var recordSkippedCommands = []string{"cd", "ls", "ll", "cat", "less", "more", "head", "tail", "pwd", "echo",
+	"clear", "exit", "history", "vi", "vim", "nvim", "nano", "emacs", "code", "mkdir", "touch", "cp", "mv", "rm", "tree", "which", "man"}
+
+// recorder sammelt die geänderten Pfade unterhalb von dir.
+type recorder struct {
+	dir     string
+	watcher *fsnotify.Watcher
+
+	mu      sync.Mutex
+	changed map[string]bool
+}
+
+func recordIgnored(name string) bool {
+	return slices.Contains(recordIgnoredDirs, name)
+}
+
+func newRecorder(dir string) (*recorder, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("dateiüberwachung starten fehlgeschlagen: %v", err)
+	}
+	r := &recorder{dir: dir, watcher: watcher, changed: make(map[string]bool)}
+	if err := r.watchTree(dir, false); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	go r.run()
+	return r, nil
+}
+
+// watchTree beobachtet path samt Unterverzeichnissen. Mit mark gelten die
+// enthaltenen Dateien als neu (Verzeichnis entstand während der Aufnahme).
+func (r *recorder) watchTree(path string, mark bool) error {
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != path && recordIgnored(d.Name()) {
+				return filepath.SkipDir
+			}
+			return r.watcher.Add(p)
+		}
+		if mark {
+			r.mark(p)
+		}
+		return nil
+	})
+}
+
+func (r *recorder) mark(path string) {
+	rel, err := filepath.Rel(r.dir, path)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	r.changed[filepath.ToSlash(rel)] = true
+	r.mu.Unlock()
+}
+
+func (r *recorder) run() {
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			info, err := os.Lstat(event.Name)
+			if err != nil {
+				continue
+			}
+			if info.IsDir() {
+				if !recordIgnored(info.Name()) {
+					r.watchTree(event.Name, true)
+				}
+				continue
+			}
+			r.mark(event.Name)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Dateiüberwachung: %v", err)
+		}
+	}
+}
+
+// files liefert Inhalt und Rechte der geänderten Dateien: Textdateien als
+// files, Binärdateien base64-kodiert als assets. Gelöschte und große Dateien
+// werden übersprungen.
+func (r *recorder) files() (files, assets map[string]string, modes map[string]os.FileMode) {
+	// Ereignisse der letzten Befehle noch abwarten
+	time.Sleep(200 * time.Millisecond)
+	r.watcher.Close()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	files, assets = make(map[string]string), make(map[string]string)
+	modes = make(map[string]os.FileMode)
+	for rel := range r.changed {
+		if slices.Contains(recordIgnoredFiles, filepath.Base(rel)) {
+			continue
+		}
+		info, err := os.Lstat(filepath.Join(r.dir, rel))
+		if err != nil || !info.Mode().IsRegular() || info.Size() > recordMaxFileSize {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(r.dir, rel))
+		if err != nil {
+			log.Printf("Überspringe %s: %v", rel, err)
+			continue
+		}
+		if bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content) {
+			assets[rel] = assetBase64Prefix + base64.StdEncoding.EncodeToString(content)
+		} else {
+			files[rel] = string(content)
+		}
+		modes[rel] = info.Mode().Perm()
+	}
+	return files, assets, modes
+}
+
+// recordShell startet eine interaktive Bash in dir und liefert die
+// eingegebenen Befehle. Die History landet sofort nach jedem Befehl in einer
+// eigenen Datei, damit auch ein abgebrochener Lauf vollständig ist.
+func recordShell(dir string) ([]string, error) {
+	tmp, err := os.MkdirTemp("", "newpipi-record-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	histFile := filepath.Join(tmp, "history")
+	rc := `[ -f ~/.bashrc ] && . ~/.bashrc
+HISTFILE=` + shellQuote(histFile) + `
+HISTCONTROL=
+unset HISTTIMEFORMAT
+PROMPT_COMMAND="history -a${PROMPT_COMMAND:+; $PROMPT_COMMAND}"
+PS1="[newpipi record] $PS1"
+`
+	rcFile := filepath.Join(tmp, "rc")
+	if err := os.WriteFile(rcFile, []byte(rc), 0600); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("bash", "--rcfile", rcFile, "-i")
+	cmd.Dir = dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	// Der Exit-Code der Shell ist der des letzten Befehls und kein Fehler der Aufnahme
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("shell starten fehlgeschlagen: %v", err)
+		}
+	}
+	content, err := os.ReadFile(histFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var commands []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			commands = append(commands, line)
+		}
+	}
+	return commands, nil
+}
+
+// installCommand erkennt Paketinstallationen und liefert Projekttyp und Pakete.
+func installCommand(command string) (ProjectType, []string, bool) {
+	// Verkettete oder umgeleitete Befehle bleiben Hooks
+	if strings.ContainsAny(command, "&|;<>$`") {
+		return 0, nil, false
+	}
+	fields := strings.Fields(command)
+	if len(fields) > 2 && (fields[0] == "python" || fields[0] == "python3") && fields[1] == "-m" {
+		fields = fields[2:]
+	}
+	if len(fields) < 3 {
+		return 0, nil, false
+	}
+	var pt ProjectType
+	switch fields[0] + " " + fields[1] {
+	case "pip install", "pip3 install":
+		pt = Python
+	case "go get":
+		pt = Go
+	case "cargo add":
+		pt = Rust
+	case "npm install", "npm i", "npm add", "yarn add", "pnpm add":
+		pt = JavaScript
+	default:
+		return 0, nil, false
+	}
+	var packages []string
+	for _, arg := range fields[2:] {
+		switch {
+		case arg == "-r" || arg == "--requirement" || arg == "-c" || arg == "-e":
+			return 0, nil, false
+		case strings.HasPrefix(arg, "-"):
+		default:
+			packages = append(packages, arg)
+		}
+	}
+	return pt, packages, len(packages) > 0
+}
+
+// draftTemplate baut aus Dateien und Befehlen einen Template-Entwurf. Ist
+// kein Typ vorgegeben, entscheiden die meisten Paketinstallationen.
+func draftTemplate(name string, pt *ProjectType, files map[string]string, commands []string) (*Template, error) {
+	type install struct {
+		command  string
+		pt       ProjectType
+		packages []string
+	}
+	var installs []install
+	votes := make(map[ProjectType]int)
+	for _, c := range commands {
+		if t, packages, ok := installCommand(c); ok {
+			installs = append(installs, install{c, t, packages})
+			votes[t]++
+		}
+	}
+	if pt == nil {
+		best, n := ProjectType(0), 0
+		for t, v := range votes {
+			if v > n || v == n && t < best {
+				best, n = t, v
+			}
+		}
+		if n == 0 {
+			return nil, fmt.Errorf("projekttyp nicht erkennbar, bitte --type angeben")
+		}
+		if _, ok := files["tsconfig.json"]; ok && best == JavaScript {
+			best = TypeScript
+		}
+		pt = &best
+	}
+	// TypeScript installiert wie JavaScript über npm
+	npmType := *pt == TypeScript
+
+	t := &Template{Name: name, Description: "Aufgezeichnet", Type: *pt, Files: files}
+	used := make(map[string]bool)
+	for _, in := range installs {
+		if in.pt == *pt || npmType && in.pt == JavaScript {
+			t.Packages = append(t.Packages, in.packages...)
+			used[in.command] = true
+		}
+	}
+	for _, c := range commands {
+		fields := strings.Fields(c)
+		if used[c] || len(fields) == 0 || slices.Contains(recordSkippedCommands, fields[0]) ||
+			fields[0] == "git" && len(fields) > 1 && slices.Contains([]string{"status", "log", "diff", "show"}, fields[1]) {
+			continue
+		}
+		if !slices.Contains(t.Hooks, c) {
+			t.Hooks = append(t.Hooks, c)
+		}
+	}
+	return t, nil
+}
+
+// setupRecord definiert `newpipi record [--type TYP] [--save] NAME [DIR]`.
+func setupRecord(fs *flag.FlagSet) func(args []string) int {
+	typeName := fs.String("type", "", "Projekttyp des Entwurfs (Standard: aus den Paketinstallationen)")
+	save := fs.Bool("save", false, "Entwurf direkt im Template-Verzeichnis speichern statt ausgeben")
+	return func(args []string) int {
+		if len(args) < 1 || len(args) > 2 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi record [--type TYP] [--save] NAME [VERZEICHNIS]")
+			return exitUsage
+		}
+		var pt *ProjectType
+		if *typeName != "" {
+			t, ok := ParseProjectType(*typeName)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Fehler: unbekannter projekttyp: %q\n", *typeName)
+				return exitUsage
+			}
+			pt = &t
+		}
+		dir := "."
+		if len(args) == 2 {
+			dir = args[1]
+		}
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		if err := recordTemplate(args[0], dir, pt, *save); err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		return ExitOK
+	}
+}
+
+func recordTemplate(name, dir string, pt *ProjectType, save bool) error {
+	r, err := newRecorder(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Aufnahme in %s – Einrichtung wie gewohnt durchführen, mit exit beenden.\n", dir)
+	commands, err := recordShell(dir)
+	files, assets, modes := r.files()
+	if err != nil {
+		return err
+	}
+	t, err := draftTemplate(name, pt, files, commands)
+	if err != nil {
+		return err
+	}
+	if len(assets) > 0 {
+		t.Assets = assets
+	}
+	// Nur Rechte festhalten, die vom Standard (0644, Skripte 0755) abweichen
+	for path, mode := range modes {
+		if def, _ := t.fileMode(path, files[path], defaultModes); mode != def {
+			if t.Modes == nil {
+				t.Modes = make(map[string]string)
+			}
+			t.Modes[path] = fmt.Sprintf("%04o", mode)
+		}
+	}
+
+	paths := make([]string, 0, len(t.Files)+len(t.Assets))
+	for p := range t.Files {
+		paths = append(paths, p)
+	}
+	for p := range t.Assets {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	fmt.Fprintf(os.Stderr, "Entwurf %q (%s): %d Dateien, %d Pakete, %d Befehle\n", t.Name, t.Type, len(paths), len(t.Packages), len(t.Hooks))
+	for _, p := range paths {
+		fmt.Fprintf(os.Stderr, "  %s\n", p)
+	}
+
+	content, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if !save {
+		fmt.Println(string(content))
+		return nil
+	}
+	settings, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	path, err := saveUserTemplate(settings, t, "Template aufgezeichnet: "+name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Gespeichert: %s – vor der Verwendung prüfen und anpassen.\n", path)
+	return nil
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Release-Task: neue Projekte erhalten auf Wunsch einen Befehl, der die
+// nächste Version aus den Conventional Commits ableitet, CHANGELOG.md
+// fortschreibt und das Tag setzt – je nach Ökosystem mit git-cliff (als
+// Taskfile-Ziel), standard-version (als npm-Skript) oder cargo-release.
+// Die Werkzeuge selbst installiert newpipi nicht.
+
+// releaseTask ist das Taskfile-Ziel für git-cliff. {{.VERSION}} ersetzt Task,
+// nicht newpipi.
+const releaseTask = `  release:
+    desc: ` + releaseDesc + `
+    vars:
+      VERSION:
+        sh: git cliff --bumped-version
+    cmds:
+      - git cliff --bump -o CHANGELOG.md
+      - git add CHANGELOG.md
+      - 'git commit -m "chore(release): {{.VERSION}}"'
+      - 'git tag -a {{.VERSION}} -m "Release {{.VERSION}}"'
+`
+
+// releaseDesc und changelogIntro übersetzt writeRelease in die Sprache
+// erzeugter Inhalte.
+const (
+	releaseDesc    = "Bump the version, update CHANGELOG.md and tag the release"
+	changelogIntro = "All notable changes to this project will be documented in this file."
+)
+
+// standardVersionScript ist das npm-Skript "release"; npx lädt
+// standard-version bei Bedarf.
+const standardVersionScript = "npx --yes standard-version"
+
+// cliffConfig gruppiert Conventional Commits im Format von Keep a Changelog.
+const cliffConfig = `[changelog]
+header = """
+# Changelog
+
+` + changelogIntro + `
+"""
+body = """
+{% if version %}\
+## [{{ version | trim_start_matches(pat="v") }}] - {{ timestamp | date(format="%Y-%m-%d") }}
+{% else %}\
+## [Unreleased]
+{% endif %}\
+{% for group, commits in commits | group_by(attribute="group") %}
+### {{ group | upper_first }}
+{% for commit in commits %}
+- {{ commit.message | split(pat="\n") | first | upper_first | trim }}\
+{% endfor %}
+{% endfor %}
+"""
+trim = true
+
+[git]
+conventional_commits = true
+filter_unconventional = true
+commit_parsers = [
+  { message = "^feat", group = "Added" },
+  { message = "^fix", group = "Fixed" },
+  { message = "^perf", group = "Performance" },
+  { message = "^refactor", group = "Changed" },
+  { message = "^doc", group = "Documentation" },
+  { message = "^chore\\(release\\)", skip = true },
+  { message = "^(chore|ci|build|style|test)", skip = true },
+]
+tag_pattern = "v?[0-9].*"
+`
+
+// cargoReleaseConfig schreibt vor dem Release-Commit CHANGELOG.md mit
+// git-cliff fort; veröffentlicht wird erst mit publish = true.
+const cargoReleaseConfig = `publish = false
+pre-release-commit-message = "chore(release): {{version}}"
+pre-release-hook = ["git", "cliff", "-o", "CHANGELOG.md", "--tag", "{{version}}"]
+`
+
+// writeRelease legt den Release-Task des Projekttyps an. Dateien aus dem
+// Template bleiben wie beim Startbefehl unverändert.
+func (ps *ProjectSetup) writeRelease(projectDir string) error {
+	lang := ps.Language()
+	if lang == nil || lang.Release == "" {
+		return nil
+	}
+	ps.step("Lege Release-Task an...")
+	modes := ps.Settings.Modes()
+	switch lang.Release {
+	case "git-cliff":
+		if err := writeShortcut(projectDir, "cliff.toml", ps.localize(cliffConfig, changelogIntro), modes); err != nil {
+			return err
+		}
+		return addTaskfileTask(projectDir, "release", ps.localize(releaseTask, releaseDesc), modes)
+	case "standard-version":
+		script := standardVersionScript
+		if pm, err := FindPackageManager(ps.PackageManager); err == nil {
+			script = pm.shell(script)
+		}
+		return ps.npmScript(projectDir, "release", script)
+	case "cargo-release":
+		if err := writeShortcut(projectDir, "cliff.toml", ps.localize(cliffConfig, changelogIntro), modes); err != nil {
+			return err
+		}
+		return writeShortcut(projectDir, "release.toml", cargoReleaseConfig, modes)
+	default:
+		log.Printf("Unbekanntes Release-Werkzeug: %s", lang.Release)
+	}
+	return nil
+}
+
+// addTaskfileTask ergänzt Taskfile.yml um das Ziel name, legt die Datei bei
+// Bedarf an und lässt eine fremde Taskfile unberührt, in der das Ziel schon
+// steht oder tasks nicht der letzte Abschnitt ist.
+func addTaskfileTask(projectDir, name, task string, modes projectModes) error {
+	path := filepath.Join(projectDir, "Taskfile.yml")
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		content = []byte("version: '3'\n\ntasks:\n")
+	} else if err != nil {
+		return fmt.Errorf("taskfile lesen fehlgeschlagen: %v", err)
+	}
+	updated, ok := withTaskfileTask(string(content), name, task)
+	if !ok {
+		log.Printf("Taskfile.yml hat das Ziel %s bereits oder lässt sich nicht ergänzen", name)
+		return nil
+	}
+	if err := modes.writeFile(path, []byte(updated), modes.File); err != nil {
+		return fmt.Errorf("taskfile schreiben fehlgeschlagen: %v", err)
+	}
+	return nil
+}
+
+// withTaskfileTask hängt task an content an, sofern tasks der letzte
+// Abschnitt ist und das Ziel name noch fehlt.
+func withTaskfileTask(content, name, task string) (string, bool) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil || len(doc.Content) == 0 {
+		return "", false
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode || len(root.Content) < 2 {
+		return "", false
+	}
+	key, tasks := root.Content[len(root.Content)-2], root.Content[len(root.Content)-1]
+	if key.Value != "tasks" {
+		return "", false
+	}
+	switch {
+	case tasks.Kind == yaml.MappingNode && tasks.Style&yaml.FlowStyle == 0:
+		// task ist mit zwei Leerzeichen eingerückt
+		if len(tasks.Content) > 0 && tasks.Content[0].Column != 3 {
+			return "", false
+		}
+		for i := 0; i < len(tasks.Content); i += 2 {
+			if tasks.Content[i].Value == name {
+				return "", false
+			}
+		}
+	case tasks.Tag != "!!null":
+		return "", false
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + task, true
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWithTaskfileTask(t *testing.T) {
+	task := "  release:\n    cmds:\n      - git cliff\n"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		ok      bool
+	}{
+		{"neu", "version: '3'\n\ntasks:\n", "version: '3'\n\ntasks:\n" + task, true},
+		{"startbefehl", taskfile("go run ."), taskfile("go run .") + task, true},
+		{"ohne zeilenumbruch", "version: '3'\ntasks:\n  run:\n    cmds: [make]", "version: '3'\ntasks:\n  run:\n    cmds: [make]\n" + task, true},
+		{"vorhanden", "version: '3'\ntasks:\n  release:\n    cmds: [make]\n", "", false},
+		{"andere einrückung", "version: '3'\ntasks:\n    run:\n        cmds: [make]\n", "", false},
+		{"tasks nicht zuletzt", "tasks:\n  run:\n    cmds: [make]\nvars:\n  A: b\n", "", false},
+		{"flow", "version: '3'\ntasks: {}\n", "", false},
+		{"ungültig", "tasks: [", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := withTaskfileTask(tt.content, "release", task)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s: got %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReleaseTaskYAML(t *testing.T) {
+	content, ok := withTaskfileTask(taskfile("go run ."), "release", releaseTask)
+	if !ok {
+		t.Fatal("release-task nicht eingetragen")
+	}
+	var file struct {
+		Tasks map[string]struct {
+			Cmds []string `yaml:"cmds"`
+		} `yaml:"tasks"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &file); err != nil {
+		t.Fatal(err)
+	}
+	cmds := file.Tasks["release"].Cmds
+	if len(cmds) != 4 || cmds[2] != `git commit -m "chore(release): {{.VERSION}}"` {
+		t.Errorf("cmds = %q", cmds)
+	}
+	if len(file.Tasks["run"].Cmds) != 1 {
+		t.Errorf("run-task verloren: %q", content)
+	}
+}
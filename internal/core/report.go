@@ -0,0 +1,255 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Jedes Projekt erhält im Verzeichnis .newpipi ein Manifest mit den Eingaben
+// der Erstellung und einen Bericht, wie sie abgelaufen ist. `newpipi verify`
+// vergleicht das Projekt mit den Prüfsummen aus dem Bericht.
+const (
+	metaDir      = ".newpipi"
+	manifestFile = "manifest.json"
+	reportFile   = "report.json"
+)
+
+// projectManifest hält fest, womit ein Projekt erzeugt wurde.
+type projectManifest struct {
+	Type     ProjectType `json:"type"`
+	Name     string      `json:"name"`
+	Template string      `json:"template,omitempty"`
+	// TemplateVersion ist die Version des Templates bei der Erstellung,
+	// Ausgangspunkt späterer Aktualisierungen.
+	TemplateVersion string `json:"templateVersion,omitempty"`
+	Toolkit         string `json:"toolkit,omitempty"`
+	Layout          string `json:"layout,omitempty"`
+	PackageManager  string `json:"packageManager,omitempty"`
+	// License ist die SPDX-Kennung aus dem Profil (siehe badges.go).
+	License   string            `json:"license,omitempty"`
+	Options   map[string]string `json:"options,omitempty"`
+	Snippets  []string          `json:"snippets,omitempty"`
+	Addons    []string          `json:"addons,omitempty"`
+	Profile   string            `json:"profile,omitempty"`
+	Scratch   bool              `json:"scratch,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// creationReport beschreibt den Ablauf der Erstellung reproduzierbar.
+type creationReport struct {
+	Commands     []string          `json:"commands"`
+	ToolVersions map[string]string `json:"toolVersions,omitempty"`
+	Steps        []stepResult      `json:"steps"`
+	// Phases sind die bis zum Bericht abgeschlossenen Phasen (siehe timings.go).
+	Phases     []phaseTiming `json:"phases,omitempty"`
+	DurationMs int64         `json:"durationMs"`
+	// Files ordnet jeder erzeugten Datei ihren SHA-256 zu.
+	Files map[string]string `json:"files"`
+}
+
+// stepRecord ist ein gemeldeter Schritt mit Startzeit.
+type stepRecord struct {
+	message string
+	start   time.Time
+}
+
+// dependencyDirs enthalten installierte Abhängigkeiten oder Build-Ausgaben und
+// gehören nicht zu den erzeugten Dateien. Sie gelten nur im Projektverzeichnis
+// selbst, ein Paket wie src/build gehört dazu; Repositorys (.git, .jj, .hg)
+// werden überall übersprungen.
+var dependencyDirs = map[string]bool{
+	".git": true, ".jj": true, ".hg": true, metaDir: true, "venv": true, "node_modules": true,
+	"target": true, "bin": true, "obj": true, "build": true, "dist": true,
+}
+
+// fileChecksums berechnet die SHA-256-Prüfsummen aller erzeugten Dateien.
+func fileChecksums(projectDir string) (map[string]string, error) {
+	sums := make(map[string]string)
+	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if isVCSDir(d.Name()) || filepath.Dir(path) == projectDir && dependencyDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		sum, err := fileChecksum(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(projectDir, path)
+		sums[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	return sums, err
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// stepResults rechnet die gemeldeten Schritte in Dauern um; ein Schritt reicht
+// bis zum Beginn des nächsten.
+func (ps *ProjectSetup) stepResults(end time.Time) []stepResult {
+	results := make([]stepResult, len(ps.stepLog))
+	for i, s := range ps.stepLog {
+		next := end
+		if i+1 < len(ps.stepLog) {
+			next = ps.stepLog[i+1].start
+		}
+		results[i] = stepResult{Message: s.message, DurationMs: next.Sub(s.start).Milliseconds()}
+	}
+	return results
+}
+
+// writeReport legt Manifest und Bericht im Projekt ab.
+func (ps *ProjectSetup) writeReport(projectDir string, start time.Time) error {
+	ps.step("Schreibe Erstellungsbericht...")
+	manifest := projectManifest{
+		Type:      ps.ProjectType,
+		Name:      ps.ProjectName,
+		Options:   ps.Options,
+		Snippets:  ps.Snippets,
+		Addons:    ps.Addons,
+		Profile:   ps.ProfileName,
+		License:   ps.Profile().License,
+		Scratch:   ps.Scratch,
+		CreatedAt: start.UTC(),
+	}
+	if ps.Template != nil {
+		manifest.Template, manifest.TemplateVersion = ps.Template.Name, ps.Template.Version
+	}
+	// Auch ein Standard-Toolkit wird festgehalten, damit die Erstellung reproduzierbar bleibt
+	if tk, _ := ps.Toolkit(); tk != nil {
+		manifest.Toolkit = tk.Name
+	}
+	if l, _ := ps.layout(); l != nil {
+		manifest.Layout = l.Name
+	}
+	if pm, err := FindPackageManager(ps.PackageManager); err == nil && ps.NpmProject() {
+		manifest.PackageManager = pm.Name
+	}
+
+	files, err := fileChecksums(projectDir)
+	if err != nil {
+		return fmt.Errorf("prüfsummen berechnen fehlgeschlagen: %v", err)
+	}
+	now := time.Now()
+	report := creationReport{
+		Commands:     ps.commandLog,
+		ToolVersions: ps.toolVersions,
+		Steps:        ps.stepResults(now),
+		Phases:       ps.phaseLog,
+		DurationMs:   now.Sub(start).Milliseconds(),
+		Files:        files,
+	}
+
+	dir := filepath.Join(projectDir, metaDir)
+	modes := ps.Settings.Modes()
+	if err := modes.mkdirAll(dir); err != nil {
+		return fmt.Errorf("%s erstellen fehlgeschlagen: %v", metaDir, err)
+	}
+	for name, v := range map[string]any{manifestFile: manifest, reportFile: report} {
+		content, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), append(content, '\n'), modes.File); err != nil {
+			return fmt.Errorf("%s schreiben fehlgeschlagen: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// readManifest liest das Manifest eines Projekts.
+func readManifest(projectDir string) (*projectManifest, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, metaDir, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("kein manifest gefunden: %v", err)
+	}
+	var manifest projectManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("manifest parsen fehlgeschlagen: %v", err)
+	}
+	return &manifest, nil
+}
+
+// readReport liest den Erstellungsbericht eines Projekts.
+func readReport(projectDir string) (*creationReport, error) {
+	content, err := os.ReadFile(filepath.Join(projectDir, metaDir, reportFile))
+	if err != nil {
+		return nil, fmt.Errorf("kein erstellungsbericht gefunden: %v", err)
+	}
+	var report creationReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("erstellungsbericht parsen fehlgeschlagen: %v", err)
+	}
+	return &report, nil
+}
+
+// verifyProject vergleicht die Dateien des Projekts mit dem Bericht und
+// liefert die Abweichungen.
+func verifyProject(projectDir string) ([]string, error) {
+	report, err := readReport(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var diffs []string
+	for path, want := range report.Files {
+		got, err := fileChecksum(filepath.Join(projectDir, filepath.FromSlash(path)))
+		switch {
+		case os.IsNotExist(err):
+			diffs = append(diffs, "fehlt:    "+path)
+		case err != nil:
+			return nil, err
+		case got != want:
+			diffs = append(diffs, "geändert: "+path)
+		}
+	}
+	sort.Strings(diffs)
+	return diffs, nil
+}
+
+// setupVerify definiert `newpipi verify [VERZEICHNIS]`.
+func setupVerify(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		diffs, err := verifyProject(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		if len(diffs) > 0 {
+			fmt.Println(strings.Join(diffs, "\n"))
+			return exitFailure
+		}
+		fmt.Println("Projekt entspricht dem Erstellungsbericht")
+		return ExitOK
+	}
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Während der Erstellung wird nach jeder Phase festgehalten, was bereits
+// erledigt ist. Bricht das Programm ab, kann ein neuer Start die Erstellung
+// an dieser Stelle fortsetzen, statt am vorhandenen Verzeichnis zu scheitern.
+
+// CreationState ist der gespeicherte Stand einer laufenden Erstellung.
+type CreationState struct {
+	Dir         string            `json:"dir"`
+	Type        ProjectType       `json:"type"`
+	Name        string            `json:"name"`
+	ParentPath  string            `json:"parentPath"`
+	Template    string            `json:"template,omitempty"`
+	Toolkit     string            `json:"toolkit,omitempty"`
+	Layout      string            `json:"layout,omitempty"`
+	Module      string            `json:"module,omitempty"`
+	PM          string            `json:"packageManager,omitempty"`
+	RunCommand  string            `json:"runCommand,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	Options     map[string]string `json:"options,omitempty"`
+	Snippets    []string          `json:"snippets,omitempty"`
+	Addons      []string          `json:"addons,omitempty"`
+	Scratch     bool              `json:"scratch,omitempty"`
+	Profile     string            `json:"profile,omitempty"`
+	Audit       bool              `json:"audit,omitempty"`
+	Licenses    bool              `json:"licenses,omitempty"`
+	SBOM        string            `json:"sbom,omitempty"`
+	SkipGit     bool              `json:"skipGit,omitempty"`
+	SkipInstall bool              `json:"skipInstall,omitempty"`
+	SkipCI      bool              `json:"skipCI,omitempty"`
+	GitHooks    bool              `json:"gitHooks,omitempty"`
+	Release     bool              `json:"releaseTask,omitempty"`
+	Sign        bool              `json:"sign,omitempty"`
+	LFS         bool              `json:"lfs,omitempty"`
+	VCS         string            `json:"vcs,omitempty"`
+	Push        bool              `json:"push,omitempty"`
+	StartedAt   time.Time         `json:"startedAt"`
+	// Completed sind die abgeschlossenen Phasen, siehe ProjectSetup.phase.
+	Completed []string `json:"completed"`
+}
+
+func pendingDir() string {
+	dir, err := StateDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pending")
+}
+
+func (s *CreationState) path() string {
+	return filepath.Join(pendingDir(), fmt.Sprintf("%x", sha256.Sum256([]byte(s.Dir)))[:16]+".json")
+}
+
+func (s *CreationState) save() error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(pendingDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(), content, 0644)
+}
+
+func (s *CreationState) Remove() {
+	if err := os.Remove(s.path()); err != nil && !os.IsNotExist(err) {
+		log.Printf("Erstellungsstand löschen fehlgeschlagen: %v", err)
+	}
+}
+
+func (s *CreationState) completed(phase string) bool {
+	return slices.Contains(s.Completed, phase)
+}
+
+// PendingStates liefert die unterbrochenen Erstellungen.
+func PendingStates() []*CreationState {
+	paths, _ := filepath.Glob(filepath.Join(pendingDir(), "*.json"))
+	var states []*CreationState
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var s CreationState
+		if err := json.Unmarshal(content, &s); err != nil {
+			log.Printf("Erstellungsstand %s defekt: %v", path, err)
+			continue
+		}
+		states = append(states, &s)
+	}
+	return states
+}
+
+// findPendingState sucht die unterbrochene Erstellung für ein Projektverzeichnis.
+func findPendingState(dir string) *CreationState {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	for _, s := range PendingStates() {
+		if s.Dir == abs {
+			return s
+		}
+	}
+	return nil
+}
+
+// newCreationState hält die Eingaben der beginnenden Erstellung fest.
+func (ps *ProjectSetup) newCreationState(projectDir string) *CreationState {
+	s := &CreationState{
+		Dir:         projectDir,
+		Type:        ps.ProjectType,
+		Name:        ps.ProjectName,
+		ParentPath:  ps.ParentPath,
+		Toolkit:     ps.ToolkitName,
+		Layout:      ps.LayoutName,
+		Module:      ps.ModulePath,
+		PM:          ps.PackageManager,
+		RunCommand:  ps.runCommand,
+		Env:         ps.EnvVars,
+		Options:     ps.Options,
+		Snippets:    ps.Snippets,
+		Addons:      ps.Addons,
+		Scratch:     ps.Scratch,
+		Profile:     ps.ProfileName,
+		Audit:       ps.Audit,
+		Licenses:    ps.Licenses,
+		SBOM:        ps.SbomFormat,
+		SkipGit:     ps.SkipGit,
+		SkipInstall: ps.skipInstall,
+		SkipCI:      ps.skipCI,
+		GitHooks:    ps.GitHooks,
+		Release:     ps.ReleaseTask,
+		Sign:        ps.SignCommits,
+		LFS:         ps.GitLFS,
+		VCS:         ps.Vcs,
+		Push:        ps.Push,
+		StartedAt:   time.Now().UTC(),
+		Completed:   []string{},
+	}
+	if ps.Template != nil {
+		s.Template = ps.Template.Name
+	}
+	if abs, err := filepath.Abs(projectDir); err == nil {
+		s.Dir = abs
+	}
+	return s
+}
+
+// phase führt einen Abschnitt der Erstellung aus, sofern er nicht schon vor
+// einer Unterbrechung abgeschlossen wurde, und speichert danach den Stand.
+func (ps *ProjectSetup) phase(name string, run func() error) error {
+	if ps.state.completed(name) {
+		log.Printf("Überspringe abgeschlossene Phase: %s", name)
+		return nil
+	}
+	if err := ps.context().Err(); err != nil {
+		return err
+	}
+	if err := ps.timePhase(name, run); err != nil {
+		return err
+	}
+	ps.state.Completed = append(ps.state.Completed, name)
+	if err := ps.state.save(); err != nil {
+		log.Printf("Erstellungsstand speichern fehlgeschlagen: %v", err)
+	}
+	return nil
+}
+
+// Resume setzt eine unterbrochene Erstellung mit deren ursprünglichen Eingaben fort.
+func (ps *ProjectSetup) Resume(s *CreationState) error {
+	ps.ProjectType = s.Type
+	ps.ProjectName = s.Name
+	if err := ps.SelectProfile(s.Profile); err != nil {
+		return err
+	}
+	ps.ParentPath = s.ParentPath
+	ps.Template = nil
+	if s.Template != "" {
+		ps.Template = FindTemplate(s.Type, s.Template)
+		if ps.Template == nil {
+			if !s.completed("template") {
+				return fmt.Errorf("template %s nicht mehr vorhanden", s.Template)
+			}
+			// Das Template ist schon angewendet, gebraucht wird nur noch sein Name
+			ps.Template = &Template{Name: s.Template, Type: s.Type}
+		}
+	}
+	ps.ToolkitName = s.Toolkit
+	ps.LayoutName = s.Layout
+	ps.ModulePath = s.Module
+	ps.PackageManager = s.PM
+	ps.runCommand = s.RunCommand
+	ps.EnvVars = s.Env
+	ps.Options = s.Options
+	ps.Snippets = s.Snippets
+	ps.Addons = s.Addons
+	ps.Scratch = s.Scratch
+	ps.Audit = s.Audit
+	ps.Licenses = s.Licenses
+	ps.SbomFormat = s.SBOM
+	ps.SkipGit = s.SkipGit
+	ps.skipInstall = s.SkipInstall
+	ps.skipCI = s.SkipCI
+	ps.GitHooks = s.GitHooks
+	ps.ReleaseTask = s.Release
+	ps.SignCommits = s.Sign
+	ps.GitLFS = s.LFS
+	ps.Vcs = s.VCS
+	ps.Push = s.Push
+	ps.state = s
+	return ps.CreateProject()
+}
+
+// setupResume definiert `newpipi resume [DIR]`.
+func setupResume(fs *flag.FlagSet) func(args []string) int {
+	discard := fs.Bool("discard", false, "Gespeicherten Stand verwerfen statt fortzusetzen")
+	return func(args []string) int {
+		if len(args) == 0 {
+			for _, s := range PendingStates() {
+				fmt.Printf("%s\t%s\t%s\t%d Phasen erledigt\n", s.Dir, s.Type, s.StartedAt.Local().Format(time.DateTime), len(s.Completed))
+			}
+			return ExitOK
+		}
+		s := findPendingState(args[0])
+		if s == nil {
+			fmt.Fprintf(os.Stderr, "Fehler: keine unterbrochene erstellung für %s\n", args[0])
+			return exitUsage
+		}
+		if *discard {
+			s.Remove()
+			return ExitOK
+		}
+		ps := NewProjectSetup()
+		ps.skipTerminal = true
+		if err := ps.Resume(s); err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return ExitCodeFor(err)
+		}
+		fmt.Println(s.Dir)
+		return ExitOK
+	}
+}
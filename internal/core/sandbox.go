@@ -0,0 +1,244 @@
+package core
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+// Sandbox-Projekte (-sandbox) entstehen unabhängig vom Projektpfad in einem
+// eigenen Verzeichnis unter dem Temp-Verzeichnis des Systems, zum schnellen
+// Ausprobieren. Gefällt eines, verschiebt `newpipi sandbox promote` bzw.
+// „Promote Sandbox Project...“ es in einen Projektpfad und ersetzt dabei den
+// alten absoluten Pfad in den Dateien, etwa in den Skripten eines venv.
+
+// sandboxRoot enthält je Sandbox-Projekt ein eigenes Verzeichnis.
+func sandboxRoot() string {
+	return filepath.Join(os.TempDir(), "newpipi-sandbox")
+}
+
+// maxRewriteSize begrenzt die Dateien, in denen PromoteSandbox Pfade ersetzt.
+const maxRewriteSize = 1 << 20
+
+// EnterSandbox ersetzt das Elternverzeichnis durch ein neues Sandbox-Verzeichnis.
+func (ps *ProjectSetup) EnterSandbox() error {
+	if err := os.MkdirAll(sandboxRoot(), 0700); err != nil {
+		return fmt.Errorf("sandbox anlegen fehlgeschlagen: %v", err)
+	}
+	dir, err := os.MkdirTemp(sandboxRoot(), "")
+	if err != nil {
+		return fmt.Errorf("sandbox anlegen fehlgeschlagen: %v", err)
+	}
+	ps.ParentPath = dir
+	return nil
+}
+
+// SandboxProjects liefert die Projekte in der Sandbox, neueste zuerst.
+func SandboxProjects() []string {
+	matches, _ := filepath.Glob(filepath.Join(sandboxRoot(), "*", "*"))
+	type entry struct {
+		dir     string
+		modTime int64
+	}
+	var entries []entry
+	for _, dir := range matches {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			entries = append(entries, entry{dir, info.ModTime().UnixNano()})
+		}
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return cmp.Compare(b.modTime, a.modTime) })
+	dirs := make([]string, len(entries))
+	for i, e := range entries {
+		dirs[i] = e.dir
+	}
+	return dirs
+}
+
+// PromoteSandbox verschiebt ein Sandbox-Projekt nach parent, ersetzt den alten
+// Pfad in Dateien und Symlinks und liefert das neue Projektverzeichnis.
+func PromoteSandbox(dir, parent string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(sandboxRoot(), dir); err != nil || !filepath.IsLocal(rel) || strings.Count(rel, string(filepath.Separator)) != 1 {
+		return "", fmt.Errorf("%s ist kein sandbox-projekt", dir)
+	}
+	if parent, err = filepath.Abs(parent); err != nil {
+		return "", err
+	}
+	target := filepath.Join(parent, filepath.Base(dir))
+	if _, err := os.Lstat(target); err == nil {
+		return "", fmt.Errorf("%s existiert bereits", target)
+	}
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return "", fmt.Errorf("projektpfad anlegen fehlgeschlagen: %v", err)
+	}
+	// Nach dem Verschieben fiele das Projekt aus der Liste heraus
+	scratch := ScratchProjects()
+	if err := moveDir(dir, target); err != nil {
+		return "", fmt.Errorf("%s verschieben fehlgeschlagen: %v", dir, err)
+	}
+	os.Remove(filepath.Dir(dir))
+	if err := rewritePaths(target, dir, target); err != nil {
+		return target, fmt.Errorf("pfade anpassen fehlgeschlagen: %v", err)
+	}
+	if i := slices.IndexFunc(scratch, func(p ScratchProject) bool { return p.Dir == dir }); i >= 0 {
+		scratch[i].Dir = target
+		if err := writeScratchProjects(scratch); err != nil {
+			log.Printf("Wegwerfprojekt umtragen fehlgeschlagen: %v", err)
+		}
+	}
+	if err := recordRecent(target); err != nil {
+		log.Printf("Zuletzt erstellte Projekte speichern fehlgeschlagen: %v", err)
+	}
+	return target, nil
+}
+
+// moveDir verschiebt ein Verzeichnis; liegt das Ziel auf einem anderen
+// Dateisystem (/tmp ist oft ein tmpfs), wird kopiert und danach gelöscht.
+func moveDir(src, dst string) error {
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyTree kopiert src samt Rechten und Symlinks nach dst.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.Mkdir(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// rewritePaths ersetzt old durch new in den Textdateien und absoluten
+// Symlinks unter root. Versionskontrolle und große Abhängigkeitsverzeichnisse
+// wie node_modules bleiben unberührt, virtuelle Umgebungen nicht.
+func rewritePaths(root, old, new string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && (isVCSDir(d.Name()) || d.Name() == "node_modules" || d.Name() == "target") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if rest, ok := strings.CutPrefix(link, old); ok && (rest == "" || strings.HasPrefix(rest, string(filepath.Separator))) {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+				return os.Symlink(new+rest, path)
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxRewriteSize {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(content, 0) >= 0 || !bytes.Contains(content, []byte(old)) {
+			return nil
+		}
+		return os.WriteFile(path, bytes.ReplaceAll(content, []byte(old), []byte(new)), info.Mode().Perm())
+	})
+}
+
+// setupSandbox definiert `newpipi sandbox [list]` und `newpipi sandbox [-to DIR] promote [PROJEKT]`.
+func setupSandbox(fs *flag.FlagSet) func(args []string) int {
+	to := fs.String("to", "", "Projektpfad, in den promote verschiebt (Standard: gespeicherter Projektpfad)")
+	return func(args []string) int {
+		switch {
+		case len(args) == 0 || args[0] == "list" && len(args) == 1:
+			for _, dir := range SandboxProjects() {
+				fmt.Println(dir)
+			}
+			return ExitOK
+		case args[0] == "promote" && len(args) <= 2:
+			var dir string
+			if len(args) == 2 {
+				dir = args[1]
+			} else if projects := SandboxProjects(); len(projects) > 0 {
+				dir = projects[0]
+			} else {
+				fmt.Fprintln(os.Stderr, "Fehler: keine sandbox-projekte")
+				return exitFailure
+			}
+			parent := *to
+			if parent == "" {
+				parent = NewProjectSetup().ParentPath
+			}
+			if parent == "" {
+				fmt.Fprintln(os.Stderr, "Fehler: kein projektpfad gespeichert, -to angeben")
+				return exitUsage
+			}
+			target, err := PromoteSandbox(dir, parent)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			fmt.Println(target)
+			return ExitOK
+		}
+		fmt.Fprintln(os.Stderr, "Verwendung: newpipi sandbox [list] | newpipi sandbox [-to DIR] promote [PROJEKT]")
+		return exitUsage
+	}
+}
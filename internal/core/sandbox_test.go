@@ -0,0 +1,61 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewritePaths(t *testing.T) {
+	root := t.TempDir()
+	old, new := "/tmp/newpipi-sandbox/x1/demo", root
+	files := map[string]string{
+		"venv/bin/activate":       "VIRTUAL_ENV=\"" + old + "/venv\"\n",
+		".vscode/settings.json":   "{\"python\": \"" + old + "/venv/bin/python\"}",
+		"README.md":               "# demo\n",
+		".git/config":             "path = " + old + "\n",
+		"node_modules/x/index.js": old,
+	}
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Symlink(old+"/venv/bin", filepath.Join(root, "bin"))
+	os.Symlink("/usr/bin/python3", filepath.Join(root, "python"))
+	os.Symlink(old+"2/other", filepath.Join(root, "other"))
+
+	if err := rewritePaths(root, old, new); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want string
+		link bool
+	}{
+		{path: "venv/bin/activate", want: "VIRTUAL_ENV=\"" + new + "/venv\"\n"},
+		{path: ".vscode/settings.json", want: "{\"python\": \"" + new + "/venv/bin/python\"}"},
+		{path: "README.md", want: "# demo\n"},
+		{path: ".git/config", want: "path = " + old + "\n"},
+		{path: "node_modules/x/index.js", want: old},
+		{path: "bin", want: new + "/venv/bin", link: true},
+		{path: "python", want: "/usr/bin/python3", link: true},
+		{path: "other", want: old + "2/other", link: true},
+	}
+	for _, tt := range tests {
+		var got string
+		if tt.link {
+			got, _ = os.Readlink(filepath.Join(root, tt.path))
+		} else {
+			content, _ := os.ReadFile(filepath.Join(root, tt.path))
+			got = string(content)
+		}
+		if got != tt.want {
+			t.Errorf("%s = %q, erwartet %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package core
+
+import (
+	"bytes"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ein von Hand eingerichtetes Startprojekt wird mit `newpipi save` (in der
+// Oberfläche „Save Directory as Template...“) zum Template: Dateien ohne
+// Build-Ergebnisse und Abhängigkeiten werden übernommen, der Projektname
+// (der Verzeichnisname) wird in Inhalten und Pfaden zu {{.ProjectName}}.
+
+// saveIgnoredFiles gehören nie in ein Template: Geheimnisse und Systemdateien.
+var saveIgnoredFiles = []string{".env", ".DS_Store"}
+
+// replaceName ersetzt name in s durch repl, aber nur als eigenes Wort: Vor
+// und nach dem Namen steht kein Buchstabe und keine Ziffer, damit "app" in
+// "application" erhalten bleibt. Geliefert wird die Zahl der Ersetzungen.
+func replaceName(s, name, repl string) (string, int) {
+	if name == "" {
+		return s, 0
+	}
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	var b strings.Builder
+	n := 0
+	for {
+		i := strings.Index(s, name)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), n
+		}
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[i+len(name):])
+		if (i > 0 && isWord(before)) || (i+len(name) < len(s) && isWord(after)) {
+			b.WriteString(s[:i+len(name)])
+		} else {
+			b.WriteString(s[:i] + repl)
+			n++
+		}
+		s = s[i+len(name):]
+	}
+}
+
+// templatizeName ersetzt den Projektnamen und seine Kleinschreibung durch
+// Platzhalter. Vorhandene "{{" werden geschützt, da das Template rendert.
+func templatizeName(s, name string) (string, int) {
+	s = escapeTemplate(s)
+	s, n := replaceName(s, name, "{{.ProjectName}}")
+	if lower := strings.ToLower(name); lower != name {
+		var m int
+		s, m = replaceName(s, lower, "{{.ProjectName | lower}}")
+		n += m
+	}
+	return s, n
+}
+
+// saveIgnored meldet, ob ein Pfad im Verzeichnis nicht übernommen wird.
+func saveIgnored(name string, dir bool) bool {
+	if dir {
+		return recordIgnored(name) || name == ".newpipi"
+	}
+	return slices.Contains(recordIgnoredFiles, name) || slices.Contains(saveIgnoredFiles, name)
+}
+
+// templateFromDir baut ein Template aus dem Verzeichnis dir. In Git-Repositories
+// entfallen zusätzlich die Dateien, die .gitignore ausschließt.
+func templateFromDir(dir, name string, pt *ProjectType) (*Template, []string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	t := &Template{Name: name, Files: make(map[string]string)}
+	if pt != nil {
+		t.Type = *pt
+	} else if detected, ok := DetectProjectType(dir); ok {
+		t.Type = detected
+	} else {
+		return nil, nil, fmt.Errorf("projekttyp von %s nicht erkannt, mit -type angeben", dir)
+	}
+
+	var ignored map[string]bool
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		if out, err := gitOutput(dir, "ls-files", "-z", "--others", "--ignored", "--exclude-standard", "--directory"); err == nil {
+			ignored = make(map[string]bool)
+			for _, rel := range strings.Split(out, "\x00") {
+				ignored[strings.TrimSuffix(rel, "/")] = true
+			}
+		}
+	}
+
+	projectName := filepath.Base(dir)
+	var skipped []string
+	files := make(map[string]string)
+	modes := make(map[string]os.FileMode)
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == dir {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, p)
+		rel = filepath.ToSlash(rel)
+		if saveIgnored(d.Name(), d.IsDir()) || ignored[rel] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		if info.Size() > recordMaxFileSize {
+			skipped = append(skipped, fmt.Sprintf("%s (%d KB)", rel, info.Size()>>10))
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content) {
+			if _, n := templatizeName(rel, projectName); n > 0 {
+				skipped = append(skipped, rel+" (binärdatei mit projektnamen im pfad)")
+				return nil
+			}
+			if t.Assets == nil {
+				t.Assets = make(map[string]string)
+			}
+			t.Assets[rel] = assetBase64Prefix + base64.StdEncoding.EncodeToString(content)
+			return nil
+		}
+		files[rel] = string(content)
+		modes[rel] = info.Mode().Perm()
+		if _, n := templatizeName(rel+"\n"+string(content), projectName); n > 0 {
+			t.Render = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Gerendert wird nur, wenn der Projektname vorkommt
+	for rel, content := range files {
+		dest := rel
+		if t.Render {
+			dest, _ = templatizeName(rel, projectName)
+			content, _ = templatizeName(content, projectName)
+		}
+		t.Files[dest] = content
+		// Nur Rechte festhalten, die vom Standard (0644, Skripte 0755) abweichen
+		if def, _ := t.fileMode(dest, content, defaultModes); modes[rel] != def {
+			if t.Modes == nil {
+				t.Modes = make(map[string]string)
+			}
+			t.Modes[dest] = fmt.Sprintf("%04o", modes[rel])
+		}
+	}
+	return t, skipped, nil
+}
+
+// SaveDirTemplate speichert dir als Template im Template-Verzeichnis.
+func SaveDirTemplate(dir, name string, pt *ProjectType) (*Template, string, []string, error) {
+	if name == "" {
+		return nil, "", nil, fmt.Errorf("template-name darf nicht leer sein")
+	}
+	t, skipped, err := templateFromDir(dir, name, pt)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	settings, err := LoadSettings()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	path, err := saveUserTemplate(settings, t, "Verzeichnis als Template gespeichert: "+name)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return t, path, skipped, nil
+}
+
+// setupSave definiert `newpipi save [-type TYP] [-name NAME] [VERZEICHNIS]`.
+func setupSave(fs *flag.FlagSet) func(args []string) int {
+	typeName := fs.String("type", "", "Projekttyp des Templates (Standard: aus den Dateien erkannt)")
+	name := fs.String("name", "", "Name des Templates, Standard ist der Verzeichnisname")
+	return func(args []string) int {
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi save [-type TYP] [-name NAME] [VERZEICHNIS]")
+			return exitUsage
+		}
+		var pt *ProjectType
+		if *typeName != "" {
+			t, ok := ParseProjectType(*typeName)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Fehler: unbekannter projekttyp: %q\n", *typeName)
+				return exitUsage
+			}
+			pt = &t
+		}
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		if *name == "" {
+			*name = filepath.Base(dir)
+		}
+		t, path, skipped, err := SaveDirTemplate(dir, *name, pt)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		for _, s := range skipped {
+			fmt.Fprintf(os.Stderr, "Übersprungen: %s\n", s)
+		}
+		fmt.Printf("Template %s (%s) mit %d Dateien gespeichert: %s\n", t.Name, t.Type, len(t.Files)+len(t.Assets), path)
+		return ExitOK
+	}
+}
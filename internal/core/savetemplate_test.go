@@ -0,0 +1,24 @@
+package core
+
+import "testing"
+
+func TestTemplatizeName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		n    int
+	}{
+		{"module example.com/MyApp", "module example.com/{{.ProjectName}}", 1},
+		{"name = \"myapp\"", "name = \"{{.ProjectName | lower}}\"", 1},
+		{"cmd/MyApp/main.go", "cmd/{{.ProjectName}}/main.go", 1},
+		{"MyApp_test MyAppServer", "{{.ProjectName}}_test MyAppServer", 1},
+		{"MyApplication", "MyApplication", 0},
+		{"${{ github.ref }} MyApp", `${{"{{"}} github.ref }} {{.ProjectName}}`, 1},
+	}
+	for _, tt := range tests {
+		got, n := templatizeName(tt.in, "MyApp")
+		if got != tt.want || n != tt.n {
+			t.Errorf("templatizeName(%q) = %q, %d, erwartet %q, %d", tt.in, got, n, tt.want, tt.n)
+		}
+	}
+}
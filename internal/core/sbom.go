@@ -0,0 +1,64 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+)
+
+// sbomFormats sind die unterstützten SBOM-Formate und ihre Dateinamen im Projekt.
+var sbomFormats = map[string]string{
+	"cyclonedx": "sbom.cdx.json",
+	"spdx":      "sbom.spdx.json",
+}
+
+// sbomCommand wählt das Werkzeug für das SBOM: syft, wenn vorhanden, sonst die
+// Bordmittel des Ökosystems (language.SBOM). Schreibt das Werkzeug nach stdout,
+// ist toStdout gesetzt.
+func (ps *ProjectSetup) sbomCommand(projectDir, format, file string) (args []string, toStdout bool) {
+	if _, err := exec.LookPath("syft"); err == nil {
+		syftFormat := map[string]string{"cyclonedx": "cyclonedx-json", "spdx": "spdx-json"}[format]
+		return []string{"syft", "scan", "dir:.", "-o", syftFormat + "=" + file}, false
+	}
+	lang := ps.Language()
+	if lang == nil || lang.SBOM == nil || !slices.Contains(lang.SBOM.Formats, format) {
+		return nil, false
+	}
+	args, ok := ps.toolArgs(projectDir, lang.SBOM, map[string]string{"{format}": format, "{file}": file})
+	if !ok || lang.SBOM.missing() != "" {
+		return nil, false
+	}
+	return args, lang.SBOM.Stdout
+}
+
+// writeSBOM legt ein SBOM der installierten Abhängigkeiten im Projekt ab.
+func (ps *ProjectSetup) writeSBOM(projectDir string) error {
+	file, ok := sbomFormats[ps.SbomFormat]
+	if !ok {
+		return fmt.Errorf("unbekanntes sbom-format: %s", ps.SbomFormat)
+	}
+	args, toStdout := ps.sbomCommand(projectDir, ps.SbomFormat, file)
+	if args == nil {
+		return fmt.Errorf("kein werkzeug für %s-sbom von %s-projekten gefunden (syft installieren)", ps.SbomFormat, ps.ProjectType)
+	}
+
+	ps.step(fmt.Sprintf("Erstelle SBOM mit %s...", args[0]))
+	cmd := ps.command(args[0], args[1:]...)
+	cmd.Dir = projectDir
+	if !toStdout {
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("sbom erstellen fehlgeschlagen: %v: %s", err, out)
+		}
+		return nil
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("sbom erstellen fehlgeschlagen: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, file), out, ps.Settings.Modes().File); err != nil {
+		return fmt.Errorf("sbom schreiben fehlgeschlagen: %v", err)
+	}
+	return nil
+}
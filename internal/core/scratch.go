@@ -0,0 +1,307 @@
+package core
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Wegwerfprojekte (--scratch) landen in einer eigenen Liste. Sind sie älter
+// als ScratchMaxAgeDays, zeigt `newpipi cleanup` sie zum Archivieren oder
+// Löschen an; mit ScratchAutoArchive archiviert die Oberfläche sie beim Start.
+// Ein Archiv ist ein tar.gz ohne die Abhängigkeitsverzeichnisse, die sich
+// neu installieren lassen.
+
+// defaultScratchMaxAge gilt, solange ScratchMaxAgeDays nicht gesetzt ist.
+const defaultScratchMaxAge = 30
+
+// ScratchProject ist ein Eintrag der Liste der Wegwerfprojekte.
+type ScratchProject struct {
+	Dir       string    `json:"dir"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Age liefert das Alter des Projekts in ganzen Tagen.
+func (p ScratchProject) Age(now time.Time) int {
+	return int(now.Sub(p.CreatedAt) / (24 * time.Hour))
+}
+
+func scratchPath() string {
+	dir, err := StateDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "scratch.json")
+}
+
+// scratchArchiveDir ist das Verzeichnis der archivierten Wegwerfprojekte.
+func scratchArchiveDir() (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "archive"), nil
+}
+
+// ScratchMaxAge liefert das Höchstalter in Tagen.
+func (s Settings) ScratchMaxAge() int {
+	if s.ScratchMaxAgeDays > 0 {
+		return s.ScratchMaxAgeDays
+	}
+	return defaultScratchMaxAge
+}
+
+// ScratchProjects liest die Liste; bereits entfernte Verzeichnisse fallen heraus.
+func ScratchProjects() []ScratchProject {
+	var projects []ScratchProject
+	if content, err := os.ReadFile(scratchPath()); err == nil {
+		json.Unmarshal(content, &projects)
+	}
+	return slices.DeleteFunc(projects, func(p ScratchProject) bool {
+		_, err := os.Stat(p.Dir)
+		return err != nil
+	})
+}
+
+func writeScratchProjects(projects []ScratchProject) error {
+	content, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(scratchPath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(scratchPath(), content, 0644)
+}
+
+// recordScratch markiert ein gerade erstelltes Projekt als Wegwerfprojekt.
+func recordScratch(projectDir string) error {
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+	projects := slices.DeleteFunc(ScratchProjects(), func(p ScratchProject) bool { return p.Dir == abs })
+	return writeScratchProjects(append(projects, ScratchProject{Dir: abs, CreatedAt: time.Now().UTC()}))
+}
+
+// ForgetScratch nimmt ein Projekt aus der Liste, etwa wenn es bleiben soll.
+func ForgetScratch(projectDir string) error {
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+	projects := ScratchProjects()
+	n := len(projects)
+	projects = slices.DeleteFunc(projects, func(p ScratchProject) bool { return p.Dir == abs })
+	if len(projects) == n {
+		return fmt.Errorf("%s ist kein wegwerfprojekt", abs)
+	}
+	return writeScratchProjects(projects)
+}
+
+// ExpiredScratch liefert die Wegwerfprojekte, die älter als maxAge Tage sind.
+func ExpiredScratch(projects []ScratchProject, maxAge int, now time.Time) []ScratchProject {
+	var expired []ScratchProject
+	for _, p := range projects {
+		if p.Age(now) >= maxAge {
+			expired = append(expired, p)
+		}
+	}
+	return expired
+}
+
+// ArchiveScratch packt ein Wegwerfprojekt ins Archivverzeichnis, löscht es
+// und liefert den Pfad des Archivs.
+func ArchiveScratch(p ScratchProject) (string, error) {
+	return archiveProject(p.Dir, p.CreatedAt)
+}
+
+// archiveProject packt ein beliebiges Projekt ins Archivverzeichnis und
+// löscht es; created geht in den Namen des Archivs ein.
+func archiveProject(projectDir string, created time.Time) (string, error) {
+	dir, err := scratchArchiveDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("archivverzeichnis anlegen fehlgeschlagen: %v", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s.tar.gz", filepath.Base(projectDir), created.Local().Format("2006-01-02-150405")))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("archiv anlegen fehlgeschlagen: %v", err)
+	}
+	if err := writeTarGz(f, projectDir); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("%s archivieren fehlgeschlagen: %v", projectDir, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	if err := removeProject(projectDir); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// RemoveScratch löscht ein Wegwerfprojekt samt Listeneintrag.
+func RemoveScratch(p ScratchProject) error {
+	return removeProject(p.Dir)
+}
+
+// removeProject löscht ein Projekt; ein Eintrag als Wegwerfprojekt fällt mit weg.
+func removeProject(projectDir string) error {
+	if err := os.RemoveAll(projectDir); err != nil {
+		return fmt.Errorf("%s löschen fehlgeschlagen: %v", projectDir, err)
+	}
+	// ScratchProjects lässt das gelöschte Verzeichnis bereits weg
+	if err := writeScratchProjects(ScratchProjects()); err != nil {
+		log.Printf("Wegwerfprojekt vergessen fehlgeschlagen: %v", err)
+	}
+	return nil
+}
+
+// writeTarGz schreibt projectDir als tar.gz nach w; das Verzeichnis selbst ist
+// die oberste Ebene. Abhängigkeiten wie venv oder node_modules bleiben außen
+// vor, die Versionskontrolle und .newpipi nicht.
+func writeTarGz(w io.Writer, projectDir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	base := filepath.Base(projectDir)
+	err := filepath.WalkDir(projectDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() && filepath.Dir(path) == projectDir && dependencyDirs[name] && !isVCSDir(name) && name != metaDir {
+			return filepath.SkipDir
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		if d.Type()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(projectDir, path)
+		hdr.Name = filepath.ToSlash(filepath.Join(base, rel))
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// AutoArchiveScratch archiviert alle abgelaufenen Wegwerfprojekte.
+func AutoArchiveScratch(settings Settings) {
+	for _, p := range ExpiredScratch(ScratchProjects(), settings.ScratchMaxAge(), time.Now()) {
+		path, err := ArchiveScratch(p)
+		if err != nil {
+			log.Printf("Wegwerfprojekt archivieren fehlgeschlagen: %v", err)
+			continue
+		}
+		log.Printf("Wegwerfprojekt %s archiviert: %s", p.Dir, path)
+	}
+}
+
+// setupCleanup definiert `newpipi cleanup [-archive | -delete] [-yes] [-keep DIR]`.
+func setupCleanup(fs *flag.FlagSet) func(args []string) int {
+	archive := fs.Bool("archive", false, "Abgelaufene Wegwerfprojekte archivieren und löschen")
+	remove := fs.Bool("delete", false, "Abgelaufene Wegwerfprojekte ohne Archiv löschen")
+	yes := fs.Bool("yes", false, "Ohne Rückfrage löschen")
+	keep := fs.String("keep", "", "Projekt behalten, also nicht mehr als Wegwerfprojekt führen")
+	return func(args []string) int {
+		if len(args) > 0 || *archive && *remove {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi cleanup [-archive | -delete] [-yes] [-keep DIR]")
+			return exitUsage
+		}
+		if *keep != "" {
+			if err := ForgetScratch(*keep); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			fmt.Printf("Behalten: %s\n", *keep)
+			return ExitOK
+		}
+		settings, err := LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		now := time.Now()
+		expired := ExpiredScratch(ScratchProjects(), settings.ScratchMaxAge(), now)
+		if len(expired) == 0 {
+			fmt.Printf("Keine Wegwerfprojekte älter als %d Tage\n", settings.ScratchMaxAge())
+			return ExitOK
+		}
+		for _, p := range expired {
+			fmt.Printf("%s (%d Tage)\n", p.Dir, p.Age(now))
+		}
+		if !*archive && !*remove {
+			fmt.Println("Mit -archive archivieren oder mit -delete löschen")
+			return ExitOK
+		}
+		if *remove && !*yes {
+			fmt.Fprintf(os.Stderr, "%d Projekte endgültig löschen? [j/N] ", len(expired))
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "j" && a != "y" {
+				return ExitOK
+			}
+		}
+		code := ExitOK
+		for _, p := range expired {
+			if *remove {
+				err = RemoveScratch(p)
+			} else {
+				var path string
+				if path, err = ArchiveScratch(p); err == nil {
+					fmt.Printf("Archiviert: %s\n", path)
+				}
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				code = exitFailure
+			}
+		}
+		return code
+	}
+}
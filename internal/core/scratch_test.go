@@ -0,0 +1,93 @@
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExpiredScratch(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	projects := []ScratchProject{
+		{Dir: "/p/new", CreatedAt: now.Add(-time.Hour)},
+		{Dir: "/p/week", CreatedAt: now.AddDate(0, 0, -7)},
+		{Dir: "/p/month", CreatedAt: now.AddDate(0, 0, -30)},
+	}
+	tests := []struct {
+		maxAge int
+		want   []string
+	}{
+		{30, []string{"/p/month"}},
+		{7, []string{"/p/week", "/p/month"}},
+		{0, []string{"/p/new", "/p/week", "/p/month"}},
+		{31, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, p := range ExpiredScratch(projects, tt.maxAge, now) {
+			got = append(got, p.Dir)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("expiredScratch(%d) = %v, erwartet %v", tt.maxAge, got, tt.want)
+		}
+	}
+}
+
+func TestWriteTarGz(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "demo")
+	for _, path := range []string{"main.go", ".git/HEAD", ".newpipi/manifest.json", "venv/bin/python", "node_modules/x/index.js", "src/venv/keep.txt"} {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(path), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("main.go", filepath.Join(dir, "link.go")); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := writeTarGz(&buf, dir); err != nil {
+		t.Fatal(err)
+	}
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]bool)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[hdr.Name] = true
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"demo/main.go", true},
+		{"demo/link.go", true},
+		{"demo/.git/HEAD", true},
+		{"demo/.newpipi/manifest.json", true},
+		{"demo/src/venv/keep.txt", true},
+		{"demo/venv/bin/python", false},
+		{"demo/node_modules/x/index.js", false},
+	}
+	for _, tt := range tests {
+		if files[tt.name] != tt.want {
+			t.Errorf("%s im Archiv = %v, erwartet %v", tt.name, files[tt.name], tt.want)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package core
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/term"
+)
+
+const keyringService = "newpipi"
+
+// keyringRef markiert in der Konfiguration ein Secret, das im System-Schlüsselbund
+// (Secret Service, Keychain, Windows Credential Manager) liegt.
+const keyringRef = "keyring"
+
+// Arten von Secrets, die Teil des Schlüssels im Schlüsselbund sind
+const (
+	secretForge    = "forge"
+	secretRegistry = "registry"
+	secretAI       = "ai"
+)
+
+// aiSecretKey ist der Schlüssel des API-Keys im Schlüsselbund; er gehört zu
+// keinem Profil.
+var aiSecretKey = secretKey("", secretAI, "apiKey")
+
+func secretKey(profile, kind, name string) string {
+	return profile + "/" + kind + "/" + name
+}
+
+// projectSecretKey ist der Schlüssel eines Template-Secrets; er hängt am
+// Projektverzeichnis, ein verschobenes Projekt findet seine Secrets nicht mehr.
+func projectSecretKey(projectDir, name string) string {
+	return "project:" + projectDir + "/" + name
+}
+
+// resolveSecret liefert den Klartext eines Secrets aus der Konfiguration oder dem Schlüsselbund.
+func resolveSecret(profile, kind, name, value string) (string, error) {
+	if value != keyringRef {
+		return value, nil
+	}
+	secret, err := keyring.Get(keyringService, secretKey(profile, kind, name))
+	if err != nil {
+		return "", fmt.Errorf("secret %s aus schlüsselbund lesen fehlgeschlagen: %v", secretKey(profile, kind, name), err)
+	}
+	return secret, nil
+}
+
+// forgeToken liefert das API-Token des aktiven Profils für einen Forge-Host.
+func (ps *ProjectSetup) forgeToken(host string) (string, error) {
+	return resolveSecret(ps.ProfileName, secretForge, host, ps.Profile().ForgeTokens[host])
+}
+
+// registryCredential liefert die Zugangsdaten ("user:passwort" oder Token) für eine Registry.
+func (ps *ProjectSetup) registryCredential(ecosystem string) (string, error) {
+	return resolveSecret(ps.ProfileName, secretRegistry, ecosystem, ps.Profile().RegistryCredentials[ecosystem])
+}
+
+// registryEnvValue setzt die Zugangsdaten in die Mirror-URL ein; npm erwartet sie separat.
+func registryEnvValue(ecosystem, mirror, credential string) []string {
+	if credential == "" {
+		return nil
+	}
+	if ecosystem == "npm" {
+		return []string{"npm_config__auth=" + base64.StdEncoding.EncodeToString([]byte(credential))}
+	}
+	u, err := url.Parse(mirror)
+	if err != nil {
+		log.Printf("Mirror-URL %s ungültig: %v", mirror, err)
+		return nil
+	}
+	if user, pass, ok := strings.Cut(credential, ":"); ok {
+		u.User = url.UserPassword(user, pass)
+	} else {
+		u.User = url.User(credential)
+	}
+	return []string{registryEnv[ecosystem] + "=" + u.String()}
+}
+
+// aiAPIKey liefert den API-Key des KI-Endpunkts aus der Konfiguration oder dem Schlüsselbund.
+func aiAPIKey(ai AISettings) (string, error) {
+	if ai.APIKey != keyringRef {
+		return ai.APIKey, nil
+	}
+	secret, err := keyring.Get(keyringService, aiSecretKey)
+	if err != nil {
+		return "", fmt.Errorf("secret %s aus schlüsselbund lesen fehlgeschlagen: %v", aiSecretKey, err)
+	}
+	return secret, nil
+}
+
+// hasPlaintextSecrets meldet, ob noch Tokens oder der API-Key im Klartext in
+// der Konfiguration stehen.
+func (s Settings) hasPlaintextSecrets() bool {
+	if s.AI.APIKey != "" && s.AI.APIKey != keyringRef {
+		return true
+	}
+	for _, p := range s.Profiles {
+		for _, secrets := range []map[string]string{p.ForgeTokens, p.RegistryCredentials} {
+			for _, v := range secrets {
+				if v != keyringRef {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// migrateSecrets verschiebt alle Klartext-Tokens der Profile und den API-Key
+// in den Schlüsselbund und ersetzt sie in der Konfiguration durch einen Verweis.
+func migrateSecrets() (int, error) {
+	s, err := ReadSettingsFile()
+	if err != nil {
+		return 0, err
+	}
+	moved := 0
+	if s.AI.APIKey != "" && s.AI.APIKey != keyringRef {
+		if err := keyring.Set(keyringService, aiSecretKey, s.AI.APIKey); err != nil {
+			return moved, fmt.Errorf("secret in schlüsselbund speichern fehlgeschlagen: %v", err)
+		}
+		s.AI.APIKey = keyringRef
+		moved++
+	}
+	for name, p := range s.Profiles {
+		for kind, secrets := range map[string]map[string]string{secretForge: p.ForgeTokens, secretRegistry: p.RegistryCredentials} {
+			for key, value := range secrets {
+				if value == keyringRef {
+					continue
+				}
+				if err := keyring.Set(keyringService, secretKey(name, kind, key), value); err != nil {
+					return moved, fmt.Errorf("secret in schlüsselbund speichern fehlgeschlagen: %v", err)
+				}
+				secrets[key] = keyringRef
+				moved++
+			}
+		}
+	}
+	if moved == 0 {
+		return 0, nil
+	}
+	return moved, writeSettingsFile(s)
+}
+
+// storeSecret legt ein Secret im Schlüsselbund ab und vermerkt es im Profil.
+func storeSecret(profile, kind, name, secret string) error {
+	if err := keyring.Set(keyringService, secretKey(profile, kind, name), secret); err != nil {
+		return fmt.Errorf("secret in schlüsselbund speichern fehlgeschlagen: %v", err)
+	}
+	s, err := ReadSettingsFile()
+	if err != nil {
+		return err
+	}
+	p, ok := s.Profiles[profile]
+	if !ok {
+		return fmt.Errorf("profil nicht gefunden: %s", profile)
+	}
+	switch kind {
+	case secretForge:
+		if p.ForgeTokens == nil {
+			p.ForgeTokens = make(map[string]string)
+		}
+		p.ForgeTokens[name] = keyringRef
+	case secretRegistry:
+		if p.RegistryCredentials == nil {
+			p.RegistryCredentials = make(map[string]string)
+		}
+		p.RegistryCredentials[name] = keyringRef
+	}
+	s.Profiles[profile] = p
+	return writeSettingsFile(s)
+}
+
+// printProjectSecrets gibt die Secrets aus .env.example, die für das Projekt
+// im Schlüsselbund liegen, als export-Zeilen für `eval` in .envrc aus.
+func printProjectSecrets(dir string) error {
+	projectDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filepath.Join(projectDir, ".env.example"))
+	if err != nil {
+		return fmt.Errorf(".env.example lesen fehlgeschlagen: %v", err)
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		name, _, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(line, "#") {
+			continue
+		}
+		secret, err := keyring.Get(keyringService, projectSecretKey(projectDir, name))
+		if errors.Is(err, keyring.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("secret %s aus schlüsselbund lesen fehlgeschlagen: %v", name, err)
+		}
+		fmt.Printf("export %s=%s\n", name, shellQuote(secret))
+	}
+	return scanner.Err()
+}
+
+// readSecret liest ein Secret von stdin; im Terminal ohne Echo, sonst die
+// erste Zeile einer Umleitung.
+func readSecret() (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "Secret eingeben: ")
+		secret, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return strings.TrimSpace(string(secret)), err
+	}
+	secret, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && secret == "" {
+		return "", err
+	}
+	return strings.TrimSpace(secret), nil
+}
+
+// setupSecret definiert `newpipi secret set|migrate|env`.
+func setupSecret(fs *flag.FlagSet) func(args []string) int {
+	profile := fs.String("profile", "", "Profil, zu dem das Secret gehört")
+	forge := fs.String("forge", "", "Forge-Host für ein API-Token (z.B. github.com)")
+	registry := fs.String("registry", "", "Ökosystem für Registry-Zugangsdaten (npm, pip, go)")
+	return func(args []string) int {
+		if len(args) == 0 || len(args) > 1 && args[0] != "env" || len(args) > 2 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi secret set|migrate|env [VERZEICHNIS]")
+			return exitUsage
+		}
+		switch args[0] {
+		case "env":
+			dir := "."
+			if len(args) == 2 {
+				dir = args[1]
+			}
+			if err := printProjectSecrets(dir); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			return ExitOK
+		case "migrate":
+			moved, err := migrateSecrets()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			fmt.Printf("%d Secrets in den Schlüsselbund verschoben\n", moved)
+			return ExitOK
+		case "set":
+			kind, name := secretForge, *forge
+			if *registry != "" {
+				kind, name = secretRegistry, *registry
+			}
+			if *profile == "" || name == "" || *forge != "" && *registry != "" {
+				fmt.Fprintln(os.Stderr, "Verwendung: newpipi secret set --profile NAME (--forge HOST | --registry ÖKOSYSTEM) < secret")
+				return exitUsage
+			}
+			secret, err := readSecret()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: secret lesen fehlgeschlagen: %v\n", err)
+				return exitFailure
+			}
+			if err := storeSecret(*profile, kind, name, secret); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			return ExitOK
+		}
+		fmt.Fprintf(os.Stderr, "Unbekannte Aktion: %s\n", args[0])
+		return exitUsage
+	}
+}
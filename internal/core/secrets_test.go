@@ -0,0 +1,47 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestHasPlaintextSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Settings
+		want bool
+	}{
+		{"leer", Settings{}, false},
+		{"forge-token", Settings{Profiles: map[string]Profile{"work": {ForgeTokens: map[string]string{"github.com": "ghp_x"}}}}, true},
+		{"registry im schlüsselbund", Settings{Profiles: map[string]Profile{"work": {RegistryCredentials: map[string]string{"npm": keyringRef}}}}, false},
+		{"api-key", Settings{AI: AISettings{APIKey: "sk-x"}}, true},
+		{"api-key im schlüsselbund", Settings{AI: AISettings{APIKey: keyringRef}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.s.hasPlaintextSecrets(); got != tt.want {
+			t.Errorf("%s: hasPlaintextSecrets() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMigrateAIKey(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv("NEWPIPI_CONFIG_DIR", t.TempDir())
+	if err := writeSettingsFile(Settings{AI: AISettings{Model: "llama3", APIKey: "sk-geheim"}}); err != nil {
+		t.Fatal(err)
+	}
+	if moved, err := migrateSecrets(); err != nil || moved != 1 {
+		t.Fatalf("migrateSecrets() = %d, %v", moved, err)
+	}
+	s, err := ReadSettingsFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.AI.APIKey != keyringRef || s.hasPlaintextSecrets() {
+		t.Errorf("apiKey = %q nach migrate", s.AI.APIKey)
+	}
+	if key, err := aiAPIKey(s.AI); err != nil || key != "sk-geheim" {
+		t.Errorf("aiAPIKey() = %q, %v", key, err)
+	}
+}
@@ -0,0 +1,327 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Selbsttest: `newpipi selftest` legt jedes eingebaute Toolkit, jedes Layout
+// und jedes eingebaute Template in einem temporären Verzeichnis an und führt
+// den Prüfbefehl des Projekts aus ("check" bei Projekttyp, Toolkit, Layout und
+// Template). So fällt auf, wenn eine Vorlage mit neuen Werkzeugversionen nicht
+// mehr baut, bevor Benutzer darauf stoßen. Mit --container läuft die Prüfung
+// im Image des Projekttyps ("image"), erstellt wird immer auf dem Rechner.
+
+// selftestTimeout begrenzt den Prüfbefehl einer Variante.
+const selftestTimeout = 10 * time.Minute
+
+// selftestOutputLines ist die Zahl der Ausgabezeilen, die bei einem Fehler
+// gezeigt werden.
+const selftestOutputLines = 20
+
+// Ergebnisse eines Schritts im Selbsttest
+const (
+	selftestOK   = "ok"
+	selftestFail = "fail"
+	selftestSkip = "skip"
+)
+
+// selftestCase ist eine Variante im Selbsttest: ein Toolkit, ein Layout mit
+// dem Standard-Toolkit oder ein Template mit seinen Standardoptionen.
+type selftestCase struct {
+	Type     ProjectType `json:"type"`
+	Toolkit  string      `json:"toolkit,omitempty"`
+	Layout   string      `json:"layout,omitempty"`
+	Template string      `json:"template,omitempty"`
+}
+
+// selftestResult ist das Ergebnis einer Variante für die Matrix und --json.
+type selftestResult struct {
+	selftestCase
+	Create     string `json:"create"`
+	Check      string `json:"check"`
+	Command    string `json:"command,omitempty"`
+	Image      string `json:"image,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+	Output     string `json:"output,omitempty"`
+}
+
+// selftestCases zählt die Varianten der Projekttypen auf: jedes Toolkit, jedes
+// weitere Layout mit dem Standard-Toolkit und jedes eingebaute Template.
+func selftestCases(types []ProjectType) []selftestCase {
+	var cases []selftestCase
+	for _, pt := range types {
+		lang := languages[pt]
+		if lang == nil {
+			continue
+		}
+		if len(lang.Toolkits) == 0 {
+			cases = append(cases, selftestCase{Type: pt})
+		}
+		for _, tk := range lang.Toolkits {
+			cases = append(cases, selftestCase{Type: pt, Toolkit: tk.Name})
+		}
+		// Das erste Layout ist bei den Toolkits schon dabei
+		for i, l := range lang.Layouts {
+			if i > 0 {
+				cases = append(cases, selftestCase{Type: pt, Layout: l.Name})
+			}
+		}
+		for _, t := range builtinTemplates {
+			if t.Type == pt && !t.Abstract && !t.Addon {
+				cases = append(cases, selftestCase{Type: pt, Template: t.Name})
+			}
+		}
+	}
+	return cases
+}
+
+// variant beschreibt die Variante für die Matrix.
+func (c selftestCase) variant() string {
+	switch {
+	case c.Template != "":
+		return "template " + c.Template
+	case c.Layout != "":
+		return "layout " + c.Layout
+	case c.Toolkit != "":
+		return c.Toolkit
+	}
+	return "-"
+}
+
+// matches meldet, ob filter in Projekttyp oder Variante vorkommt.
+func (c selftestCase) matches(filter string) bool {
+	label := strings.ToLower(c.Type.String() + " " + c.variant())
+	return strings.Contains(label, strings.ToLower(filter))
+}
+
+// checkCommand liefert den Prüfbefehl des Projekts, den eines Templates vor
+// dem von Toolkit, Layout und Projekttyp, sowie das Image des Projekttyps.
+func (ps *ProjectSetup) checkCommand() (check, image string) {
+	lang := ps.Language()
+	if lang == nil {
+		return "", ""
+	}
+	tk, _ := ps.Toolkit()
+	lang = ps.withPackageManager(lang.with(tk))
+	check = lang.Check
+	if ps.Template != nil && ps.Template.Check != "" {
+		check = ps.npmShell(ps.Template.Check)
+	}
+	return check, lang.Image
+}
+
+// runSelftestCase legt die Variante unter dir an und prüft sie. runtime ist
+// docker oder podman, leer prüft auf dem Rechner; images ersetzt die Images
+// der Projekttypen.
+func runSelftestCase(c selftestCase, dir, runtime string, images map[string]string) (res selftestResult) {
+	res = selftestResult{selftestCase: c, Create: selftestFail, Check: selftestSkip}
+	start := time.Now()
+	defer func() { res.DurationMs = time.Since(start).Milliseconds() }()
+
+	ps := NewProjectSetup()
+	ps.ParentPath = dir
+	ps.ProjectName = "selftest"
+	ps.ProjectType = c.Type
+	ps.ToolkitName, ps.LayoutName = c.Toolkit, c.Layout
+	ps.skipTerminal, ps.SkipGit = true, true
+	if c.Template != "" {
+		if ps.Template = FindTemplate(c.Type, c.Template); ps.Template == nil {
+			res.Error = fmt.Sprintf("template nicht gefunden: %s", c.Template)
+			return res
+		}
+		if _, err := ps.Template.ResolveOptions(nil); err != nil {
+			res.Error = err.Error()
+			return res
+		}
+	}
+	if err := ps.CreateProject(); err != nil {
+		if errors.Is(err, errToolchainMissing) {
+			res.Create = selftestSkip
+		}
+		res.Error = err.Error()
+		return res
+	}
+	res.Create = selftestOK
+
+	check, image := ps.checkCommand()
+	if check == "" {
+		return res
+	}
+	if override, ok := images[c.Type.String()]; ok {
+		image = override
+	}
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	ctx, cancel := context.WithTimeout(context.Background(), selftestTimeout)
+	defer cancel()
+	res.Command = check
+	var cmd *exec.Cmd
+	if runtime != "" && image != "" {
+		// Im Container gibt es kein venv, Activate entfällt
+		res.Image = image
+		cmd = ps.commandContext(ctx, runtime, "run", "--rm", "-v", projectDir+":/project", "-w", "/project", image, "sh", "-c", ps.expandLocal(check, true))
+	} else {
+		if lang := ps.Language(); lang.Activate != "" {
+			check = ps.expandLocal(lang.Activate, true) + " && " + check
+		}
+		cmd = ps.commandContext(ctx, "bash", "-c", check)
+		cmd.Dir = projectDir
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		res.Check = selftestFail
+		res.Error = err.Error()
+		res.Output = lastLines(string(out), selftestOutputLines)
+		return res
+	}
+	res.Check = selftestOK
+	return res
+}
+
+// lastLines liefert die letzten n Zeilen von s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// failed meldet, ob Erstellen oder Prüfen fehlgeschlagen ist; übersprungene
+// Varianten zählen nicht.
+func (r selftestResult) failed() bool {
+	return r.Create == selftestFail || r.Check == selftestFail
+}
+
+// printSelftest gibt die Ergebnisse als Matrix aus, danach die Fehler mit der
+// Ausgabe des Prüfbefehls.
+func printSelftest(w io.Writer, results []selftestResult) {
+	const format = "%-12s %-28s %-10s %-8s %8s  %s\n"
+	fmt.Fprintf(w, format, "Typ", "Variante", "Erstellen", "Prüfen", "Dauer", "Umgebung")
+	var ok, failed, skipped int
+	for _, r := range results {
+		env := "lokal"
+		if r.Image != "" {
+			env = r.Image
+		}
+		duration := (time.Duration(r.DurationMs) * time.Millisecond).Round(100 * time.Millisecond)
+		fmt.Fprintf(w, format, r.Type, r.variant(), r.Create, r.Check, duration, env)
+		switch {
+		case r.failed():
+			failed++
+		case r.Create == selftestSkip:
+			skipped++
+		default:
+			ok++
+		}
+	}
+	for _, r := range results {
+		if !r.failed() {
+			continue
+		}
+		fmt.Fprintf(w, "\n=== %s %s ===\n", r.Type, r.variant())
+		if r.Command != "" {
+			fmt.Fprintf(w, "$ %s\n", r.Command)
+		}
+		fmt.Fprintln(w, r.Error)
+		if r.Output != "" {
+			fmt.Fprintln(w, r.Output)
+		}
+	}
+	fmt.Fprintf(w, "\n%d ok, %d fehlgeschlagen, %d übersprungen\n", ok, failed, skipped)
+}
+
+// setupSelftest definiert `newpipi selftest [--type TYP] [--only TEXT]
+// [--container docker|podman] [--image TYP=IMAGE] [--keep] [--json] [-v]`.
+func setupSelftest(fs *flag.FlagSet) func(args []string) int {
+	typeName := fs.String("type", "", "Nur diesen Projekttyp testen")
+	only := fs.String("only", "", "Nur Varianten, deren Typ oder Name diesen Text enthält")
+	runtime := fs.String("container", "", "Prüfbefehle im Container ausführen (docker oder podman)")
+	var images optionValues
+	fs.Var(&images, "image", "Image für einen Projekttyp (TYP=IMAGE, wiederholbar)")
+	keep := fs.Bool("keep", false, "Erzeugte Projekte nicht löschen")
+	jsonOutput := fs.Bool("json", false, "Ergebnisse als JSON ausgeben")
+	verbose := fs.Bool("v", false, "Protokoll der Projekterstellung ausgeben")
+	return func(args []string) int {
+		types := AllProjectTypes()[:Zig+1]
+		if *typeName != "" {
+			pt, ok := ParseProjectType(*typeName)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Fehler: unbekannter projekttyp: %q\n", *typeName)
+				return exitUsage
+			}
+			types = []ProjectType{pt}
+		}
+		for name := range images {
+			if _, ok := ParseProjectType(name); !ok {
+				fmt.Fprintf(os.Stderr, "Fehler: unbekannter projekttyp: %q\n", name)
+				return exitUsage
+			}
+		}
+		if *runtime != "" && *runtime != "docker" && *runtime != "podman" {
+			fmt.Fprintf(os.Stderr, "Fehler: unbekannte container-laufzeit: %q (docker, podman)\n", *runtime)
+			return exitUsage
+		}
+		cases := selftestCases(types)
+		if *only != "" {
+			cases = slices.DeleteFunc(cases, func(c selftestCase) bool { return !c.matches(*only) })
+		}
+		if len(cases) == 0 {
+			fmt.Fprintln(os.Stderr, "Fehler: keine varianten ausgewählt")
+			return exitUsage
+		}
+
+		root, err := os.MkdirTemp("", "newpipi-selftest-")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		if *keep {
+			log.Printf("Projekte bleiben unter %s", root)
+		} else {
+			defer os.RemoveAll(root)
+		}
+		// Statistik, Zeiten und zuletzt erstellte Projekte des Benutzers
+		// bleiben unberührt
+		os.Setenv("XDG_STATE_HOME", filepath.Join(root, "state"))
+		if !*verbose {
+			defer log.SetOutput(log.Writer())
+			log.SetOutput(io.Discard)
+		}
+
+		results := make([]selftestResult, 0, len(cases))
+		code := ExitOK
+		for i, c := range cases {
+			if !*jsonOutput {
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s %s\n", i+1, len(cases), c.Type, c.variant())
+			}
+			dir := filepath.Join(root, fmt.Sprintf("%02d", i+1))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			r := runSelftestCase(c, dir, *runtime, images)
+			if r.failed() {
+				code = exitFailure
+			}
+			results = append(results, r)
+		}
+		if *jsonOutput {
+			printJSON(results)
+		} else {
+			printSelftest(os.Stdout, results)
+		}
+		return code
+	}
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSelftestCases(t *testing.T) {
+	cases := selftestCases([]ProjectType{CPlusPlus, CSharp, TypeScript})
+	tests := []struct {
+		c       selftestCase
+		variant string
+	}{
+		{selftestCase{Type: CPlusPlus, Toolkit: "Make"}, "Make"},
+		{selftestCase{Type: CSharp, Layout: "Solution"}, "layout Solution"},
+		{selftestCase{Type: TypeScript, Template: "TypeScript App"}, "template TypeScript App"},
+	}
+	for _, tt := range tests {
+		if !slices.Contains(cases, tt.c) {
+			t.Errorf("%+v fehlt in den Varianten", tt.c)
+		}
+		if got := tt.c.variant(); got != tt.variant {
+			t.Errorf("variant(%+v) = %q, want %q", tt.c, got, tt.variant)
+		}
+	}
+	// Das Standard-Layout ist schon mit jedem Toolkit dabei
+	if slices.Contains(cases, selftestCase{Type: CSharp, Layout: "Project"}) {
+		t.Error("standard-layout Project als eigene Variante")
+	}
+}
+
+func TestCheckCommand(t *testing.T) {
+	tests := []struct {
+		pt                 ProjectType
+		toolkit, layout    string
+		template           string
+		pm                 string
+		wantCheck, wantImg string
+	}{
+		{CPlusPlus, "Make", "", "", "", "make", ""},
+		{CSharp, "", "Solution", "", "", "dotnet test", "mcr.microsoft.com/dotnet/sdk:9.0"},
+		{CSharp, "xUnit", "", "", "", "dotnet test", "mcr.microsoft.com/dotnet/sdk:9.0"},
+		{TypeScript, "", "", "TypeScript App", "", "npm run build && npm test", "node:22"},
+		{TypeScript, "", "", "TypeScript App", "pnpm", "pnpm run build && pnpm run test", "node:22"},
+		// Ohne eigenen Prüfbefehl prüft ein Template wie sein Projekttyp
+		{Python, "", "", "CLI App", "", "python -m compileall -q -x venv .", "python:3.12-slim"},
+	}
+	for _, tt := range tests {
+		ps := &ProjectSetup{ProjectType: tt.pt, ToolkitName: tt.toolkit, LayoutName: tt.layout, PackageManager: tt.pm}
+		if tt.template != "" {
+			if ps.Template = FindTemplate(tt.pt, tt.template); ps.Template == nil {
+				t.Fatalf("template %s nicht gefunden", tt.template)
+			}
+		}
+		check, image := ps.checkCommand()
+		if check != tt.wantCheck || image != tt.wantImg {
+			t.Errorf("%s %s%s%s: checkCommand() = %q, %q, want %q, %q", tt.pt, tt.toolkit, tt.layout, tt.template, check, image, tt.wantCheck, tt.wantImg)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package core
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+//go:embed web/index.html
+var webUI []byte
+
+// server stellt den Generator über eine lokale HTTP-API bereit, damit Editoren
+// und andere Werkzeuge Projekte erstellen können, ohne die GUI zu starten.
+type server struct {
+	// createMu serialisiert Projekterstellungen, damit sich gleichzeitige
+	// Anfragen für dasselbe Verzeichnis und die Statistik nicht in die Quere kommen.
+	createMu sync.Mutex
+
+	// defaultParentPath wird der Web-Oberfläche als Vorbelegung angeboten.
+	defaultParentPath string
+
+	// token schützt POST /api/projects über TCP: Die Web-Oberfläche erhält es
+	// als Cookie, wenn sie mit ?token= aufgerufen wird, andere Werkzeuge
+	// senden es als Bearer-Token. Leer beim
+	// Unix-Socket, den die Dateirechte schützen.
+	token string
+	// loopback ist gesetzt, wenn nur lokal gelauscht wird; dann muss der
+	// Host-Header lokal sein, was DNS-Rebinding abwehrt.
+	loopback bool
+}
+
+// tokenCookie ist der Name des Cookies mit dem Sitzungstoken.
+const tokenCookie = "newpipi_token"
+
+// CreateRequest sind die Eingaben einer Erstellung über HTTP, D-Bus, stdio
+// oder pkg/scaffold.
+type CreateRequest struct {
+	Type       ProjectType       `json:"type"`
+	Name       string            `json:"name"`
+	ParentPath string            `json:"parentPath"`
+	Template   string            `json:"template,omitempty"`
+	Toolkit    string            `json:"toolkit,omitempty"`
+	Layout     string            `json:"layout,omitempty"`
+	Module     string            `json:"module,omitempty"`
+	PM         string            `json:"packageManager,omitempty"`
+	Profile    string            `json:"profile,omitempty"`
+	Options    map[string]string `json:"options,omitempty"`
+	Snippets   []string          `json:"snippets,omitempty"`
+	Addons     []string          `json:"addons,omitempty"`
+	Scratch    bool              `json:"scratch,omitempty"`
+	Sandbox    bool              `json:"sandbox,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+	SkipGit    bool              `json:"skipGit,omitempty"`
+	GitHooks   bool              `json:"gitHooks,omitempty"`
+	Release    bool              `json:"releaseTask,omitempty"`
+	Sign       bool              `json:"sign,omitempty"`
+	LFS        bool              `json:"lfs,omitempty"`
+	VCS        string            `json:"vcs,omitempty"`
+	Audit      bool              `json:"audit,omitempty"`
+	Licenses   bool              `json:"licenses,omitempty"`
+	SBOM       string            `json:"sbom,omitempty"`
+
+	// Path ist ein Alias für ParentPath wie -path der Kommandozeile.
+	Path string `json:"path,omitempty"`
+}
+
+type validateResponse struct {
+	Valid   bool   `json:"valid"`
+	Message string `json:"message,omitempty"`
+}
+
+// setupServe definiert die Flags des Daemon-Modus.
+func setupServe(fs *flag.FlagSet) func(args []string) int {
+	addr := fs.String("addr", "127.0.0.1:7878", "TCP-Adresse der API")
+	socket := fs.String("socket", "", "Unix-Socket statt TCP verwenden")
+	return func(args []string) int {
+		if err := runServe(*addr, *socket); err != nil {
+			log.Printf("Daemon-Modus fehlgeschlagen: %v", err)
+			return exitFailure
+		}
+		return ExitOK
+	}
+}
+
+func runServe(addr, socket string) error {
+	var (
+		ln  net.Listener
+		err error
+	)
+	if socket != "" {
+		// Verwaiste Sockets eines früheren Laufs entfernen
+		if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("alten socket entfernen fehlgeschlagen: %v", err)
+		}
+		ln, err = net.Listen("unix", socket)
+		if err == nil {
+			err = os.Chmod(socket, 0600)
+		}
+	} else {
+		ln, err = net.Listen("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("listener erstellen fehlgeschlagen: %v", err)
+	}
+	defer ln.Close()
+
+	srv := &server{defaultParentPath: NewProjectSetup().ParentPath}
+	if tcp, ok := ln.Addr().(*net.TCPAddr); ok {
+		token := make([]byte, 16)
+		if _, err := rand.Read(token); err != nil {
+			return fmt.Errorf("token erzeugen fehlgeschlagen: %v", err)
+		}
+		srv.token = hex.EncodeToString(token)
+		srv.loopback = tcp.IP.IsLoopback()
+		log.Printf("API-Token dieser Sitzung: %s", srv.token)
+		log.Printf("Web-Oberfläche: http://%s/?token=%s", ln.Addr(), srv.token)
+	}
+	log.Printf("API lauscht auf %s", ln.Addr())
+	return http.Serve(ln, srv.routes())
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /api/defaults", s.handleDefaults)
+	mux.HandleFunc("GET /api/types", s.handleTypes)
+	mux.HandleFunc("GET /api/templates", s.handleTemplates)
+	mux.HandleFunc("GET /api/snippets", s.handleSnippets)
+	mux.HandleFunc("GET /api/validate", s.handleValidate)
+	mux.HandleFunc("POST /api/projects", s.handleCreate)
+	// Kurzform für Skripte, gleichwertig zu /api/projects
+	mux.HandleFunc("POST /projects", s.handleCreate)
+	return s.checkHost(mux)
+}
+
+// checkHost lehnt Anfragen mit fremdem Host- oder Origin-Header ab.
+func (s *server) checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.loopback && !isLoopbackHost(r.Host) {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": "fremder host: " + r.Host})
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if u, err := url.Parse(origin); err != nil || u.Host != r.Host {
+				writeJSON(w, http.StatusForbidden, map[string]string{"error": "fremder origin: " + origin})
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackHost meldet, ob ein Host-Header (mit oder ohne Port) lokal ist.
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// authorized prüft das Sitzungstoken aus Authorization-Header oder Cookie.
+func (s *server) authorized(r *http.Request) bool {
+	if s.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		c, err := r.Cookie(tokenCookie)
+		if err != nil {
+			return false
+		}
+		token = c.Value
+	}
+	return s.validToken(token)
+}
+
+// validToken vergleicht token in konstanter Zeit mit dem Sitzungstoken.
+func (s *server) validToken(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// handleIndex liefert die Web-Oberfläche aus; das Cookie mit dem Token setzt
+// sie nur, wenn der Aufruf das Token als ?token= oder im Header mitbringt.
+func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	_, bearer := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if s.token != "" && (s.validToken(r.URL.Query().Get("token")) || bearer && s.authorized(r)) {
+		http.SetCookie(w, &http.Cookie{Name: tokenCookie, Value: s.token, Path: "/", HttpOnly: true, SameSite: http.SameSiteStrictMode})
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(webUI)
+}
+
+func (s *server) handleDefaults(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"parentPath": s.defaultParentPath})
+}
+
+func (s *server) handleTypes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, ProjectTypeNames)
+}
+
+func (s *server) handleTemplates(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, allTemplates())
+}
+
+func (s *server) handleSnippets(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, snippets)
+}
+
+func (s *server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	valid, msg := IsValidProjectName(r.URL.Query().Get("name"))
+	writeJSON(w, http.StatusOK, validateResponse{Valid: valid, Message: msg})
+}
+
+// handleCreate erstellt ein Projekt und streamt die Arbeitsschritte als Server-Sent Events.
+func (s *server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "token fehlt oder ist ungültig"})
+		return
+	}
+	// Formulare fremder Seiten können kein application/json senden
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		writeJSON(w, http.StatusUnsupportedMediaType, map[string]string{"error": "content-type muss application/json sein"})
+		return
+	}
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	ps, status, err := req.Prepare()
+	if err != nil {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "streaming nicht unterstützt"})
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(event string, data any) {
+		payload, _ := json.Marshal(data)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+		flusher.Flush()
+	}
+	estimate := ps.LoadEstimate()
+	ps.Progress = func(step string) {
+		event := map[string]any{"message": step}
+		if fraction, remaining, ok := estimate.At(step, 0); ok {
+			event["fraction"] = fraction
+			event["eta"] = FormatETA(remaining)
+		}
+		send("step", event)
+	}
+
+	s.createMu.Lock()
+	err = ps.CreateProject()
+	s.createMu.Unlock()
+
+	if err != nil {
+		log.Printf("Fehler bei Projekterstellung über API: %v", err)
+		send("error", map[string]string{"error": err.Error()})
+		return
+	}
+	send("done", map[string]any{"path": filepath.Join(ps.ParentPath, ps.ProjectName), "audit": ps.auditReport, "licenses": ps.licenseReport})
+}
+
+// Prepare prüft eine Anfrage der HTTP- oder D-Bus-Schnittstelle und liefert
+// das vorbereitete Setup. Der Statuscode gilt für die HTTP-Antwort.
+func (req CreateRequest) Prepare() (*ProjectSetup, int, error) {
+	if valid, msg := IsValidProjectName(req.Name); !valid {
+		return nil, http.StatusBadRequest, errors.New(msg)
+	}
+	ps := NewProjectSetup()
+	ps.ProjectName = req.Name
+	ps.ProjectType = req.Type
+	ps.skipTerminal = true
+	ps.SkipGit = req.SkipGit
+	ps.GitHooks = ps.GitHooks || req.GitHooks
+	ps.ReleaseTask = ps.ReleaseTask || req.Release
+	ps.SignCommits = ps.SignCommits || req.Sign
+	ps.GitLFS = ps.GitLFS || req.LFS
+	ps.Scratch = req.Scratch
+	if req.VCS != "" {
+		ps.Vcs = req.VCS
+	}
+	ps.Audit = req.Audit
+	ps.Licenses = req.Licenses
+	ps.SbomFormat = req.SBOM
+	if _, ok := sbomFormats[req.SBOM]; req.SBOM != "" && !ok {
+		return nil, http.StatusBadRequest, fmt.Errorf("unbekanntes sbom-format: %s", req.SBOM)
+	}
+	if req.Profile != "" {
+		if err := ps.SelectProfile(req.Profile); err != nil {
+			return nil, http.StatusNotFound, err
+		}
+	}
+	if req.ParentPath == "" {
+		req.ParentPath = req.Path
+	}
+	if req.ParentPath != "" {
+		ps.ParentPath = req.ParentPath
+	}
+	if req.Sandbox {
+		if err := ps.EnterSandbox(); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+	ps.ToolkitName = req.Toolkit
+	ps.LayoutName = req.Layout
+	if _, err := ps.Toolkit(); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if ps.ModulePath = req.Module; ps.ModulePath != "" {
+		if err := checkModulePath(ps.ModulePath); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+	}
+	if req.PM != "" {
+		ps.PackageManager = req.PM
+	}
+	if _, err := FindPackageManager(ps.PackageManager); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if req.Template != "" {
+		if ps.Template = FindTemplate(req.Type, req.Template); ps.Template == nil {
+			return nil, http.StatusNotFound, fmt.Errorf("template nicht gefunden: %s", req.Template)
+		}
+		if _, err := ps.Template.ResolveOptions(req.Options); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		ps.Options = req.Options
+	}
+	for _, name := range req.Snippets {
+		if FindSnippet(req.Type, name) == nil {
+			return nil, http.StatusNotFound, fmt.Errorf("snippet nicht gefunden: %s", name)
+		}
+	}
+	ps.Snippets = req.Snippets
+	for _, name := range req.Addons {
+		addon := FindAddon(req.Type, name)
+		if addon == nil {
+			return nil, http.StatusNotFound, fmt.Errorf("add-on nicht gefunden: %s", name)
+		}
+		ps.Addons = append(ps.Addons, addon.Name)
+	}
+	if err := ValidateEnv(req.Env); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	ps.EnvVars = req.Env
+	return ps, http.StatusOK, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Antwort schreiben fehlgeschlagen: %v", err)
+	}
+}
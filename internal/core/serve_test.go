@@ -0,0 +1,41 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIndexTokenCookie(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		url    string
+		header string
+		cookie bool
+	}{
+		{"ohne token", "secret", "/", "", false},
+		{"falsches token", "secret", "/?token=wrong", "", false},
+		{"token in der adresse", "secret", "/?token=secret", "", true},
+		{"token im header", "secret", "/", "Bearer secret", true},
+		{"falscher header", "secret", "/", "Bearer wrong", false},
+		{"unix-socket", "", "/", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &server{token: tt.token}
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			srv.routes().ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d", rec.Code)
+			}
+			if got := len(rec.Result().Cookies()) > 0; got != tt.cookie {
+				t.Errorf("cookie gesetzt = %v, want %v", got, tt.cookie)
+			}
+		})
+	}
+}
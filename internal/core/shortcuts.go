@@ -0,0 +1,123 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Der Startbefehl (siehe StartCommand) wird nicht nur im Terminal angezeigt,
+// sondern auch dort abgelegt, wo ihn Werkzeuge und Editoren finden: als
+// VS-Code-Task und je nach Projekttyp als Taskfile-Ziel, npm-Skript oder
+// Cargo-Alias. Dateien, die ein Template bereits mitbringt, bleiben unverändert.
+
+// writeRunShortcuts legt den Startbefehl im Projekt ab.
+func (ps *ProjectSetup) writeRunShortcuts(projectDir string) error {
+	command := ps.StartCommand()
+	if command == "" {
+		return nil
+	}
+	ps.step("Lege Startbefehl im Projekt ab...")
+	modes := ps.Settings.Modes()
+	shell := command
+	var kinds []string
+	if lang := ps.Language(); lang != nil {
+		if lang.Activate != "" {
+			shell = ps.expandLocal(lang.Activate, true) + " && " + command
+		}
+		kinds = lang.Shortcuts
+	}
+
+	for _, kind := range kinds {
+		var err error
+		switch kind {
+		case "taskfile":
+			err = writeShortcut(projectDir, "Taskfile.yml", ps.localize(taskfile(shell), "Run the project"), modes)
+		case "npm":
+			err = ps.npmScript(projectDir, "start", command)
+		case "cargo":
+			// "cargo run" ist selbst schon der übliche Befehl
+			if sub, ok := strings.CutPrefix(command, "cargo "); ok && sub != "run" {
+				err = writeShortcut(projectDir, filepath.Join(".cargo", "config.toml"), fmt.Sprintf("[alias]\nstart = %q\n", sub), modes)
+			}
+		default:
+			log.Printf("Unbekannte Ablage für den Startbefehl: %s", kind)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return writeShortcut(projectDir, filepath.Join(".vscode", "tasks.json"), vscodeTasks(shell), modes)
+}
+
+// writeShortcut schreibt eine neue Datei; eine vorhandene bleibt unberührt.
+func writeShortcut(projectDir, path, content string, modes projectModes) error {
+	target := filepath.Join(projectDir, path)
+	if _, err := os.Lstat(target); err == nil {
+		log.Printf("%s existiert bereits, Startbefehl nicht eingetragen", path)
+		return nil
+	}
+	if err := modes.writeFile(target, []byte(content), modes.File); err != nil {
+		return fmt.Errorf("datei %s erstellen fehlgeschlagen: %v", path, err)
+	}
+	return nil
+}
+
+func taskfile(command string) string {
+	// Ein JSON-String ist auch in YAML gültig und erspart das Quoting
+	return fmt.Sprintf("version: '3'\n\ntasks:\n  run:\n    desc: Run the project\n    cmds:\n      - %s", marshalCommand(command, ""))
+}
+
+func vscodeTasks(command string) string {
+	type task struct {
+		Label          string   `json:"label"`
+		Type           string   `json:"type"`
+		Command        string   `json:"command"`
+		ProblemMatcher []string `json:"problemMatcher"`
+	}
+	return marshalCommand(struct {
+		Version string `json:"version"`
+		Tasks   []task `json:"tasks"`
+	}{"2.0.0", []task{{"run", "shell", command, []string{}}}}, "  ")
+}
+
+// marshalCommand schreibt v als JSON mit abschließendem Zeilenumbruch, ohne
+// "&&" und spitze Klammern in Befehlen als \u0026 usw. zu maskieren.
+func marshalCommand(v any, indent string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	enc.Encode(v)
+	return b.String()
+}
+
+// npmScript trägt den Befehl als Skript name in package.json ein, sofern
+// dort noch keines steht. npm pkg erhält die Formatierung der Datei.
+func (ps *ProjectSetup) npmScript(projectDir, name, command string) error {
+	content, err := os.ReadFile(filepath.Join(projectDir, "package.json"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("package.json lesen fehlgeschlagen: %v", err)
+	}
+	var pkg struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal(content, &pkg); err != nil {
+		return fmt.Errorf("package.json ungültig: %v", err)
+	}
+	if _, ok := pkg.Scripts[name]; ok {
+		log.Printf("package.json hat bereits ein %s-Skript, nicht eingetragen", name)
+		return nil
+	}
+	cmd := ps.command("npm", "pkg", "set", "scripts."+name+"="+command)
+	cmd.Dir = projectDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("npm-skript eintragen fehlgeschlagen: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
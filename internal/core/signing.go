@@ -0,0 +1,143 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Signierte Commits: newpipi richtet im neuen Repository gpg.format,
+// user.signingkey und commit.gpgsign ein, bevor der erste Commit entsteht.
+// Den Schlüssel nennt Profile.SigningKey; ohne Angabe wird der erste Schlüssel
+// im ssh-agent verwendet, sonst der GPG-Schlüssel zur E-Mail des Profils.
+
+// signingKey ist ein Schlüssel samt git-Format ("ssh" oder "openpgp").
+type signingKey struct {
+	format string
+	// key ist der Wert für user.signingkey: "key::<öffentlicher Schlüssel>",
+	// ein Pfad zur .pub-Datei oder eine GPG-Schlüssel-ID.
+	key string
+}
+
+// String kürzt den Schlüssel für Checkliste und Protokoll.
+func (k signingKey) String() string {
+	key := strings.TrimPrefix(k.key, "key::")
+	if fields := strings.Fields(key); len(fields) >= 2 {
+		// "ssh-ed25519 AAAA... kommentar" → Typ und Kommentar
+		key = fields[0]
+		if len(fields) > 2 {
+			key += " " + fields[2]
+		}
+	}
+	return k.format + ": " + key
+}
+
+// parseSigningKey ordnet Profile.SigningKey ein: öffentliche SSH-Schlüssel und
+// .pub-Dateien sind "ssh", alles andere eine GPG-Schlüssel-ID.
+func parseSigningKey(value string) signingKey {
+	switch {
+	case strings.HasPrefix(value, "ssh-") || strings.HasPrefix(value, "ecdsa-") || strings.HasPrefix(value, "sk-"):
+		return signingKey{format: "ssh", key: "key::" + value}
+	case strings.HasPrefix(value, "key::"):
+		return signingKey{format: "ssh", key: value}
+	case strings.HasSuffix(value, ".pub"):
+		return signingKey{format: "ssh", key: value}
+	}
+	return signingKey{format: "openpgp", key: value}
+}
+
+// signingKey liefert den Schlüssel für signierte Commits.
+func (ps *ProjectSetup) signingKey() (signingKey, error) {
+	if value := ps.Profile().SigningKey; value != "" {
+		key := parseSigningKey(expandHome(value))
+		if key.format == "ssh" && !strings.HasPrefix(key.key, "key::") {
+			if _, err := os.Stat(key.key); err != nil {
+				return key, fmt.Errorf("signaturschlüssel %s nicht lesbar: %v", key.key, err)
+			}
+		}
+		return key, nil
+	}
+	if out, err := exec.Command("ssh-add", "-L").Output(); err == nil {
+		if line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n"); line != "" {
+			return signingKey{format: "ssh", key: "key::" + strings.TrimSpace(line)}, nil
+		}
+	}
+	if email := ps.Profile().GitEmail; email != "" {
+		if fpr := gpgFingerprint(email); fpr != "" {
+			return signingKey{format: "openpgp", key: fpr}, nil
+		}
+	}
+	return signingKey{}, errors.New("kein signaturschlüssel: ssh-schlüssel mit ssh-add laden oder signingKey im profil setzen")
+}
+
+// gpgFingerprint sucht den ersten geheimen GPG-Schlüssel zur E-Mail-Adresse.
+func gpgFingerprint(email string) string {
+	out, err := exec.Command("gpg", "--batch", "--with-colons", "--list-secret-keys", "<"+email+">").Output()
+	if err != nil {
+		return ""
+	}
+	// Auf "sec" (Hauptschlüssel) folgt dessen "fpr"-Zeile mit dem Fingerabdruck in Feld 10
+	primary := false
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Split(line, ":")
+		switch {
+		case fields[0] == "sec":
+			primary = true
+		case fields[0] == "fpr" && primary && len(fields) > 9:
+			return fields[9]
+		default:
+			primary = false
+		}
+	}
+	return ""
+}
+
+// checkSigning prüft vorab, ob ein Schlüssel gefunden wird und das Werkzeug
+// zum Signieren installiert ist.
+func (ps *ProjectSetup) checkSigning() PreflightCheck {
+	c := PreflightCheck{Name: "Commit signing"}
+	key, err := ps.signingKey()
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	c.Detail = key.String()
+	tool := "ssh-keygen"
+	if key.format == "openpgp" {
+		tool = "gpg"
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		c.Err = fmt.Errorf("%w: %s zum signieren nicht gefunden", errToolchainMissing, tool)
+	}
+	return c
+}
+
+// signingConfig sind die git-config-Aufrufe für signierte Commits.
+func (ps *ProjectSetup) signingConfig() ([][]string, error) {
+	key, err := ps.signingKey()
+	if err != nil {
+		return nil, err
+	}
+	ps.step("Signiere Commits mit " + key.String())
+	return [][]string{
+		{"git", "config", "gpg.format", key.format},
+		{"git", "config", "user.signingkey", key.key},
+		{"git", "config", "commit.gpgsign", "true"},
+	}, nil
+}
+
+// expandHome ersetzt ein führendes ~/ durch das Home-Verzeichnis.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
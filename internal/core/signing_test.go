@@ -0,0 +1,25 @@
+package core
+
+import "testing"
+
+func TestParseSigningKey(t *testing.T) {
+	tests := []struct {
+		value  string
+		format string
+		key    string
+	}{
+		{"ssh-ed25519 AAAAC3Nza test@host", "ssh", "key::ssh-ed25519 AAAAC3Nza test@host"},
+		{"ecdsa-sha2-nistp256 AAAAE2Vj", "ssh", "key::ecdsa-sha2-nistp256 AAAAE2Vj"},
+		{"sk-ssh-ed25519@openssh.com AAAAGnNr", "ssh", "key::sk-ssh-ed25519@openssh.com AAAAGnNr"},
+		{"key::ssh-rsa AAAAB3", "ssh", "key::ssh-rsa AAAAB3"},
+		{"/home/me/.ssh/id_ed25519.pub", "ssh", "/home/me/.ssh/id_ed25519.pub"},
+		{"3AA5C34371567BD2", "openpgp", "3AA5C34371567BD2"},
+		{"me@example.com", "openpgp", "me@example.com"},
+	}
+	for _, tt := range tests {
+		got := parseSigningKey(tt.value)
+		if got.format != tt.format || got.key != tt.key {
+			t.Errorf("parseSigningKey(%q) = %+v, erwartet %s %s", tt.value, got, tt.format, tt.key)
+		}
+	}
+}
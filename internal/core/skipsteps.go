@@ -0,0 +1,56 @@
+package core
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// Schritte überspringen: In der Checkliste vor der Erstellung lassen sich
+// einzelne Schritte für diesen einen Lauf abwählen, ohne die gespeicherten
+// Vorgaben zu ändern. Ohne Installation bleiben Paketlisten, die newpipi
+// selbst schreibt (requirements.txt), stehen, damit sie sich nachholen lassen.
+// Die CI-Dateien der Richtlinie bleiben vorgeschrieben.
+
+// StepSkips sind die Schritte, die ein Lauf überspringt.
+type StepSkips struct {
+	Install  bool
+	Git      bool
+	Terminal bool
+	CI       bool
+}
+
+// Skips liefert die abgewählten Schritte des nächsten Laufs.
+func (ps *ProjectSetup) Skips() StepSkips {
+	return StepSkips{Install: ps.skipInstall, Git: ps.SkipGit, Terminal: ps.skipTerminal, CI: ps.skipCI}
+}
+
+// ApplySkips wählt die Schritte für einen Lauf ab; restore stellt danach die
+// vorherigen Werte wieder her.
+func (ps *ProjectSetup) ApplySkips(s StepSkips) (restore func()) {
+	old := ps.Skips()
+	ps.skipInstall, ps.SkipGit, ps.skipTerminal, ps.skipCI = s.Install, s.Git, s.Terminal, s.CI
+	return func() {
+		ps.skipInstall, ps.SkipGit, ps.skipTerminal, ps.skipCI = old.Install, old.Git, old.Terminal, old.CI
+	}
+}
+
+// withoutInstall lässt die Befehle weg, die Abhängigkeiten installieren.
+func withoutInstall(commands []TemplateCommand) []TemplateCommand {
+	return slices.DeleteFunc(slices.Clone(commands), func(c TemplateCommand) bool { return c.Install })
+}
+
+// ciDirs enthalten die Pipelines von GitHub, Forgejo, Gitea und Woodpecker.
+var ciDirs = []string{".github/workflows", ".forgejo/workflows", ".gitea/workflows", ".woodpecker"}
+
+// ciFiles sind einzelne CI-Konfigurationen im Projektverzeichnis.
+var ciFiles = []string{".gitlab-ci.yml", ".woodpecker.yml", ".travis.yml", "azure-pipelines.yml", "Jenkinsfile"}
+
+// isCIFile meldet, ob file (relativ zum Projekt, mit /) eine CI-Konfiguration ist.
+func isCIFile(file string) bool {
+	file = path.Clean(file)
+	if slices.Contains(ciFiles, file) {
+		return true
+	}
+	return slices.ContainsFunc(ciDirs, func(dir string) bool { return strings.HasPrefix(file, dir+"/") })
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestIsCIFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{".github/workflows/ci.yml", true},
+		{".gitlab-ci.yml", true},
+		{".forgejo/workflows/test.yaml", true},
+		{"./Jenkinsfile", true},
+		{".github/dependabot.yml", false},
+		{"docs/.gitlab-ci.yml", false},
+		{"workflows/ci.yml", false},
+	}
+	for _, tt := range tests {
+		if got := isCIFile(tt.path); got != tt.want {
+			t.Errorf("isCIFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSkipCIFiles(t *testing.T) {
+	tmpl := &Template{
+		Name: "ci",
+		Type: Go,
+		Files: map[string]string{
+			"README.md":                "# demo\n",
+			".github/workflows/ci.yml": "name: CI\n",
+		},
+	}
+	for _, skip := range []bool{false, true} {
+		dir := t.TempDir()
+		ps := &ProjectSetup{ProjectName: "demo", ProjectType: Go, Template: tmpl}
+		restore := ps.ApplySkips(StepSkips{CI: skip})
+		if _, err := ps.writeTemplateFiles(dir); err != nil {
+			t.Fatal(err)
+		}
+		restore()
+		if ps.skipCI {
+			t.Error("skipCI nach dem lauf noch gesetzt")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "README.md")); err != nil {
+			t.Errorf("skip %v: README.md fehlt", skip)
+		}
+		_, err := os.Stat(filepath.Join(dir, ".github/workflows/ci.yml"))
+		if exists := err == nil; exists == skip {
+			t.Errorf("skip %v: ci.yml vorhanden %v", skip, exists)
+		}
+	}
+}
+
+func TestWithoutInstall(t *testing.T) {
+	wails := languages[Go].with(&languages[Go].Toolkits[2])
+	vite := languages[JavaScript].with(&languages[JavaScript].Toolkits[1])
+	tests := []struct {
+		name     string
+		commands []TemplateCommand
+	}{
+		{"Go", languages[Go].Commands},
+		{"Wails", wails.Commands},
+		{"Python", languages[Python].Commands},
+		{"Rust", languages[Rust].Commands},
+		{"JavaScript", languages[JavaScript].Commands},
+		{"Vite", vite.Commands},
+		{"TypeScript", languages[TypeScript].Commands},
+		{"TypeScript App", typeScriptTemplates[0].Commands},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.ContainsFunc(tt.commands, func(c TemplateCommand) bool { return c.Install }) {
+				t.Fatal("kein installationsbefehl markiert")
+			}
+			for _, c := range withoutInstall(tt.commands) {
+				if c.Install || slices.Contains(c.Run, "install") || slices.Contains(c.Run, "tidy") || slices.Contains(c.Run, "{packages}") {
+					t.Errorf("installationsbefehl nicht entfernt: %v", c.Run)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Snippet ist ein wiederverwendbarer Dateibaustein, der unabhängig vom Template
+// in neue oder bestehende Projekte eingefügt werden kann.
+type Snippet struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Types schränkt das Snippet auf Projekttypen ein; leer heißt für alle.
+	Types []ProjectType     `json:"types,omitempty"`
+	Files map[string]string `json:"files"`
+}
+
+var snippets = []Snippet{
+	{
+		Name:        "EditorConfig",
+		Description: "Einheitliche Einrückung und Zeilenenden",
+		Files: map[string]string{
+			".editorconfig": "root = true\n\n[*]\nend_of_line = lf\ninsert_final_newline = true\ncharset = utf-8\n",
+		},
+	},
+	{
+		Name:        "Makefile",
+		Description: "Build, Test und Run als make-Ziele",
+		Types:       []ProjectType{Go},
+		Files: map[string]string{
+			"Makefile": ".PHONY: build test run\n\nbuild:\n\tgo build -o bin/{{.ProjectName}} .\n\ntest:\n\tgo test ./...\n\nrun:\n\tgo run .\n",
+		},
+	},
+	{
+		Name:        "Makefile",
+		Description: "Virtuelle Umgebung, Test und Run als make-Ziele",
+		Types:       []ProjectType{Python},
+		Files: map[string]string{
+			"Makefile": ".PHONY: install test run\n\ninstall:\n\tvenv/bin/pip install -r requirements.txt\n\ntest:\n\tvenv/bin/python -m pytest\n\nrun:\n\tvenv/bin/python main.py\n",
+		},
+	},
+	{
+		Name:        "Dockerfile",
+		Description: "Mehrstufiges Image mit statischem Binary",
+		Types:       []ProjectType{Go},
+		Files: map[string]string{
+			"Dockerfile":    "FROM golang:1.23 AS build\nWORKDIR /src\nCOPY . .\nRUN CGO_ENABLED=0 go build -o /{{.ProjectName}} .\n\nFROM gcr.io/distroless/static\nCOPY --from=build /{{.ProjectName}} /{{.ProjectName}}\nENTRYPOINT [\"/{{.ProjectName}}\"]\n",
+			".dockerignore": ".git\nbin/\n",
+		},
+	},
+	{
+		Name:        "Dockerfile",
+		Description: "Schlankes Python-Image",
+		Types:       []ProjectType{Python},
+		Files: map[string]string{
+			"Dockerfile":    "FROM python:3-slim\nWORKDIR /app\nCOPY requirements.txt .\nRUN pip install --no-cache-dir -r requirements.txt\nCOPY . .\nCMD [\"python\", \"main.py\"]\n",
+			".dockerignore": ".git\nvenv/\n__pycache__/\n",
+		},
+	},
+	{
+		Name:        "Logging",
+		Description: "Logging-Konfiguration mit Level aus der Umgebung",
+		Types:       []ProjectType{Python},
+		Files: map[string]string{
+			"logging_setup.py": "import logging\nimport os\n\n\ndef setup_logging():\n    logging.basicConfig(\n        level=os.environ.get(\"LOG_LEVEL\", \"INFO\"),\n        format=\"%(asctime)s %(levelname)s %(name)s: %(message)s\",\n    )\n",
+		},
+	},
+	{
+		Name:        "Config Loader",
+		Description: "JSON-Konfiguration mit Überschreibung per Umgebung",
+		Types:       []ProjectType{Go},
+		Files: map[string]string{
+			"config.go": "package main\n\nimport (\n\t\"encoding/json\"\n\t\"os\"\n)\n\ntype Config struct {\n\tAddr string `json:\"addr\"`\n}\n\nfunc loadConfig(path string) (Config, error) {\n\tcfg := Config{Addr: \":8080\"}\n\tif content, err := os.ReadFile(path); err == nil {\n\t\tif err := json.Unmarshal(content, &cfg); err != nil {\n\t\t\treturn cfg, err\n\t\t}\n\t}\n\tif addr := os.Getenv(\"ADDR\"); addr != \"\" {\n\t\tcfg.Addr = addr\n\t}\n\treturn cfg, nil\n}\n",
+		},
+	},
+}
+
+func (s Snippet) appliesTo(pt ProjectType) bool {
+	return len(s.Types) == 0 || slices.Contains(s.Types, pt)
+}
+
+// SnippetsFor liefert die Snippets, die zum Projekttyp passen.
+func SnippetsFor(pt ProjectType) []Snippet {
+	var out []Snippet
+	for _, s := range snippets {
+		if s.appliesTo(pt) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func FindSnippet(pt ProjectType, name string) *Snippet {
+	for i := range snippets {
+		if snippets[i].appliesTo(pt) && strings.EqualFold(snippets[i].Name, name) {
+			return &snippets[i]
+		}
+	}
+	return nil
+}
+
+func snippetDir() string {
+	dir, err := configDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "snippets")
+}
+
+// LoadUserSnippets ergänzt die eingebauten Snippets um die JSON-Definitionen
+// aus <config>/snippets.
+func LoadUserSnippets() {
+	paths, err := filepath.Glob(filepath.Join(snippetDir(), "*.json"))
+	if err != nil {
+		log.Printf("Snippets suchen fehlgeschlagen: %v", err)
+		return
+	}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Snippet %s lesen fehlgeschlagen: %v", path, err)
+			continue
+		}
+		var s Snippet
+		if err := json.Unmarshal(content, &s); err != nil {
+			log.Printf("Snippet %s parsen fehlgeschlagen: %v", path, err)
+			continue
+		}
+		snippets = append(snippets, s)
+		log.Printf("Snippet geladen: %s", s.Name)
+	}
+}
+
+// projectMarkers ordnet typische Dateien dem Projekttyp zu, der sie anlegt.
+var projectMarkers = []struct {
+	pattern string
+	pt      ProjectType
+}{
+	{"go.mod", Go},
+	{"Cargo.toml", Rust},
+	{"tsconfig.json", TypeScript},
+	{"package.json", JavaScript},
+	{"requirements.txt", Python},
+	{"pyproject.toml", Python},
+	{"CMakeLists.txt", CPlusPlus},
+	{"meson.build", CPlusPlus},
+	{"*.csproj", CSharp},
+	{"*.sln", CSharp},
+	{"*.slnx", CSharp},
+	{"pom.xml", Java},
+	{"src/main/kotlin", Kotlin},
+	{"build.gradle*", Java},
+	{"build.zig", Zig},
+}
+
+// DetectProjectType erkennt den Typ eines bestehenden Projekts an seinen Dateien.
+func DetectProjectType(dir string) (ProjectType, bool) {
+	for _, m := range projectMarkers {
+		if matches, _ := filepath.Glob(filepath.Join(dir, m.pattern)); len(matches) > 0 {
+			return m.pt, true
+		}
+	}
+	return 0, false
+}
+
+// PlanSnippet rendert die Dateien des Snippets und vergleicht sie mit dem
+// Stand in dir.
+func PlanSnippet(dir string, s *Snippet) ([]FilePatch, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]string{"ProjectName": filepath.Base(abs)}
+	files := make(map[string]string)
+	for path, content := range s.Files {
+		tmpl, err := template.New(path).Option("missingkey=error").Parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("snippet %s: %s parsen fehlgeschlagen: %v", s.Name, path, err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, vars); err != nil {
+			return nil, fmt.Errorf("snippet %s: %s rendern fehlgeschlagen: %v", s.Name, path, err)
+		}
+		files[path] = b.String()
+	}
+	return planFiles(dir, files)
+}
+
+// injectSnippet schreibt die Dateien des Snippets nach dir. Bestehende Dateien
+// mit anderem Inhalt werden nicht überschrieben.
+func injectSnippet(dir string, s *Snippet, modes projectModes) error {
+	patches, err := PlanSnippet(dir, s)
+	if err != nil {
+		return err
+	}
+	for _, p := range patches {
+		if p.Conflict() {
+			return fmt.Errorf("snippet %s: %s existiert bereits", s.Name, p.Path)
+		}
+	}
+	return WritePatches(dir, patches, modes)
+}
+
+// confirmPatches fragt auf der Konsole je geänderter Datei mit Diff, ob sie
+// überschrieben werden soll. Mit all werden alle Änderungen übernommen.
+func confirmPatches(patches []FilePatch, all bool) {
+	in := bufio.NewReader(os.Stdin)
+	for i := range patches {
+		p := &patches[i]
+		if !p.Conflict() {
+			continue
+		}
+		if all {
+			p.Accepted = true
+			continue
+		}
+		fmt.Fprint(os.Stderr, p.Diff())
+		fmt.Fprintf(os.Stderr, "%s überschreiben? [j/N] ", p.Path)
+		answer, _ := in.ReadString('\n')
+		a := strings.ToLower(strings.TrimSpace(answer))
+		p.Accepted = a == "j" || a == "y"
+	}
+}
+
+// applySnippet fügt ein gewähltes Snippet in das neue Projekt ein.
+func (ps *ProjectSetup) applySnippet(projectDir, name string) error {
+	s := FindSnippet(ps.ProjectType, name)
+	if s == nil {
+		return fmt.Errorf("snippet nicht gefunden: %s", name)
+	}
+	ps.step(fmt.Sprintf("Füge Snippet %q ein...", s.Name))
+	return injectSnippet(projectDir, s, ps.Settings.Modes())
+}
+
+// setupSnippet definiert `newpipi snippet list|add`.
+func setupSnippet(fs *flag.FlagSet) func(args []string) int {
+	dir := fs.String("dir", ".", "Bestehendes Projektverzeichnis")
+	overwrite := fs.Bool("overwrite", false, "Abweichende bestehende Dateien ohne Rückfrage überschreiben")
+	typeName := fs.String("type", "", "Projekttyp (Standard: anhand der Dateien erkennen)")
+	return func(args []string) int {
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi snippet list | add NAME...")
+			return exitUsage
+		}
+		// Flags dürfen auch nach der Aktion und zwischen den Namen stehen
+		rest, names := args[1:], []string{}
+		for len(rest) > 0 {
+			if err := fs.Parse(rest); err != nil {
+				return exitUsage
+			}
+			if rest = fs.Args(); len(rest) > 0 {
+				names, rest = append(names, rest[0]), rest[1:]
+			}
+		}
+		args = append(args[:1], names...)
+		pt, ok := ParseProjectType(*typeName)
+		if *typeName == "" {
+			pt, ok = DetectProjectType(*dir)
+		}
+		switch args[0] {
+		case "list":
+			list := snippets
+			if ok {
+				list = SnippetsFor(pt)
+			}
+			for _, s := range list {
+				fmt.Printf("%-16s %s\n", s.Name, s.Description)
+			}
+			return ExitOK
+		case "add":
+			if !ok {
+				fmt.Fprintln(os.Stderr, "Fehler: projekttyp nicht erkannt, bitte --type angeben")
+				return exitUsage
+			}
+			if len(args) < 2 {
+				fmt.Fprintln(os.Stderr, "Verwendung: newpipi snippet add NAME...")
+				return exitUsage
+			}
+			for _, name := range args[1:] {
+				s := FindSnippet(pt, name)
+				if s == nil {
+					fmt.Fprintf(os.Stderr, "Fehler: snippet %s für %s nicht gefunden\n", name, pt)
+					return exitUsage
+				}
+				patches, err := PlanSnippet(*dir, s)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+					return exitFailure
+				}
+				confirmPatches(patches, *overwrite)
+				settings, _ := LoadSettings()
+				if err := WritePatches(*dir, patches, settings.Modes()); err != nil {
+					fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+					return exitFailure
+				}
+			}
+			return ExitOK
+		}
+		fmt.Fprintf(os.Stderr, "Unbekannte Aktion: %s\n", args[0])
+		return exitUsage
+	}
+}
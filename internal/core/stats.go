@@ -0,0 +1,174 @@
+package core
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Nutzungsstatistik, die nur lokal im Zustandsverzeichnis liegt: wie oft
+// welcher Projekttyp bzw. welches Template erstellt wurde, wie lange das im
+// Mittel dauerte und wie oft es fehlschlug.
+
+// usageEntry sammelt die Läufe eines Schlüssels (siehe timingKey).
+type usageEntry struct {
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+	// TotalMs ist die Summe der Dauern erfolgreicher Läufe.
+	TotalMs  int64     `json:"totalMs"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+func (e usageEntry) failureRate() float64 {
+	if e.Created+e.Failed == 0 {
+		return 0
+	}
+	return float64(e.Failed) / float64(e.Created+e.Failed)
+}
+
+func (e usageEntry) averageDuration() time.Duration {
+	if e.Created == 0 {
+		return 0
+	}
+	return time.Duration(e.TotalMs/int64(e.Created)) * time.Millisecond
+}
+
+func usageStatsPath() string {
+	dir, err := StateDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "stats.json")
+}
+
+func readUsageStats() map[string]usageEntry {
+	stats := make(map[string]usageEntry)
+	if content, err := os.ReadFile(usageStatsPath()); err == nil {
+		if err := json.Unmarshal(content, &stats); err != nil {
+			log.Printf("Statistik defekt, beginne neu: %v", err)
+			return make(map[string]usageEntry)
+		}
+	}
+	return stats
+}
+
+// recordUsage zählt einen abgeschlossenen Lauf.
+func (ps *ProjectSetup) recordUsage(start time.Time, err error) {
+	path := usageStatsPath()
+	if path == "" {
+		return
+	}
+	stats := readUsageStats()
+	key := ps.timingKey()
+	e := stats[key]
+	if err != nil {
+		e.Failed++
+	} else {
+		e.Created++
+		e.TotalMs += time.Since(start).Milliseconds()
+	}
+	e.LastUsed = time.Now().UTC()
+	stats[key] = e
+
+	content, err := json.MarshalIndent(stats, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			err = os.WriteFile(path, content, 0644)
+		}
+	}
+	if err != nil {
+		log.Printf("Statistik speichern fehlgeschlagen: %v", err)
+	}
+}
+
+// usageRow ist eine Zeile der Statistikansicht.
+type usageRow struct {
+	Key string
+	usageEntry
+}
+
+// UsageRows liefert die Statistik, die meistgenutzten Einträge zuerst.
+func UsageRows() []usageRow {
+	var rows []usageRow
+	for key, e := range readUsageStats() {
+		rows = append(rows, usageRow{key, e})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		ni, nj := rows[i].Created+rows[i].Failed, rows[j].Created+rows[j].Failed
+		if ni != nj {
+			return ni > nj
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return rows
+}
+
+// Cells formatiert eine Zeile für Tabelle und Konsole.
+func (r usageRow) Cells() []string {
+	avg := "-"
+	if r.Created > 0 {
+		avg = r.averageDuration().Round(100 * time.Millisecond).String()
+	}
+	return []string{
+		r.Key,
+		fmt.Sprint(r.Created),
+		fmt.Sprint(r.Failed),
+		fmt.Sprintf("%.0f%%", r.failureRate()*100),
+		avg,
+	}
+}
+
+var UsageHeader = []string{"Type/Template", "Created", "Failed", "Failure rate", "Avg. time"}
+
+// setupStats definiert `newpipi stats [--reset] [--phases [--top N]]`.
+func setupStats(fs *flag.FlagSet) func(args []string) int {
+	reset := fs.Bool("reset", false, "Statistik löschen")
+	phases := fs.Bool("phases", false, "Langsamste Phasen mit Netzwerk- und Rechenzeit anzeigen")
+	top := fs.Int("top", 10, "Anzahl der Phasen mit --phases")
+	return func(args []string) int {
+		if *reset {
+			if err := os.Remove(usageStatsPath()); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			return ExitOK
+		}
+		if *phases {
+			rows := PhaseRows(ReadTimings())
+			if len(rows) == 0 {
+				fmt.Println("Noch keine Zeitmessungen")
+				return ExitOK
+			}
+			format := "%-28s %-16s %10s %10s %10s %5s\n"
+			fmt.Printf(format, toAny(PhaseHeader)...)
+			for _, r := range rows[:min(*top, len(rows))] {
+				fmt.Printf(format, toAny(r.Cells())...)
+			}
+			return ExitOK
+		}
+		rows := UsageRows()
+		if len(rows) == 0 {
+			fmt.Println("Noch keine Projekte erstellt")
+			return ExitOK
+		}
+		format := "%-28s %8s %8s %13s %10s\n"
+		fmt.Printf(format, toAny(UsageHeader)...)
+		for _, r := range rows {
+			fmt.Printf(format, toAny(r.Cells())...)
+		}
+		return ExitOK
+	}
+}
+
+func toAny(values []string) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
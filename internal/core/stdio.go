@@ -0,0 +1,186 @@
+package core
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Protokoll für Editor-Plugins (`newpipi --stdio`): je Zeile ein JSON-Objekt.
+// Anfragen auf stdin:
+//
+//	{"id": 1, "method": "hello", "params": {"client": "vscode", "protocol": 1}}
+//
+// Antworten auf stdout tragen dieselbe id und entweder "result" oder "error".
+// Während "create" laufen Ereignisse ohne result voraus:
+//
+//	{"id": 2, "event": "progress", "message": "...", "fraction": 0.4}
+//	{"id": 2, "result": {"path": "/home/me/code/demo"}}
+//
+// Anfragen werden nacheinander bearbeitet. Eine Referenz-Implementierung
+// liegt in editor/newpipi-client.js.
+
+// stdioProtocol ist die Version des Protokolls; sie ändert sich nur bei
+// inkompatiblen Änderungen.
+const stdioProtocol = 1
+
+type stdioRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+type stdioResponse struct {
+	ID       json.RawMessage `json:"id"`
+	Result   any             `json:"result,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Event    string          `json:"event,omitempty"`
+	Message  string          `json:"message,omitempty"`
+	Fraction float64         `json:"fraction,omitempty"`
+}
+
+// stdioTemplate beschreibt ein Template für die Auswahl im Editor.
+type stdioTemplate struct {
+	Name        string           `json:"name"`
+	Type        ProjectType      `json:"type"`
+	Description string           `json:"description"`
+	Options     []TemplateOption `json:"options,omitempty"`
+	// Origin ist die Herkunft: user, team, source, system; leer für eingebaute.
+	Origin string `json:"origin,omitempty"`
+}
+
+// runStdio bearbeitet Anfragen, bis stdin geschlossen wird.
+func runStdio(in io.Reader, out io.Writer) int {
+	enc := json.NewEncoder(out)
+	send := func(r stdioResponse) {
+		if err := enc.Encode(r); err != nil {
+			log.Printf("Antwort schreiben fehlgeschlagen: %v", err)
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var req stdioRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			send(stdioResponse{ID: json.RawMessage("null"), Error: fmt.Sprintf("ungültige anfrage: %v", err)})
+			continue
+		}
+		result, err := handleStdio(req, func(message string, fraction float64) {
+			send(stdioResponse{ID: req.ID, Event: "progress", Message: message, Fraction: fraction})
+		})
+		if err != nil {
+			send(stdioResponse{ID: req.ID, Error: err.Error()})
+			continue
+		}
+		send(stdioResponse{ID: req.ID, Result: result})
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Eingabe lesen fehlgeschlagen: %v", err)
+		return exitFailure
+	}
+	return ExitOK
+}
+
+func handleStdio(req stdioRequest, progress func(message string, fraction float64)) (any, error) {
+	switch req.Method {
+	case "hello":
+		return map[string]any{
+			"protocol": stdioProtocol,
+			"version":  VersionString(),
+			"methods":  []string{"hello", "types", "toolkits", "templates", "validate", "create"},
+		}, nil
+	case "types":
+		return ProjectTypeNames, nil
+	case "toolkits":
+		var params struct {
+			Type string `json:"type"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		pt, ok := ParseProjectType(params.Type)
+		if !ok {
+			return nil, fmt.Errorf("unbekannter projekttyp: %q", params.Type)
+		}
+		names := []string{}
+		if lang := languages[pt]; lang != nil {
+			names = append(names, lang.ToolkitNames()...)
+		}
+		return names, nil
+	case "templates":
+		var params struct {
+			Type string `json:"type"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		list := []stdioTemplate{}
+		for _, t := range ListTemplates(params.Type) {
+			list = append(list, stdioTemplate{t.Name, t.Type, t.Description, t.Options, t.layer})
+		}
+		return list, nil
+	case "validate":
+		var params struct {
+			Name string `json:"name"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		valid, msg := IsValidProjectName(params.Name)
+		return validateResponse{Valid: valid, Message: msg}, nil
+	case "create":
+		var params CreateRequest
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		ps, _, err := params.Prepare()
+		if err != nil {
+			return nil, err
+		}
+		estimate := ps.LoadEstimate()
+		ps.Progress = func(step string) {
+			fraction, _, _ := estimate.At(step, 0)
+			progress(step, fraction)
+		}
+		if err := ps.CreateProject(); err != nil {
+			return nil, err
+		}
+		return map[string]string{"path": filepath.Join(ps.ParentPath, ps.ProjectName)}, nil
+	}
+	return nil, fmt.Errorf("unbekannte methode: %q", req.Method)
+}
+
+// ListTemplates liefert die nicht abstrakten Templates eines Projekttyps,
+// bei leerem Typ alle.
+func ListTemplates(projectType string) []Template {
+	var list []Template
+	for _, t := range allTemplates() {
+		if !t.Abstract && (projectType == "" || t.Type.String() == projectType) {
+			list = append(list, t)
+		}
+	}
+	return list
+}
+
+func decodeParams(raw json.RawMessage, v any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("ungültige parameter: %v", err)
+	}
+	return nil
+}
+
+// startStdio startet das Editor-Protokoll. stdout gehört allein dem
+// Protokoll, Ausgaben aufgerufener Werkzeuge landen auf stderr.
+func startStdio() int {
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	return runStdio(os.Stdin, out)
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// templateIndex speichert die Kopfdaten der Benutzer-Templates je Dateipfad,
+// damit der Start nicht jede Definition lesen und parsen muss.
+type templateIndex map[string]templateIndexEntry
+
+type templateIndexEntry struct {
+	Size        int64       `json:"size"`
+	ModTime     time.Time   `json:"modTime"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Type        ProjectType `json:"type"`
+	Abstract    bool        `json:"abstract,omitempty"`
+	Addon       bool        `json:"addon,omitempty"`
+}
+
+// cacheDir liefert das Cache-Verzeichnis. Es liegt bewusst nicht im
+// Konfigurationsverzeichnis, das per Git synchronisiert werden kann.
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "newpipi"), nil
+}
+
+func templateIndexPath() string {
+	dir, err := cacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "template-index.json")
+}
+
+// readTemplateIndex liest den Index; fehlt er oder ist er defekt, wird neu aufgebaut.
+func readTemplateIndex() templateIndex {
+	index := make(templateIndex)
+	path := templateIndexPath()
+	if path == "" {
+		return index
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return index
+	}
+	if err := json.Unmarshal(content, &index); err != nil {
+		log.Printf("Template-Index defekt, wird neu aufgebaut: %v", err)
+		return make(templateIndex)
+	}
+	return index
+}
+
+func writeTemplateIndex(index templateIndex) {
+	path := templateIndexPath()
+	if path == "" {
+		return
+	}
+	content, err := json.Marshal(index)
+	if err != nil {
+		log.Printf("Template-Index speichern fehlgeschlagen: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Printf("Template-Index speichern fehlgeschlagen: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		log.Printf("Template-Index speichern fehlgeschlagen: %v", err)
+	}
+}
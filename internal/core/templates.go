@@ -0,0 +1,655 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// templateFiles ordnet die Namen aller Benutzer-Templates, auch abstrakter,
+// ihren Dateien zu; darüber werden Basis-Templates und Mixins gefunden.
+var templateFiles = make(map[string]string)
+
+// templateMu schützt das Nachladen von Katalogeinträgen.
+var templateMu sync.Mutex
+
+// systemTemplateDir ist das Verzeichnis der systemweit installierten
+// Templates, NEWPIPI_SYSTEM_TEMPLATE_DIR ersetzt es.
+func systemTemplateDir() string {
+	if dir := os.Getenv("NEWPIPI_SYSTEM_TEMPLATE_DIR"); dir != "" {
+		return dir
+	}
+	return "/usr/share/newpipi/templates"
+}
+
+// Herkunft eines Templates, zugleich die Rangfolge bei gleichen Namen:
+// Benutzer vor Team vor Template-Quellen vor System vor eingebauten.
+const (
+	layerUser   = "user"
+	layerTeam   = "team"
+	layerSource = "source"
+	layerSystem = "system"
+)
+
+// templateLayerLabels sind die Kennzeichen der Herkunft in der Auswahl;
+// eingebaute Templates haben keins.
+var templateLayerLabels = map[string]string{
+	layerUser:   "User",
+	layerTeam:   "Team",
+	layerSource: "Source",
+	layerSystem: "System",
+}
+
+// Label ist der Name samt Herkunft für die Templateauswahl.
+func (t *Template) Label() string {
+	if badge, ok := templateLayerLabels[t.layer]; ok {
+		return t.Name + " [" + badge + "]"
+	}
+	return t.Name
+}
+
+// templateDirPaths liefert die Template-Dateien eines Verzeichnisses.
+func templateDirPaths(dir string) []string {
+	var paths []string
+	for _, ext := range templateExtensions {
+		matches, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+		if err != nil {
+			log.Printf("Templates suchen fehlgeschlagen: %v", err)
+			continue
+		}
+		paths = append(paths, matches...)
+	}
+	return paths
+}
+
+// LoadUserTemplates ergänzt die eingebauten Templates um die Definitionen aus
+// dem Template-Verzeichnis des Benutzers, dem Team-Verzeichnis, den
+// Template-Quellen (siehe templatesources.go) und dem Systemverzeichnis.
+// Gleichnamige Templates verdecken sich in dieser Reihenfolge. Erneutes
+// Aufrufen lädt neu.
+//
+// Gelesen werden nur Name, Typ und Beschreibung, und auch die nur für Dateien,
+// die sich seit dem letzten Start geändert haben (siehe templateIndex). Den
+// vollständigen Inhalt lädt erst ensureLoaded.
+func LoadUserTemplates() {
+	templateFiles = make(map[string]string)
+	settings, err := LoadSettings()
+	if err != nil {
+		log.Printf("Fehler beim Laden der Einstellungen: %v", err)
+	}
+	type layer struct {
+		name  string
+		paths []string
+	}
+	layers := []layer{{name: layerUser}}
+	if dir := settings.userTemplateDir(); dir != "" {
+		layers[0].paths = templateDirPaths(dir)
+	}
+	// Sources zuerst, da sourceTemplatePaths templateOrigins neu anlegt
+	sources := sourceTemplatePaths(settings)
+	if dir := settings.TeamTemplateDir; dir != "" {
+		// Jeder mit Schreibrechten auf der Freigabe kann die Templates ändern;
+		// Befehle laufen daher wie bei Quellen erst nach Bestätigung
+		team := templateDirPaths(dir)
+		for _, path := range team {
+			templateOrigins[path] = dir
+		}
+		layers = append(layers, layer{layerTeam, team})
+	}
+	layers = append(layers, layer{layerSource, sources}, layer{layerSystem, templateDirPaths(systemTemplateDir())})
+
+	var paths []string
+	for _, l := range layers {
+		paths = append(paths, l.paths...)
+	}
+	var loaded, addons []Template
+	index := readTemplateIndex()
+	fresh := make(templateIndex, len(paths))
+	changed := len(index) != len(paths)
+	for _, l := range layers {
+		for _, path := range l.paths {
+			entry, ok := readTemplateEntry(index, path, &changed)
+			if !ok {
+				continue
+			}
+			fresh[path] = entry
+			key := strings.ToLower(entry.Name)
+			if shadowing, ok := templateFiles[key]; ok {
+				log.Printf("Template %s aus %s verdeckt von %s", entry.Name, path, shadowing)
+				continue
+			}
+			templateFiles[key] = path
+			stub := Template{Name: entry.Name, Description: entry.Description, Type: entry.Type, Addon: entry.Addon, path: path, layer: l.name}
+			if entry.Addon {
+				addons = append(addons, stub)
+			} else if !entry.Abstract {
+				loaded = append(loaded, stub)
+			}
+		}
+	}
+	Templates = nil
+	for _, t := range builtinTemplates {
+		if _, ok := templateFiles[strings.ToLower(t.Name)]; !ok {
+			Templates = append(Templates, t)
+		}
+	}
+	Templates = append(Templates, loaded...)
+	// Eigene Add-ons verdecken eingebaute gleichen Namens und Typs, Mixins nicht
+	addonTemplates = nil
+	for _, t := range builtinAddons {
+		if !slices.ContainsFunc(addons, func(a Template) bool { return a.Type == t.Type && strings.EqualFold(a.Name, t.Name) }) {
+			addonTemplates = append(addonTemplates, t)
+		}
+	}
+	addonTemplates = append(addonTemplates, addons...)
+	if changed {
+		writeTemplateIndex(fresh)
+	}
+}
+
+// readTemplateEntry liefert die Kopfdaten eines Templates aus dem Index oder,
+// wenn sich die Datei geändert hat, aus der Datei selbst.
+func readTemplateEntry(index templateIndex, path string, changed *bool) (templateIndexEntry, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Printf("Template %s lesen fehlgeschlagen: %v", path, err)
+		return templateIndexEntry{}, false
+	}
+	entry, ok := index[path]
+	if ok && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+		return entry, true
+	}
+	t, err := readTemplateFile(path)
+	if err != nil {
+		log.Printf("%v", err)
+		return templateIndexEntry{}, false
+	}
+	*changed = true
+	return templateIndexEntry{
+		Size:        info.Size(),
+		ModTime:     info.ModTime(),
+		Name:        t.Name,
+		Description: t.Description,
+		Type:        t.Type,
+		Abstract:    t.Abstract,
+		Addon:       t.Addon,
+	}, true
+}
+
+// templateExtensions sind die Dateiendungen eigener Templates.
+var templateExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// decodeTemplate parst ein Template als JSON, YAML oder TOML je nach Endung.
+// YAML und TOML werden über JSON in Template übertragen, damit für alle
+// Formate dieselben Feldnamen gelten.
+func decodeTemplate(path string, content []byte) (Template, error) {
+	var t Template
+	var raw any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &raw); err != nil {
+			return t, err
+		}
+	case ".toml":
+		var table map[string]any
+		if err := toml.Unmarshal(content, &table); err != nil {
+			return t, err
+		}
+		raw = table
+	default:
+		return t, json.Unmarshal(content, &t)
+	}
+	content, err := json.Marshal(raw)
+	if err != nil {
+		return t, err
+	}
+	return t, json.Unmarshal(content, &t)
+}
+
+// saveUserTemplate legt t als JSON im Template-Verzeichnis ab und liefert den
+// Pfad. Ein gleichnamiges Template wird nicht überschrieben.
+func saveUserTemplate(settings Settings, t *Template, message string) (string, error) {
+	templateDir := settings.userTemplateDir()
+	if templateDir == "" {
+		return "", fmt.Errorf("kein template-verzeichnis")
+	}
+	if err := os.MkdirAll(templateDir, 0755); err != nil {
+		return "", fmt.Errorf("template-verzeichnis erstellen fehlgeschlagen: %v", err)
+	}
+	content, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(templateDir, TemplateFileName(t.Name))
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("template %s existiert bereits: %s", t.Name, path)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("template speichern fehlgeschlagen: %v", err)
+	}
+	autoCommitConfig(message)
+	return path, nil
+}
+
+// readTemplateFile liest und parst eine Template-Datei.
+func readTemplateFile(path string) (Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, fmt.Errorf("template %s lesen fehlgeschlagen: %v", path, err)
+	}
+	t, err := decodeTemplate(path, content)
+	if err != nil {
+		return Template{}, fmt.Errorf("template %s parsen fehlgeschlagen: %v", path, err)
+	}
+	t.checksum = fmt.Sprintf("%x", sha256.Sum256(content))
+	// Asset-Pfade beziehen sich auf das Verzeichnis der Template-Datei
+	for dest, src := range t.Assets {
+		if strings.HasPrefix(src, assetBase64Prefix) {
+			continue
+		}
+		if t.Source != "" {
+			return Template{}, fmt.Errorf("template %s: assets aus dem marktplatz nur als base64", t.Name)
+		}
+		if !filepath.IsLocal(src) {
+			return Template{}, fmt.Errorf("template %s: asset %s liegt außerhalb von assets/", t.Name, src)
+		}
+		t.Assets[dest] = filepath.Join(filepath.Dir(path), "assets", src)
+	}
+	// Templates aus Quellen brauchen wie die aus dem Marktplatz eine Bestätigung
+	if origin, ok := templateOrigins[path]; ok {
+		t.Source = origin
+	}
+	return t, nil
+}
+
+// ensureLoaded lädt einen Katalogeintrag vollständig und setzt ihn aus Basis
+// und Mixins zusammen. Das Ergebnis ersetzt den Eintrag, geladen wird also nur einmal.
+func (t *Template) ensureLoaded() error {
+	templateMu.Lock()
+	defer templateMu.Unlock()
+	if t.path == "" {
+		return nil
+	}
+	full, err := readTemplateFile(t.path)
+	if err != nil {
+		return err
+	}
+	resolved, err := resolveTemplate(full, lookupTemplate, nil)
+	if err != nil {
+		return fmt.Errorf("template %s zusammensetzen fehlgeschlagen: %v", t.Name, err)
+	}
+	layer := t.layer
+	*t = resolved
+	t.layer = layer
+	log.Printf("Template geladen: %s (%s)", t.Name, t.Type)
+	return nil
+}
+
+// allTemplates lädt den ganzen Katalog; nur für Stellen, die alle Inhalte brauchen.
+func allTemplates() []Template {
+	var out []Template
+	for i := range Templates {
+		if err := Templates[i].ensureLoaded(); err != nil {
+			log.Printf("%v", err)
+			continue
+		}
+		out = append(out, Templates[i])
+	}
+	return out
+}
+
+// lookupTemplate findet Basis-Templates und Mixins über ihren Namen, auch die
+// eingebauten. Es gilt die Rangfolge aus LoadUserTemplates.
+func lookupTemplate(name string) (Template, error) {
+	if path, ok := templateFiles[strings.ToLower(name)]; ok {
+		return readTemplateFile(path)
+	}
+	for _, t := range builtinTemplates {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+	return Template{}, fmt.Errorf("template %s nicht gefunden", name)
+}
+
+// resolveTemplate setzt ein Template aus seiner Basis (Extends), seinen Mixins
+// und den eigenen Angaben zusammen; spätere Teile überschreiben frühere.
+func resolveTemplate(t Template, lookup func(name string) (Template, error), chain []string) (Template, error) {
+	if slices.Contains(chain, strings.ToLower(t.Name)) {
+		return Template{}, fmt.Errorf("zyklische vererbung: %s -> %s", strings.Join(chain, " -> "), t.Name)
+	}
+	chain = append(chain, strings.ToLower(t.Name))
+
+	var result Template
+	parts := t.Mixins
+	if t.Extends != "" {
+		parts = append([]string{t.Extends}, parts...)
+	}
+	for _, name := range parts {
+		part, err := lookup(name)
+		if err != nil {
+			return Template{}, err
+		}
+		part, err = resolveTemplate(part, lookup, chain)
+		if err != nil {
+			return Template{}, err
+		}
+		result.merge(part)
+	}
+	result.merge(t)
+	result.Name, result.Type, result.Abstract, result.Addon = t.Name, t.Type, t.Abstract, t.Addon
+	result.Version, result.Changelog = t.Version, t.Changelog
+	result.Extends, result.Mixins = t.Extends, t.Mixins
+	result.checksum, result.SourceSHA256 = t.checksum, t.SourceSHA256
+	return result, nil
+}
+
+// merge legt src über t: Dateien und Optionen gleichen Namens werden ersetzt,
+// Pakete und bedingte Blöcke ergänzt.
+func (t *Template) merge(src Template) {
+	if src.Description != "" {
+		t.Description = src.Description
+	}
+	if src.Run != "" {
+		t.Run = src.Run
+	}
+	if src.Check != "" {
+		t.Check = src.Check
+	}
+	if len(src.Files) > 0 && t.Files == nil {
+		t.Files = make(map[string]string)
+	}
+	for path, content := range src.Files {
+		t.Files[path] = content
+	}
+	if len(src.Modes) > 0 && t.Modes == nil {
+		t.Modes = make(map[string]string)
+	}
+	for path, mode := range src.Modes {
+		t.Modes[path] = mode
+	}
+	if len(src.Assets) > 0 && t.Assets == nil {
+		t.Assets = make(map[string]string)
+	}
+	for path, asset := range src.Assets {
+		t.Assets[path] = asset
+	}
+	if len(src.Symlinks) > 0 && t.Symlinks == nil {
+		t.Symlinks = make(map[string]string)
+	}
+	for link, target := range src.Symlinks {
+		t.Symlinks[link] = target
+	}
+	for _, p := range src.Packages {
+		if !slices.Contains(t.Packages, p) {
+			t.Packages = append(t.Packages, p)
+		}
+	}
+	for _, p := range src.LFS {
+		if !slices.Contains(t.LFS, p) {
+			t.LFS = append(t.LFS, p)
+		}
+	}
+	for _, o := range src.Options {
+		i := slices.IndexFunc(t.Options, func(existing TemplateOption) bool { return existing.Name == o.Name })
+		if i >= 0 {
+			t.Options[i] = o
+		} else {
+			t.Options = append(t.Options, o)
+		}
+	}
+	for _, s := range src.Secrets {
+		i := slices.IndexFunc(t.Secrets, func(existing TemplateSecret) bool { return existing.Name == s.Name })
+		if i >= 0 {
+			t.Secrets[i] = s
+		} else {
+			t.Secrets = append(t.Secrets, s)
+		}
+	}
+	for lang, messages := range src.Messages {
+		if t.Messages == nil {
+			t.Messages = make(map[string]map[string]string)
+		}
+		t.Messages[lang] = maps.Clone(t.Messages[lang])
+		if t.Messages[lang] == nil {
+			t.Messages[lang] = make(map[string]string)
+		}
+		maps.Copy(t.Messages[lang], messages)
+	}
+	t.Conditional = append(t.Conditional, src.Conditional...)
+	if src.Script != "" {
+		t.Script = src.Script
+	}
+	t.Render = t.Render || src.Render
+	if src.Generator != "" {
+		t.Generator, t.GeneratorArgs = src.Generator, src.GeneratorArgs
+	}
+	t.Commands = append(t.Commands, src.Commands...)
+	t.Hooks = append(t.Hooks, src.Hooks...)
+	// Ein Teil aus dem Marktplatz macht das ganze Template vertrauenspflichtig
+	if src.Source != "" {
+		t.Source = src.Source
+	}
+}
+
+// TemplateOption ist eine Auswahl, die ein Template beim Erstellen anbietet.
+type TemplateOption struct {
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+	// Type ist "bool", "choice" (mit Choices) oder "string".
+	Type    string   `json:"type"`
+	Choices []string `json:"choices,omitempty"`
+	Default string   `json:"default,omitempty"`
+	// Required verlangt einen Wert; fehlt er, fragen Oberfläche und
+	// Kommandozeile vor der Erstellung danach.
+	Required bool `json:"required,omitempty"`
+}
+
+// TemplateSecret ist eine geheime Variable wie ein API-Schlüssel. Sie steht
+// mit Platzhalter in .env.example, der Wert in .env oder im Schlüsselbund.
+type TemplateSecret struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Placeholder steht in .env.example, Standard ist "changeme".
+	Placeholder string `json:"placeholder,omitempty"`
+}
+
+// ConditionalFiles ergänzt Dateien und Pakete, wenn die Bedingung When erfüllt
+// ist: "with_docker" für eine gesetzte bool-Option, "db=postgres" für einen Wert.
+type ConditionalFiles struct {
+	When     string            `json:"when"`
+	Files    map[string]string `json:"files,omitempty"`
+	Packages []string          `json:"packages,omitempty"`
+}
+
+func (o TemplateOption) DisplayLabel() string {
+	if o.Label != "" {
+		return o.Label
+	}
+	return o.Name
+}
+
+// ResolveOptions ergänzt fehlende Werte um die Standardwerte und prüft die übrigen.
+func (t *Template) ResolveOptions(given map[string]string) (map[string]string, error) {
+	known := make(map[string]bool)
+	values := make(map[string]string)
+	for _, o := range t.Options {
+		known[o.Name] = true
+		value, ok := given[o.Name]
+		if !ok {
+			value = o.Default
+		}
+		switch o.Type {
+		case "bool":
+			if value == "" {
+				value = "false"
+			}
+			if value != "true" && value != "false" {
+				return nil, fmt.Errorf("option %s erwartet true oder false, nicht %q", o.Name, value)
+			}
+		case "choice":
+			if value == "" && len(o.Choices) > 0 {
+				value = o.Choices[0]
+			}
+			if !slices.Contains(o.Choices, value) {
+				return nil, fmt.Errorf("option %s erlaubt nur %s, nicht %q", o.Name, strings.Join(o.Choices, "|"), value)
+			}
+		default:
+			if o.Required && strings.TrimSpace(value) == "" {
+				return nil, fmt.Errorf("option %s braucht einen wert", o.Name)
+			}
+		}
+		values[o.Name] = value
+	}
+	for name := range given {
+		if !known[name] {
+			return nil, fmt.Errorf("template %s kennt keine option %s", t.Name, name)
+		}
+	}
+	return values, nil
+}
+
+// MissingOptions liefert die Pflichtoptionen, für die weder given noch der
+// Standardwert einen Wert hat.
+func (t *Template) MissingOptions(given map[string]string) []TemplateOption {
+	var missing []TemplateOption
+	for _, o := range t.Options {
+		value, ok := given[o.Name]
+		if !ok {
+			value = o.Default
+		}
+		if o.Required && o.Type != "bool" && o.Type != "choice" && strings.TrimSpace(value) == "" {
+			missing = append(missing, o)
+		}
+	}
+	return missing
+}
+
+// conditionMet wertet die When-Bedingung eines ConditionalFiles-Blocks aus.
+func conditionMet(when string, options map[string]string) bool {
+	if name, value, ok := strings.Cut(when, "="); ok {
+		return options[name] == value
+	}
+	return options[when] == "true"
+}
+
+// expand liefert Dateien und Pakete des Templates für die gewählten Optionen.
+func (t *Template) expand(options map[string]string) (map[string]string, []string) {
+	files := make(map[string]string, len(t.Files))
+	for path, content := range t.Files {
+		files[path] = content
+	}
+	packages := slices.Clone(t.Packages)
+	for _, c := range t.Conditional {
+		if !conditionMet(c.When, options) {
+			continue
+		}
+		for path, content := range c.Files {
+			files[path] = content
+		}
+		packages = append(packages, c.Packages...)
+	}
+	return files, packages
+}
+
+// fileMode liefert die Rechte einer Template-Datei: aus Modes, sonst die von
+// modes, für Skripte mit Shebang zusätzlich ausführbar.
+func (t *Template) fileMode(path, content string, modes projectModes) (os.FileMode, error) {
+	if mode, ok := t.Modes[path]; ok {
+		bits, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || bits > 0o777 {
+			return 0, fmt.Errorf("template %s: ungültige rechte %q für %s", t.Name, mode, path)
+		}
+		return os.FileMode(bits), nil
+	}
+	if strings.HasPrefix(content, "#!") {
+		return modes.executable(), nil
+	}
+	return modes.File, nil
+}
+
+// checkPaths lehnt Zielpfade ab, die aus dem Projekt führen ("../x", "/etc/x").
+func (t *Template) checkPaths() error {
+	paths := slices.Collect(maps.Keys(t.Files))
+	for _, c := range t.Conditional {
+		paths = slices.AppendSeq(paths, maps.Keys(c.Files))
+	}
+	paths = slices.AppendSeq(paths, maps.Keys(t.Assets))
+	paths = slices.AppendSeq(paths, maps.Keys(t.Symlinks))
+	for _, path := range paths {
+		if !filepath.IsLocal(filepath.FromSlash(path)) {
+			return fmt.Errorf("template %s: datei %q liegt außerhalb des projekts", t.Name, path)
+		}
+	}
+	return nil
+}
+
+// writeSymlinks legt die Links des Templates an. Links und Ziele müssen im
+// Projekt bleiben, damit ein Template nichts außerhalb erreichen kann.
+func (t *Template) writeSymlinks(projectDir string, excluded func(string) bool, modes projectModes) error {
+	for link, target := range t.Symlinks {
+		if excluded(link) {
+			continue
+		}
+		if !filepath.IsLocal(link) || filepath.IsAbs(target) || !filepath.IsLocal(filepath.Join(filepath.Dir(link), target)) {
+			return fmt.Errorf("template %s: link %s -> %s zeigt aus dem projekt", t.Name, link, target)
+		}
+		path := filepath.Join(projectDir, link)
+		if err := modes.mkdirAll(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("verzeichnis für %s erstellen fehlgeschlagen: %v", link, err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			return fmt.Errorf("link %s erstellen fehlgeschlagen: %v", link, err)
+		}
+	}
+	return nil
+}
+
+// assetBase64Prefix kennzeichnet eingebettete Assets.
+const assetBase64Prefix = "base64:"
+
+// writeAssets kopiert die Binärdateien des Templates ins Projekt.
+func (t *Template) writeAssets(projectDir string, excluded func(string) bool, modes projectModes) error {
+	for dest, src := range t.Assets {
+		if excluded(dest) {
+			continue
+		}
+		if !filepath.IsLocal(dest) {
+			return fmt.Errorf("template %s: asset %s liegt außerhalb des projekts", t.Name, dest)
+		}
+		var content []byte
+		var err error
+		if data, ok := strings.CutPrefix(src, assetBase64Prefix); ok {
+			content, err = base64.StdEncoding.DecodeString(data)
+		} else {
+			content, err = os.ReadFile(src)
+		}
+		if err != nil {
+			return fmt.Errorf("template %s: asset %s lesen fehlgeschlagen: %v", t.Name, dest, err)
+		}
+		mode, err := t.fileMode(dest, "", modes)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(projectDir, dest)
+		if err := modes.mkdirAll(filepath.Dir(target)); err != nil {
+			return fmt.Errorf("verzeichnis für %s erstellen fehlgeschlagen: %v", dest, err)
+		}
+		if err := os.WriteFile(target, content, mode); err != nil {
+			return fmt.Errorf("asset %s schreiben fehlgeschlagen: %v", dest, err)
+		}
+	}
+	return nil
+}
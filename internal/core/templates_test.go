@@ -0,0 +1,322 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// benchCatalog legt einen Katalog mit n Templates samt Basis-Template an und
+// leitet Konfiguration und Cache in temporäre Verzeichnisse um.
+func benchCatalog(b *testing.B, n int) {
+	b.Helper()
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+	root := b.TempDir()
+	b.Setenv("HOME", root)
+	b.Setenv("XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	b.Setenv("NEWPIPI_CONFIG_DIR", filepath.Join(root, "config"))
+	dir := filepath.Join(root, "templates")
+	b.Setenv("NEWPIPI_TEMPLATE_DIR", dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		b.Fatal(err)
+	}
+
+	write := func(t Template) {
+		content, err := json.Marshal(t)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, TemplateFileName(t.Name)), content, 0644); err != nil {
+			b.Fatal(err)
+		}
+	}
+	write(Template{Name: "base", Type: Go, Abstract: true, Files: map[string]string{"README.md": "# {{.ProjectName}}\n"}})
+	for i := range n {
+		write(Template{
+			Name:        fmt.Sprintf("Template %d", i),
+			Description: "Benchmark",
+			Type:        Go,
+			Extends:     "base",
+			Files:       map[string]string{"main.go": "package main\n\nfunc main() {}\n"},
+			Packages:    []string{"github.com/spf13/cobra"},
+		})
+	}
+}
+
+func BenchmarkLoadUserTemplates(b *testing.B) {
+	benchCatalog(b, 500)
+	LoadUserTemplates()
+	b.ResetTimer()
+	for range b.N {
+		LoadUserTemplates()
+	}
+}
+
+func BenchmarkLoadUserTemplatesCold(b *testing.B) {
+	benchCatalog(b, 500)
+	for range b.N {
+		os.Remove(templateIndexPath())
+		LoadUserTemplates()
+	}
+}
+
+func BenchmarkFindTemplate(b *testing.B) {
+	benchCatalog(b, 500)
+	for range b.N {
+		b.StopTimer()
+		LoadUserTemplates()
+		b.StartTimer()
+		if FindTemplate(Go, "Template 250") == nil {
+			b.Fatal("template nicht gefunden")
+		}
+	}
+}
+
+func BenchmarkRenderTemplate(b *testing.B) {
+	t := &Template{
+		Name:    "Render",
+		Type:    Go,
+		Options: []TemplateOption{{Name: "db", Type: "choice", Choices: []string{"sqlite", "postgres"}, Default: "sqlite"}},
+		Script:  "def configure(ctx):\n    return {\"vars\": {\"module\": \"example.com/\" + ctx.name}}\n",
+		Files:   make(map[string]string),
+	}
+	for i := range 20 {
+		t.Files[fmt.Sprintf("pkg%d/file.go", i)] = "// {{.ProjectName}} ({{.module}}) mit {{.db}}\npackage pkg\n"
+	}
+	ps := &ProjectSetup{ProjectName: "demo", ProjectType: Go}
+	b.ResetTimer()
+	for range b.N {
+		options, err := t.ResolveOptions(nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ps.Options = options
+		files, packages := t.expand(options)
+		script, err := t.runScript(ps, packages)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for path, content := range files {
+			if _, err := script.render(path, content); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestTemplateCheckPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl Template
+		ok   bool
+	}{
+		{"relative Pfade", Template{Files: map[string]string{"main.go": "", "cmd/app/main.go": ""}}, true},
+		{"Punkte im Namen", Template{Files: map[string]string{"..env": "", "a/../b.go": ""}}, true},
+		{"Elternverzeichnis", Template{Files: map[string]string{"../evil.sh": ""}}, false},
+		{"absolut", Template{Files: map[string]string{"/etc/profile.d/x.sh": ""}}, false},
+		{"leer", Template{Files: map[string]string{"": ""}}, false},
+		{"bedingte Datei", Template{Conditional: []ConditionalFiles{{Files: map[string]string{"../../.bashrc": ""}}}}, false},
+		{"Asset", Template{Assets: map[string]string{"../icon.png": "base64:"}}, false},
+		{"Link", Template{Symlinks: map[string]string{"a/../../link": "target"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tmpl.Name = tt.name
+			if err := tt.tmpl.checkPaths(); (err == nil) != tt.ok {
+				t.Errorf("checkPaths() = %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeTemplate(t *testing.T) {
+	tests := []struct {
+		path    string
+		content string
+	}{
+		{"cli.json", `{"name": "CLI", "type": "Python", "files": {"src/cli.py": "print()"}, "packages": ["click"], "commands": [{"run": ["ruff", "check"]}]}`},
+		{"cli.yaml", "name: CLI\ntype: Python\nfiles:\n  src/cli.py: print()\npackages: [click]\ncommands:\n  - run: [ruff, check]\n"},
+		{"cli.yml", "name: CLI\ntype: Python\nfiles: {src/cli.py: print()}\npackages:\n  - click\ncommands:\n  - run: [ruff, check]\n"},
+		{"cli.toml", "name = \"CLI\"\ntype = \"Python\"\npackages = [\"click\"]\n\n[files]\n\"src/cli.py\" = \"print()\"\n\n[[commands]]\nrun = [\"ruff\", \"check\"]\n"},
+	}
+	for _, tt := range tests {
+		got, err := decodeTemplate(tt.path, []byte(tt.content))
+		if err != nil {
+			t.Errorf("%s: %v", tt.path, err)
+			continue
+		}
+		if got.Name != "CLI" || got.Type != Python || got.Files["src/cli.py"] != "print()" ||
+			len(got.Packages) != 1 || got.Packages[0] != "click" || len(got.Commands) != 1 || len(got.Commands[0].Run) != 2 {
+			t.Errorf("%s: %+v", tt.path, got)
+		}
+	}
+	if _, err := decodeTemplate("cli.yaml", []byte("modes:\n  run.sh: [0755]\n")); err == nil {
+		t.Error("falscher Typ in YAML nicht gemeldet")
+	}
+}
+
+func TestLoadUserTemplatesPrecedence(t *testing.T) {
+	saved, savedFiles, savedAddons := Templates, templateFiles, addonTemplates
+	t.Cleanup(func() { Templates, templateFiles, addonTemplates = saved, savedFiles, savedAddons })
+
+	user, team, system := t.TempDir(), t.TempDir(), t.TempDir()
+	t.Setenv("NEWPIPI_CONFIG_DIR", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("NEWPIPI_POLICY", filepath.Join(t.TempDir(), "policy.yaml"))
+	t.Setenv("NEWPIPI_TEMPLATE_DIR", user)
+	t.Setenv("NEWPIPI_TEAM_TEMPLATE_DIR", team)
+	t.Setenv("NEWPIPI_SYSTEM_TEMPLATE_DIR", system)
+	write := func(dir, file, content string) {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(user, "a.json", `{"name": "Shared", "type": "Go", "description": "user"}`)
+	write(team, "a.json", `{"name": "shared", "type": "Go", "description": "team"}`)
+	write(team, "b.yaml", "name: Team Only\ntype: Go\n")
+	write(system, "b.json", `{"name": "Team Only", "type": "Go", "description": "system"}`)
+	write(system, "c.json", `{"name": "`+builtinTemplates[0].Name+`", "type": "Go", "description": "system"}`)
+
+	LoadUserTemplates()
+	tests := []struct {
+		name        string
+		layer       string
+		description string
+	}{
+		{"Shared", layerUser, "user"},
+		{"Team Only", layerTeam, ""},
+		{builtinTemplates[0].Name, layerSystem, "system"},
+	}
+	for _, tt := range tests {
+		var found []Template
+		for _, tmpl := range Templates {
+			if strings.EqualFold(tmpl.Name, tt.name) {
+				found = append(found, tmpl)
+			}
+		}
+		if len(found) != 1 {
+			t.Errorf("%s: %d einträge, erwartet 1", tt.name, len(found))
+			continue
+		}
+		if found[0].layer != tt.layer || found[0].Description != tt.description {
+			t.Errorf("%s: herkunft %q, beschreibung %q, erwartet %q, %q", tt.name, found[0].layer, found[0].Description, tt.layer, tt.description)
+		}
+	}
+}
+
+func TestMissingOptions(t *testing.T) {
+	tmpl := &Template{Name: "Vars", Options: []TemplateOption{
+		{Name: "Company", Required: true},
+		{Name: "License", Required: true, Default: "MIT"},
+		{Name: "Docs", Type: "bool", Required: true},
+		{Name: "Note"},
+	}}
+	tests := []struct {
+		given   map[string]string
+		missing []string
+	}{
+		{nil, []string{"Company"}},
+		{map[string]string{"Company": "  "}, []string{"Company"}},
+		{map[string]string{"Company": "ACME", "License": ""}, []string{"License"}},
+		{map[string]string{"Company": "ACME"}, nil},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, o := range tmpl.MissingOptions(tt.given) {
+			names = append(names, o.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.missing, ",") {
+			t.Errorf("missingOptions(%v) = %v, erwartet %v", tt.given, names, tt.missing)
+		}
+		if _, err := tmpl.ResolveOptions(tt.given); (err == nil) != (len(tt.missing) == 0) {
+			t.Errorf("resolveOptions(%v) = %v", tt.given, err)
+		}
+	}
+}
+
+func TestComposeAddons(t *testing.T) {
+	saved := addonTemplates
+	t.Cleanup(func() { addonTemplates = saved })
+	addonTemplates = []Template{
+		{Name: "docker", Type: Go, Addon: true, Files: map[string]string{"Dockerfile": "FROM scratch\n", "README.md": "# docker\n"}},
+		{Name: "lint", Type: Go, Addon: true, Packages: []string{"golang.org/x/lint"}, Hooks: []string{"make lint"}, Source: "https://example.com/lint.json", checksum: "abc"},
+	}
+	base := &Template{Name: "Web", Type: Go, Files: map[string]string{"main.go": "package main\n", "README.md": "# web\n"}, Packages: []string{"github.com/pkg/errors"}}
+
+	tests := []struct {
+		name     string
+		template *Template
+		addons   []string
+		trusted  map[string]string
+		files    []string
+		packages int
+		err      string
+	}{
+		{"Template mit Add-on", base, []string{"+docker"}, nil, []string{"Dockerfile", "README.md", "main.go"}, 1, ""},
+		{"nur Add-ons", nil, []string{"docker"}, nil, []string{"Dockerfile", "README.md"}, 0, ""},
+		{"unbekannt", base, []string{"tests"}, nil, nil, 0, "nicht gefunden"},
+		{"nicht vertraut", base, []string{"lint"}, nil, nil, 0, "lint führt Befehle aus"},
+		{"vertraut", base, []string{"lint", "docker"}, map[string]string{"lint": "abc"}, []string{"Dockerfile", "README.md", "main.go"}, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ps := &ProjectSetup{ProjectType: Go, Template: tt.template, Addons: tt.addons}
+			ps.Settings.TrustedTemplates = tt.trusted
+			got, err := ps.composeAddons()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("composeAddons() = %v, erwartet Fehler mit %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			for path := range got.Files {
+				files = append(files, path)
+			}
+			slices.Sort(files)
+			if !slices.Equal(files, tt.files) || len(got.Packages) != tt.packages || got.NeedsTrust(ps.Settings) {
+				t.Errorf("composeAddons() = %v, %v, erwartet %v mit %d Paketen", files, got.Packages, tt.files, tt.packages)
+			}
+		})
+	}
+	if base.Files["README.md"] != "# web\n" || len(base.Files) != 2 {
+		t.Errorf("Template verändert: %v", base.Files)
+	}
+}
+
+func TestTemplateChanges(t *testing.T) {
+	changelog := map[string]string{"1.0.0": "erste Fassung", "1.1.0": "Dockerfile ergänzt", "1.10.0": "Go 1.23", "2.0.0": "noch nicht veröffentlicht"}
+	before := map[string]Template{
+		"web":  {Name: "Web", Version: "1.0.0", checksum: "a"},
+		"cli":  {Name: "CLI", Version: "0.1", checksum: "b"},
+		"lib":  {Name: "Lib", checksum: "c"},
+		"same": {Name: "Same", Version: "1", checksum: "d"},
+	}
+	after := map[string]Template{
+		"web":  {Name: "Web", Version: "1.10.0", Changelog: changelog, checksum: "a2"},
+		"lib":  {Name: "Lib", checksum: "c2"},
+		"same": {Name: "Same", Version: "1", checksum: "d"},
+		"new":  {Name: "New", Version: "0.1.0", checksum: "e"},
+	}
+	want := []string{
+		"CLI: entfernt",
+		"Lib: geändert, Version (ohne) unverändert",
+		"New: neu (Version 0.1.0)",
+		"Web: 1.0.0 -> 1.10.0",
+		"  1.1.0: Dockerfile ergänzt",
+		"  1.10.0: Go 1.23",
+	}
+	if got := templateChanges(before, after); !slices.Equal(got, want) {
+		t.Errorf("templateChanges() = %q, erwartet %q", got, want)
+	}
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// Gerenderte Dateiinhalte sehen neben den Optionen die Standardvariablen
+// {{.ProjectName}}, {{.Author}} und {{.Year}}.
+//
+// Templates können ein Starlark-Skript enthalten, das eine Funktion
+// configure(ctx) definiert. ctx bietet name, type, profile, options und packages; das
+// Ergebnis ist ein Dict mit den optionalen Schlüsseln
+//
+//	vars     – abgeleitete Variablen für die Dateiinhalte ({{.name}})
+//	exclude  – Dateien oder Verzeichnisse ("docs/"), die nicht erzeugt werden
+//	packages – Paketliste, die die des Templates ersetzt
+
+// Ein Skript aus dem Marktplatz darf die Erstellung nicht aufhängen: Schritte
+// und Laufzeit sind begrenzt.
+const (
+	scriptMaxSteps = 10_000_000
+	scriptTimeout  = 10 * time.Second
+)
+
+// scriptResult ist das ausgewertete Ergebnis von configure(ctx).
+type scriptResult struct {
+	vars     map[string]string
+	exclude  []string
+	packages []string
+	// text übersetzt für {{t "..."}}; ohne bleiben die Texte englisch
+	text func(msg string, args ...any) string
+}
+
+// templateVars sind die Variablen für Dateiinhalte ohne Skript: Projektname,
+// Autor, Jahr und die Optionen.
+func (ps *ProjectSetup) templateVars() map[string]string {
+	vars := map[string]string{
+		"ProjectName": ps.ProjectName,
+		"Author":      ps.author(),
+		"Year":        fmt.Sprint(time.Now().Year()),
+		"Language":    ps.Settings.contentLanguage(),
+	}
+	for name, value := range ps.Options {
+		vars[name] = value
+	}
+	return vars
+}
+
+// runScript führt das Skript des Templates für das aktuelle Projekt aus; packages
+// ist die Paketliste nach Auswertung der Optionen.
+func (t *Template) runScript(ps *ProjectSetup, packages []string) (*scriptResult, error) {
+	result := &scriptResult{vars: ps.templateVars(), packages: packages, text: ps.text}
+	options := starlark.NewDict(len(ps.Options))
+	for name, value := range ps.Options {
+		options.SetKey(starlark.String(name), starlark.String(value))
+	}
+	if t.Script == "" {
+		return result, nil
+	}
+
+	thread := &starlark.Thread{
+		Name:  "template " + t.Name,
+		Print: func(_ *starlark.Thread, msg string) { ps.step(msg) },
+	}
+	thread.SetMaxExecutionSteps(scriptMaxSteps)
+	timer := time.AfterFunc(scriptTimeout, func() { thread.Cancel(fmt.Sprintf("zeitlimit von %v überschritten", scriptTimeout)) })
+	defer timer.Stop()
+	globals, err := starlark.ExecFile(thread, t.Name+".star", t.Script, nil)
+	if err != nil {
+		return nil, fmt.Errorf("template-skript laden fehlgeschlagen: %v", err)
+	}
+	configure, ok := globals["configure"].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("template-skript definiert keine funktion configure(ctx)")
+	}
+
+	pkgs := make([]starlark.Value, len(packages))
+	for i, p := range packages {
+		pkgs[i] = starlark.String(p)
+	}
+	ctx := starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"name":     starlark.String(ps.ProjectName),
+		"type":     starlark.String(ps.ProjectType.String()),
+		"profile":  starlark.String(ps.ProfileName),
+		"options":  options,
+		"packages": starlark.NewList(pkgs),
+	})
+
+	ret, err := starlark.Call(thread, configure, starlark.Tuple{ctx}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("template-skript fehlgeschlagen: %v", err)
+	}
+	if ret == starlark.None {
+		return result, nil
+	}
+	dict, ok := ret.(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("configure(ctx) muss ein dict liefern, nicht %s", ret.Type())
+	}
+
+	if v, found, _ := dict.Get(starlark.String("vars")); found {
+		vars, ok := v.(*starlark.Dict)
+		if !ok {
+			return nil, fmt.Errorf("vars muss ein dict sein")
+		}
+		for _, item := range vars.Items() {
+			result.vars[starlarkString(item[0])] = starlarkString(item[1])
+		}
+	}
+	if v, found, _ := dict.Get(starlark.String("exclude")); found {
+		if result.exclude, err = starlarkStrings(v); err != nil {
+			return nil, fmt.Errorf("exclude: %v", err)
+		}
+	}
+	if v, found, _ := dict.Get(starlark.String("packages")); found {
+		if result.packages, err = starlarkStrings(v); err != nil {
+			return nil, fmt.Errorf("packages: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// author ist der Autor für {{.Author}}: der Git-Name des Profils, sonst der
+// aus der Git-Konfiguration, zuletzt der Benutzername.
+func (ps *ProjectSetup) author() string {
+	if name := ps.Profile().GitName; name != "" {
+		return name
+	}
+	if out, err := exec.Command("git", "config", "user.name").Output(); err == nil {
+		if name := strings.TrimSpace(string(out)); name != "" {
+			return name
+		}
+	}
+	return os.Getenv("USER")
+}
+
+// excluded meldet, ob eine Template-Datei durch das Skript ausgeschlossen wurde.
+func (r *scriptResult) excluded(file string) bool {
+	for _, pattern := range r.exclude {
+		if strings.HasSuffix(pattern, "/") && strings.HasPrefix(file, pattern) {
+			return true
+		}
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// renderFuncs stehen in Dateiinhalten zur Verfügung, etwa {{.name | lower}}.
+var renderFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	// title schreibt wie Pythons str.title jeden Buchstaben nach einem
+	// Nicht-Buchstaben groß: "my-app" wird "My-App"
+	"title": func(s string) string {
+		r := []rune(strings.ToLower(s))
+		for i := range r {
+			if i == 0 || !unicode.IsLetter(r[i-1]) {
+				r[i] = unicode.ToUpper(r[i])
+			}
+		}
+		return string(r)
+	},
+	"trim": strings.TrimSpace,
+	// replace hat den Text als letztes Argument, damit er per Pipe kommt
+	"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	// t übersetzt einen englischen Text in die Sprache erzeugter Inhalte
+	// (siehe contentlang.go), etwa {{t "%s not found" .tool}}
+	"t": func(msg string, args ...any) string { return translate(nil, "en", msg, args...) },
+}
+
+// render setzt die Variablen in den Dateiinhalt ein.
+func (r *scriptResult) render(name, content string) (string, error) {
+	tmpl := template.New(name).Option("missingkey=error").Funcs(renderFuncs)
+	if r.text != nil {
+		tmpl = tmpl.Funcs(template.FuncMap{"t": r.text})
+	}
+	tmpl, err := tmpl.Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("template %s parsen fehlgeschlagen: %v", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, r.vars); err != nil {
+		return "", fmt.Errorf("template %s rendern fehlgeschlagen: %v", name, err)
+	}
+	return buf.String(), nil
+}
+
+func starlarkString(v starlark.Value) string {
+	if s, ok := starlark.AsString(v); ok {
+		return s
+	}
+	return v.String()
+}
+
+func starlarkStrings(v starlark.Value) ([]string, error) {
+	iterable, ok := v.(starlark.Iterable)
+	if !ok {
+		return nil, fmt.Errorf("liste erwartet, nicht %s", v.Type())
+	}
+	var out []string
+	iter := iterable.Iterate()
+	defer iter.Done()
+	var item starlark.Value
+	for iter.Next(&item) {
+		out = append(out, starlarkString(item))
+	}
+	return out, nil
+}
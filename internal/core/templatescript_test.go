@@ -0,0 +1,28 @@
+package core
+
+import "testing"
+
+func TestScriptResultExcluded(t *testing.T) {
+	r := &scriptResult{exclude: []string{"docs/", "*.md", "cmd/*/main.go", "Dockerfile"}}
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"docs/index.md", true},
+		{"docs/img/logo.png", true},
+		{"docs", false},
+		{"mydocs/readme.txt", false},
+		{"README.md", true},
+		{"pkg/README.md", false},
+		{"cmd/server/main.go", true},
+		{"cmd/server/util/main.go", false},
+		{"Dockerfile", true},
+		{"build/Dockerfile", false},
+		{"main.go", false},
+	}
+	for _, tt := range tests {
+		if got := r.excluded(tt.file); got != tt.want {
+			t.Errorf("excluded(%q) = %v, erwartet %v", tt.file, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Template-Quellen sind Git-Repositorys, über die ein Team seine Templates
+// teilt (ähnlich degit): `newpipi source add URL` klont sie flach in den
+// Cache, `newpipi source update` holt den neuesten Stand. Templates im
+// Wurzelverzeichnis und unter templates/ erscheinen neben den eingebauten und
+// eigenen; Befehle führen sie wie Marktplatz-Templates erst nach Bestätigung aus.
+
+// sourceTimeout begrenzt Klonen und Aktualisieren einer Quelle.
+const sourceTimeout = 2 * time.Minute
+
+// templateOrigins ordnet die Dateien aus Template-Quellen deren URL zu.
+var templateOrigins = make(map[string]string)
+
+// templateSource ist ein Eintrag aus Settings.TemplateSources: eine Git-URL,
+// optional mit Branch oder Tag nach "#".
+type templateSource struct {
+	URL string
+	Ref string
+}
+
+// parseTemplateSource prüft eine Quelle; "owner/repo" steht für GitHub.
+func parseTemplateSource(value string) (templateSource, error) {
+	url, ref, _ := strings.Cut(strings.TrimSpace(value), "#")
+	switch {
+	case strings.HasPrefix(url, "https://"), strings.HasPrefix(url, "ssh://"),
+		strings.HasPrefix(url, "git@"), strings.HasPrefix(url, "file://"):
+	case strings.Count(url, "/") == 1 && !strings.ContainsAny(url, ":@") && !strings.HasPrefix(url, ".") && !strings.HasPrefix(url, "/"):
+		url = "https://github.com/" + strings.TrimSuffix(url, ".git") + ".git"
+	default:
+		return templateSource{}, fmt.Errorf("template-quelle %q: nur https-, ssh- oder file-urls und owner/repo", value)
+	}
+	if strings.HasPrefix(ref, "-") {
+		return templateSource{}, fmt.Errorf("template-quelle %q: ungültiger branch %s", value, ref)
+	}
+	return templateSource{URL: url, Ref: ref}, nil
+}
+
+func (s templateSource) String() string {
+	if s.Ref == "" {
+		return s.URL
+	}
+	return s.URL + "#" + s.Ref
+}
+
+// dir liefert das Cache-Verzeichnis der Quelle.
+func (s templateSource) dir() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sources", fmt.Sprintf("%x", sha256.Sum256([]byte(s.String())))[:16]), nil
+}
+
+// sync klont die Quelle oder holt ihren neuesten Stand; lokale Änderungen im
+// Cache gehen dabei verloren.
+func (s templateSource) sync(ctx context.Context) error {
+	dir, err := s.dir()
+	if err != nil {
+		return err
+	}
+	var commands [][]string
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		ref := s.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		commands = [][]string{
+			{"git", "-C", dir, "fetch", "--depth", "1", "origin", ref},
+			{"git", "-C", dir, "reset", "--hard", "FETCH_HEAD"},
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+			return fmt.Errorf("cache-verzeichnis erstellen fehlgeschlagen: %v", err)
+		}
+		clone := []string{"git", "clone", "--quiet", "--depth", "1"}
+		if s.Ref != "" {
+			clone = append(clone, "--branch", s.Ref)
+		}
+		commands = [][]string{append(clone, "--", s.URL, dir)}
+	}
+	for _, args := range commands {
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		// Ohne Terminal nach Zugangsdaten zu fragen, hinge der Aufruf
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("%w: %s: zeitlimit überschritten", errNetwork, s)
+			}
+			return fmt.Errorf("%w: %s: %s", errNetwork, s, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// templatePaths liefert die Template-Dateien der Quelle aus dem Cache.
+func (s templateSource) templatePaths() ([]string, error) {
+	dir, err := s.dir()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("template-quelle %s nicht im cache, newpipi source update ausführen", s)
+	}
+	var paths []string
+	for _, sub := range []string{dir, filepath.Join(dir, "templates")} {
+		for _, ext := range templateExtensions {
+			matches, err := filepath.Glob(filepath.Join(sub, "*"+ext))
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, matches...)
+		}
+	}
+	return paths, nil
+}
+
+// sourceTemplatePaths sammelt die Template-Dateien aller Quellen und merkt
+// sich ihre Herkunft in templateOrigins.
+func sourceTemplatePaths(settings Settings) []string {
+	templateOrigins = make(map[string]string)
+	if err := settings.Policy.remoteTemplates(); err != nil {
+		if len(settings.TemplateSources) > 0 {
+			log.Printf("Template-Quellen ignoriert: %v", err)
+		}
+		return nil
+	}
+	var paths []string
+	for _, value := range settings.TemplateSources {
+		source, err := parseTemplateSource(value)
+		if err != nil {
+			log.Printf("%v", err)
+			continue
+		}
+		found, err := source.templatePaths()
+		if err != nil {
+			log.Printf("%v", err)
+			continue
+		}
+		for _, path := range found {
+			templateOrigins[path] = source.String()
+		}
+		paths = append(paths, found...)
+	}
+	return paths
+}
+
+// setupSource definiert `newpipi source [list | add URL | remove URL | update]`.
+func setupSource(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		settings, err := LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), sourceTimeout)
+		defer cancel()
+
+		switch {
+		case len(args) == 0 || args[0] == "list":
+			for _, value := range settings.TemplateSources {
+				source, err := parseTemplateSource(value)
+				if err != nil {
+					fmt.Printf("%-50s %v\n", value, err)
+					continue
+				}
+				paths, err := source.templatePaths()
+				if err != nil {
+					fmt.Printf("%-50s nicht im cache\n", source)
+					continue
+				}
+				fmt.Printf("%-50s %d Templates\n", source, len(paths))
+			}
+			return ExitOK
+		case (args[0] == "add" || args[0] == "update") && settings.Policy.remoteTemplates() != nil:
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", settings.Policy.remoteTemplates())
+			return exitPolicy
+		case args[0] == "add" && len(args) == 2:
+			source, err := parseTemplateSource(args[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitUsage
+			}
+			if err := source.sync(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return ExitCodeFor(err)
+			}
+			err = UpdateSettingsFile(func(s *Settings) {
+				if !slices.Contains(s.TemplateSources, source.String()) {
+					s.TemplateSources = append(s.TemplateSources, source.String())
+				}
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			autoCommitConfig("Template-Quelle hinzugefügt: " + source.String())
+			paths, _ := source.templatePaths()
+			fmt.Printf("Template-Quelle hinzugefügt: %s (%d Templates)\n", source, len(paths))
+			return ExitOK
+		case args[0] == "remove" && len(args) == 2:
+			source, err := parseTemplateSource(args[1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitUsage
+			}
+			if !slices.Contains(settings.TemplateSources, source.String()) {
+				fmt.Fprintf(os.Stderr, "Fehler: template-quelle %s nicht konfiguriert\n", source)
+				return exitUsage
+			}
+			err = UpdateSettingsFile(func(s *Settings) {
+				s.TemplateSources = slices.DeleteFunc(s.TemplateSources, func(v string) bool { return v == source.String() })
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+			if dir, err := source.dir(); err == nil {
+				os.RemoveAll(dir)
+			}
+			autoCommitConfig("Template-Quelle entfernt: " + source.String())
+			return ExitOK
+		case args[0] == "update" && len(args) == 1:
+			var errs []error
+			for _, value := range settings.TemplateSources {
+				source, err := parseTemplateSource(value)
+				var before map[string]Template
+				if err == nil {
+					before = source.templateVersions()
+					err = source.sync(ctx)
+				}
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+					errs = append(errs, err)
+					continue
+				}
+				fmt.Printf("Aktualisiert: %s\n", source)
+				for _, line := range templateChanges(before, source.templateVersions()) {
+					fmt.Println("  " + line)
+				}
+			}
+			if err := errors.Join(errs...); err != nil {
+				return ExitCodeFor(err)
+			}
+			return ExitOK
+		}
+		fmt.Fprintln(os.Stderr, "Verwendung: newpipi source [list | add URL | remove URL | update]")
+		return exitUsage
+	}
+}
@@ -0,0 +1,34 @@
+package core
+
+import "testing"
+
+func TestParseTemplateSource(t *testing.T) {
+	tests := []struct {
+		value string
+		url   string
+		ref   string
+		ok    bool
+	}{
+		{"https://git.example.com/team/templates.git", "https://git.example.com/team/templates.git", "", true},
+		{"git@github.com:team/templates.git#v2", "git@github.com:team/templates.git", "v2", true},
+		{"ssh://git@example.com/templates", "ssh://git@example.com/templates", "", true},
+		{"file:///srv/templates", "file:///srv/templates", "", true},
+		{"team/templates", "https://github.com/team/templates.git", "", true},
+		{"team/templates.git#main", "https://github.com/team/templates.git", "main", true},
+		{"http://example.com/templates.git", "", "", false},
+		{"ext::sh -c touch% /tmp/pwned", "", "", false},
+		{"../templates", "", "", false},
+		{"/srv/templates", "", "", false},
+		{"team/templates#--upload-pack=evil", "", "", false},
+	}
+	for _, tt := range tests {
+		got, err := parseTemplateSource(tt.value)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseTemplateSource(%q): fehler %v, erwartet ok=%v", tt.value, err, tt.ok)
+			continue
+		}
+		if tt.ok && (got.URL != tt.url || got.Ref != tt.ref) {
+			t.Errorf("parseTemplateSource(%q) = %+v, erwartet %s#%s", tt.value, got, tt.url, tt.ref)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Templates tragen eine Version ("version": "1.2.0") und ein Changelog
+// (Version → Beschreibung). Aktualisiert sich eine Template-Quelle oder ein
+// Marktplatz-Template, zeigt newpipi die Einträge zwischen alter und neuer
+// Version; die verwendete Version steht im Manifest jedes Projekts.
+
+// changesSince liefert die Changelog-Einträge von t, die neuer als from sind,
+// aufsteigend sortiert. Ohne from zählen alle bis zur aktuellen Version.
+func (t *Template) changesSince(from string) []string {
+	var versions []string
+	for v := range t.Changelog {
+		if (from == "" || versionLess(from, v)) && (t.Version == "" || !versionLess(t.Version, v)) {
+			versions = append(versions, v)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versionLess(versions[i], versions[j]) })
+	changes := make([]string, len(versions))
+	for i, v := range versions {
+		changes[i] = v + ": " + t.Changelog[v]
+	}
+	return changes
+}
+
+// templateChanges beschreibt die Unterschiede zweier Stände einer Quelle,
+// jeweils Template-Name (klein geschrieben) → Template, eine Zeile je Änderung.
+func templateChanges(before, after map[string]Template) []string {
+	var keys []string
+	for key := range after {
+		keys = append(keys, key)
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var lines []string
+	for _, key := range keys {
+		old, existed := before[key]
+		t, exists := after[key]
+		switch {
+		case !exists:
+			lines = append(lines, old.Name+": entfernt")
+		case !existed:
+			lines = append(lines, fmt.Sprintf("%s: neu (Version %s)", t.Name, versionOrNone(t.Version)))
+		case old.checksum == t.checksum:
+			// unverändert
+		case old.Version == t.Version:
+			lines = append(lines, fmt.Sprintf("%s: geändert, Version %s unverändert", t.Name, versionOrNone(t.Version)))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %s -> %s", t.Name, versionOrNone(old.Version), versionOrNone(t.Version)))
+			for _, change := range t.changesSince(old.Version) {
+				lines = append(lines, "  "+change)
+			}
+		}
+	}
+	return lines
+}
+
+func versionOrNone(v string) string {
+	if v == "" {
+		return "(ohne)"
+	}
+	return v
+}
+
+// templateVersions liest die Templates der Quelle aus dem Cache, für den
+// Vergleich vor und nach einer Aktualisierung.
+func (s templateSource) templateVersions() map[string]Template {
+	versions := make(map[string]Template)
+	paths, err := s.templatePaths()
+	if err != nil {
+		return versions
+	}
+	for _, path := range paths {
+		t, err := readTemplateFile(path)
+		if err != nil {
+			log.Printf("%v", err)
+			continue
+		}
+		versions[strings.ToLower(t.Name)] = t
+	}
+	return versions
+}
@@ -0,0 +1,242 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Die Schrittdauern erfolgreicher Erstellungen werden je Projekttyp und
+// Template im Zustandsverzeichnis gesammelt. Spätere Läufe schätzen daraus
+// Fortschritt und Restdauer, statt nur einen unbestimmten Balken zu zeigen.
+// Dazu kommen die Phasen mit der Zeit, die ihre Befehle auf das Netz
+// warteten bzw. rechneten; `newpipi stats -phases` zeigt die langsamsten.
+
+// timingHistory ist die Anzahl der Läufe, die je Schlüssel aufbewahrt werden.
+const timingHistory = 5
+
+// timingRun ist ein aufgezeichneter Lauf.
+type timingRun struct {
+	Steps      []stepResult  `json:"steps"`
+	Phases     []phaseTiming `json:"phases,omitempty"`
+	DurationMs int64         `json:"durationMs"`
+}
+
+// phaseTiming ist die Dauer einer Phase (siehe ProjectSetup.phase). NetworkMs
+// ist die Laufzeit ihrer Befehle mit Network, CPUMs die Rechenzeit aller
+// ihrer Befehle samt Unterprozessen.
+type phaseTiming struct {
+	Name       string `json:"name"`
+	DurationMs int64  `json:"durationMs"`
+	NetworkMs  int64  `json:"networkMs,omitempty"`
+	CPUMs      int64  `json:"cpuMs,omitempty"`
+}
+
+// timePhase führt run als Phase name aus und misst sie.
+func (ps *ProjectSetup) timePhase(name string, run func() error) error {
+	t := &phaseTiming{Name: name}
+	outer := ps.activePhase
+	ps.activePhase = t
+	start := time.Now()
+	err := run()
+	t.DurationMs = time.Since(start).Milliseconds()
+	ps.activePhase = outer
+	ps.phaseLog = append(ps.phaseLog, *t)
+	return err
+}
+
+// trackCommand rechnet einen beendeten Befehl der laufenden Phase zu.
+func (ps *ProjectSetup) trackCommand(cmd *exec.Cmd, started time.Time, network bool) {
+	t := ps.activePhase
+	if t == nil {
+		return
+	}
+	if network {
+		t.NetworkMs += time.Since(started).Milliseconds()
+	}
+	if state := cmd.ProcessState; state != nil {
+		t.CPUMs += (state.UserTime() + state.SystemTime()).Milliseconds()
+	}
+}
+
+func timingsPath() string {
+	dir, err := StateDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "timings.json")
+}
+
+func ReadTimings() map[string][]timingRun {
+	timings := make(map[string][]timingRun)
+	content, err := os.ReadFile(timingsPath())
+	if err != nil {
+		return timings
+	}
+	if err := json.Unmarshal(content, &timings); err != nil {
+		return make(map[string][]timingRun)
+	}
+	return timings
+}
+
+// timingKey fasst Läufe zusammen, die vergleichbar lange dauern.
+func (ps *ProjectSetup) timingKey() string {
+	if ps.Template == nil || ps.Template.Name == "" {
+		return ps.ProjectType.String()
+	}
+	return ps.ProjectType.String() + "/" + ps.Template.Name
+}
+
+// recordTimings speichert die Schritte des abgeschlossenen Laufs.
+func (ps *ProjectSetup) recordTimings(start, end time.Time) error {
+	path := timingsPath()
+	if path == "" {
+		return fmt.Errorf("kein zustandsverzeichnis")
+	}
+	timings := ReadTimings()
+	key := ps.timingKey()
+	runs := append(timings[key], timingRun{Steps: ps.stepResults(end), Phases: ps.phaseLog, DurationMs: end.Sub(start).Milliseconds()})
+	if len(runs) > timingHistory {
+		runs = runs[len(runs)-timingHistory:]
+	}
+	timings[key] = runs
+
+	content, err := json.MarshalIndent(timings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// progressEstimate ist der gemittelte Ablauf früherer Läufe.
+type progressEstimate struct {
+	total time.Duration
+	// start und end sind die erwarteten Zeitpunkte je Schritt, gemessen ab Beginn.
+	start map[string]time.Duration
+	end   map[string]time.Duration
+}
+
+// LoadEstimate liefert die Schätzung für ps oder nil, wenn es noch keine Läufe gibt.
+func (ps *ProjectSetup) LoadEstimate() *progressEstimate {
+	runs := ReadTimings()[ps.timingKey()]
+	if len(runs) == 0 {
+		return nil
+	}
+	e := &progressEstimate{start: make(map[string]time.Duration), end: make(map[string]time.Duration)}
+	counts := make(map[string]int)
+	for _, run := range runs {
+		e.total += time.Duration(run.DurationMs) * time.Millisecond
+		var offset time.Duration
+		seen := make(map[string]bool)
+		for _, s := range run.Steps {
+			d := time.Duration(s.DurationMs) * time.Millisecond
+			// Mehrfach gemeldete Schritte zählen nur beim ersten Auftreten
+			if !seen[s.Message] {
+				seen[s.Message] = true
+				e.start[s.Message] += offset
+				e.end[s.Message] += offset + d
+				counts[s.Message]++
+			}
+			offset += d
+		}
+	}
+	e.total /= time.Duration(len(runs))
+	for msg, n := range counts {
+		e.start[msg] /= time.Duration(n)
+		e.end[msg] /= time.Duration(n)
+	}
+	return e
+}
+
+// At schätzt den Fortschritt (0 bis 1) und die Restdauer, wenn step seit
+// elapsed läuft. Unbekannte Schritte liefern ok=false.
+func (e *progressEstimate) At(step string, elapsed time.Duration) (fraction float64, remaining time.Duration, ok bool) {
+	if e == nil || e.total <= 0 {
+		return 0, 0, false
+	}
+	start, known := e.start[step]
+	if !known {
+		return 0, 0, false
+	}
+	// Innerhalb eines Schritts wächst der Fortschritt nur bis zu dessen erwartetem Ende
+	pos := min(start+elapsed, e.end[step])
+	return min(float64(pos)/float64(e.total), 1), max(e.total-pos, 0), true
+}
+
+// FormatETA formatiert eine Restdauer für Statusanzeigen.
+func FormatETA(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("noch ca. %ds", int(d.Round(time.Second).Seconds()))
+	}
+	return fmt.Sprintf("noch ca. %d min", int(d.Round(time.Minute).Minutes()))
+}
+
+// phaseRow ist eine Phase eines Projekttyps bzw. Templates, gemittelt über
+// die aufbewahrten Läufe.
+type phaseRow struct {
+	Key   string
+	Phase string
+	Runs  int
+	// Durchschnitte je Lauf
+	DurationMs, NetworkMs, CPUMs int64
+}
+
+// PhaseRows mittelt die Phasen aller Schlüssel, die langsamsten zuerst.
+func PhaseRows(timings map[string][]timingRun) []phaseRow {
+	var rows []phaseRow
+	for key, runs := range timings {
+		index := make(map[string]int)
+		var keyRows []phaseRow
+		for _, run := range runs {
+			for _, p := range run.Phases {
+				i, ok := index[p.Name]
+				if !ok {
+					i = len(keyRows)
+					index[p.Name] = i
+					keyRows = append(keyRows, phaseRow{Key: key, Phase: p.Name})
+				}
+				r := &keyRows[i]
+				r.Runs++
+				r.DurationMs += p.DurationMs
+				r.NetworkMs += p.NetworkMs
+				r.CPUMs += p.CPUMs
+			}
+		}
+		for _, r := range keyRows {
+			n := int64(r.Runs)
+			r.DurationMs, r.NetworkMs, r.CPUMs = r.DurationMs/n, r.NetworkMs/n, r.CPUMs/n
+			rows = append(rows, r)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].DurationMs != rows[j].DurationMs {
+			return rows[i].DurationMs > rows[j].DurationMs
+		}
+		if rows[i].Key != rows[j].Key {
+			return rows[i].Key < rows[j].Key
+		}
+		return rows[i].Phase < rows[j].Phase
+	})
+	return rows
+}
+
+// Cells formatiert eine Zeile für Tabelle und Konsole.
+func (r phaseRow) Cells() []string {
+	ms := func(v int64) string {
+		d := time.Duration(v) * time.Millisecond
+		if d < time.Second {
+			return d.String()
+		}
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return []string{r.Key, r.Phase, ms(r.DurationMs), ms(r.NetworkMs), ms(r.CPUMs), fmt.Sprint(r.Runs)}
+}
+
+var PhaseHeader = []string{"Type/Template", "Phase", "Avg. time", "Network", "CPU", "Runs"}
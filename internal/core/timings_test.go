@@ -0,0 +1,32 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPhaseRows(t *testing.T) {
+	timings := map[string][]timingRun{
+		"Go": {
+			{Phases: []phaseTiming{{Name: "scaffold", DurationMs: 3000, NetworkMs: 2000, CPUMs: 500}, {Name: "git", DurationMs: 100}}},
+			{Phases: []phaseTiming{{Name: "scaffold", DurationMs: 1000, CPUMs: 300}, {Name: "git", DurationMs: 300, CPUMs: 20}}},
+		},
+		"Python/Flask": {
+			{Phases: []phaseTiming{{Name: "scaffold", DurationMs: 2500}}},
+			// Läufe vor der Zeitmessung der Phasen
+			{},
+		},
+	}
+	got := PhaseRows(timings)
+	want := []phaseRow{
+		{Key: "Python/Flask", Phase: "scaffold", Runs: 1, DurationMs: 2500},
+		{Key: "Go", Phase: "scaffold", Runs: 2, DurationMs: 2000, NetworkMs: 1000, CPUMs: 400},
+		{Key: "Go", Phase: "git", Runs: 2, DurationMs: 200, CPUMs: 10},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("rows = %+v, want %+v", got, want)
+	}
+	if cells := want[1].Cells(); cells[2] != "2s" || cells[3] != "1s" || cells[4] != "400ms" {
+		t.Errorf("cells = %q", cells)
+	}
+}
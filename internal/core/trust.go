@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Templates aus dem Marktplatz können über Hooks beliebige Befehle ausführen.
+// Solche Hooks laufen nur, wenn der Benutzer genau dieser Fassung des
+// Templates vertraut hat (SHA-256 der Datei in Settings.TrustedTemplates), und
+// dann mit bereinigter Umgebung: ohne Tokens und Registry-Zugangsdaten, im
+// Projektverzeichnis und mit Zeitlimit.
+
+// hookTimeout begrenzt die Laufzeit eines einzelnen Hooks.
+const hookTimeout = 5 * time.Minute
+
+// NeedsTrust meldet, ob das Template vor dem Ausführen seiner Hooks bestätigt werden muss.
+func (t *Template) NeedsTrust(settings Settings) bool {
+	return !t.trusted && t.RunsCommands() && t.Source != "" && settings.TrustedTemplates[t.Name] != t.checksum
+}
+
+// TrustSummary beschreibt für die Rückfrage, was das Template ausführen wird.
+func (t *Template) TrustSummary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Template %q von %s führt nach dem Anlegen diese Befehle aus:\n", t.Name, t.Source)
+	if packages := t.allPackages(); len(packages) > 0 {
+		fmt.Fprintf(&b, "  Pakete installieren: %s\n", strings.Join(packages, " "))
+	}
+	if t.Script != "" {
+		b.WriteString("  Pakete laut Skript installieren\n")
+	}
+	if t.Generator != "" {
+		fmt.Fprintf(&b, "  Generator %s: %s\n", t.Generator, strings.Join(t.GeneratorArgs, " "))
+	}
+	for _, c := range t.Commands {
+		fmt.Fprintf(&b, "  %s\n", strings.Join(c.Run, " "))
+	}
+	for _, hook := range t.Hooks {
+		fmt.Fprintf(&b, "  %s\n", hook)
+	}
+	if t.Run != "" {
+		fmt.Fprintf(&b, "  im Terminal: %s\n", t.Run)
+	}
+	return b.String()
+}
+
+// TrustTemplate merkt sich die aktuelle Fassung des Templates als vertrauenswürdig.
+func TrustTemplate(t *Template) error {
+	return UpdateSettingsFile(func(s *Settings) {
+		if s.TrustedTemplates == nil {
+			s.TrustedTemplates = make(map[string]string)
+		}
+		s.TrustedTemplates[t.Name] = t.checksum
+	})
+}
+
+// TrustTemplate vertraut dem gewählten Template oder Add-on t und übernimmt
+// das in die laufenden Einstellungen.
+func (ps *ProjectSetup) TrustTemplate(t *Template) error {
+	if err := TrustTemplate(t); err != nil {
+		return err
+	}
+	if ps.Settings.TrustedTemplates == nil {
+		ps.Settings.TrustedTemplates = make(map[string]string)
+	}
+	ps.Settings.TrustedTemplates[t.Name] = t.checksum
+	return nil
+}
+
+// sandboxEnv ist die Umgebung der Hooks: nur, was Build-Werkzeuge brauchen.
+func sandboxEnv(projectName string) []string {
+	env := []string{"PROJECT_NAME=" + projectName}
+	for _, name := range []string{"PATH", "HOME", "LANG", "TMPDIR"} {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// runHooks führt die Hooks des Templates im Projektverzeichnis aus.
+func (ps *ProjectSetup) runHooks(projectDir string) error {
+	if len(ps.Template.Hooks) == 0 {
+		return nil
+	}
+	if ps.Template.NeedsTrust(ps.Settings) {
+		return fmt.Errorf("%w: %s", errUntrusted, ps.Template.Name)
+	}
+	for _, hook := range ps.Template.Hooks {
+		ps.step("Führe Hook aus: " + hook)
+		ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+		cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+		cmd.Dir = projectDir
+		cmd.Env = sandboxEnv(ps.ProjectName)
+		out, err := cmd.CombinedOutput()
+		cancel()
+		if err != nil {
+			return fmt.Errorf("hook %q fehlgeschlagen: %v: %s", hook, err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package core
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tuiStage beschreibt den aktuellen Schritt des Terminal-Assistenten.
+type tuiStage int
+
+const (
+	stageType tuiStage = iota
+	stageTemplate
+	stageOptions
+	stagePath
+	stageName
+	stageCreating
+	stageDone
+)
+
+// stepMsg meldet einen Arbeitsschritt der laufenden Projekterstellung.
+type stepMsg string
+
+// createDoneMsg wird nach Abschluss der Projekterstellung gesendet.
+type createDoneMsg struct{ err error }
+
+type tuiModel struct {
+	ps        *ProjectSetup
+	stage     tuiStage
+	cursor    int
+	templates []*Template
+	// option ist der Index der gerade abgefragten Template-Option
+	option      int
+	optionInput textinput.Model
+	pathInput   textinput.Model
+	nameInput   textinput.Model
+	nameError   string
+	spinner     spinner.Model
+	steps       []string
+	estimate    *progressEstimate
+	stepStart   time.Time
+	events      chan tea.Msg
+	err         error
+}
+
+// setupTUI startet den Terminal-Assistenten; er kennt keine eigenen Flags.
+func setupTUI(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		if err := runTUI(); err != nil {
+			log.Printf("Terminal-Modus fehlgeschlagen: %v", err)
+			return exitFailure
+		}
+		return ExitOK
+	}
+}
+
+func runTUI() error {
+	ps := NewProjectSetup()
+	ps.skipTerminal = true
+
+	// Log-Ausgaben würden die Oberfläche zerstören, die Schritte erscheinen ohnehin in der TUI
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(io.Discard)
+
+	pathInput := textinput.New()
+	pathInput.Placeholder = "/home/user/projects"
+	pathInput.SetValue(ps.ParentPath)
+
+	nameInput := textinput.New()
+	nameInput.Placeholder = "my-project"
+	nameInput.CharLimit = 255
+
+	m := &tuiModel{
+		ps:          ps,
+		optionInput: textinput.New(),
+		pathInput:   pathInput,
+		nameInput:   nameInput,
+		spinner:     spinner.New(spinner.WithSpinner(spinner.Dot)),
+	}
+	result, err := tea.NewProgram(m).Run()
+	if err != nil {
+		return fmt.Errorf("tui fehlgeschlagen: %v", err)
+	}
+	final := result.(*tuiModel)
+	if final.stage != stageDone {
+		return nil
+	}
+	if final.err != nil {
+		return final.err
+	}
+	fmt.Printf("cd %s\n", filepath.Join(ps.ParentPath, ps.ProjectName))
+	return nil
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC || (msg.Type == tea.KeyEsc && m.stage != stageCreating) {
+			return m, tea.Quit
+		}
+	case stepMsg:
+		m.steps = append(m.steps, string(msg))
+		m.stepStart = time.Now()
+		return m, m.waitForEvent()
+	case createDoneMsg:
+		m.err = msg.err
+		m.stage = stageDone
+		return m, tea.Quit
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	switch m.stage {
+	case stageType:
+		return m, m.updateList(msg, len(ProjectTypeNames), func() {
+			m.ps.ProjectType = ProjectType(m.cursor)
+			m.templates = nil
+			for i := range Templates {
+				if Templates[i].Type == m.ps.ProjectType {
+					m.templates = append(m.templates, &Templates[i])
+				}
+			}
+			m.cursor = 0
+			m.stage = stageTemplate
+		})
+	case stageTemplate:
+		return m, m.updateList(msg, len(m.templates)+1, func() {
+			// Eintrag 0 steht für "kein Template"
+			if m.cursor > 0 {
+				m.ps.Template = FindTemplate(m.ps.ProjectType, m.templates[m.cursor-1].Name)
+			}
+			if m.ps.Template != nil {
+				m.ps.Options, _ = m.ps.Template.ResolveOptions(nil)
+			}
+			m.option = 0
+			m.stage = stageOptions
+			m.showOption()
+		})
+	case stageOptions:
+		o := m.ps.Template.Options[m.option]
+		choices := optionChoices(o)
+		if choices == nil {
+			if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+				// Pflichtoptionen lassen sich nicht leer bestätigen
+				if o.Required && strings.TrimSpace(m.optionInput.Value()) == "" {
+					return m, nil
+				}
+				m.ps.Options[o.Name] = m.optionInput.Value()
+				m.optionInput.Blur()
+				m.option++
+				m.showOption()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.optionInput, cmd = m.optionInput.Update(msg)
+			return m, cmd
+		}
+		return m, m.updateList(msg, len(choices), func() {
+			m.ps.Options[o.Name] = choices[m.cursor]
+			m.option++
+			m.showOption()
+		})
+	case stagePath:
+		if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+			m.ps.ParentPath = strings.TrimSpace(m.pathInput.Value())
+			m.pathInput.Blur()
+			m.stage = stageName
+			return m, m.nameInput.Focus()
+		}
+		var cmd tea.Cmd
+		m.pathInput, cmd = m.pathInput.Update(msg)
+		return m, cmd
+	case stageName:
+		if key, ok := msg.(tea.KeyMsg); ok && key.Type == tea.KeyEnter {
+			valid, errMsg := IsValidProjectName(m.nameInput.Value())
+			if !valid {
+				m.nameError = errMsg
+				return m, nil
+			}
+			m.ps.ProjectName = m.nameInput.Value()
+			m.stage = stageCreating
+			return m, tea.Batch(m.spinner.Tick, m.startCreate())
+		}
+		var cmd tea.Cmd
+		m.nameInput, cmd = m.nameInput.Update(msg)
+		m.nameError = ""
+		return m, cmd
+	}
+	return m, nil
+}
+
+// showOption bereitet die Abfrage der aktuellen Template-Option vor und geht
+// zur Pfadeingabe weiter, wenn alle Optionen beantwortet sind.
+func (m *tuiModel) showOption() {
+	if m.ps.Template == nil || m.option >= len(m.ps.Template.Options) {
+		m.stage = stagePath
+		m.pathInput.Focus()
+		return
+	}
+	o := m.ps.Template.Options[m.option]
+	value := m.ps.Options[o.Name]
+	choices := optionChoices(o)
+	if choices == nil {
+		m.optionInput.SetValue(value)
+		m.optionInput.Focus()
+		return
+	}
+	m.cursor = max(slices.Index(choices, value), 0)
+}
+
+// optionChoices liefert die Auswahlwerte einer Option, nil für freie Texteingabe.
+func optionChoices(o TemplateOption) []string {
+	switch o.Type {
+	case "bool":
+		return []string{"true", "false"}
+	case "choice":
+		return o.Choices
+	}
+	return nil
+}
+
+// updateList bewegt den Cursor einer Auswahlliste und ruft onSelect bei Enter auf.
+func (m *tuiModel) updateList(msg tea.Msg, n int, onSelect func()) tea.Cmd {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch key.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < n-1 {
+			m.cursor++
+		}
+	case "enter":
+		onSelect()
+	}
+	return nil
+}
+
+// startCreate führt die Projekterstellung im Hintergrund aus und leitet
+// Fortschritt und Ergebnis als Nachrichten an das Modell weiter.
+func (m *tuiModel) startCreate() tea.Cmd {
+	m.events = make(chan tea.Msg)
+	m.estimate = m.ps.LoadEstimate()
+	m.ps.Progress = func(step string) {
+		m.events <- stepMsg(step)
+	}
+	go func() {
+		err := m.ps.CreateProject()
+		m.events <- createDoneMsg{err: err}
+	}()
+	return m.waitForEvent()
+}
+
+func (m *tuiModel) waitForEvent() tea.Cmd {
+	return func() tea.Msg {
+		return <-m.events
+	}
+}
+
+func (m *tuiModel) View() string {
+	var b strings.Builder
+	b.WriteString("Project Setup\n\n")
+
+	switch m.stage {
+	case stageType:
+		b.WriteString("Projekttyp wählen:\n")
+		for i, name := range ProjectTypeNames {
+			b.WriteString(listLine(i == m.cursor, name))
+		}
+	case stageTemplate:
+		b.WriteString(fmt.Sprintf("Template für %s wählen:\n", m.ps.ProjectType))
+		b.WriteString(listLine(m.cursor == 0, "(kein Template)"))
+		for i, t := range m.templates {
+			b.WriteString(listLine(m.cursor == i+1, t.Label()+" – "+t.Description))
+		}
+	case stageOptions:
+		o := m.ps.Template.Options[m.option]
+		b.WriteString(o.DisplayLabel() + ":\n")
+		if choices := optionChoices(o); choices != nil {
+			for i, c := range choices {
+				b.WriteString(listLine(i == m.cursor, c))
+			}
+		} else {
+			b.WriteString(m.optionInput.View() + "\n")
+		}
+	case stagePath:
+		b.WriteString("Parent Path:\n" + m.pathInput.View() + "\n")
+	case stageName:
+		b.WriteString("Project Name:\n" + m.nameInput.View() + "\n")
+		if m.nameError != "" {
+			b.WriteString("\n" + m.nameError + "\n")
+		}
+	case stageCreating, stageDone:
+		for _, s := range m.steps {
+			b.WriteString("  ✓ " + s + "\n")
+		}
+		if m.stage == stageCreating {
+			b.WriteString(m.spinner.View() + " Erstelle Projekt..." + m.eta() + "\n")
+		} else if m.err != nil {
+			b.WriteString("\nFehler: " + m.err.Error() + "\n")
+		} else {
+			b.WriteString("\nProjekt erfolgreich erstellt\n")
+		}
+	}
+
+	if m.stage < stageCreating {
+		b.WriteString("\n↑/↓ auswählen • Enter bestätigen • Esc abbrechen\n")
+	}
+	return b.String()
+}
+
+// eta liefert Prozent und Restdauer des laufenden Schritts, sofern frühere
+// Läufe gemessen wurden.
+func (m *tuiModel) eta() string {
+	if len(m.steps) == 0 {
+		return ""
+	}
+	fraction, remaining, ok := m.estimate.At(m.steps[len(m.steps)-1], time.Since(m.stepStart))
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" %d%% (%s)", int(fraction*100), FormatETA(remaining))
+}
+
+func listLine(selected bool, text string) string {
+	if selected {
+		return "> " + text + "\n"
+	}
+	return "  " + text + "\n"
+}
@@ -0,0 +1,146 @@
+package core
+
+// TypeScript-Presets: Das Template "TypeScript App" ersetzt tsconfig.json
+// und package.json des Grundgerüsts. Die Optionen wählen Strenge und
+// Modulauflösung der tsconfig sowie den Bundler; die npm-Skripte build, dev,
+// start und test passen dazu, sodass `npm run dev` sofort läuft.
+
+// typeScriptTemplates sind die eingebauten Templates für TypeScript.
+var typeScriptTemplates = []Template{
+	{
+		Name:        "TypeScript App",
+		Description: "TypeScript mit tsconfig-Preset, Bundler und npm-Skripten",
+		Type:        TypeScript,
+		Options: []TemplateOption{
+			{Name: "strictness", Label: "Type checking", Type: "choice", Choices: []string{"strict", "loose"}},
+			{Name: "module", Label: "Module resolution", Type: "choice", Choices: []string{"nodenext", "bundler"}},
+			{Name: "bundler", Label: "Bundler", Type: "choice", Choices: []string{"tsc", "tsup", "esbuild", "vite"}},
+		},
+		Files: map[string]string{
+			"package.json":      typeScriptPackageJSON,
+			"tsconfig.json":     typeScriptConfig,
+			"src/index.ts":      "import { greet } from \"./greet.js\";\n\nconsole.log(greet(\"TypeScript\"));\n",
+			"src/greet.ts":      "export function greet(name: string): string {\n  return `Hello, ${name}!`;\n}\n",
+			"src/greet.test.ts": typeScriptGreetTest,
+			".gitignore":        "node_modules/\ndist/\n",
+		},
+		Conditional: []ConditionalFiles{
+			{When: "bundler=tsup", Files: map[string]string{"tsup.config.ts": tsupConfig}},
+			{When: "bundler=vite", Files: map[string]string{"vite.config.ts": viteNodeConfig}},
+		},
+		// package.json ist neu geschrieben, die Abhängigkeiten fehlen also noch
+		Commands: []TemplateCommand{
+			{Run: []string{"npm", "install"}, Step: "Installiere Abhängigkeiten...", Network: true, Install: true},
+		},
+		Run:   "npm run dev",
+		Check: "npm run build && npm test",
+	},
+}
+
+const (
+	typeScriptPackageJSON = `{
+  "name": "{{.ProjectName}}",
+  "version": "0.1.0",
+  "private": true,
+  "type": "module",
+  "scripts": {
+{{- if eq .bundler "tsup"}}
+    "build": "tsup",
+    "dev": "tsup --watch --onSuccess \"node dist/index.js\"",
+{{- else if eq .bundler "esbuild"}}
+    "build": "tsc --noEmit && esbuild src/index.ts --bundle --platform=node --format=esm --packages=external --sourcemap --outfile=dist/index.js",
+    "dev": "tsx watch src/index.ts",
+{{- else if eq .bundler "vite"}}
+    "build": "tsc --noEmit && vite build",
+    "dev": "vite-node --watch src/index.ts",
+{{- else}}
+    "build": "tsc",
+    "dev": "tsx watch src/index.ts",
+{{- end}}
+    "start": "node dist/index.js",
+    "test": "vitest run"
+  },
+  "devDependencies": {
+    "@types/node": "^22.0.0",
+{{- if eq .bundler "tsup"}}
+    "tsup": "^8.3.0",
+{{- else if eq .bundler "esbuild"}}
+    "esbuild": "^0.24.0",
+    "tsx": "^4.19.0",
+{{- else if eq .bundler "vite"}}
+    "vite": "^5.4.0",
+    "vite-node": "^2.1.0",
+{{- else}}
+    "tsx": "^4.19.0",
+{{- end}}
+    "typescript": "^5.6.0",
+    "vitest": "^2.1.0"
+  }
+}
+`
+
+	typeScriptConfig = `{
+  "compilerOptions": {
+    "target": "ES2022",
+{{- if eq .module "bundler"}}
+    "module": "ESNext",
+    "moduleResolution": "Bundler",
+{{- else}}
+    "module": "NodeNext",
+    "moduleResolution": "NodeNext",
+{{- end}}
+{{- if eq .strictness "strict"}}
+    "strict": true,
+    "noUncheckedIndexedAccess": true,
+    "noImplicitOverride": true,
+    "noFallthroughCasesInSwitch": true,
+{{- else}}
+    "strict": false,
+    "noImplicitAny": false,
+{{- end}}
+    "esModuleInterop": true,
+    "skipLibCheck": true,
+    "types": ["node"],
+    "rootDir": "src",
+    "outDir": "dist",
+    "declaration": true,
+    "sourceMap": true
+  },
+  "include": ["src"],
+  "exclude": ["src/**/*.test.ts"]
+}
+`
+
+	typeScriptGreetTest = `import { describe, expect, it } from "vitest";
+import { greet } from "./greet.js";
+
+describe("greet", () => {
+  it("greets by name", () => {
+    expect(greet("TypeScript")).toBe("Hello, TypeScript!");
+  });
+});
+`
+
+	tsupConfig = `import { defineConfig } from "tsup";
+
+export default defineConfig({
+  entry: ["src/index.ts"],
+  format: ["esm"],
+  dts: true,
+  sourcemap: true,
+  clean: true,
+});
+`
+
+	// vite baut das Programm als SSR-Bundle für Node statt für den Browser
+	viteNodeConfig = `import { defineConfig } from "vite";
+
+export default defineConfig({
+  build: {
+    ssr: "src/index.ts",
+    outDir: "dist",
+    target: "node20",
+  },
+});
+`
+)
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTypeScriptTemplate(t *testing.T) {
+	tmpl := &typeScriptTemplates[0]
+	for _, bundler := range []string{"tsc", "tsup", "esbuild", "vite"} {
+		for _, module := range []string{"nodenext", "bundler"} {
+			for _, strictness := range []string{"strict", "loose"} {
+				ps := &ProjectSetup{ProjectName: "demo", Template: tmpl}
+				options, err := tmpl.ResolveOptions(map[string]string{"bundler": bundler, "module": module, "strictness": strictness})
+				if err != nil {
+					t.Fatal(err)
+				}
+				ps.Options = options
+				data := &scriptResult{vars: ps.templateVars()}
+				var pkg struct {
+					Scripts         map[string]string `json:"scripts"`
+					DevDependencies map[string]string `json:"devDependencies"`
+				}
+				var config struct {
+					CompilerOptions map[string]any `json:"compilerOptions"`
+				}
+				for path, v := range map[string]any{"package.json": &pkg, "tsconfig.json": &config} {
+					content, err := data.render(path, tmpl.Files[path])
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := json.Unmarshal([]byte(content), v); err != nil {
+						t.Fatalf("%s/%s/%s: %s ungültig: %v\n%s", bundler, module, strictness, path, err, content)
+					}
+				}
+				for _, script := range []string{"build", "dev", "start", "test"} {
+					if pkg.Scripts[script] == "" {
+						t.Errorf("%s: skript %s fehlt", bundler, script)
+					}
+				}
+				if bundler != "tsc" && pkg.DevDependencies[bundler] == "" {
+					t.Errorf("%s: abhängigkeit fehlt: %v", bundler, pkg.DevDependencies)
+				}
+				if got := config.CompilerOptions["strict"]; got != (strictness == "strict") {
+					t.Errorf("%s: strict = %v", strictness, got)
+				}
+				if got := config.CompilerOptions["moduleResolution"]; module == "bundler" && got != "Bundler" || module == "nodenext" && got != "NodeNext" {
+					t.Errorf("%s: moduleResolution = %v", module, got)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// lastCreation merkt sich das zuletzt erstellte Projekt für `newpipi undo`.
+type lastCreation struct {
+	Dir       string    `json:"dir"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func lastCreationPath() string {
+	dir, err := StateDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "last.json")
+}
+
+// recordLastCreation hält das gerade erstellte Projekt fest.
+func recordLastCreation(projectDir string) error {
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(lastCreation{Dir: abs, CreatedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	path := lastCreationPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+func ReadLastCreation() (*lastCreation, error) {
+	content, err := os.ReadFile(lastCreationPath())
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("keine erstellung zum rückgängigmachen")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var last lastCreation
+	if err := json.Unmarshal(content, &last); err != nil {
+		return nil, fmt.Errorf("letzte erstellung nicht lesbar: %v", err)
+	}
+	return &last, nil
+}
+
+// userChanges listet alles, was seit der Erstellung im Projekt hinzugekommen
+// oder verändert worden ist: abweichende und neue Dateien sowie weitere Commits.
+func userChanges(projectDir string) ([]string, error) {
+	changes, err := verifyProject(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	report, err := readReport(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := fileChecksums(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	for path := range files {
+		if _, ok := report.Files[path]; !ok {
+			changes = append(changes, "neu:      "+path)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(projectDir, ".git")); err == nil {
+		out, err := gitOutput(projectDir, "rev-list", "--count", "--all")
+		if err != nil {
+			return nil, err
+		}
+		if n := strings.TrimSpace(out); n != "1" {
+			changes = append(changes, fmt.Sprintf("git:      %s commits statt 1", n))
+		}
+		// Auch ignorierte Dateien wie .env.local oder Zugangsdaten gingen beim
+		// Löschen verloren; nur die Abhängigkeiten im Projektverzeichnis zählen nicht
+		out, err = gitOutput(projectDir, "status", "--porcelain", "--ignored")
+		if err != nil {
+			return nil, err
+		}
+		dirty := false
+		for _, line := range strings.Split(out, "\n") {
+			path, ignored := strings.CutPrefix(line, "!! ")
+			switch {
+			case line == "":
+			case !ignored:
+				dirty = true
+			case dependencyDirs[strings.TrimSuffix(path, "/")]:
+			case files[path] != "":
+			default:
+				changes = append(changes, "ignoriert: "+path)
+			}
+		}
+		if dirty {
+			changes = append(changes, "git:      nicht committete änderungen")
+		}
+	}
+	for _, v := range VcsSystems[1:] {
+		if _, err := os.Stat(filepath.Join(projectDir, v.Dir)); err != nil {
+			continue
+		}
+		commits, dirty, err := vcsHistory(projectDir, v)
+		if err != nil {
+			return nil, err
+		}
+		if commits != 1 {
+			changes = append(changes, fmt.Sprintf("%s:       %d commits statt 1", v.Name, commits))
+		}
+		if dirty {
+			changes = append(changes, v.Name+":       nicht committete änderungen")
+		}
+	}
+	sort.Strings(changes)
+	return changes, nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s fehlgeschlagen: %v", args[0], err)
+	}
+	return string(out), nil
+}
+
+// UndoLastCreation löscht das zuletzt erstellte Projekt, sofern es seither
+// unverändert ist. Zurückgenommen wird nur das lokale Verzeichnis; Projekte
+// aus GitHub-Template-Repositorys (siehe github.go) merkt sich undo nicht, ihr
+// entferntes Repository bleibt bestehen.
+func UndoLastCreation(force bool) (string, error) {
+	last, err := ReadLastCreation()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(last.Dir); err != nil {
+		os.Remove(lastCreationPath())
+		return "", fmt.Errorf("projekt %s existiert nicht mehr", last.Dir)
+	}
+	if !force {
+		changes, err := userChanges(last.Dir)
+		if err != nil {
+			return "", err
+		}
+		if len(changes) > 0 {
+			return "", fmt.Errorf("%s wurde seit der erstellung verändert:\n%s", last.Dir, strings.Join(changes, "\n"))
+		}
+	}
+	if err := os.RemoveAll(last.Dir); err != nil {
+		return "", fmt.Errorf("projekt löschen fehlgeschlagen: %v", err)
+	}
+	if err := os.Remove(lastCreationPath()); err != nil {
+		log.Printf("Letzte Erstellung vergessen fehlgeschlagen: %v", err)
+	}
+	return last.Dir, nil
+}
+
+// setupUndo definiert `newpipi undo [--force]`.
+func setupUndo(fs *flag.FlagSet) func(args []string) int {
+	force := fs.Bool("force", false, "Auch löschen, wenn das Projekt verändert wurde")
+	return func(args []string) int {
+		dir, err := UndoLastCreation(*force)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		fmt.Printf("Gelöscht: %s\n", dir)
+		return ExitOK
+	}
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeTestProject legt ein Projekt mit Erstellungsbericht an.
+func writeTestProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for path, content := range files {
+		writeTestFile(t, filepath.Join(dir, path), content)
+	}
+	sums, err := fileChecksums(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := json.Marshal(creationReport{Files: sums})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, metaDir, reportFile), string(content))
+	return dir
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUserChanges(t *testing.T) {
+	generated := map[string]string{"main.go": "package main\n", "src/build/gen.go": "package build\n"}
+	tests := []struct {
+		name   string
+		change func(t *testing.T, dir string)
+		want   []string
+	}{
+		{"unverändert", func(*testing.T, string) {}, nil},
+		{"geändert", func(t *testing.T, dir string) {
+			writeTestFile(t, filepath.Join(dir, "main.go"), "package other\n")
+		}, []string{"geändert: main.go"}},
+		{"gelöscht", func(t *testing.T, dir string) {
+			os.Remove(filepath.Join(dir, "main.go"))
+		}, []string{"fehlt:    main.go"}},
+		{"neu", func(t *testing.T, dir string) {
+			writeTestFile(t, filepath.Join(dir, "notes.txt"), "x")
+		}, []string{"neu:      notes.txt"}},
+		{"Build-Ausgabe im Projektverzeichnis", func(t *testing.T, dir string) {
+			writeTestFile(t, filepath.Join(dir, "bin", "app"), "x")
+			writeTestFile(t, filepath.Join(dir, "node_modules", "x", "index.js"), "x")
+		}, nil},
+		{"Verzeichnis build tiefer im Projekt", func(t *testing.T, dir string) {
+			writeTestFile(t, filepath.Join(dir, "src", "build", "more.go"), "package build\n")
+		}, []string{"neu:      src/build/more.go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeTestProject(t, generated)
+			tt.change(t, dir)
+			got, err := userChanges(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("userChanges() = %q, erwartet %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserChangesIgnoredFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git nicht installiert")
+	}
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "test")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "test@example.com")
+	}
+	dir := writeTestProject(t, map[string]string{"main.go": "package main\n", ".gitignore": "bin/\n*.local\n"})
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}, {"commit", "-qm", "Initial commit"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", args[0], err, out)
+		}
+	}
+	writeTestFile(t, filepath.Join(dir, "bin", "app"), "x")
+	if got, err := userChanges(dir); err != nil || len(got) != 0 {
+		t.Fatalf("userChanges() mit ignorierter Build-Ausgabe = %q, %v", got, err)
+	}
+	writeTestFile(t, filepath.Join(dir, "secrets.local"), "token")
+	// Links erfasst fileChecksums nicht, wohl aber git status --ignored
+	if err := os.Symlink("/etc/hosts", filepath.Join(dir, "hosts.local")); err != nil {
+		t.Fatal(err)
+	}
+	got, err := userChanges(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"ignoriert: hosts.local", "neu:      secrets.local"}
+	if !slices.Equal(got, want) {
+		t.Errorf("userChanges() = %q, erwartet %q", got, want)
+	}
+}
@@ -0,0 +1,349 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Die Update-Prüfung ist opt-in ("updateCheck": true). Sie vergleicht newpipi
+// selbst mit dem neuesten Release, die installierten Toolchains mit den
+// aktuellen Versionen und Marktplatz-Templates mit ihrer Quelle. Ergebnisse werden im Zustandsverzeichnis zwischengespeichert
+// und höchstens einmal pro UpdateInterval erneuert.
+
+const UpdateInterval = 24 * time.Hour
+
+// UpdateInfo ist ein verfügbares Update.
+type UpdateInfo struct {
+	// Kind ist "app", "toolchain" oder "template".
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Installed string `json:"installed,omitempty"`
+	Latest    string `json:"latest,omitempty"`
+	// URL ist die Download-Seite, wenn das Update von Hand eingespielt wird.
+	URL string `json:"url,omitempty"`
+	// Asset und Checksums sind Binary und SHA256SUMS eines Releases für das
+	// Selbst-Update unter Linux.
+	Asset     string `json:"asset,omitempty"`
+	Checksums string `json:"checksums,omitempty"`
+	// Changes sind die Changelog-Einträge eines Template-Updates.
+	Changes []string `json:"changes,omitempty"`
+}
+
+func (u UpdateInfo) String() string {
+	if u.Installed == "" {
+		return fmt.Sprintf("%s: neue Fassung verfügbar", u.Name)
+	}
+	return fmt.Sprintf("%s: %s -> %s", u.Name, u.Installed, u.Latest)
+}
+
+// Automatic meldet, ob ApplyUpdate das Update selbst einspielen kann.
+func (u UpdateInfo) Automatic() bool {
+	return u.URL == "" || u.Asset != ""
+}
+
+type updateState struct {
+	CheckedAt time.Time    `json:"checkedAt"`
+	Updates   []UpdateInfo `json:"updates"`
+	// Prompted ist die neue Version, für die bereits nachgefragt wurde.
+	Prompted string `json:"prompted,omitempty"`
+}
+
+func updateStatePath() string {
+	dir, err := StateDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "updates.json")
+}
+
+func ReadUpdateState() updateState {
+	var s updateState
+	if content, err := os.ReadFile(updateStatePath()); err == nil {
+		json.Unmarshal(content, &s)
+	}
+	return s
+}
+
+func (s updateState) Save() {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(updateStatePath()), 0755); err == nil {
+			err = os.WriteFile(updateStatePath(), content, 0644)
+		}
+	}
+	if err != nil {
+		log.Printf("Update-Stand speichern fehlgeschlagen: %v", err)
+	}
+}
+
+// CachedUpdates liefert die zuletzt gefundenen Updates und prüft neu, wenn
+// das Ergebnis älter als UpdateInterval ist.
+func CachedUpdates(ctx context.Context, settings Settings) []UpdateInfo {
+	s := ReadUpdateState()
+	if time.Since(s.CheckedAt) < UpdateInterval {
+		return s.Updates
+	}
+	s.CheckedAt, s.Updates = time.Now(), checkUpdates(ctx, settings)
+	s.Save()
+	return s.Updates
+}
+
+// checkUpdates prüft alle Quellen; Fehler einzelner Prüfungen werden nur protokolliert.
+func checkUpdates(ctx context.Context, settings Settings) []UpdateInfo {
+	var updates []UpdateInfo
+	for _, check := range []func(context.Context) (*UpdateInfo, error){checkAppUpdate, checkGoUpdate, checkNodeUpdate, checkRustUpdate} {
+		u, err := check(ctx)
+		if err != nil {
+			log.Printf("Update-Prüfung fehlgeschlagen: %v", err)
+			continue
+		}
+		if u != nil {
+			updates = append(updates, *u)
+		}
+	}
+	return append(updates, checkTemplateUpdates(ctx)...)
+}
+
+// installedVersion liefert die erste Ausgabezeile eines Versionsbefehls oder
+// "", wenn das Werkzeug fehlt.
+func installedVersion(name string, args ...string) string {
+	if _, err := exec.LookPath(name); err != nil {
+		return ""
+	}
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return line
+}
+
+func checkGoUpdate(ctx context.Context) (*UpdateInfo, error) {
+	installed := installedVersion("go", "env", "GOVERSION")
+	if installed == "" {
+		return nil, nil
+	}
+	content, err := fetchURL(ctx, "https://go.dev/VERSION?m=text")
+	if err != nil {
+		return nil, err
+	}
+	latest, _, _ := strings.Cut(strings.TrimSpace(string(content)), "\n")
+	if !versionLess(installed, latest) {
+		return nil, nil
+	}
+	return &UpdateInfo{Kind: "toolchain", Name: "Go", Installed: installed, Latest: latest, URL: "https://go.dev/dl/"}, nil
+}
+
+// checkNodeUpdate vergleicht mit der neuesten LTS-Version; neuere Current-Versionen gelten als aktuell.
+func checkNodeUpdate(ctx context.Context) (*UpdateInfo, error) {
+	installed := installedVersion("node", "--version")
+	if installed == "" {
+		return nil, nil
+	}
+	content, err := fetchURL(ctx, "https://nodejs.org/dist/index.json")
+	if err != nil {
+		return nil, err
+	}
+	var releases []struct {
+		Version string `json:"version"`
+		LTS     any    `json:"lts"`
+	}
+	if err := json.Unmarshal(content, &releases); err != nil {
+		return nil, fmt.Errorf("node-versionen parsen fehlgeschlagen: %v", err)
+	}
+	for _, r := range releases {
+		if lts, ok := r.LTS.(string); ok && lts != "" {
+			if !versionLess(installed, r.Version) {
+				return nil, nil
+			}
+			return &UpdateInfo{Kind: "toolchain", Name: "Node.js", Installed: installed, Latest: r.Version, URL: "https://nodejs.org/"}, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkRustUpdate wertet `rustup check` aus, das selbst die Versionen abfragt.
+func checkRustUpdate(ctx context.Context) (*UpdateInfo, error) {
+	if _, err := exec.LookPath("rustup"); err != nil {
+		return nil, nil
+	}
+	out, err := exec.CommandContext(ctx, "rustup", "check").Output()
+	if err != nil {
+		return nil, fmt.Errorf("rustup check fehlgeschlagen: %v", err)
+	}
+	// stable-x86_64-unknown-linux-gnu - Update available : 1.80.0 (...) -> 1.81.0 (...)
+	for _, line := range strings.Split(string(out), "\n") {
+		_, versions, ok := strings.Cut(line, "Update available :")
+		if !ok {
+			continue
+		}
+		installed, latest, _ := strings.Cut(versions, "->")
+		installed, _, _ = strings.Cut(strings.TrimSpace(installed), " ")
+		latest, _, _ = strings.Cut(strings.TrimSpace(latest), " ")
+		return &UpdateInfo{Kind: "toolchain", Name: "Rust", Installed: installed, Latest: latest}, nil
+	}
+	return nil, nil
+}
+
+// checkTemplateUpdates vergleicht Marktplatz-Templates mit ihrer Quelle.
+func checkTemplateUpdates(ctx context.Context) []UpdateInfo {
+	var updates []UpdateInfo
+	for _, t := range allTemplates() {
+		if t.Source == "" || t.SourceSHA256 == "" {
+			continue
+		}
+		content, err := fetchURL(ctx, t.Source)
+		if err != nil {
+			log.Printf("Update-Prüfung für %s fehlgeschlagen: %v", t.Name, err)
+			continue
+		}
+		if sum := fmt.Sprintf("%x", sha256.Sum256(content)); sum != t.SourceSHA256 {
+			u := UpdateInfo{Kind: "template", Name: t.Name}
+			// Mit Versionen zeigt das Update, was sich geändert hat
+			if latest, err := decodeTemplate(t.Source, content); err == nil && latest.Version != "" && latest.Version != t.Version {
+				u.Installed, u.Latest = versionOrNone(t.Version), latest.Version
+				u.Changes = latest.changesSince(t.Version)
+			}
+			updates = append(updates, u)
+		}
+	}
+	return updates
+}
+
+// ApplyUpdate spielt ein Update ein, das keine Download-Seite braucht.
+// Aktualisierte Templates müssen erneut als vertrauenswürdig bestätigt werden.
+func ApplyUpdate(ctx context.Context, settings Settings, u UpdateInfo) error {
+	switch {
+	case u.Kind == "app" && u.Asset != "":
+		if err := selfReplace(ctx, u); err != nil {
+			return err
+		}
+	case u.Kind == "toolchain" && u.Name == "Rust":
+		if out, err := exec.CommandContext(ctx, "rustup", "update").CombinedOutput(); err != nil {
+			return fmt.Errorf("rustup update fehlgeschlagen: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+	case u.Kind == "template":
+		index, err := FetchMarketIndex(ctx, settings)
+		if err != nil {
+			return err
+		}
+		entry := index.find(u.Name)
+		if entry == nil {
+			return fmt.Errorf("template %s nicht mehr im marktplatz", u.Name)
+		}
+		if _, err := InstallMarketTemplate(ctx, settings, entry); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s muss von %s aktualisiert werden", u.Name, u.URL)
+	}
+
+	// Das eingespielte Update aus dem Zwischenspeicher entfernen
+	s := ReadUpdateState()
+	for i, other := range s.Updates {
+		if other.Kind == u.Kind && other.Name == u.Name {
+			s.Updates = append(s.Updates[:i], s.Updates[i+1:]...)
+			break
+		}
+	}
+	s.Save()
+	return nil
+}
+
+// versionLess vergleicht Versionen wie "go1.22.3" oder "v20.11.0" Teil für Teil numerisch.
+func versionLess(a, b string) bool {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := range max(len(pa), len(pb)) {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			return x < y
+		}
+	}
+	return false
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimLeft(v, "gov")
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		// Zusätze wie "rc1" beenden den Vergleich
+		digits := strings.IndexFunc(p, func(r rune) bool { return r < '0' || r > '9' })
+		if digits == 0 {
+			break
+		}
+		if digits > 0 {
+			p = p[:digits]
+		}
+		n, _ := strconv.Atoi(p)
+		parts = append(parts, n)
+		if digits > 0 {
+			break
+		}
+	}
+	return parts
+}
+
+// setupUpdates definiert `newpipi updates [apply NAME]`.
+func setupUpdates(fs *flag.FlagSet) func(args []string) int {
+	return func(args []string) int {
+		settings, err := LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		if len(args) == 2 && args[0] == "apply" {
+			for _, u := range ReadUpdateState().Updates {
+				if strings.EqualFold(u.Name, args[1]) {
+					if err := ApplyUpdate(ctx, settings, u); err != nil {
+						fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+						return ExitCodeFor(err)
+					}
+					return ExitOK
+				}
+			}
+			fmt.Fprintf(os.Stderr, "Fehler: kein update für %s bekannt\n", args[1])
+			return exitUsage
+		}
+		if len(args) > 0 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi updates [apply NAME]")
+			return exitUsage
+		}
+
+		s := ReadUpdateState()
+		s.CheckedAt, s.Updates = time.Now(), checkUpdates(ctx, settings)
+		s.Save()
+		if len(s.Updates) == 0 {
+			fmt.Println("Alles aktuell")
+		}
+		for _, u := range s.Updates {
+			hint := "newpipi updates apply " + shellQuote(u.Name)
+			if !u.Automatic() {
+				hint = u.URL
+			}
+			fmt.Printf("%-40s %s\n", u, hint)
+			for _, change := range u.Changes {
+				fmt.Println("  " + change)
+			}
+		}
+		return ExitOK
+	}
+}
@@ -0,0 +1,243 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Neben Git kann newpipi ein Jujutsu- oder Mercurial-Repository anlegen. Die
+// Git-spezifischen Schritte (Hooks, .gitattributes, Signatur, LFS, Push)
+// gelten nur für Git; jj liest die .gitignore selbst, für Mercurial entsteht
+// daraus eine .hgignore.
+
+// versionControl beschreibt ein Versionskontrollsystem.
+type versionControl struct {
+	// Name ist der Wert für --vcs und Settings.VCS.
+	Name string
+	// Label ist die UI-Beschriftung.
+	Label string
+	// Tool ist das Programm, Dir das Verzeichnis des Repositorys im Projekt.
+	Tool string
+	Dir  string
+}
+
+var VcsSystems = []versionControl{
+	{Name: "git", Label: "Git", Tool: "git", Dir: ".git"},
+	{Name: "jj", Label: "Jujutsu", Tool: "jj", Dir: ".jj"},
+	{Name: "hg", Label: "Mercurial", Tool: "hg", Dir: ".hg"},
+}
+
+// FindVCS sucht ein System nach Name oder Beschriftung.
+func FindVCS(name string) (versionControl, bool) {
+	for _, v := range VcsSystems {
+		if strings.EqualFold(v.Name, name) || strings.EqualFold(v.Label, name) {
+			return v, true
+		}
+	}
+	return versionControl{}, false
+}
+
+// VersionControl liefert das gewählte System; ohne Angabe Git.
+func (ps *ProjectSetup) VersionControl() versionControl {
+	if v, ok := FindVCS(ps.Vcs); ok {
+		return v
+	}
+	return VcsSystems[0]
+}
+
+// gitRepo meldet, ob das Projekt ein Git-Repository bekommt.
+func (ps *ProjectSetup) gitRepo() bool {
+	return !ps.SkipGit && ps.VersionControl().Name == "git"
+}
+
+// isVCSDir meldet, ob name das Verzeichnis eines Repositorys ist.
+func isVCSDir(name string) bool {
+	return slices.ContainsFunc(VcsSystems, func(v versionControl) bool { return v.Dir == name })
+}
+
+// checkVCS prüft vorab, ob das gewählte System bekannt und installiert ist.
+func (ps *ProjectSetup) checkVCS() PreflightCheck {
+	v, ok := FindVCS(ps.Vcs)
+	if ps.Vcs == "" {
+		v, ok = VcsSystems[0], true
+	}
+	c := PreflightCheck{Name: "Version control", Detail: v.Label}
+	if !ok {
+		c.Detail = ps.Vcs
+		c.Err = fmt.Errorf("unbekanntes versionskontrollsystem %q (git, jj oder hg)", ps.Vcs)
+		return c
+	}
+	if _, err := exec.LookPath(v.Tool); err != nil {
+		c.Err = fmt.Errorf("%w: %s nicht gefunden", errToolchainMissing, v.Tool)
+	}
+	return c
+}
+
+// initVCS legt ein jj- oder hg-Repository mit dem ersten Commit an. Nach
+// einem Abbruch werden vorhandenes Repository und Commit übernommen.
+func (ps *ProjectSetup) initVCS(v versionControl) error {
+	projectDir := filepath.Join(ps.ParentPath, ps.ProjectName)
+	if _, err := os.Stat(filepath.Join(projectDir, v.Dir)); os.IsNotExist(err) {
+		init := []string{v.Tool, "init"}
+		if v.Name == "jj" {
+			init = []string{"jj", "git", "init"}
+		}
+		cmd := ps.command(init[0], init[1:]...)
+		cmd.Dir = projectDir
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s-initialisierung fehlgeschlagen: %v", v.Name, err)
+		}
+	}
+
+	var commands [][]string
+	name, email := ps.Profile().GitName, ps.Profile().GitEmail
+	switch v.Name {
+	case "jj":
+		if name != "" {
+			commands = append(commands, []string{"jj", "config", "set", "--repo", "user.name", name})
+		}
+		if email != "" {
+			commands = append(commands, []string{"jj", "config", "set", "--repo", "user.email", email})
+		}
+		// jj übernimmt das Arbeitsverzeichnis selbst; der erste Commit ist
+		// beschrieben, sobald @- eine Beschreibung hat
+		out, err := vcsOutput(projectDir, "jj", "log", "--no-graph", "-r", "@-", "-T", "description")
+		if err != nil || strings.TrimSpace(out) == "" {
+			commands = append(commands, []string{"jj", "describe", "-m", "Initial commit"}, []string{"jj", "new"})
+		}
+	case "hg":
+		if name != "" {
+			if err := writeHgUsername(projectDir, name, email); err != nil {
+				return err
+			}
+		}
+		if err := writeHgIgnore(projectDir, ps.Settings.Modes()); err != nil {
+			return err
+		}
+		out, err := vcsOutput(projectDir, "hg", "log", "-l", "1", "-T", "{node}")
+		if err != nil || strings.TrimSpace(out) == "" {
+			commands = append(commands, []string{"hg", "add", "-q"}, []string{"hg", "commit", "-m", "Initial commit"})
+		}
+	}
+
+	for _, args := range commands {
+		cmd := ps.command(args[0], args[1:]...)
+		cmd.Dir = projectDir
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s-befehl fehlgeschlagen: %v", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// vcsHistory zählt die Commits eines jj- oder hg-Repositorys und meldet
+// Änderungen im Arbeitsverzeichnis. Jeder Commit gibt eine Zeile aus.
+func vcsHistory(projectDir string, v versionControl) (commits int, dirty bool, err error) {
+	var logArgs, statusArgs []string
+	switch v.Name {
+	case "jj":
+		// @ ist die offene Änderung nach dem ersten Commit
+		logArgs = []string{"jj", "log", "--no-graph", "-r", "::@- ~ root()", "-T", `"x\n"`}
+		statusArgs = []string{"jj", "log", "--no-graph", "-r", "@", "-T", `if(empty, "", "x")`}
+	case "hg":
+		logArgs = []string{"hg", "log", "-T", "x\n"}
+		statusArgs = []string{"hg", "status"}
+	default:
+		return 0, false, fmt.Errorf("unbekanntes versionskontrollsystem %q", v.Name)
+	}
+	out, err := vcsOutput(projectDir, logArgs[0], logArgs[1:]...)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s log fehlgeschlagen: %v", v.Name, err)
+	}
+	commits = strings.Count(out, "\n")
+	out, err = vcsOutput(projectDir, statusArgs[0], statusArgs[1:]...)
+	if err != nil {
+		return 0, false, fmt.Errorf("%s status fehlgeschlagen: %v", v.Name, err)
+	}
+	return commits, strings.TrimSpace(out) != "", nil
+}
+
+// vcsOutput führt einen Befehl im Projekt aus und liefert dessen Ausgabe.
+func vcsOutput(dir, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// writeHgUsername trägt die Identität des Profils in .hg/hgrc ein.
+func writeHgUsername(projectDir, name, email string) error {
+	username := name
+	if email != "" {
+		username += " <" + email + ">"
+	}
+	path := filepath.Join(projectDir, ".hg", "hgrc")
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf(".hg/hgrc lesen fehlgeschlagen: %v", err)
+	}
+	if strings.Contains(string(existing), "username =") {
+		return nil
+	}
+	content := string(existing) + "[ui]\nusername = " + username + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf(".hg/hgrc schreiben fehlgeschlagen: %v", err)
+	}
+	return nil
+}
+
+// hgIgnore übersetzt eine .gitignore in die glob-Syntax der .hgignore.
+// Muster mit führendem / gelten nur im Projektverzeichnis (rootglob);
+// Ausnahmen mit ! kennt Mercurial nicht, sie werden übersprungen.
+func hgIgnore(gitignore string) (string, []string) {
+	lines := []string{"syntax: glob"}
+	var skipped []string
+	scanner := bufio.NewScanner(strings.NewReader(gitignore))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "!"):
+			skipped = append(skipped, line)
+			continue
+		case strings.HasPrefix(line, "/"):
+			line = "rootglob:" + strings.TrimSuffix(strings.TrimPrefix(line, "/"), "/")
+		default:
+			line = strings.TrimSuffix(line, "/")
+		}
+		if !slices.Contains(lines, line) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n", skipped
+}
+
+// writeHgIgnore legt die .hgignore aus der .gitignore an, sofern das
+// Template keine eigene mitbringt.
+func writeHgIgnore(projectDir string, modes projectModes) error {
+	path := filepath.Join(projectDir, ".hgignore")
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	gitignore, err := os.ReadFile(filepath.Join(projectDir, ".gitignore"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf(".gitignore lesen fehlgeschlagen: %v", err)
+	}
+	content, skipped := hgIgnore(string(gitignore))
+	for _, line := range skipped {
+		log.Printf("Ausnahme %s aus .gitignore kennt Mercurial nicht", line)
+	}
+	if err := modes.writeFile(path, []byte(content), modes.File); err != nil {
+		return fmt.Errorf(".hgignore schreiben fehlgeschlagen: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHgIgnore(t *testing.T) {
+	tests := []struct {
+		gitignore string
+		want      string
+		skipped   []string
+	}{
+		{"", "syntax: glob\n", nil},
+		{"/venv\n__pycache__\n*.pyc\n", "syntax: glob\nrootglob:venv\n__pycache__\n*.pyc\n", nil},
+		{"# Build\nbuild/\n\n/dist/\n.env\n.env\n", "syntax: glob\nbuild\nrootglob:dist\n.env\n", nil},
+		{"*.log\n!keep.log\n", "syntax: glob\n*.log\n", []string{"!keep.log"}},
+	}
+	for _, tt := range tests {
+		got, skipped := hgIgnore(tt.gitignore)
+		if got != tt.want || !slices.Equal(skipped, tt.skipped) {
+			t.Errorf("hgIgnore(%q) = %q, %v, erwartet %q, %v", tt.gitignore, got, skipped, tt.want, tt.skipped)
+		}
+	}
+}
+
+func TestFindVCS(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"git", "git", true},
+		{"Jujutsu", "jj", true},
+		{"HG", "hg", true},
+		{"svn", "", false},
+	}
+	for _, tt := range tests {
+		v, ok := FindVCS(tt.name)
+		if v.Name != tt.want || ok != tt.ok {
+			t.Errorf("findVCS(%q) = %s, %v, erwartet %s, %v", tt.name, v.Name, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// version wird beim Build gesetzt:
+//
+//	go build -ldflags "-X github.com/alexander-graf/go_pipi/internal/core.version=v1.2.0"
+var version = "dev"
+
+// releasesURL ist die GitHub-API für das neueste Release.
+const releasesURL = "https://api.github.com/repos/alexander-graf/go_pipi/releases/latest"
+
+// VersionString liefert die Version für --version, bei Entwicklungs-Builds
+// ergänzt um den Commit aus den Build-Informationen.
+func VersionString() string {
+	v := version
+	if info, ok := debug.ReadBuildInfo(); ok && v == "dev" {
+		if info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" && len(s.Value) >= 12 {
+				v += " (" + s.Value[:12] + ")"
+			}
+		}
+	}
+	return fmt.Sprintf("newpipi %s %s/%s", v, runtime.GOOS, runtime.GOARCH)
+}
+
+// releaseAsset ist der Name des Linux-Binaries eines Releases.
+func releaseAsset() string {
+	return "newpipi-linux-" + runtime.GOARCH
+}
+
+// checkAppUpdate fragt das neueste GitHub-Release ab. Unter Linux kann das
+// Programm sich selbst ersetzen, wenn das Release ein passendes Binary und
+// eine SHA256SUMS-Datei enthält.
+func checkAppUpdate(ctx context.Context) (*UpdateInfo, error) {
+	if version == "dev" {
+		return nil, nil
+	}
+	content, err := fetchURL(ctx, releasesURL)
+	if err != nil {
+		return nil, err
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(content, &release); err != nil {
+		return nil, fmt.Errorf("release parsen fehlgeschlagen: %v", err)
+	}
+	if !versionLess(version, release.TagName) {
+		return nil, nil
+	}
+	u := &UpdateInfo{Kind: "app", Name: "newpipi", Installed: version, Latest: release.TagName, URL: release.HTMLURL}
+	if runtime.GOOS == "linux" {
+		for _, a := range release.Assets {
+			switch a.Name {
+			case releaseAsset():
+				u.Asset = a.URL
+			case "SHA256SUMS":
+				u.Checksums = a.URL
+			}
+		}
+		// Ohne Prüfsummen wird nur auf die Download-Seite verwiesen
+		if u.Checksums == "" {
+			u.Asset = ""
+		}
+	}
+	return u, nil
+}
+
+// selfReplace lädt das neue Binary, prüft es gegen SHA256SUMS und ersetzt
+// damit die laufende Datei. Wirksam wird es beim nächsten Start.
+func selfReplace(ctx context.Context, u UpdateInfo) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+	sums, err := fetchURL(ctx, u.Checksums)
+	if err != nil {
+		return err
+	}
+	want := ""
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == releaseAsset() {
+			want = fields[0]
+		}
+	}
+	if want == "" {
+		return fmt.Errorf("SHA256SUMS nennt %s nicht", releaseAsset())
+	}
+	bin, err := fetchURL(ctx, u.Asset)
+	if err != nil {
+		return err
+	}
+	if got := fmt.Sprintf("%x", sha256.Sum256(bin)); !strings.EqualFold(got, want) {
+		return fmt.Errorf("prüfsumme des updates stimmt nicht: erwartet %s, erhalten %s", want, got)
+	}
+
+	// Neben die alte Datei schreiben, damit das Umbenennen atomar ist
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".newpipi-update-")
+	if err != nil {
+		return fmt.Errorf("keine schreibrechte für %s, bitte von hand aktualisieren: %v", filepath.Dir(exe), err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(bin)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0755)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), exe)
+	}
+	if err != nil {
+		return fmt.Errorf("programm ersetzen fehlgeschlagen: %v", err)
+	}
+	return nil
+}
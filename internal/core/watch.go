@@ -0,0 +1,277 @@
+package core
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch-Modus für Template-Autoren: Nach jedem Speichern rendert newpipi das
+// Template neu in ein Sandbox-Verzeichnis und aktualisiert die Vorschau.
+// Gerendert werden nur Dateien, Assets und Symlinks; Pakete, Befehle und
+// Hooks laufen nicht, die Vorschau braucht daher auch keine Bestätigung.
+
+// watchDebounce fasst die Ereignisse eines Speicherns zusammen; Editoren
+// schreiben oft mehrmals oder über eine temporäre Datei.
+const watchDebounce = 200 * time.Millisecond
+
+// previewMarker kennzeichnet ein Sandbox-Verzeichnis; nur solche leert die Vorschau.
+const previewMarker = ".newpipi-preview"
+
+// PreviewProjectName ist der Projektname der Vorschau, solange keiner angegeben ist.
+const PreviewProjectName = "preview"
+
+// PreviewDir ist das Standard-Sandbox-Verzeichnis eines Templates.
+func PreviewDir(name string) (string, error) {
+	dir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "preview", strings.TrimSuffix(TemplateFileName(name), ".json")), nil
+}
+
+// PreviewFile ist eine gerenderte Datei der Vorschau.
+type PreviewFile struct {
+	Path string
+	// Content fehlt bei Binärdateien; bei Symlinks ist es das Ziel
+	Content string
+	Size    int64
+	Binary  bool
+}
+
+// RenderPreview rendert t mit options nach dir und liefert die entstandenen
+// Dateien. Der bisherige Inhalt von dir wird ersetzt.
+func RenderPreview(settings Settings, t *Template, projectName string, options map[string]string, dir string) ([]PreviewFile, error) {
+	if err := clearPreviewDir(dir); err != nil {
+		return nil, err
+	}
+	ps := &ProjectSetup{
+		Settings:    settings,
+		ProfileName: settings.Profile,
+		ProjectName: projectName,
+		ProjectType: t.Type,
+		Template:    t,
+		Options:     options,
+	}
+	if _, err := ps.writeTemplateFiles(dir); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, previewMarker), nil, 0644); err != nil {
+		return nil, err
+	}
+	return readPreview(dir)
+}
+
+// clearPreviewDir leert dir, legt es bei Bedarf an und verweigert Verzeichnisse
+// mit fremdem Inhalt, damit ein vertipptes -out nichts löscht.
+func clearPreviewDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return os.MkdirAll(dir, 0755)
+	}
+	if err != nil {
+		return err
+	}
+	if len(entries) > 0 {
+		if _, err := os.Stat(filepath.Join(dir, previewMarker)); err != nil {
+			return fmt.Errorf("%s ist nicht leer und keine vorschau", dir)
+		}
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			return fmt.Errorf("vorschau leeren fehlgeschlagen: %v", err)
+		}
+	}
+	return nil
+}
+
+// readPreview liest die Dateien eines Sandbox-Verzeichnisses, sortiert nach Pfad.
+func readPreview(dir string) ([]PreviewFile, error) {
+	var files []PreviewFile
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path == filepath.Join(dir, previewMarker) {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		f := PreviewFile{Path: filepath.ToSlash(rel)}
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			f.Content = "-> " + target
+			files = append(files, f)
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f.Size = int64(len(content))
+		if utf8.Valid(content) && !bytes.ContainsRune(content, 0) {
+			f.Content = string(content)
+		} else {
+			f.Binary = true
+		}
+		files = append(files, f)
+		return nil
+	})
+	return files, err
+}
+
+// reloadTemplate lädt die Templates neu von der Platte und liefert name
+// vollständig geladen, damit Änderungen an Basis und Mixins mitkommen.
+func reloadTemplate(name string) (*Template, error) {
+	LoadUserTemplates()
+	t := templateByName(name)
+	if t == nil {
+		return nil, fmt.Errorf("template %s nicht gefunden", name)
+	}
+	return t, nil
+}
+
+// templateWatchDirs liefert die Verzeichnisse, deren Änderungen die Vorschau
+// von t betreffen: die seiner Datei, seiner Basis und Mixins und seiner
+// lokalen Assets.
+func templateWatchDirs(t *Template) []string {
+	var dirs []string
+	add := func(dir string) {
+		if dir != "" && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, name := range append([]string{t.Name, t.Extends}, t.Mixins...) {
+		if path, ok := templateFiles[strings.ToLower(name)]; ok {
+			add(filepath.Dir(path))
+		}
+	}
+	for _, src := range t.Assets {
+		if !strings.HasPrefix(src, assetBase64Prefix) {
+			add(filepath.Dir(src))
+		}
+	}
+	return dirs
+}
+
+// WatchTemplate ruft render sofort und nach jeder Änderung an den Dateien des
+// Templates name auf, bis stop geschlossen wird. render bekommt das neu
+// geladene Template oder den Fehler beim Laden.
+func WatchTemplate(name string, stop <-chan struct{}, render func(t *Template, err error)) error {
+	if _, ok := templateFiles[strings.ToLower(name)]; !ok {
+		return fmt.Errorf("template %s hat keine datei zum beobachten", name)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("dateiüberwachung starten fehlgeschlagen: %v", err)
+	}
+	defer watcher.Close()
+	update := func() {
+		t, err := reloadTemplate(name)
+		render(t, err)
+		if err != nil {
+			return
+		}
+		// Neue Assets oder Mixins bringen neue Verzeichnisse mit
+		for _, dir := range templateWatchDirs(t) {
+			if !slices.Contains(watcher.WatchList(), dir) {
+				if err := watcher.Add(dir); err != nil {
+					log.Printf("Dateiüberwachung: %v", err)
+				}
+			}
+		}
+	}
+	update()
+	var pending <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			pending = time.After(watchDebounce)
+		case <-pending:
+			pending = nil
+			update()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Dateiüberwachung: %v", err)
+		}
+	}
+}
+
+// setupWatch definiert `newpipi watch [-out DIR] [-name NAME] [-option N=V] TEMPLATE`.
+func setupWatch(fs *flag.FlagSet) func(args []string) int {
+	out := fs.String("out", "", "Sandbox-Verzeichnis (Standard: preview/TEMPLATE im Statusverzeichnis)")
+	projectName := fs.String("name", PreviewProjectName, "Projektname für die Platzhalter")
+	var options optionValues
+	fs.Var(&options, "option", "Template-Option NAME=WERT (mehrfach möglich)")
+	return func(args []string) int {
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "Verwendung: newpipi watch [-out DIR] [-name NAME] [-option NAME=WERT] TEMPLATE")
+			return exitUsage
+		}
+		name := args[0]
+		dir := *out
+		if dir == "" {
+			var err error
+			if dir, err = PreviewDir(name); err != nil {
+				fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+				return exitFailure
+			}
+		}
+		settings, err := LoadSettings()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		stop := make(chan struct{})
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		go func() {
+			<-interrupt
+			close(stop)
+		}()
+		fmt.Printf("Beobachte %s, Vorschau in %s (Strg+C beendet)\n", name, dir)
+		err = WatchTemplate(name, stop, func(t *Template, err error) {
+			stamp := time.Now().Format("15:04:05")
+			var files []PreviewFile
+			if err == nil {
+				files, err = RenderPreview(settings, t, *projectName, options, dir)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "[%s] Fehler: %v\n", stamp, err)
+				return
+			}
+			fmt.Printf("[%s] %d Dateien gerendert\n", stamp, len(files))
+			for _, f := range files {
+				fmt.Printf("  %s (%s)\n", f.Path, FormatSize(f.Size))
+			}
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fehler: %v\n", err)
+			return exitFailure
+		}
+		return ExitOK
+	}
+}
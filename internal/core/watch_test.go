@@ -0,0 +1,76 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRenderPreview(t *testing.T) {
+	tmpl := &Template{
+		Name:    "demo",
+		Type:    Go,
+		Render:  true,
+		Options: []TemplateOption{{Name: "db", Default: "sqlite"}},
+		Files: map[string]string{
+			"README.md":           "# {{.ProjectName}}\n",
+			"cmd/{{.db}}/main.go": "package main\n",
+		},
+		Assets: map[string]string{"logo.png": assetBase64Prefix + "iVBORw0KGgoAAA=="},
+	}
+	tests := []struct {
+		name    string
+		options map[string]string
+		// stale liegt vorher im Verzeichnis, ohne Marker gilt es als fremd
+		stale   string
+		marker  bool
+		want    []string
+		wantErr bool
+	}{
+		{name: "defaults", want: []string{"README.md", "cmd/sqlite/main.go", "logo.png"}},
+		{name: "option", options: map[string]string{"db": "postgres"}, want: []string{"README.md", "cmd/postgres/main.go", "logo.png"}},
+		{name: "old preview", stale: "old.txt", marker: true, want: []string{"README.md", "cmd/sqlite/main.go", "logo.png"}},
+		{name: "foreign dir", stale: "notes.txt", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "out")
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			if tt.stale != "" {
+				os.WriteFile(filepath.Join(dir, tt.stale), []byte("x"), 0644)
+			}
+			if tt.marker {
+				os.WriteFile(filepath.Join(dir, previewMarker), nil, 0644)
+			}
+			files, err := RenderPreview(Settings{}, tmpl, "shop", tt.options, dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("fehler erwartet")
+				}
+				if _, err := os.Stat(filepath.Join(dir, tt.stale)); err != nil {
+					t.Errorf("fremde datei gelöscht: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range files {
+				got = append(got, f.Path)
+				if f.Path == "README.md" && f.Content != "# shop\n" {
+					t.Errorf("README.md = %q", f.Content)
+				}
+				if f.Path == "logo.png" && !f.Binary {
+					t.Error("logo.png nicht als binär erkannt")
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("dateien = %v, erwartet %v", got, tt.want)
+			}
+		})
+	}
+}
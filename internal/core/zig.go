@@ -0,0 +1,113 @@
+package core
+
+// Zig: `zig init --minimal` legt im Projektverzeichnis build.zig.zon mit dem
+// Fingerabdruck des Pakets an; build.zig und die Quelltexte schreibt das
+// Layout, ein Programm (Executable) oder eine Bibliothek (Library). Die
+// früheren Befehle zig init-exe und init-lib gibt es seit Zig 0.12 nicht
+// mehr, --minimal seit 0.14.
+
+// zigLayouts sind die Projektformen von Zig; beide legen das Projekt im
+// Projektverzeichnis selbst an.
+var zigLayouts = []toolkit{
+	{
+		Name: "Executable",
+		Dir:  ".",
+		Files: map[string]string{
+			"build.zig":    zigExeBuild,
+			"src/main.zig": zigMain,
+		},
+	},
+	{
+		Name: "Library",
+		Dir:  ".",
+		Files: map[string]string{
+			"build.zig":    zigLibBuild,
+			"src/root.zig": zigRoot,
+		},
+		Run: "zig build test",
+	},
+}
+
+const (
+	zigExeBuild = `const std = @import("std");
+
+pub fn build(b: *std.Build) void {
+    const target = b.standardTargetOptions(.{});
+    const optimize = b.standardOptimizeOption(.{});
+
+    const exe = b.addExecutable(.{
+        .name = "{{.ProjectName}}",
+        .root_module = b.createModule(.{
+            .root_source_file = b.path("src/main.zig"),
+            .target = target,
+            .optimize = optimize,
+        }),
+    });
+    b.installArtifact(exe);
+
+    const run_cmd = b.addRunArtifact(exe);
+    run_cmd.step.dependOn(b.getInstallStep());
+    if (b.args) |args| run_cmd.addArgs(args);
+    const run_step = b.step("run", "Run the app");
+    run_step.dependOn(&run_cmd.step);
+
+    const tests = b.addTest(.{ .root_module = exe.root_module });
+    const test_step = b.step("test", "Run unit tests");
+    test_step.dependOn(&b.addRunArtifact(tests).step);
+}
+`
+
+	zigMain = `const std = @import("std");
+
+pub fn greeting(buf: []u8, name: []const u8) ![]u8 {
+    return std.fmt.bufPrint(buf, "Hello, {s}!", .{name});
+}
+
+pub fn main() !void {
+    var buf: [64]u8 = undefined;
+    std.debug.print("{s}\n", .{try greeting(&buf, "Zig")});
+}
+
+test "greeting" {
+    var buf: [64]u8 = undefined;
+    try std.testing.expectEqualStrings("Hello, Zig!", try greeting(&buf, "Zig"));
+}
+`
+
+	zigLibBuild = `const std = @import("std");
+
+pub fn build(b: *std.Build) void {
+    const target = b.standardTargetOptions(.{});
+    const optimize = b.standardOptimizeOption(.{});
+
+    // Andere Pakete importieren die Bibliothek unter diesem Namen
+    const mod = b.addModule("{{.ProjectName}}", .{
+        .root_source_file = b.path("src/root.zig"),
+        .target = target,
+        .optimize = optimize,
+    });
+
+    const lib = b.addLibrary(.{
+        .linkage = .static,
+        .name = "{{.ProjectName}}",
+        .root_module = mod,
+    });
+    b.installArtifact(lib);
+
+    const tests = b.addTest(.{ .root_module = mod });
+    const test_step = b.step("test", "Run unit tests");
+    test_step.dependOn(&b.addRunArtifact(tests).step);
+}
+`
+
+	zigRoot = `const std = @import("std");
+
+pub fn add(a: i32, b: i32) i32 {
+    return a + b;
+}
+
+test "add" {
+    try std.testing.expectEqual(@as(i32, 3), add(1, 2));
+}
+`
+)
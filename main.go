@@ -1,738 +1,277 @@
 package main
 
 import (
+	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-	"golang.org/x/sys/unix"
+	"github.com/alexander-graf/go_pipi/internal/core"
 )
 
 //go:embed icon/ceferino.ico
 var iconData []byte
 
-const configFile = ".config/newpipi_project_path"
-
-type ProjectType int
-
-const (
-	Python ProjectType = iota
-	Go
-	Rust
-	JavaScript
-	TypeScript
-	CPlusPlus
-	CSharp
-	Java
-)
-
-type ProjectSetup struct {
-	window         fyne.Window
-	parentPath     string
-	projectName    string
-	projectType    ProjectType
-	statusMessage  string
-	messageTimeout *time.Timer
-	createBtn      *widget.Button
-}
-
-type Template struct {
-	Name        string
-	Description string
-	Type        ProjectType
-	Files       map[string]string
-	Packages    []string
-}
-
-var templates = []Template{
-	{
-		Name:        "CLI App",
-		Description: "Kommandozeilen-Anwendung",
-		Type:        Python,
-		Files: map[string]string{
-			"src/cli.py": `import click
-@click.command()
-def main():
-    click.echo("Hello CLI!")
-
-if __name__ == "__main__":
-    main()`,
-		},
-		Packages: []string{"click"},
-	},
-	// Weitere Templates...
-}
-
-func NewProjectSetup() *ProjectSetup {
-	ps := &ProjectSetup{}
-	if err := ps.loadProjectPath(); err != nil {
-		log.Printf("Fehler beim Laden des Projektpfads: %v", err)
-	}
-	return ps
+func main() {
+	// Stellvertreter eines Befehls beim Aufzeichnen und Abspielen von Fixtures
+	if len(os.Args) > 1 && os.Args[1] == core.FixtureShimArg {
+		os.Exit(core.RunFixtureShim(os.Args[2:]))
+	}
+	core.StartLogFile()
+	defer core.ReportPanic()
+	// newpipi --serve ist die Kurzform für den Daemon-Modus
+	if len(os.Args) > 1 && os.Args[1] == "--serve" {
+		os.Args[1] = "serve"
+	}
+	// Vor den Templates, deren Projekttyp von einem Plugin stammen kann
+	if core.NeedsPlugins(os.Args[1:]) {
+		core.LoadPlugins()
+	}
+	core.LoadLanguages()
+	core.LoadUserTemplates()
+	core.LoadUserSnippets()
+	core.QuickWindow = runQuickWindow
+	if len(os.Args) > 1 {
+		os.Exit(core.RunCommand(os.Args[1:]))
+	}
+	runGUI()
 }
 
-func (ps *ProjectSetup) loadProjectPath() error {
-	log.Println("Lade Projektpfad...")
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("home dir nicht gefunden: %v", err)
-	}
-
-	configPath := filepath.Join(homeDir, configFile)
-	content, err := os.ReadFile(configPath)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return fmt.Errorf("config lesen fehlgeschlagen: %v", err)
-		}
-		return nil
+func runGUI() {
+	// Log-Zeilen zusätzlich für „View Logs“ der Befehlspalette sammeln
+	logs := &logBuffer{}
+	log.SetOutput(io.MultiWriter(log.Writer(), logs))
+	log.Println("Starte Anwendung...")
+	// Die Skalierung muss vor app.New feststehen
+	if s, err := core.LoadSettings(); err == nil && s.UIScale > 0 && os.Getenv("FYNE_SCALE") == "" {
+		os.Setenv("FYNE_SCALE", strconv.FormatFloat(s.UIScale, 'f', -1, 64))
 	}
-
-	path := strings.TrimSpace(string(content))
-	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
-		ps.parentPath = path
-		log.Printf("Projektpfad geladen: %s", path)
+	myApp := app.New()
+	if s, err := core.LoadSettings(); err == nil {
+		myApp.Settings().SetTheme(newAppTheme(s))
 	}
-	return nil
-}
 
-func (ps *ProjectSetup) saveProjectPath() error {
-	log.Printf("Speichere Projektpfad: %s", ps.parentPath)
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("home dir nicht gefunden: %v", err)
-	}
+	// Korrigierte Icon-Setzung
+	icon := fyne.NewStaticResource("icon", iconData)
+	myApp.SetIcon(icon)
 
-	configPath := filepath.Join(homeDir, configFile)
-	configDir := filepath.Dir(configPath)
+	window := myApp.NewWindow("Project Setup")
 
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("config dir erstellen fehlgeschlagen: %v", err)
-	}
+	ps := core.NewProjectSetup()
 
-	if err := os.WriteFile(configPath, []byte(ps.parentPath), 0644); err != nil {
-		return fmt.Errorf("config schreiben fehlgeschlagen: %v", err)
+	if ps.Settings.ScratchAutoArchive {
+		go core.AutoArchiveScratch(ps.Settings)
 	}
-
-	log.Println("Projektpfad erfolgreich gespeichert")
-	return nil
-}
-
-func (ps *ProjectSetup) showMessage(msg string) {
-	log.Printf("Zeige Nachricht: %s", msg)
-	ps.statusMessage = msg
-	if ps.messageTimeout != nil {
-		ps.messageTimeout.Stop()
+	if ps.Settings.PrefetchOnStart {
+		// Eigenes Setup, damit die Fortschrittsanzeige der Erstellung unberührt bleibt
+		go func() {
+			if err := core.NewProjectSetup().Prefetch(core.AllProjectTypes()); err != nil {
+				log.Printf("Vorladen fehlgeschlagen: %v", err)
+			}
+		}()
 	}
-	ps.messageTimeout = time.AfterFunc(2*time.Second, func() {
-		ps.statusMessage = ""
-		ps.window.Canvas().Refresh(ps.window.Content())
-	})
-}
 
-func (ps *ProjectSetup) createProject() error {
-	log.Println("Starte Projekterstellung...")
-
-	// Validierungen
-	if ps.parentPath == "" {
-		return fmt.Errorf("elternpfad darf nicht leer sein")
-	}
-	if ps.projectName == "" {
-		return fmt.Errorf("projektname darf nicht leer sein")
-	}
-	if strings.ContainsAny(ps.projectName, " \t\n") {
-		return fmt.Errorf("projektname darf keine Leerzeichen enthalten")
+	// Startbefehl im Terminal, vorbelegt aus Template, Toolkit und Einstellungen
+	runEntry := widget.NewEntry()
+	runEntry.SetPlaceHolder("Default of the project type")
+	refreshRun := func() {
+		runEntry.SetText(ps.StartCommand())
 	}
 
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-	if _, err := os.Stat(projectDir); !os.IsNotExist(err) {
-		return fmt.Errorf("projektverzeichnis existiert bereits: %s", projectDir)
-	}
+	// Umgebungsvariablen, bearbeitet in einer eigenen Tabelle
+	var envBtn *widget.Button
+	envBtn = widget.NewButton("Edit Variables...", func() {
+		showEnvDialog(window, ps.EnvVars, func(env map[string]string) {
+			ps.EnvVars = env
+			envBtn.SetText(fmt.Sprintf("Edit Variables (%d)...", len(env)))
+		})
+	})
 
-	// Prüfe zuerst die Installation
-	var err error
-	switch ps.projectType {
-	case Go:
-		err = ps.checkGoInstallation()
-	case Python:
-		// Python-Check implementieren
-	case Rust:
-		err = ps.checkRustInstallation()
-	case JavaScript:
-		err = ps.checkJavaScriptInstallation()
-	case TypeScript:
-		// Prüfe sowohl Node.js als auch TypeScript
-		if err = ps.checkJavaScriptInstallation(); err == nil {
-			err = ps.checkTypeScriptInstallation()
+	// Templateauswahl mit den Optionen des gewählten Templates
+	const noTemplate = "(kein Template)"
+	optionsBox := container.NewVBox()
+	var lfsCheck *widget.Check
+	// templateNames ordnet die Einträge samt Herkunft den Template-Namen zu
+	templateNames := make(map[string]string)
+	templateSelect := widget.NewSelect(nil, func(value string) {
+		if name, ok := templateNames[value]; ok {
+			value = name
 		}
-	case CPlusPlus:
-		err = ps.checkCPlusPlusInstallation()
-	case CSharp:
-		err = ps.checkCSharpInstallation()
-	case Java:
-		err = ps.checkJavaInstallation()
-	}
-
-	if err != nil {
-		return fmt.Errorf("installation prüfung fehlgeschlagen: %v", err)
-	}
-
-	// Wenn die Installation-Prüfung erfolgreich war, erstelle das Projekt
-	switch ps.projectType {
-	case Python:
-		return ps.createPythonProject()
-	case Go:
-		return ps.createGoProject()
-	case Rust:
-		return ps.createRustProject()
-	case JavaScript:
-		return ps.createJavaScriptProject()
-	case TypeScript:
-		return ps.createTypeScriptProject()
-	case CPlusPlus:
-		return ps.createCPlusPlusProject()
-	case CSharp:
-		return ps.createCSharpProject()
-	case Java:
-		return ps.createJavaProject()
-	}
-
-	return nil
-}
-
-func (ps *ProjectSetup) createPythonProject() error {
-	log.Println("Erstelle Python-Projekt...")
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-
-	// Erstelle Projektverzeichnis
-	if err := os.MkdirAll(projectDir, 0755); err != nil {
-		return fmt.Errorf("projektverzeichnis erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Wechsel ins Projektverzeichnis
-	if err := os.Chdir(projectDir); err != nil {
-		return fmt.Errorf("verzeichniswechsel fehlgeschlagen: %v", err)
-	}
-
-	// Erstelle virtuelle Umgebung
-	log.Println("Erstelle virtuelle Umgebung...")
-	cmd := exec.Command("python3", "-m", "venv", "venv")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("venv erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Aktualisiere pip und installiere Pakete
-	log.Println("Installiere Pakete...")
-	cmd = exec.Command("sh", "-c", "source venv/bin/activate && pip install --upgrade pip && pip install numpy PyQt5")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("paketinstallation fehlgeschlagen: %v", err)
-	}
-
-	// Erstelle Projektstruktur
-	log.Println("Erstelle Projektstruktur...")
-	dirs := []string{"src", "tests"}
-	for _, dir := range dirs {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("verzeichnis %s erstellen fehlgeschlagen: %v", dir, err)
+		ps.Template = core.FindTemplate(ps.ProjectType, value)
+		// Templates mit LFS-Mustern schalten LFS immer ein
+		if lfsCheck != nil && ps.Template != nil && len(ps.Template.LFS) > 0 {
+			lfsCheck.SetChecked(true)
+			lfsCheck.Disable()
+		} else if lfsCheck != nil {
+			lfsCheck.SetChecked(ps.GitLFS)
+			lfsCheck.Enable()
 		}
-	}
-
-	// Erstelle Dateien
-	files := map[string]string{
-		"src/__init__.py": "",
-		"src/main.py": `import sys
-from PyQt5.QtWidgets import QApplication, QMainWindow, QPushButton, QVBoxLayout, QWidget
-
-class MainWindow(QMainWindow):
-    def __init__(self):
-        super().__init__()
-        self.setWindowTitle("PyQt5 Boilerplate")
-        self.setGeometry(100, 100, 300, 200)
-
-        layout = QVBoxLayout()
-        
-        button = QPushButton("Click me!")
-        button.clicked.connect(self.button_clicked)
-        layout.addWidget(button)
-
-        central_widget = QWidget()
-        central_widget.setLayout(layout)
-        self.setCentralWidget(central_widget)
-
-    def button_clicked(self):
-        print("Button clicked!")
-
-def main():
-    app = QApplication(sys.argv)
-    window = MainWindow()
-    window.show()
-    sys.exit(app.exec_())
-
-if __name__ == "__main__":
-    main()`,
-		"tests/__init__.py": "",
-		"README.md":         "",
-		"requirements.txt":  "numpy\nPyQt5\n",
-		".gitignore":        "/venv\n__pycache__\n*.pyc\n",
-	}
-
-	for path, content := range files {
-		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-			return fmt.Errorf("datei %s erstellen fehlgeschlagen: %v", path, err)
+		ps.Options = make(map[string]string)
+		ps.SecretValues, ps.SecretsInKeyring = make(map[string]string), false
+		optionsBox.Objects = nil
+		if ps.Template != nil {
+			defaults, _ := ps.Template.ResolveOptions(nil)
+			for _, o := range ps.Template.Options {
+				optionsBox.Add(templateOptionWidget(o, defaults[o.Name], ps.Options))
+			}
+			for _, w := range templateSecretWidgets(ps.Template, ps.SecretValues, &ps.SecretsInKeyring) {
+				optionsBox.Add(w)
+			}
 		}
-	}
-
-	// Öffne Terminal
-	log.Println("Öffne Terminal...")
-	terminalCmd := fmt.Sprintf("wezterm start --cwd '%s' --always-new-process -- bash -c 'source venv/bin/activate && echo \"python src/main.py\" && bash'", projectDir)
-	cmd = exec.Command("sh", "-c", terminalCmd)
-	if err := cmd.Start(); err != nil {
-		log.Printf("Terminal öffnen fehlgeschlagen: %v", err)
-	}
-
-	// Warte kurz, damit das Terminal Zeit hat zu starten
-	time.Sleep(100 * time.Millisecond)
-	return nil
-}
-
-func (ps *ProjectSetup) createGoProject() error {
-	log.Println("Erstelle Go-Projekt...")
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-
-	// Erstelle Projektverzeichnis
-	if err := os.MkdirAll(projectDir, 0755); err != nil {
-		return fmt.Errorf("projektverzeichnis erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Wechsel ins Projektverzeichnis
-	if err := os.Chdir(projectDir); err != nil {
-		return fmt.Errorf("verzeichniswechsel fehlgeschlagen: %v", err)
-	}
-
-	// Initialisiere Go-Modul
-	log.Println("Initialisiere Go-Modul...")
-	cmd := exec.Command("go", "mod", "init", ps.projectName)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("go mod init fehlgeschlagen: %v", err)
-	}
-
-	// Installiere Fyne
-	log.Println("Installiere Fyne...")
-	cmd = exec.Command("go", "get", "fyne.io/fyne/v2")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("fyne installation fehlgeschlagen: %v", err)
-	}
-
-	// Erstelle main.go
-	mainContent := `package main
-
-import (
-	"fyne.io/fyne/v2"
-	"fyne.io/fyne/v2/app"
-	"fyne.io/fyne/v2/container"
-	"fyne.io/fyne/v2/layout"
-	"fyne.io/fyne/v2/widget"
-)
-
-func main() {
-	myApp := app.New()
-	myWindow := myApp.NewWindow("Hello")
-	myWindow.Resize(fyne.NewSize(600, 400))
-
-	hello := widget.NewLabel("Hello Fyne!")
-	content := container.New(layout.NewVBoxLayout(),
-		hello,
-		widget.NewButton("Hi!", func() {
-			hello.SetText("Welcome :)")
-		}),
-	)
-	leftAligned := container.New(layout.NewHBoxLayout(), content, layout.NewSpacer())
-
-	myWindow.SetContent(leftAligned)
-	myWindow.ShowAndRun()
-}`
-
-	if err := os.WriteFile("main.go", []byte(mainContent), 0644); err != nil {
-		return fmt.Errorf("main.go erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Führe go mod tidy aus
-	log.Println("Führe go mod tidy aus...")
-	cmd = exec.Command("go", "mod", "tidy")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("go mod tidy fehlgeschlagen: %v", err)
-	}
-
-	// Öffne Terminal
-	log.Println("Öffne Terminal...")
-	terminalCmd := fmt.Sprintf("wezterm start --cwd '%s' --always-new-process -- bash -c 'echo \"go run .\" && bash'", projectDir)
-	cmd = exec.Command("sh", "-c", terminalCmd)
-	if err := cmd.Start(); err != nil {
-		log.Printf("Terminal öffnen fehlgeschlagen: %v", err)
-	}
-
-	// Warte kurz, damit das Terminal Zeit hat zu starten
-	time.Sleep(100 * time.Millisecond)
-	return nil
-}
-
-func (ps *ProjectSetup) createRustProject() error {
-	log.Println("Erstelle Rust-Projekt...")
-
-	// Wechsel ins Elternverzeichnis
-	if err := os.Chdir(ps.parentPath); err != nil {
-		return fmt.Errorf("verzeichniswechsel fehlgeschlagen: %v", err)
-	}
-
-	// Erstelle neues Cargo-Projekt
-	log.Println("Erstelle Cargo-Projekt...")
-	cmd := exec.Command("cargo", "new", ps.projectName)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("cargo new fehlgeschlagen: %v", err)
-	}
-
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-	if err := os.Chdir(projectDir); err != nil {
-		return fmt.Errorf("verzeichniswechsel fehlgeschlagen: %v", err)
-	}
-
-	// Füge Druid hinzu
-	log.Println("Füge Druid hinzu...")
-	cmd = exec.Command("cargo", "add", "druid")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("druid installation fehlgeschlagen: %v", err)
-	}
-
-	// Erstelle main.rs
-	mainContent := `use druid::widget::{Button, Flex, Label};
-use druid::{AppLauncher, LocalizedString, PlatformError, Widget, WidgetExt, WindowDesc};
-
-fn main() -> Result<(), PlatformError> {
-	let main_window = WindowDesc::new(ui_builder())
-		.title(LocalizedString::new("Rust Druid App"))
-		.window_size((300.0, 200.0));
-
-	let data = 0_u32;
-
-	AppLauncher::with_window(main_window)
-		.log_to_console()
-		.launch(data)
-}
-
-fn ui_builder() -> impl Widget<u32> {
-	let label = Label::new(|data: &u32, _env: &_| format!("Count: {}", data))
-		.padding(5.0)
-		.center();
-
-	let button = Button::new("Increment")
-		.on_click(|_ctx, data: &mut u32, _env| *data += 1)
-		.padding(5.0);
-
-	Flex::column().with_child(label).with_child(button)
-}`
-
-	if err := os.WriteFile("src/main.rs", []byte(mainContent), 0644); err != nil {
-		return fmt.Errorf("main.rs erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Öffne Terminal
-	log.Println("Öffne Terminal...")
-	terminalCmd := fmt.Sprintf("wezterm start --cwd '%s' --always-new-process -- bash -c 'echo \"cargo run\" && bash'", projectDir)
-	cmd = exec.Command("sh", "-c", terminalCmd)
-	if err := cmd.Start(); err != nil {
-		log.Printf("Terminal öffnen fehlgeschlagen: %v", err)
-	}
-
-	// Warte kurz, damit das Terminal Zeit hat zu starten
-	time.Sleep(100 * time.Millisecond)
-	return nil
-}
-
-func (ps *ProjectSetup) createJavaScriptProject() error {
-	log.Println("Erstelle JavaScript-Projekt...")
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-
-	// Erstelle Projektverzeichnis
-	if err := os.MkdirAll(projectDir, 0755); err != nil {
-		return fmt.Errorf("projektverzeichnis erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Wechsel ins Projektverzeichnis
-	if err := os.Chdir(projectDir); err != nil {
-		return fmt.Errorf("verzeichniswechsel fehlgeschlagen: %v", err)
-	}
-
-	// Initialisiere npm
-	cmd := exec.Command("npm", "init", "-y")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("npm init fehlgeschlagen: %v", err)
-	}
-
-	// Installiere Express
-	cmd = exec.Command("npm", "install", "express")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("express installation fehlgeschlagen: %v", err)
-	}
-
-	// Erstelle app.js
-	appContent := `const express = require('express');
-const app = express();
-const port = 3000;
-
-app.get('/', (req, res) => {
-    res.send('Hello World!');
-});
-
-app.listen(port, () => {
-    console.log('Server running at http://localhost:' + port);
-});`
-
-	if err := os.WriteFile("app.js", []byte(appContent), 0644); err != nil {
-		return fmt.Errorf("app.js erstellen fehlgeschlagen: %v", err)
-	}
-
-	return ps.openTerminal(projectDir, "node app.js")
-}
-
-func (ps *ProjectSetup) createTypeScriptProject() error {
-	log.Println("Erstelle TypeScript-Projekt...")
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-
-	// Erstelle Projektverzeichnis
-	if err := os.MkdirAll(projectDir, 0755); err != nil {
-		return fmt.Errorf("projektverzeichnis erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Wechsel ins Projektverzeichnis
-	if err := os.Chdir(projectDir); err != nil {
-		return fmt.Errorf("verzeichniswechsel fehlgeschlagen: %v", err)
-	}
-
-	// Initialisiere npm und installiere TypeScript
-	commands := [][]string{
-		{"npm", "init", "-y"},
-		{"npm", "install", "typescript", "@types/node", "--save-dev"},
-		{"npx", "tsc", "--init"},
-	}
-
-	for _, args := range commands {
-		cmd := exec.Command(args[0], args[1:]...)
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("befehl fehlgeschlagen %v: %v", args, err)
+		optionsBox.Refresh()
+		refreshRun()
+	})
+	refreshTemplates := func() {
+		names := []string{noTemplate}
+		for _, t := range core.Templates {
+			if t.Type == ps.ProjectType {
+				names = append(names, t.Label())
+				templateNames[t.Label()] = t.Name
+			}
 		}
+		templateSelect.Options = names
+		templateSelect.SetSelected(noTemplate)
+	}
+
+	// KI-Erzeugung nur anbieten, wenn ein Endpunkt konfiguriert ist
+	aiBtn := widget.NewButton("Generate with AI...", func() {
+		showAIDialog(window, ps, func() {
+			templateSelect.Selected = "AI: " + ps.Template.Description
+			templateSelect.Refresh()
+			optionsBox.Objects = nil
+			optionsBox.Refresh()
+			refreshRun()
+		})
+	})
+	if !ps.Settings.AI.Enabled() {
+		aiBtn.Hide()
 	}
 
-	// Erstelle src/index.ts
-	if err := os.MkdirAll("src", 0755); err != nil {
-		return fmt.Errorf("src verzeichnis erstellen fehlgeschlagen: %v", err)
-	}
-
-	indexContent := `class Greeter {
-    constructor(private name: string) {}
-
-    greet(): void {
-        console.log("Hello, " + this.name + "!");
-    }
-}
-
-const greeter = new Greeter("World");
-greeter.greet();`
-
-	if err := os.WriteFile("src/index.ts", []byte(indexContent), 0644); err != nil {
-		return fmt.Errorf("index.ts erstellen fehlgeschlagen: %v", err)
-	}
-
-	return ps.openTerminal(projectDir, "npx tsc && node dist/index.js")
-}
-
-func (ps *ProjectSetup) createCPlusPlusProject() error {
-	log.Println("Erstelle C++-Projekt...")
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-
-	// Erstelle Projektstruktur
-	dirs := []string{
-		"src",
-		"include",
-		"build",
-	}
+	snippetCheck := widget.NewCheckGroup(nil, func(selected []string) {
+		ps.Snippets = selected
+	})
+	snippetCheck.Horizontal = true
+	addonCheck := widget.NewCheckGroup(nil, func(selected []string) {
+		ps.Addons = selected
+	})
+	addonCheck.Horizontal = true
 
-	// Erstelle Projektverzeichnis und Unterverzeichnisse
-	if err := os.MkdirAll(projectDir, 0755); err != nil {
-		return fmt.Errorf("projektverzeichnis erstellen fehlgeschlagen: %v", err)
+	// Toolkit-Auswahl, nur für Projekttypen mit Toolkits sichtbar
+	toolkitLabel := widget.NewLabel("Framework:")
+	toolkitSelect := widget.NewSelect(nil, func(value string) {
+		ps.ToolkitName = value
+		refreshRun()
+	})
+	// Layout-Auswahl, nur für Projekttypen mit Layouts sichtbar
+	layoutLabel := widget.NewLabel("Layout:")
+	layoutSelect := widget.NewSelect(nil, func(value string) {
+		ps.LayoutName = value
+		refreshRun()
+	})
+	// Paketmanager, nur für JavaScript und TypeScript sichtbar
+	pmLabel := widget.NewLabel("Package Manager:")
+	pmSelect := widget.NewSelect(core.PackageManagerNames(), func(value string) {
+		ps.PackageManager = value
+		refreshRun()
+	})
+	if pm, err := core.FindPackageManager(ps.PackageManager); err == nil {
+		pmSelect.SetSelected(pm.Name)
 	}
-	for _, dir := range dirs {
-		path := filepath.Join(projectDir, dir)
-		if err := os.MkdirAll(path, 0755); err != nil {
-			return fmt.Errorf("verzeichnis %s erstellen fehlgeschlagen: %v", dir, err)
+	refreshPackageManager := func() {
+		if ps.NpmProject() {
+			pmLabel.Show()
+			pmSelect.Show()
+		} else {
+			pmLabel.Hide()
+			pmSelect.Hide()
 		}
 	}
-
-	// Erstelle CMakeLists.txt
-	cmakeContent := fmt.Sprintf(`cmake_minimum_required(VERSION 3.10)
-project(%s)
-
-set(CMAKE_CXX_STANDARD 17)
-set(CMAKE_CXX_STANDARD_REQUIRED ON)
-
-add_executable(${PROJECT_NAME} src/main.cpp)
-target_include_directories(${PROJECT_NAME} PRIVATE include)`, ps.projectName)
-
-	if err := os.WriteFile(filepath.Join(projectDir, "CMakeLists.txt"), []byte(cmakeContent), 0644); err != nil {
-		return fmt.Errorf("CMakeLists.txt erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Erstelle main.cpp
-	mainContent := `#include <iostream>
-
-int main() {
-    std::cout << "Hello, C++17!" << std::endl;
-    return 0;
-}`
-
-	if err := os.WriteFile(filepath.Join(projectDir, "src", "main.cpp"), []byte(mainContent), 0644); err != nil {
-		return fmt.Errorf("main.cpp erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Öffne Terminal
-	terminalCmd := fmt.Sprintf("wezterm start --cwd '%s' --always-new-process -- bash -c 'cd build && cmake .. && make && echo \"Build abgeschlossen.\" && bash'", projectDir)
-	cmd := exec.Command("sh", "-c", terminalCmd)
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("terminal öffnen fehlgeschlagen: %v", err)
-	}
-
-	return nil
-}
-
-func (ps *ProjectSetup) createCSharpProject() error {
-	log.Println("Erstelle C#-Projekt...")
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-
-	// Erstelle neues .NET Projekt
-	cmd := exec.Command("dotnet", "new", "console", "-n", ps.projectName)
-	cmd.Dir = ps.parentPath
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("dotnet new fehlgeschlagen: %v", err)
-	}
-
-	return ps.openTerminal(projectDir, "dotnet run")
-}
-
-func (ps *ProjectSetup) createJavaProject() error {
-	log.Println("Erstelle Java-Projekt...")
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-
-	// Erstelle Projektstruktur
-	srcPath := filepath.Join(projectDir, "src", "main", "java")
-	if err := os.MkdirAll(srcPath, 0755); err != nil {
-		return fmt.Errorf("verzeichnis struktur erstellen fehlgeschlagen: %v", err)
-	}
-
-	// Erstelle Main.java
-	mainContent := `public class Main {
-    public static void main(String[] args) {
-        System.out.println("Hello, Java!");
-    }
-}`
-
-	if err := os.WriteFile(filepath.Join(srcPath, "Main.java"), []byte(mainContent), 0644); err != nil {
-		return fmt.Errorf("Main.java erstellen fehlgeschlagen: %v", err)
-	}
-
-	return ps.openTerminal(projectDir, "javac src/main/java/Main.java && java -cp src/main/java Main")
-}
-
-// Hilfsfunktion für das Öffnen des Terminals
-func (ps *ProjectSetup) openTerminal(dir string, command string) error {
-	// Füge eine kleine Verzögerung hinzu
-	time.Sleep(100 * time.Millisecond)
-
-	terminalCmd := fmt.Sprintf("wezterm start --cwd '%s' --always-new-process -- bash -c 'echo \"Running: %s\"; %s; exec bash'",
-		dir, command, command)
-
-	cmd := exec.Command("sh", "-c", terminalCmd)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("terminal öffnen fehlgeschlagen: %v", err)
-	}
-
-	// Warte kurz nach dem Öffnen
-	time.Sleep(100 * time.Millisecond)
-	return nil
-}
-
-func isValidProjectName(name string) (bool, string) {
-	if name == "" {
-		return false, "Projektname darf nicht leer sein"
-	}
-	if len(name) > 255 {
-		return false, "Projektname ist zu lang (max 255 Zeichen)"
+	// Modulpfad, nur für Go-Projekte sichtbar
+	moduleLabel := widget.NewLabel("Module Path:")
+	moduleEntry := widget.NewEntry()
+	moduleEntry.OnChanged = func(value string) {
+		ps.ModulePath = strings.TrimSpace(value)
+	}
+	refreshModule := func() {
+		moduleEntry.SetPlaceHolder(ps.DefaultGoModule())
+		if ps.ProjectType == core.Go {
+			moduleLabel.Show()
+			moduleEntry.Show()
+		} else {
+			moduleLabel.Hide()
+			moduleEntry.Hide()
+		}
 	}
-	if strings.ContainsAny(name, "/\\:*?\"<>|$%&# \t\n") {
-		return false, "Projektname darf nur Buchstaben, Zahlen, Unterstriche und Bindestriche enthalten"
+	refreshLayouts := func() {
+		ps.LayoutName = ""
+		lang := ps.Language()
+		if lang == nil || len(lang.Layouts) == 0 {
+			layoutLabel.Hide()
+			layoutSelect.Hide()
+			return
+		}
+		layoutSelect.Options = lang.LayoutNames()
+		layoutSelect.SetSelected(lang.Layouts[0].Name)
+		layoutLabel.Show()
+		layoutSelect.Show()
+	}
+	refreshToolkits := func() {
+		ps.ToolkitName = ""
+		lang := ps.Language()
+		if lang == nil || len(lang.Toolkits) == 0 {
+			toolkitLabel.Hide()
+			toolkitSelect.Hide()
+			return
+		}
+		toolkitSelect.Options = lang.ToolkitNames()
+		if tk, err := ps.Toolkit(); err == nil {
+			toolkitSelect.SetSelected(tk.Name)
+		}
+		toolkitLabel.Show()
+		toolkitSelect.Show()
 	}
-	return true, ""
-}
-
-func main() {
-	log.Println("Starte Anwendung...")
-	myApp := app.New()
-
-	// Korrigierte Icon-Setzung
-	icon := fyne.NewStaticResource("icon", iconData)
-	myApp.SetIcon(icon)
-
-	window := myApp.NewWindow("Project Setup")
-
-	ps := NewProjectSetup()
-	ps.window = window
 
 	// UI-Komponenten erstellen
-	projectTypeRadio := widget.NewRadioGroup([]string{
-		"Python",
-		"Go",
-		"Rust",
-		"JavaScript",
-		"TypeScript",
-		"C++",
-		"C#",
-		"Java",
-	}, func(value string) {
-		switch value {
-		case "Python":
-			ps.projectType = Python
-		case "Go":
-			ps.projectType = Go
-		case "Rust":
-			ps.projectType = Rust
-		case "JavaScript":
-			ps.projectType = JavaScript
-		case "TypeScript":
-			ps.projectType = TypeScript
-		case "C++":
-			ps.projectType = CPlusPlus
-		case "C#":
-			ps.projectType = CSharp
-		case "Java":
-			ps.projectType = Java
+	projectTypeRadio := widget.NewRadioGroup(core.ProjectTypeNames, func(value string) {
+		if pt, ok := core.ParseProjectType(value); ok {
+			ps.ProjectType = pt
 		}
+		refreshLayouts()
+		refreshToolkits()
+		refreshModule()
+		refreshPackageManager()
+		snippetCheck.Options = snippetNames(ps.ProjectType)
+		snippetCheck.SetSelected(nil)
+		addonCheck.Options = core.AddonNames(ps.ProjectType)
+		addonCheck.SetSelected(nil)
+		refreshTemplates()
+		refreshRun()
 		log.Printf("Projekttyp gewählt: %s", value)
 	})
 	projectTypeRadio.SetSelected("Python")
 
 	var parentPathBtn *widget.Button
-	parentPathBtn = widget.NewButton(ps.parentPath, func() {
+	parentPathBtn = widget.NewButton(ps.ParentPath, func() {
 		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
 			if err != nil {
 				log.Printf("Fehler bei Ordnerauswahl: %v", err)
@@ -741,42 +280,143 @@ func main() {
 			if uri == nil {
 				return
 			}
-			ps.parentPath = uri.Path()
-			parentPathBtn.SetText(ps.parentPath)
-			if err := ps.saveProjectPath(); err != nil {
+			ps.ParentPath = uri.Path()
+			parentPathBtn.SetText(ps.ParentPath)
+			if err := ps.SaveProjectPath(); err != nil {
 				log.Printf("Fehler beim Speichern des Pfads: %v", err)
 			}
 		}, window)
 	})
 
+	// Profilauswahl; ohne konfigurierte Profile wird sie ausgeblendet
+	const noProfile = "(kein Profil)"
+	// Push nur anbieten, wenn das Profil eine Remote-URL hat
+	pushCheck := widget.NewCheck("Push to remote", func(checked bool) {
+		ps.Push = checked
+	})
+	refreshPush := func() {
+		if ps.Profile().RemoteURL == "" {
+			pushCheck.SetChecked(false)
+			pushCheck.Hide()
+		} else {
+			pushCheck.Show()
+		}
+	}
+
+	profileSelect := widget.NewSelect(append([]string{noProfile}, ps.Settings.ProfileNames()...), func(value string) {
+		name := value
+		if name == noProfile {
+			name = ""
+		}
+		if err := ps.SelectProfile(name); err != nil {
+			log.Printf("Fehler beim Wechseln des Profils: %v", err)
+			return
+		}
+		parentPathBtn.SetText(ps.ParentPath)
+		refreshPush()
+		if err := core.UpdateSettingsFile(func(s *core.Settings) { s.Profile = name }); err != nil {
+			log.Printf("Fehler beim Speichern des Profils: %v", err)
+		}
+		log.Printf("Profil gewählt: %s", value)
+	})
+	if ps.ProfileName != "" {
+		profileSelect.SetSelected(ps.ProfileName)
+	} else {
+		profileSelect.SetSelected(noProfile)
+	}
+	profileLabel := widget.NewLabel("Profile:")
+	if len(ps.Settings.Profiles) == 0 {
+		profileLabel.Hide()
+		profileSelect.Hide()
+	}
+
+	// Versionskontrolle; die Git-Optionen daneben gelten nur für Git
+	const noVCS = "None"
+	vcsOptions := []string{noVCS}
+	for _, v := range core.VcsSystems {
+		vcsOptions = append(vcsOptions, v.Label)
+	}
+	vcsSelect := widget.NewSelect(vcsOptions, func(value string) {
+		ps.SkipGit = value == noVCS
+		if v, ok := core.FindVCS(value); ok {
+			ps.Vcs = v.Name
+		}
+	})
+	vcsSelect.SetSelected(ps.VersionControl().Label)
+	hooksCheck := widget.NewCheck("Native git hooks", func(checked bool) {
+		ps.GitHooks = checked
+	})
+	hooksCheck.SetChecked(ps.GitHooks)
+	releaseCheck := widget.NewCheck("Release task", func(checked bool) {
+		ps.ReleaseTask = checked
+	})
+	releaseCheck.SetChecked(ps.ReleaseTask)
+	signCheck := widget.NewCheck("Sign commits", func(checked bool) {
+		ps.SignCommits = checked
+	})
+	signCheck.SetChecked(ps.SignCommits)
+	lfsCheck = widget.NewCheck("Git LFS", func(checked bool) {
+		ps.GitLFS = checked
+	})
+	lfsCheck.SetChecked(ps.GitLFS)
+	scratchCheck := widget.NewCheck("Scratch project", func(checked bool) {
+		ps.Scratch = checked
+	})
+	sandboxCheck := widget.NewCheck("Sandbox", nil)
+
+	// SBOM-Auswahl; "None" erzeugt keins
+	sbomSelect := widget.NewSelect([]string{"None", "CycloneDX", "SPDX"}, func(value string) {
+		ps.SbomFormat = strings.ToLower(value)
+		if value == "None" {
+			ps.SbomFormat = ""
+		}
+	})
+	sbomSelect.SetSelected("None")
+
+	auditCheck := widget.NewCheck("Audit dependencies", func(checked bool) {
+		ps.Audit = checked
+	})
+	// Funde vor dem ersten Commit anzeigen; die Erstellung wartet auf die Bestätigung
+	showFindings := func(title, text string) {
+		done := make(chan struct{})
+		details := widget.NewLabel(text)
+		details.TextStyle.Monospace = true
+		d := dialog.NewCustom(title, "Continue", container.NewVScroll(details), window)
+		d.Resize(fyne.NewSize(450, 300))
+		d.SetOnClosed(func() { close(done) })
+		d.Show()
+		<-done
+	}
+	ps.OnAudit = func(r *core.AuditReport) { showFindings("Vulnerabilities found", r.Details()) }
+	licensesCheck := widget.NewCheck("License summary", func(checked bool) {
+		ps.Licenses = checked
+	})
+	// Regelt die Richtlinie Lizenzen, läuft die Übersicht immer
+	if ps.Settings.Policy.RestrictsLicenses() {
+		licensesCheck.SetChecked(true)
+		licensesCheck.Disable()
+	}
+	ps.OnLicenses = func(r *core.LicenseReport) { showFindings("License policy violations", r.Details()) }
+
 	var createBtn *widget.Button
 	projectNameEntry := widget.NewEntry()
 
-	// Status-Label mit fester Breite
+	// Status-Label, lange Meldungen werden umbrochen statt gekürzt
 	statusLabel := widget.NewLabel("")
 	statusLabel.Alignment = fyne.TextAlignCenter
-
-	// Container für Status mit fester Breite
-	statusContainer := container.NewHBox(
-		layout.NewSpacer(),
-		container.NewPadded(statusLabel),
-		layout.NewSpacer(),
-	)
+	statusLabel.Wrapping = fyne.TextWrapWord
 
 	// Funktion zum Aktualisieren der Statusmeldung
 	updateStatus := func(msg string) {
 		log.Printf("Zeige Nachricht: %s", msg)
-		// Kürze die Nachricht auf maximal 50 Zeichen
-		if len(msg) > 50 {
-			msg = msg[:47] + "..."
-		}
 		statusLabel.SetText(msg)
 	}
 
 	// OnChanged-Handler für projectNameEntry
 	projectNameEntry.OnChanged = func(value string) {
-		ps.projectName = value
-		valid, msg := isValidProjectName(value)
+		ps.ProjectName = value
+		refreshModule()
+		valid, msg := core.IsValidProjectName(value)
 		if !valid {
 			projectNameEntry.SetText(strings.Map(func(r rune) rune {
 				if strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-", r) {
@@ -791,189 +431,1298 @@ func main() {
 		}
 	}
 
-	// Setze feste Fensterbreite
+	// Startgröße; bei großen Schriften oder HiDPI darf das Fenster wachsen
 	window.Resize(fyne.NewSize(500, 300))
-	window.SetFixedSize(true)
+	restorePlacement(myApp, window, readPlacement())
+	window.SetCloseIntercept(func() {
+		savePlacement(window)
+		window.Close()
+	})
 
 	progress := widget.NewProgressBarInfinite()
 	progress.Hide()
+	// Gibt es Zeitmessungen früherer Läufe, zeigt estimateBar Prozent und Restdauer
+	estimateBar := widget.NewProgressBar()
+	estimateBar.Hide()
+
+	// resumeState ist gesetzt, wenn der nächste Klick eine unterbrochene Erstellung fortsetzt
+	var resumeState *core.CreationState
+	// startCreation beginnt die Erstellung, nachdem die Checkliste bestätigt
+	// wurde; skips gelten nur für diesen Lauf
+	var startCreation func(skips core.StepSkips)
+	// sandboxParent ist der eigentliche Projektpfad, solange ps.ParentPath auf
+	// eine Sandbox zeigt; jeder Versuch bekommt eine frische Sandbox
+	var sandboxParent string
+	projectParent := func() string {
+		if sandboxParent != "" {
+			return sandboxParent
+		}
+		return ps.ParentPath
+	}
 
 	// Initialisiere createBtn
 	createBtn = widget.NewButton("Create Project", func() {
+		// Hooks aus Marktplatz-Templates und -Add-ons laufen erst nach ausdrücklicher Zustimmung
+		parts := []*core.Template{ps.Template}
+		for _, name := range ps.Addons {
+			parts = append(parts, core.FindAddon(ps.ProjectType, name))
+		}
+		for _, t := range parts {
+			if t == nil || !t.NeedsTrust(ps.Settings) {
+				continue
+			}
+			dialog.ShowConfirm("Trust template?", t.TrustSummary(), func(trusted bool) {
+				if !trusted {
+					return
+				}
+				if err := ps.TrustTemplate(t); err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				createBtn.OnTapped()
+			}, window)
+			return
+		}
+		// Fehlende Pflichtoptionen vor der Prüfung abfragen
+		if ps.Template != nil {
+			if missing := ps.Template.MissingOptions(ps.Options); len(missing) > 0 {
+				showMissingOptionsDialog(window, missing, ps.Options, createBtn.OnTapped)
+				return
+			}
+		}
+
+		createBtn.Disable()
+		updateStatus("Prüfe Voraussetzungen...")
+		resuming := resumeState != nil
+		if sandboxParent != "" {
+			os.Remove(ps.ParentPath)
+			ps.ParentPath, sandboxParent = sandboxParent, ""
+		}
+		if sandboxCheck.Checked && !resuming {
+			parent := ps.ParentPath
+			if err := ps.EnterSandbox(); err != nil {
+				createBtn.Enable()
+				statusLabel.SetText("")
+				dialog.ShowError(err, window)
+				return
+			}
+			sandboxParent = parent
+		}
+		if resuming {
+			// Die Prüfung gilt dem fortgesetzten Projekt, nicht den Eingaben
+			ps.ProjectType, ps.ProjectName, ps.ParentPath = resumeState.Type, resumeState.Name, resumeState.ParentPath
+		}
+		go func() {
+			checks := ps.Preflight(resuming)
+			createBtn.Enable()
+			statusLabel.SetText("")
+			showPreflight(window, checks, ps.Skips(), startCreation)
+		}()
+	})
+
+	startCreation = func(skips core.StepSkips) {
+		ps.Preflighted = true
+		restore := ps.ApplySkips(skips)
+		if command := strings.TrimSpace(runEntry.Text); command != ps.StartCommand() {
+			if err := ps.RememberRunCommand(command); err != nil {
+				log.Printf("Startbefehl speichern fehlgeschlagen: %v", err)
+			}
+		}
 		// Deaktiviere UI-Elemente
 		createBtn.Disable()
 		projectNameEntry.Disable()
 		parentPathBtn.Disable()
 		projectTypeRadio.Disable()
-		progress.Show()
+		templateSelect.Disable()
+		toolkitSelect.Disable()
+		layoutSelect.Disable()
+		pmSelect.Disable()
+		moduleEntry.Disable()
+		runEntry.Disable()
+		envBtn.Disable()
+		aiBtn.Disable()
+		snippetCheck.Disable()
+		addonCheck.Disable()
+		profileSelect.Disable()
+		vcsSelect.Disable()
+		hooksCheck.Disable()
+		releaseCheck.Disable()
+		signCheck.Disable()
+		lfsCheck.Disable()
+		pushCheck.Disable()
+		auditCheck.Disable()
+		licensesCheck.Disable()
+		sbomSelect.Disable()
+		done := make(chan struct{})
+		if estimate := ps.LoadEstimate(); estimate != nil {
+			estimateBar.SetValue(0)
+			estimateBar.Show()
+			var mu sync.Mutex
+			currentStep, stepStart := "", time.Now()
+			ps.Progress = func(step string) {
+				mu.Lock()
+				currentStep, stepStart = step, time.Now()
+				mu.Unlock()
+			}
+			go func() {
+				ticker := time.NewTicker(500 * time.Millisecond)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case <-ticker.C:
+					}
+					mu.Lock()
+					fraction, remaining, ok := estimate.At(currentStep, time.Since(stepStart))
+					mu.Unlock()
+					// Unbekannte Schritte lassen die Anzeige stehen, zurück läuft sie nie
+					if ok && fraction >= estimateBar.Value {
+						estimateBar.SetValue(fraction)
+						statusLabel.SetText("Erstelle Projekt... " + core.FormatETA(remaining))
+					}
+				}
+			}()
+		} else {
+			progress.Show()
+		}
 
 		// Starte Projekterstellung
 		updateStatus("Erstelle Projekt...")
 		go func() {
-			if err := ps.createProject(); err != nil {
+			var err error
+			if s := resumeState; s != nil {
+				resumeState = nil
+				err = ps.Resume(s)
+			} else {
+				err = ps.CreateProject()
+			}
+			restore()
+			close(done)
+			if err != nil {
 				log.Printf("Fehler bei Projekterstellung: %v", err)
 				updateStatus("Fehler: " + err.Error())
+				if path, link := core.ReportFailure(ps.Settings, nil, err); path != "" {
+					dialog.ShowConfirm("Error report saved", "Bericht: "+path+"\nOpen a prefilled GitHub issue?", func(ok bool) {
+						if u, err := url.Parse(link); ok && err == nil {
+							myApp.OpenURL(u)
+						}
+					}, window)
+				}
 				createBtn.Enable()
 				projectNameEntry.Enable()
 				parentPathBtn.Enable()
 				projectTypeRadio.Enable()
+				templateSelect.Enable()
+				toolkitSelect.Enable()
+				layoutSelect.Enable()
+				pmSelect.Enable()
+				moduleEntry.Enable()
+				runEntry.Enable()
+				envBtn.Enable()
+				aiBtn.Enable()
+				snippetCheck.Enable()
+				addonCheck.Enable()
+				profileSelect.Enable()
+				vcsSelect.Enable()
+				hooksCheck.Enable()
+				releaseCheck.Enable()
+				signCheck.Enable()
+				if ps.Template == nil || len(ps.Template.LFS) == 0 {
+					lfsCheck.Enable()
+				}
+				pushCheck.Enable()
+				auditCheck.Enable()
+				if !ps.Settings.Policy.RestrictsLicenses() {
+					licensesCheck.Enable()
+				}
+				sbomSelect.Enable()
 				progress.Hide()
+				estimateBar.Hide()
 			} else {
 				updateStatus("Projekt erfolgreich erstellt")
+				savePlacement(window)
 				os.Exit(0)
 			}
 		}()
-	})
+	}
+
+	// Update-Hinweis, nur bei aktivierter Prüfung
+	updatesBtn := widget.NewButtonWithIcon("", theme.InfoIcon(), nil)
+	updatesBtn.Importance = widget.LowImportance
+	updatesBtn.Hide()
+	if ps.Settings.UpdateCheck {
+		var refreshUpdates func()
+		refreshUpdates = func() {
+			updates := core.CachedUpdates(context.Background(), ps.Settings)
+			promptAppUpdate(myApp, window, ps.Settings, updates)
+			updatesBtn.SetText(fmt.Sprintf("%d Updates", len(updates)))
+			updatesBtn.OnTapped = func() {
+				showUpdatesDialog(window, ps.Settings, updates, refreshTemplates)
+			}
+			if len(updates) > 0 {
+				updatesBtn.Show()
+			} else {
+				updatesBtn.Hide()
+			}
+		}
+		go func() {
+			for {
+				refreshUpdates()
+				time.Sleep(core.UpdateInterval)
+			}
+		}()
+	}
 
 	// Layout erstellen
 	content := container.NewVBox(
-		widget.NewLabel("Project Setup"),
+		container.NewHBox(widget.NewLabel("Project Setup"), layout.NewSpacer(), updatesBtn,
+			widget.NewButtonWithIcon("", theme.SettingsIcon(), func() { showPreferences(window) })),
 		container.NewHBox(layout.NewSpacer(), projectTypeRadio, layout.NewSpacer()),
 		container.NewGridWithColumns(2,
+			toolkitLabel,
+			toolkitSelect,
+			layoutLabel,
+			layoutSelect,
+			pmLabel,
+			pmSelect,
+			moduleLabel,
+			moduleEntry,
+			widget.NewLabel("Template:"),
+			templateSelect,
+			widget.NewLabel("Run Command:"),
+			runEntry,
+			widget.NewLabel("Environment:"),
+			envBtn,
+		),
+		aiBtn,
+		optionsBox,
+		container.NewBorder(nil, nil, widget.NewLabel("Add-ons:"), nil, addonCheck),
+		container.NewBorder(nil, nil, widget.NewLabel("Snippets:"), nil, snippetCheck),
+		container.NewGridWithColumns(2,
+			profileLabel,
+			profileSelect,
 			widget.NewLabel("Parent Path:"),
 			parentPathBtn,
 			widget.NewLabel("Project Name:"),
-			projectNameEntry,
+			container.NewBorder(nil, nil, nil, container.NewHBox(scratchCheck, sandboxCheck), projectNameEntry),
 		),
+		container.NewHBox(widget.NewLabel("VCS:"), vcsSelect, hooksCheck, releaseCheck, signCheck, lfsCheck, pushCheck, auditCheck, licensesCheck, widget.NewLabel("SBOM:"), sbomSelect),
 		createBtn,
+		widget.NewButton("Add Snippets to Existing Project...", func() {
+			showSnippetDialog(window)
+		}),
+		widget.NewButton("New from GitHub Template...", func() {
+			showGitHubTemplateDialog(window, ps, projectParent())
+		}),
+		widget.NewButton("Undo Last Creation...", func() {
+			showUndoDialog(window)
+		}),
+		container.NewGridWithColumns(2,
+			widget.NewButton("Clean Up Scratch Projects...", func() {
+				showCleanupDialog(window, ps.Settings)
+			}),
+			widget.NewButton("Promote Sandbox Project...", func() {
+				showPromoteSandboxDialog(window, projectParent())
+			}),
+		),
+		widget.NewButton("Save Directory as Template...", func() {
+			showSaveTemplateDialog(window, refreshTemplates)
+		}),
+		container.NewGridWithColumns(3,
+			widget.NewButton("Export Template...", func() {
+				showExportTemplateDialog(window, ps.Template)
+			}),
+			widget.NewButton("Watch Template...", func() {
+				showWatchTemplateDialog(window, ps.Settings, ps.Template)
+			}),
+			widget.NewButton("Import Template...", func() {
+				showImportArchiveDialog(window, refreshTemplates)
+			}),
+		),
 		progress,
-		statusContainer, // Verwende den Container mit fester Höhe
+		estimateBar,
+		statusLabel,
+	)
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Create", content),
+		container.NewTabItem("Discover", discoverTab(window, ps.Settings, refreshTemplates)),
+		container.NewTabItem("Stats", statsTab()),
+		container.NewTabItem("Projects", projectsTab(window, ps)),
 	)
+	window.SetContent(tabs)
 
-	window.SetContent(content)
+	// Auf das Fenster gezogene Template-Archive werden importiert
+	window.SetOnDropped(func(_ fyne.Position, uris []fyne.URI) {
+		for _, uri := range uris {
+			if strings.HasSuffix(uri.Path(), core.ArchiveExtension) {
+				importArchiveGUI(window, uri.Path(), refreshTemplates)
+			}
+		}
+	})
+
+	// Befehlspalette (Strg+K) mit allen Aktionen; die Liste wird bei jedem
+	// Öffnen neu gebaut, damit neue Templates und Projekte erscheinen
+	paletteActions := func() []paletteAction {
+		enabled := !createBtn.Disabled()
+		actions := []paletteAction{}
+		if enabled {
+			actions = append(actions, paletteAction{"Create Project", func() {
+				tabs.SelectIndex(0)
+				createBtn.OnTapped()
+			}})
+			for _, t := range core.Templates {
+				if t.Abstract {
+					continue
+				}
+				actions = append(actions, paletteAction{
+					fmt.Sprintf("Create with template: %s (%s)", t.Label(), t.Type),
+					func() {
+						tabs.SelectIndex(0)
+						projectTypeRadio.SetSelected(t.Type.String())
+						templateSelect.SetSelected(t.Label())
+						window.Canvas().Focus(projectNameEntry)
+					},
+				})
+			}
+		}
+		for _, dir := range core.RecentProjects() {
+			actions = append(actions, paletteAction{"Open recent project: " + dir, func() {
+				if err := ps.OpenProject(dir); err != nil {
+					dialog.ShowError(err, window)
+				}
+			}})
+		}
+		actions = append(actions,
+			paletteAction{"Open Settings", func() { showPreferences(window) }},
+			paletteAction{"View Logs", func() { showLogs(window, logs) }},
+			paletteAction{"Add Snippets to Existing Project...", func() { showSnippetDialog(window) }},
+			paletteAction{"New from GitHub Template...", func() { showGitHubTemplateDialog(window, ps, projectParent()) }},
+			paletteAction{"Undo Last Creation...", func() { showUndoDialog(window) }},
+			paletteAction{"Clean Up Scratch Projects...", func() { showCleanupDialog(window, ps.Settings) }},
+			paletteAction{"Promote Sandbox Project...", func() { showPromoteSandboxDialog(window, projectParent()) }},
+			paletteAction{"Save Directory as Template...", func() { showSaveTemplateDialog(window, refreshTemplates) }},
+			paletteAction{"Export Template...", func() { showExportTemplateDialog(window, ps.Template) }},
+			paletteAction{"Import Template...", func() { showImportArchiveDialog(window, refreshTemplates) }},
+			paletteAction{"Watch Template...", func() { showWatchTemplateDialog(window, ps.Settings, ps.Template) }},
+			paletteAction{"Show Discover", func() { tabs.SelectIndex(1) }},
+			paletteAction{"Show Stats", func() { tabs.SelectIndex(2) }},
+			paletteAction{"Show Projects", func() { tabs.SelectIndex(3) }},
+		)
+		if enabled && ps.Settings.AI.Enabled() {
+			actions = append(actions, paletteAction{"Generate with AI...", func() {
+				tabs.SelectIndex(0)
+				aiBtn.OnTapped()
+			}})
+		}
+		if updatesBtn.Visible() {
+			actions = append(actions, paletteAction{"Show Updates", func() { updatesBtn.OnTapped() }})
+		}
+		return actions
+	}
+	window.Canvas().AddShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyK, Modifier: fyne.KeyModifierShortcutDefault},
+		func(fyne.Shortcut) { showCommandPalette(window, paletteActions()) })
+
+	// Nach einem Absturz die unterbrochene Erstellung anbieten
+	if pending := core.PendingStates(); len(pending) > 0 {
+		s := pending[0]
+		msg := fmt.Sprintf("Die Erstellung von %s wurde unterbrochen (%d Phasen erledigt).\nFortsetzen?", s.Dir, len(s.Completed))
+		resumeDialog := dialog.NewConfirm("Resume interrupted project?", msg, func(resume bool) {
+			if !resume {
+				s.Remove()
+				return
+			}
+			resumeState = s
+			projectNameEntry.SetText(s.Name)
+			createBtn.OnTapped()
+		}, window)
+		resumeDialog.SetConfirmText("Resume")
+		resumeDialog.SetDismissText("Discard")
+		resumeDialog.Show()
+	}
 
 	window.ShowAndRun()
 }
 
-// Hilfsfunktionen für Installationsprüfungen
-func (ps *ProjectSetup) checkGoInstallation() error {
-	cmd := exec.Command("go", "version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("go ist nicht installiert: %v", err)
+// promptAppUpdate fragt einmal je neuer Version, ob sie geladen werden soll.
+// Unter Linux ersetzt sich das Programm dann selbst, sonst öffnet sich die Release-Seite.
+func promptAppUpdate(a fyne.App, window fyne.Window, settings core.Settings, updates []core.UpdateInfo) {
+	s := core.ReadUpdateState()
+	for _, u := range updates {
+		if u.Kind != "app" || s.Prompted == u.Latest {
+			continue
+		}
+		s.Prompted = u.Latest
+		s.Save()
+		msg := fmt.Sprintf("newpipi %s ist verfügbar (installiert: %s).", u.Latest, u.Installed)
+		dialog.ShowConfirm("Update available", msg+"\nDownload new version?", func(ok bool) {
+			if !ok {
+				return
+			}
+			if u.Asset == "" {
+				if link, err := url.Parse(u.URL); err == nil {
+					a.OpenURL(link)
+				}
+				return
+			}
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				defer cancel()
+				if err := core.ApplyUpdate(ctx, settings, u); err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				dialog.ShowInformation("Update", "Update installiert, wirksam nach dem nächsten Start.", window)
+			}()
+		}, window)
 	}
-	return nil
 }
 
-func (ps *ProjectSetup) checkRustInstallation() error {
-	cmd := exec.Command("rustc", "--version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("rust ist nicht installiert: %v", err)
-	}
-	return nil
+// showPreferences bearbeitet die Darstellungseinstellungen. Schrift und
+// Dichte wirken sofort, die Skalierung legt Fyne beim Start fest.
+func showPreferences(window fyne.Window) {
+	settings, err := core.ReadSettingsFile()
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	const auto = "Auto"
+	scales := []string{auto, "0.8", "1", "1.25", "1.5", "2"}
+	scale := widget.NewSelect(scales, nil)
+	scale.SetSelected(auto)
+	if settings.UIScale > 0 {
+		scale.SetSelected(strconv.FormatFloat(settings.UIScale, 'f', -1, 64))
+	}
+	fontSize := widget.NewSelect([]string{auto, "11", "12", "14", "16", "18", "20", "24"}, nil)
+	fontSize.SetSelected(auto)
+	if settings.FontSize > 0 {
+		fontSize.SetSelected(strconv.FormatFloat(float64(settings.FontSize), 'f', -1, 32))
+	}
+	density := widget.NewRadioGroup([]string{"compact", "normal", "comfortable"}, nil)
+	density.Horizontal = true
+	density.SetSelected("normal")
+	if settings.Density != "" {
+		density.SetSelected(settings.Density)
+	}
+	form := widget.NewForm(
+		widget.NewFormItem("UI Scale", scale),
+		widget.NewFormItem("Font Size", fontSize),
+		widget.NewFormItem("Density", density),
+	)
+	dialog.ShowCustomConfirm("Preferences", "Save", "Cancel", form, func(save bool) {
+		if !save {
+			return
+		}
+		oldScale := settings.UIScale
+		err := core.UpdateSettingsFile(func(s *core.Settings) {
+			s.UIScale, _ = strconv.ParseFloat(scale.Selected, 64)
+			size, _ := strconv.ParseFloat(fontSize.Selected, 32)
+			s.FontSize = float32(size)
+			s.Density = density.Selected
+			if s.Density == "normal" {
+				s.Density = ""
+			}
+			settings = *s
+		})
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		// Der Theme-Wechsel zeichnet alle Fenster neu
+		fyne.CurrentApp().Settings().SetTheme(newAppTheme(settings))
+		if settings.UIScale != oldScale {
+			dialog.ShowInformation("Preferences", "Die Skalierung wirkt nach dem nächsten Start.", window)
+		}
+	}, window)
 }
 
-func (ps *ProjectSetup) validate() error {
-	if valid, msg := isValidProjectName(ps.projectName); !valid {
-		return fmt.Errorf(msg)
-	}
-	if _, err := os.Stat(ps.parentPath); os.IsNotExist(err) {
-		return fmt.Errorf("ausgewählter pfad existiert nicht")
+// showUpdatesDialog listet die verfügbaren Updates mit ihren Aktionen.
+func showUpdatesDialog(window fyne.Window, settings core.Settings, updates []core.UpdateInfo, onTemplateUpdated func()) {
+	list := container.NewVBox()
+	for _, u := range updates {
+		var action fyne.CanvasObject
+		if u.Automatic() {
+			var btn *widget.Button
+			btn = widget.NewButton("Update", func() {
+				btn.Disable()
+				go func() {
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+					defer cancel()
+					if err := core.ApplyUpdate(ctx, settings, u); err != nil {
+						dialog.ShowError(err, window)
+						btn.Enable()
+						return
+					}
+					btn.SetText("Done")
+					if u.Kind == "template" {
+						onTemplateUpdated()
+					}
+				}()
+			})
+			action = btn
+		} else {
+			link, _ := url.Parse(u.URL)
+			action = widget.NewHyperlink("Download", link)
+		}
+		// Changelog-Einträge eines Template-Updates unter der Zeile
+		text := strings.Join(append([]string{u.String()}, u.Changes...), "\n  ")
+		list.Add(container.NewBorder(nil, nil, nil, action, widget.NewLabel(text)))
 	}
-	return nil
+	dialog.ShowCustom("Updates", "Close", list, window)
 }
 
-func (ps *ProjectSetup) showProjectPreview() string {
-	return fmt.Sprintf(
-		"Projektübersicht:\n"+
-			"- Name: %s\n"+
-			"- Typ: %s\n"+
-			"- Pfad: %s\n"+
-			"- Geschätzte Größe: ~%dMB",
-		ps.projectName,
-		[]string{"Python", "Go", "Rust"}[ps.projectType],
-		filepath.Join(ps.parentPath, ps.projectName),
-		ps.estimateProjectSize(),
+// statsTab zeigt die lokale Nutzungsstatistik und darunter die langsamsten
+// Phasen; "Refresh" liest beides neu ein.
+func statsTab() fyne.CanvasObject {
+	rows := core.UsageRows()
+	table := widget.NewTable(
+		func() (int, int) { return len(rows) + 1, len(core.UsageHeader) },
+		func() fyne.CanvasObject { return widget.NewLabel("Type/Template              ") },
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			label := cell.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(core.UsageHeader[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			label.SetText(rows[id.Row-1].Cells()[id.Col])
+		},
 	)
+	phases := core.PhaseRows(core.ReadTimings())
+	phaseTable := widget.NewTable(
+		func() (int, int) { return len(phases) + 1, len(core.PhaseHeader) },
+		func() fyne.CanvasObject { return widget.NewLabel("Type/Template              ") },
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			label := cell.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(core.PhaseHeader[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			label.SetText(phases[id.Row-1].Cells()[id.Col])
+		},
+	)
+	refresh := widget.NewButton("Refresh", func() {
+		rows = core.UsageRows()
+		phases = core.PhaseRows(core.ReadTimings())
+		table.Refresh()
+		phaseTable.Refresh()
+	})
+	return container.NewBorder(nil, refresh, nil, nil, container.NewVSplit(table, phaseTable))
 }
 
-func (ps *ProjectSetup) estimateProjectSize() int {
-	switch ps.projectType {
-	case Python:
-		return 50 // Python mit venv und Paketen
-	case Go:
-		return 30 // Go mit Fyne
-	case Rust:
-		return 100 // Rust mit Cargo und Druid
+// projectsTab zeigt die Speicherbelegung der verwalteten Projekte; die
+// Artefakte eines Ökosystems lassen sich je Projekt löschen. Gewählte Projekte
+// lassen sich gemeinsam bearbeiten (siehe internal/core/bulk.go). Gemessen wird im
+// Hintergrund, da node_modules und target groß werden.
+func projectsTab(window fyne.Window, ps *core.ProjectSetup) fyne.CanvasObject {
+	list := container.NewVBox()
+	status := widget.NewLabel("")
+	selected := make(map[string]bool)
+	checks := make(map[string]*widget.Check)
+	var dirs []string
+	var refresh func()
+	refresh = func() {
+		list.RemoveAll()
+		status.SetText("Measuring...")
+		go func() {
+			var sum int64
+			var rows []fyne.CanvasObject
+			dirs = core.ManagedProjects()
+			for _, dir := range dirs {
+				u, err := core.MeasureProject(dir)
+				if err != nil {
+					log.Printf("%v", err)
+					continue
+				}
+				sum += u.Total()
+				check := widget.NewCheck(fmt.Sprintf("%s\nSource %s, dependencies %s, total %s",
+					dir, core.FormatSize(u.Source), core.FormatSize(u.Dependencies()), core.FormatSize(u.Total())), func(on bool) { selected[dir] = on })
+				check.SetChecked(selected[dir])
+				checks[dir] = check
+				actions := container.NewHBox()
+				for _, k := range core.ArtifactKinds {
+					size, ok := u.Artifacts[k.Name]
+					if !ok {
+						continue
+					}
+					actions.Add(widget.NewButton(fmt.Sprintf("Clean %s (%s)", k.Name, core.FormatSize(size)), func() {
+						msg := fmt.Sprintf("%s in %s löschen?\nWiederherstellen mit: %s", strings.Join(k.Dirs, ", "), dir, k.Restore)
+						dialog.ShowConfirm("Clean "+k.Name+"?", msg, func(ok bool) {
+							if !ok {
+								return
+							}
+							if _, err := core.CleanArtifacts(dir, k); err != nil {
+								dialog.ShowError(err, window)
+							}
+							refresh()
+						}, window)
+					}))
+				}
+				rows = append(rows, container.NewBorder(nil, nil, nil, actions, check))
+			}
+			for _, row := range rows {
+				list.Add(row)
+			}
+			status.SetText(fmt.Sprintf("%d projects, %s total", len(rows), core.FormatSize(sum)))
+		}()
 	}
-	return 0
-}
 
-func (ps *ProjectSetup) checkDiskSpace() error {
-	var stat unix.Statfs_t
-	if err := unix.Statfs(ps.parentPath, &stat); err != nil {
-		return fmt.Errorf("konnte speicherplatz nicht prüfen: %v", err)
+	// Sammelaktionen laufen über die Warteschlange; Fehler zeigt ein Dialog am Ende
+	var bulkButtons []fyne.CanvasObject
+	run := func(action core.BulkAction) {
+		var chosen []string
+		for _, dir := range dirs {
+			if selected[dir] {
+				chosen = append(chosen, dir)
+			}
+		}
+		if len(chosen) == 0 {
+			dialog.ShowInformation(action.Label, "Keine Projekte gewählt", window)
+			return
+		}
+		start := func() {
+			for _, b := range bulkButtons {
+				b.(*widget.Button).Disable()
+			}
+			go func() {
+				var errs []error
+				core.RunJobs(core.BulkJobs(ps, action, chosen), core.MaxJobs, func(done int, r core.JobResult) {
+					status.SetText(fmt.Sprintf("%s: %d/%d", action.Label, done, len(chosen)))
+					if r.Err != nil {
+						errs = append(errs, fmt.Errorf("%s: %v", r.Name, r.Err))
+					}
+				})
+				for _, b := range bulkButtons {
+					b.(*widget.Button).Enable()
+				}
+				if len(errs) > 0 {
+					dialog.ShowError(errors.Join(errs...), window)
+				}
+				if action.Name != "terminal" {
+					refresh()
+				}
+			}()
+		}
+		if action.Name != "archive" {
+			start()
+			return
+		}
+		msg := fmt.Sprintf("%d Projekte als tar.gz archivieren und löschen?", len(chosen))
+		dialog.ShowConfirm("Archive projects?", msg, func(ok bool) {
+			if ok {
+				start()
+			}
+		}, window)
 	}
-
-	// Verfügbarer Speicher in MB
-	available := (stat.Bavail * uint64(stat.Bsize)) / 1024 / 1024
-	needed := uint64(ps.estimateProjectSize())
-
-	if available < needed {
-		return fmt.Errorf("nicht genug speicherplatz. benötigt: %dMB, verfügbar: %dMB", needed, available)
+	for _, action := range core.BulkActions {
+		bulkButtons = append(bulkButtons, widget.NewButton(action.Label, func() { run(action) }))
 	}
-	return nil
+	selectAll := widget.NewCheck("Select all", func(on bool) {
+		for _, check := range checks {
+			check.SetChecked(on)
+		}
+	})
+	refresh()
+	toolbar := container.NewHBox(append([]fyne.CanvasObject{selectAll}, bulkButtons...)...)
+	bottom := container.NewBorder(nil, nil, nil, widget.NewButton("Refresh", refresh), status)
+	return container.NewBorder(toolbar, bottom, nil, nil, container.NewVScroll(list))
 }
 
-func (ps *ProjectSetup) initGit() error {
-	projectDir := filepath.Join(ps.parentPath, ps.projectName)
-	cmd := exec.Command("git", "init")
-	cmd.Dir = projectDir
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("git-initialisierung fehlgeschlagen: %v", err)
+// showUndoDialog bietet an, das zuletzt erstellte Projekt wieder zu löschen.
+func showUndoDialog(window fyne.Window) {
+	last, err := core.ReadLastCreation()
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
 	}
+	msg := fmt.Sprintf("%s löschen?\nErstellt: %s", last.Dir, last.CreatedAt.Local().Format(time.DateTime))
+	dialog.ShowConfirm("Undo last creation?", msg, func(ok bool) {
+		if !ok {
+			return
+		}
+		dir, err := core.UndoLastCreation(false)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Undo", "Gelöscht: "+dir, window)
+	}, window)
+}
 
-	// Erstelle initial commit
-	commands := [][]string{
-		{"git", "add", "."},
-		{"git", "commit", "-m", "Initial commit"},
+// showCleanupDialog zeigt die abgelaufenen Wegwerfprojekte zum Archivieren,
+// Löschen oder Behalten.
+func showCleanupDialog(window fyne.Window, settings core.Settings) {
+	now := time.Now()
+	expired := core.ExpiredScratch(core.ScratchProjects(), settings.ScratchMaxAge(), now)
+	if len(expired) == 0 {
+		dialog.ShowInformation("Clean up", fmt.Sprintf("Keine Wegwerfprojekte älter als %d Tage", settings.ScratchMaxAge()), window)
+		return
+	}
+	selected := make(map[string]bool)
+	list := container.NewVBox()
+	for _, p := range expired {
+		check := widget.NewCheck(fmt.Sprintf("%s (%d Tage)", p.Dir, p.Age(now)), func(on bool) { selected[p.Dir] = on })
+		check.SetChecked(true)
+		list.Add(check)
+	}
+	var d dialog.Dialog
+	apply := func(action func(core.ScratchProject) error) {
+		d.Hide()
+		var errs []string
+		for _, p := range expired {
+			if !selected[p.Dir] {
+				continue
+			}
+			if err := action(p); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			dialog.ShowError(errors.New(strings.Join(errs, "\n")), window)
+		}
 	}
+	archiveBtn := widget.NewButton("Archive", func() {
+		apply(func(p core.ScratchProject) error {
+			_, err := core.ArchiveScratch(p)
+			return err
+		})
+	})
+	deleteBtn := widget.NewButton("Delete", func() {
+		dialog.ShowConfirm("Delete projects?", "Die gewählten Projekte werden ohne Archiv gelöscht.", func(ok bool) {
+			if ok {
+				apply(core.RemoveScratch)
+			}
+		}, window)
+	})
+	deleteBtn.Importance = widget.DangerImportance
+	keepBtn := widget.NewButton("Keep", func() {
+		apply(func(p core.ScratchProject) error { return core.ForgetScratch(p.Dir) })
+	})
+	content := container.NewBorder(nil, container.NewHBox(archiveBtn, deleteBtn, keepBtn), nil, nil, container.NewVScroll(list))
+	d = dialog.NewCustom("Scratch projects", "Close", content, window)
+	d.Resize(fyne.NewSize(550, 350))
+	d.Show()
+}
 
-	for _, args := range commands {
-		cmd := exec.Command(args[0], args[1:]...)
-		cmd.Dir = projectDir
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("git-befehl fehlgeschlagen: %v", err)
+// showGitHubTemplateDialog erzeugt ein Repository aus einem der eigenen
+// GitHub-Template-Repositorys, klont es und bietet das Bootstrap an (siehe internal/core/github.go).
+func showGitHubTemplateDialog(window fyne.Window, ps *core.ProjectSetup, parentPath string) {
+	token, err := ps.GithubToken(core.GithubHost)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	repoSelect := widget.NewSelect(nil, nil)
+	repoSelect.PlaceHolder = "Loading..."
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), core.ForgeTimeout)
+		defer cancel()
+		repos, err := core.ListTemplateRepos(ctx, core.GithubHost, token)
+		if err != nil {
+			repoSelect.PlaceHolder = "Error: " + err.Error()
+			repoSelect.Refresh()
+			return
 		}
+		var names []string
+		for _, repo := range repos {
+			names = append(names, repo.FullName)
+		}
+		repoSelect.PlaceHolder = "(no template repositories)"
+		repoSelect.SetOptions(names)
+		if len(names) > 0 {
+			repoSelect.SetSelected(names[0])
+		}
+	}()
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("new-project")
+	parentEntry := widget.NewEntry()
+	parentEntry.SetText(parentPath)
+	privateCheck := widget.NewCheck("Private repository", nil)
+	form := []*widget.FormItem{
+		widget.NewFormItem("Template", repoSelect),
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Clone to", parentEntry),
+		widget.NewFormItem("", privateCheck),
+	}
+	dialog.ShowForm("New from GitHub template", "Create", "Cancel", form, func(ok bool) {
+		if !ok || repoSelect.Selected == "" {
+			return
+		}
+		setup := &core.ProjectSetup{Settings: ps.Settings, ProfileName: ps.ProfileName, ParentPath: parentEntry.Text, ProjectName: nameEntry.Text}
+		progress := dialog.NewCustomWithoutButtons("New from GitHub template", widget.NewProgressBarInfinite(), window)
+		progress.Show()
+		go func() {
+			repo, err := setup.CreateFromTemplateRepo(core.GithubHost, repoSelect.Selected, "", privateCheck.Checked)
+			progress.Hide()
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			projectDir := filepath.Join(setup.ParentPath, setup.ProjectName)
+			commands := core.DetectBootstrap(projectDir)
+			if len(commands) == 0 {
+				dialog.ShowInformation("GitHub template", fmt.Sprintf("%s\nGeklont nach %s", repo.HTMLURL, projectDir), window)
+				return
+			}
+			msg := fmt.Sprintf("Geklont nach %s. Bootstrap ausführen?\n\n%s", projectDir, strings.Join(commands, "\n"))
+			dialog.ShowConfirm("Run bootstrap?", msg, func(run bool) {
+				if !run {
+					return
+				}
+				go func() {
+					if err := setup.RunBootstrap(projectDir, commands); err != nil {
+						dialog.ShowError(err, window)
+						return
+					}
+					dialog.ShowInformation("GitHub template", "Bootstrap abgeschlossen: "+projectDir, window)
+				}()
+			}, window)
+		}()
+	}, window)
+}
+
+// showPromoteSandboxDialog verschiebt ein Sandbox-Projekt in einen Projektpfad.
+func showPromoteSandboxDialog(window fyne.Window, parentPath string) {
+	projects := core.SandboxProjects()
+	if len(projects) == 0 {
+		dialog.ShowInformation("Promote", "Keine Sandbox-Projekte", window)
+		return
+	}
+	projectSelect := widget.NewSelect(projects, nil)
+	projectSelect.SetSelected(projects[0])
+	parentEntry := widget.NewEntry()
+	parentEntry.SetText(parentPath)
+	form := []*widget.FormItem{
+		widget.NewFormItem("Project", projectSelect),
+		widget.NewFormItem("Move to", parentEntry),
+	}
+	dialog.ShowForm("Promote to real project", "Promote", "Cancel", form, func(ok bool) {
+		if !ok {
+			return
+		}
+		target, err := core.PromoteSandbox(projectSelect.Selected, parentEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		dialog.ShowInformation("Promote", "Verschoben nach "+target, window)
+	}, window)
+}
+
+func snippetNames(pt core.ProjectType) []string {
+	var names []string
+	for _, s := range core.SnippetsFor(pt) {
+		names = append(names, s.Name)
 	}
-	return nil
+	return names
+}
+
+// showSaveTemplateDialog speichert ein gewähltes Verzeichnis als Template;
+// onSaved aktualisiert danach die Templateauswahl.
+func showSaveTemplateDialog(window fyne.Window, onSaved func()) {
+	dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+		if err != nil || uri == nil {
+			return
+		}
+		dir := uri.Path()
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(filepath.Base(dir))
+		dialog.ShowForm("Save "+filepath.Base(dir)+" as template", "Save", "Cancel", []*widget.FormItem{
+			widget.NewFormItem("Template Name", nameEntry),
+		}, func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			t, path, skipped, err := core.SaveDirTemplate(dir, strings.TrimSpace(nameEntry.Text), nil)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			log.Printf("Template %s gespeichert: %s", t.Name, path)
+			onSaved()
+			msg := fmt.Sprintf("%s (%s) is now available in the template list.", t.Name, t.Type)
+			if len(skipped) > 0 {
+				msg += "\n\nSkipped:\n" + strings.Join(skipped, "\n")
+			}
+			dialog.ShowInformation("Template saved", msg, window)
+		}, window)
+	}, window)
 }
 
-func (ps *ProjectSetup) checkJavaScriptInstallation() error {
-	cmd := exec.Command("node", "--version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("node.js ist nicht installiert: %v", err)
+// showExportTemplateDialog speichert das gewählte Template als Archiv.
+func showExportTemplateDialog(window fyne.Window, t *core.Template) {
+	if t == nil {
+		dialog.ShowError(fmt.Errorf("kein template gewählt"), window)
+		return
 	}
-	return nil
+	d := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil || w == nil {
+			return
+		}
+		if err := core.ExportTemplate(t, w); err != nil {
+			w.Close()
+			dialog.ShowError(fmt.Errorf("template %s exportieren fehlgeschlagen: %v", t.Name, err), window)
+			return
+		}
+		if err := w.Close(); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		log.Printf("Template %s exportiert: %s", t.Name, w.URI().Path())
+	}, window)
+	d.SetFileName(strings.TrimSuffix(core.TemplateFileName(t.Name), ".json") + core.ArchiveExtension)
+	d.Show()
 }
 
-func (ps *ProjectSetup) checkTypeScriptInstallation() error {
-	cmd := exec.Command("tsc", "--version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("typescript ist nicht installiert: %v", err)
+// showWatchTemplateDialog zeigt die Dateien des gewählten Templates und
+// rendert sie bei jedem Speichern neu (siehe internal/core/watch.go).
+func showWatchTemplateDialog(window fyne.Window, settings core.Settings, t *core.Template) {
+	if t == nil {
+		dialog.ShowError(fmt.Errorf("kein template gewählt"), window)
+		return
 	}
-	return nil
+	name := t.Name
+	dir, err := core.PreviewDir(name)
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	status := widget.NewLabel("")
+	files := widget.NewAccordion()
+	stop := make(chan struct{})
+	content := container.NewBorder(widget.NewLabel("Output: "+dir), status, nil, nil, container.NewVScroll(files))
+	d := dialog.NewCustom("Watch Template: "+name, "Close", content, window)
+	d.SetOnClosed(func() { close(stop) })
+	d.Resize(fyne.NewSize(700, 500))
+	d.Show()
+	go func() {
+		err := core.WatchTemplate(name, stop, func(t *core.Template, err error) {
+			var rendered []core.PreviewFile
+			if err == nil {
+				rendered, err = core.RenderPreview(settings, t, core.PreviewProjectName, nil, dir)
+			}
+			stamp := time.Now().Format("15:04:05")
+			if err != nil {
+				status.SetText(fmt.Sprintf("%s: %v", stamp, err))
+				return
+			}
+			// Aufgeklappte Dateien bleiben nach dem Neuladen offen
+			open := make(map[string]bool)
+			for _, item := range files.Items {
+				open[item.Title] = item.Open
+			}
+			items := make([]*widget.AccordionItem, len(rendered))
+			for i, f := range rendered {
+				text := f.Content
+				if f.Binary {
+					text = fmt.Sprintf("(binary, %s)", core.FormatSize(f.Size))
+				}
+				label := widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+				items[i] = widget.NewAccordionItem(f.Path, label)
+				items[i].Open = open[f.Path]
+			}
+			files.Items = items
+			files.Refresh()
+			status.SetText(fmt.Sprintf("Updated %s: %d files", stamp, len(rendered)))
+		})
+		if err != nil {
+			d.Hide()
+			dialog.ShowError(err, window)
+		}
+	}()
+}
+
+// showImportArchiveDialog importiert ein gewähltes Template-Archiv.
+func showImportArchiveDialog(window fyne.Window, onImported func()) {
+	d := dialog.NewFileOpen(func(r fyne.URIReadCloser, err error) {
+		if err != nil || r == nil {
+			return
+		}
+		r.Close()
+		importArchiveGUI(window, r.URI().Path(), onImported)
+	}, window)
+	d.SetFilter(storage.NewExtensionFileFilter([]string{core.ArchiveExtension}))
+	d.Show()
+}
+
+// importArchiveGUI importiert ein Archiv und fragt wie der Discover-Tab nach
+// Vertrauen, wenn das Template Befehle ausführt.
+func importArchiveGUI(window fyne.Window, path string, onImported func()) {
+	t, err := core.ImportArchive(path, "")
+	if err != nil {
+		dialog.ShowError(err, window)
+		return
+	}
+	log.Printf("Template importiert: %s", t.Name)
+	onImported()
+	if !t.RunsCommands() {
+		dialog.ShowInformation("Imported", fmt.Sprintf("%s (%s) is now available in the template list.", t.Name, t.Type), window)
+		return
+	}
+	dialog.ShowConfirm("Trust template?", t.TrustSummary(), func(trusted bool) {
+		if !trusted {
+			return
+		}
+		if err := core.TrustTemplate(t); err != nil {
+			dialog.ShowError(err, window)
+		}
+	}, window)
 }
 
-func (ps *ProjectSetup) checkCPlusPlusInstallation() error {
-	cmd := exec.Command("g++", "--version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("g++ ist nicht installiert: %v", err)
+// showSnippetDialog fügt Snippets in ein bestehendes Projekt ein, dessen Typ
+// anhand der vorhandenen Dateien erkannt wird.
+func showSnippetDialog(window fyne.Window) {
+	dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+		if err != nil || uri == nil {
+			return
+		}
+		dir := uri.Path()
+		pt, ok := core.DetectProjectType(dir)
+		if !ok {
+			dialog.ShowError(fmt.Errorf("projekttyp von %s nicht erkannt", dir), window)
+			return
+		}
+		check := widget.NewCheckGroup(snippetNames(pt), nil)
+		// Snippets nacheinander einfügen; Dateien, die sich ändern würden, erst nach Durchsicht
+		var apply func(names []string)
+		apply = func(names []string) {
+			if len(names) == 0 {
+				return
+			}
+			patches, err := core.PlanSnippet(dir, core.FindSnippet(pt, names[0]))
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			write := func() {
+				settings, _ := core.LoadSettings()
+				if err := core.WritePatches(dir, patches, settings.Modes()); err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				log.Printf("Snippet %s in %s eingefügt", names[0], dir)
+				apply(names[1:])
+			}
+			if slices.ContainsFunc(patches, core.FilePatch.Conflict) {
+				showPatchReview(window, names[0], patches, write)
+				return
+			}
+			write()
+		}
+		dialog.ShowCustomConfirm(fmt.Sprintf("Snippets for %s (%s)", filepath.Base(dir), pt), "Add", "Cancel", check, func(confirmed bool) {
+			if confirmed {
+				apply(check.Selected)
+			}
+		}, window)
+	}, window)
+}
+
+// showPatchReview zeigt je Datei, die überschrieben würde, den Diff zum
+// Annehmen oder Ablehnen. onApply schreibt danach; Abbrechen schreibt nichts.
+func showPatchReview(window fyne.Window, title string, patches []core.FilePatch, onApply func()) {
+	items := []*widget.AccordionItem{}
+	for i := range patches {
+		p := &patches[i]
+		if !p.Conflict() {
+			continue
+		}
+		accept := widget.NewCheck("Overwrite "+p.Path, func(on bool) { p.Accepted = on })
+		diff := widget.NewLabel(p.Diff())
+		diff.TextStyle.Monospace = true
+		items = append(items, widget.NewAccordionItem(p.Path, container.NewBorder(accept, nil, nil, nil, container.NewScroll(diff))))
+	}
+	accordion := widget.NewAccordion(items...)
+	accordion.Open(0)
+	d := dialog.NewCustomConfirm("Review changes: "+title, "Apply", "Cancel", container.NewVScroll(accordion), func(ok bool) {
+		if ok {
+			onApply()
+		}
+	}, window)
+	d.Resize(fyne.NewSize(700, 500))
+	d.Show()
+}
+
+// discoverTab zeigt die Templates des Marktplatz-Index und installiert sie
+// auf Knopfdruck; onInstalled aktualisiert danach die Templateauswahl.
+func discoverTab(window fyne.Window, settings core.Settings, onInstalled func()) fyne.CanvasObject {
+	var index *core.MarketIndex
+	list := widget.NewList(
+		func() int {
+			if index == nil {
+				return 0
+			}
+			return len(index.Templates)
+		},
+		func() fyne.CanvasObject {
+			title := widget.NewLabel("")
+			title.TextStyle.Bold = true
+			desc := widget.NewLabel("")
+			desc.Wrapping = fyne.TextWrapWord
+			return container.NewBorder(nil, nil, nil, widget.NewButton("Install", nil), container.NewVBox(title, desc))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			entry := &index.Templates[id]
+			row := item.(*fyne.Container)
+			text := row.Objects[0].(*fyne.Container)
+			text.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s (%s) %s", entry.Name, entry.Type, core.RatingStars(entry.Rating)))
+			desc := entry.Description
+			if entry.Author != "" {
+				desc += " – " + entry.Author
+			}
+			text.Objects[1].(*widget.Label).SetText(desc)
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				ctx, cancel := context.WithTimeout(context.Background(), core.MarketTimeout)
+				defer cancel()
+				t, err := core.InstallMarketTemplate(ctx, settings, entry)
+				if err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				log.Printf("Template installiert: %s", t.Name)
+				onInstalled()
+				if !t.RunsCommands() {
+					dialog.ShowInformation("Installed", entry.Name+" is now available in the Create tab.", window)
+					return
+				}
+				dialog.ShowConfirm("Trust template?", t.TrustSummary(), func(trusted bool) {
+					if !trusted {
+						return
+					}
+					if err := core.TrustTemplate(t); err != nil {
+						dialog.ShowError(err, window)
+					}
+				}, window)
+			}
+		},
+	)
+
+	status := widget.NewLabel("")
+	refresh := func() {
+		status.SetText("Loading...")
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), core.MarketTimeout)
+			defer cancel()
+			idx, err := core.FetchMarketIndex(ctx, settings)
+			if err != nil {
+				log.Printf("Marktplatz laden fehlgeschlagen: %v", err)
+				status.SetText("Fehler: " + err.Error())
+				return
+			}
+			index = idx
+			status.SetText(fmt.Sprintf("%d templates", len(idx.Templates)))
+			list.Refresh()
+		}()
+	}
+	if settings.MarketplaceURL != "" {
+		refresh()
+	} else {
+		status.SetText("No marketplace URL configured")
 	}
-	return nil
+	return container.NewBorder(
+		container.NewBorder(nil, nil, nil, widget.NewButton("Refresh", refresh), status),
+		nil, nil, nil, list,
+	)
+}
+
+// showAIDialog fragt eine Projektbeschreibung ab, lässt die Dateien erzeugen
+// und zeigt sie zur Freigabe an. Erst nach Freigabe wird das Ergebnis als
+// Template übernommen.
+func showAIDialog(window fyne.Window, ps *core.ProjectSetup, onApproved func()) {
+	description := widget.NewMultiLineEntry()
+	description.SetPlaceHolder("A REST API that stores todo items in SQLite")
+	description.SetMinRowsVisible(4)
+	dialog.ShowCustomConfirm("Generate with AI", "Generate", "Cancel", description, func(confirmed bool) {
+		if !confirmed || strings.TrimSpace(description.Text) == "" {
+			return
+		}
+		progress := dialog.NewCustomWithoutButtons("Generating...", widget.NewProgressBarInfinite(), window)
+		progress.Show()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+			files, err := core.GenerateFiles(ctx, ps.Settings.AI, ps.ProjectType, ps.ProjectName, description.Text)
+			progress.Hide()
+			if err != nil {
+				log.Printf("KI-Erzeugung fehlgeschlagen: %v", err)
+				dialog.ShowError(err, window)
+				return
+			}
+
+			paths := make([]string, 0, len(files))
+			for path := range files {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			preview := widget.NewAccordion()
+			for _, path := range paths {
+				content := widget.NewLabel(files[path])
+				content.TextStyle.Monospace = true
+				preview.Append(widget.NewAccordionItem(path, content))
+			}
+			scroll := container.NewVScroll(preview)
+			scroll.SetMinSize(fyne.NewSize(450, 300))
+			dialog.ShowCustomConfirm("Preview", "Use Files", "Discard", scroll, func(approved bool) {
+				if !approved {
+					return
+				}
+				ps.Template = core.AiTemplate(ps.ProjectType, description.Text, files)
+				ps.Options = nil
+				log.Printf("KI-Dateien übernommen: %d Dateien", len(files))
+				onApproved()
+			}, window)
+		}()
+	}, window)
 }
 
-func (ps *ProjectSetup) checkCSharpInstallation() error {
-	cmd := exec.Command("dotnet", "--version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf(".NET SDK ist nicht installiert: %v", err)
+// templateOptionWidget zeigt eine Template-Option als Checkbox, Auswahl oder
+// Eingabefeld an und schreibt den gewählten Wert nach values.
+func templateOptionWidget(o core.TemplateOption, value string, values map[string]string) fyne.CanvasObject {
+	values[o.Name] = value
+	switch o.Type {
+	case "bool":
+		check := widget.NewCheck(o.DisplayLabel(), func(checked bool) {
+			values[o.Name] = fmt.Sprint(checked)
+		})
+		check.SetChecked(value == "true")
+		return check
+	case "choice":
+		sel := widget.NewSelect(o.Choices, func(v string) { values[o.Name] = v })
+		sel.SetSelected(value)
+		return container.NewGridWithColumns(2, widget.NewLabel(o.DisplayLabel()+":"), sel)
+	default:
+		entry := widget.NewEntry()
+		entry.SetText(value)
+		entry.OnChanged = func(v string) { values[o.Name] = v }
+		return container.NewGridWithColumns(2, widget.NewLabel(o.DisplayLabel()+":"), entry)
 	}
-	return nil
 }
 
-func (ps *ProjectSetup) checkJavaInstallation() error {
-	cmd := exec.Command("javac", "-version")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("Java Development Kit ist nicht installiert: %v", err)
+// showMissingOptionsDialog fragt nach den Werten fehlender Pflichtoptionen und
+// ruft danach onDone auf.
+func showMissingOptionsDialog(window fyne.Window, missing []core.TemplateOption, values map[string]string, onDone func()) {
+	entries := make([]*widget.Entry, len(missing))
+	items := make([]*widget.FormItem, len(missing))
+	for i, o := range missing {
+		entries[i] = widget.NewEntry()
+		entries[i].Validator = func(s string) error {
+			if strings.TrimSpace(s) == "" {
+				return fmt.Errorf("wert fehlt")
+			}
+			return nil
+		}
+		items[i] = widget.NewFormItem(o.DisplayLabel(), entries[i])
 	}
-	return nil
+	dialog.ShowForm("Template variables", "Continue", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		for i, o := range missing {
+			values[o.Name] = strings.TrimSpace(entries[i].Text)
+		}
+		onDone()
+	}, window)
 }
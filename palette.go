@@ -0,0 +1,173 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// paletteAction ist ein Eintrag der Befehlspalette (Strg+K).
+type paletteAction struct {
+	Title string
+	Run   func()
+}
+
+// fuzzyScore prüft, ob alle Zeichen der Suche in dieser Reihenfolge im Text
+// vorkommen. Zusammenhängende Treffer und Treffer am Wortanfang zählen mehr.
+func fuzzyScore(query, text string) (int, bool) {
+	query, text = strings.ToLower(query), strings.ToLower(text)
+	score, last := 0, -1
+	pos := 0
+	for _, q := range query {
+		if q == ' ' {
+			continue
+		}
+		i := strings.IndexRune(text[pos:], q)
+		if i < 0 {
+			return 0, false
+		}
+		i += pos
+		switch {
+		case i == last+1:
+			score += 3
+		case i == 0 || strings.ContainsRune(" /:-", rune(text[i-1])):
+			score += 2
+		default:
+			score++
+		}
+		last, pos = i, i+1
+	}
+	return score, true
+}
+
+// filterActions liefert die passenden Aktionen, die besten zuerst.
+func filterActions(actions []paletteAction, query string) []paletteAction {
+	type match struct {
+		action paletteAction
+		score  int
+	}
+	var matches []match
+	for _, a := range actions {
+		if score, ok := fuzzyScore(query, a.Title); ok {
+			matches = append(matches, match{a, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	out := make([]paletteAction, len(matches))
+	for i, m := range matches {
+		out[i] = m.action
+	}
+	return out
+}
+
+// showCommandPalette öffnet die Befehlspalette. Enter führt den markierten
+// Eintrag aus, ↑/↓ wählen.
+func showCommandPalette(window fyne.Window, actions []paletteAction) {
+	shown := actions
+	selected := 0
+	var d dialog.Dialog
+	list := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) { obj.(*widget.Label).SetText(shown[id].Title) },
+	)
+	run := func(i int) {
+		if i < 0 || i >= len(shown) {
+			return
+		}
+		d.Hide()
+		shown[i].Run()
+	}
+	list.OnSelected = func(id widget.ListItemID) { run(id) }
+
+	query := newPaletteEntry()
+	query.SetPlaceHolder("Type a command...")
+	query.OnChanged = func(text string) {
+		shown = filterActions(actions, text)
+		selected = 0
+		list.UnselectAll()
+		list.Refresh()
+		list.ScrollToTop()
+	}
+	query.OnSubmitted = func(string) { run(selected) }
+	query.onMove = func(delta int) {
+		selected = max(0, min(len(shown)-1, selected+delta))
+		list.ScrollTo(selected)
+		// Markieren ohne auszuführen
+		handler := list.OnSelected
+		list.OnSelected = nil
+		list.Select(selected)
+		list.OnSelected = handler
+	}
+
+	content := container.NewBorder(query, nil, nil, nil, list)
+	d = dialog.NewCustomWithoutButtons("Command Palette", content, window)
+	d.Resize(fyne.NewSize(460, 360))
+	d.Show()
+	window.Canvas().Focus(query)
+}
+
+// paletteEntry ist ein Eingabefeld, das ↑/↓ an die Trefferliste weitergibt.
+type paletteEntry struct {
+	widget.Entry
+	onMove func(delta int)
+}
+
+func newPaletteEntry() *paletteEntry {
+	e := &paletteEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *paletteEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyDown:
+		e.onMove(1)
+	case fyne.KeyUp:
+		e.onMove(-1)
+	default:
+		e.Entry.TypedKey(key)
+	}
+}
+
+// logBuffer hält die letzten Log-Zeilen der Oberfläche für „View Logs“.
+type logBuffer struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// maxLogLines begrenzt den Log-Puffer.
+const maxLogLines = 500
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines = append(b.lines, strings.TrimRight(string(p), "\n"))
+	if len(b.lines) > maxLogLines {
+		b.lines = b.lines[len(b.lines)-maxLogLines:]
+	}
+	return len(p), nil
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Join(b.lines, "\n")
+}
+
+// showLogs zeigt die gesammelten Log-Zeilen.
+func showLogs(window fyne.Window, logs *logBuffer) {
+	text := widget.NewMultiLineEntry()
+	text.SetText(logs.String())
+	text.Wrapping = fyne.TextWrapOff
+	text.TextStyle = fyne.TextStyle{Monospace: true}
+	d := dialog.NewCustom("Logs", "Close", text, window)
+	d.Resize(fyne.NewSize(700, 450))
+	d.Show()
+	text.CursorRow = len(strings.Split(text.Text, "\n"))
+}
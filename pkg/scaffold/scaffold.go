@@ -0,0 +1,219 @@
+// Package scaffold bindet den Projektgenerator von newpipi in andere
+// Go-Programme ein.
+//
+// Die Typen dieses Pakets sind stabil; dahinter arbeitet derselbe Generator
+// wie in GUI und CLI (internal/core), im eigenen Prozess und ohne Fyne.
+// Einstellungen, Plugins, eigene Templates und Snippets aus der
+// newpipi-Konfiguration gelten genauso, Template-Vertrauen und
+// Erstellungsbericht ebenfalls.
+//
+// Creator ist die Schnittstelle der Engine, gegen die Aufrufer programmieren
+// und die sie in Tests ersetzen; Step meldet den Fortschritt. Die Befehle
+// externer Werkzeuge (go, cargo, npm, git ...) erstellt ein Runner
+// (NewWithRunner), sonst startet ExecRunner sie direkt.
+//
+//	engine := scaffold.New()
+//	result, err := engine.Create(ctx, scaffold.Options{Type: "Go", Name: "demo"}, nil)
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/alexander-graf/go_pipi/internal/core"
+)
+
+// Option ist eine Einstellung eines Templates.
+type Option struct {
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+	// Type ist "bool", "choice" (mit Choices) oder "string".
+	Type    string   `json:"type"`
+	Choices []string `json:"choices,omitempty"`
+	Default string   `json:"default,omitempty"`
+}
+
+// Template beschreibt ein verfügbares Template.
+type Template struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Options     []Option `json:"options,omitempty"`
+}
+
+// Options sind die Eingaben einer Projekterstellung. Leere Felder nehmen die
+// Standardwerte der newpipi-Konfiguration.
+type Options struct {
+	Type       string `json:"type"`
+	Name       string `json:"name"`
+	ParentPath string `json:"parentPath,omitempty"`
+	Template   string `json:"template,omitempty"`
+	// Toolkit ist das GUI-Toolkit des Projekttyps, etwa "PySide6" für Python.
+	Toolkit  string            `json:"toolkit,omitempty"`
+	Profile  string            `json:"profile,omitempty"`
+	Values   map[string]string `json:"options,omitempty"`
+	Snippets []string          `json:"snippets,omitempty"`
+	// Env sind Umgebungsvariablen, die in .env des Projekts landen.
+	Env     map[string]string `json:"env,omitempty"`
+	SkipGit bool              `json:"skipGit,omitempty"`
+	// VCS ist "git" (Standard), "jj" oder "hg".
+	VCS string `json:"vcs,omitempty"`
+	// GitHooks legt native Git-Hooks unter .githooks/ an.
+	GitHooks bool `json:"gitHooks,omitempty"`
+	// Sign signiert die Commits per SSH oder GPG.
+	Sign bool `json:"sign,omitempty"`
+	// LFS verwaltet große Binärdateien mit Git LFS.
+	LFS   bool `json:"lfs,omitempty"`
+	Audit bool `json:"audit,omitempty"`
+	// Licenses fasst die Lizenzen der Abhängigkeiten zusammen.
+	Licenses bool `json:"licenses,omitempty"`
+	// SBOM ist "cyclonedx", "spdx" oder leer.
+	SBOM string `json:"sbom,omitempty"`
+}
+
+// Result ist das Ergebnis einer Erstellung.
+type Result struct {
+	// Path ist das Verzeichnis des neuen Projekts.
+	Path string `json:"path"`
+}
+
+// Step meldet einen Arbeitsschritt. Fraction ist der geschätzte
+// Fortschritt zwischen 0 und 1, ohne frühere Läufe 0.
+type Step struct {
+	Message  string  `json:"message"`
+	Fraction float64 `json:"fraction"`
+}
+
+// Progress ist der frühere Name von Step.
+//
+// Deprecated: Step verwenden.
+type Progress = Step
+
+// Creator erstellt Projekte. *Engine erfüllt es; Aufrufer setzen in ihren
+// Tests eine eigene Implementierung ein, statt Projekte anzulegen.
+type Creator interface {
+	Types(ctx context.Context) ([]string, error)
+	Templates(ctx context.Context, projectType string) ([]Template, error)
+	Validate(ctx context.Context, name string) error
+	Create(ctx context.Context, opts Options, progress func(Step)) (*Result, error)
+}
+
+var _ Creator = (*Engine)(nil)
+
+// Runner erstellt die Befehle, die eine Erstellung ausführt, etwa
+// `go mod init` oder `git init`. Ein eigener Runner kann sie in einem
+// Container ausführen, protokollieren oder im Test ersetzen; der Befehl muss
+// ctx beachten.
+type Runner interface {
+	Command(ctx context.Context, name string, args ...string) *exec.Cmd
+}
+
+// ExecRunner startet die Befehle direkt.
+type ExecRunner struct{}
+
+func (ExecRunner) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
+
+// load lädt einmal je Prozess Plugins, Sprachen, eigene Templates und Snippets.
+var load = sync.OnceFunc(func() {
+	core.LoadPlugins()
+	core.LoadLanguages()
+	core.LoadUserTemplates()
+	core.LoadUserSnippets()
+})
+
+// Engine ist der Generator. Eine Engine darf aus mehreren Goroutinen genutzt
+// werden; Erstellungen laufen dann nebeneinander.
+type Engine struct {
+	runner Runner
+
+	// Version ist die Versionsangabe des Generators.
+	Version string
+}
+
+// New liefert eine Engine, die die Befehle der Erstellung direkt startet.
+func New() *Engine {
+	return NewWithRunner(ExecRunner{})
+}
+
+// NewWithRunner liefert eine Engine, deren Befehle runner erstellt.
+func NewWithRunner(runner Runner) *Engine {
+	load()
+	return &Engine{runner: runner, Version: core.VersionString()}
+}
+
+// Types liefert die Projekttypen.
+func (e *Engine) Types(ctx context.Context) ([]string, error) {
+	return append([]string(nil), core.ProjectTypeNames...), ctx.Err()
+}
+
+// Templates liefert die Templates eines Projekttyps, bei leerem Typ alle.
+func (e *Engine) Templates(ctx context.Context, projectType string) ([]Template, error) {
+	var templates []Template
+	for _, t := range core.ListTemplates(projectType) {
+		template := Template{Name: t.Name, Type: t.Type.String(), Description: t.Description}
+		for _, o := range t.Options {
+			template.Options = append(template.Options, Option{o.Name, o.Label, o.Type, o.Choices, o.Default})
+		}
+		templates = append(templates, template)
+	}
+	return templates, ctx.Err()
+}
+
+// Validate prüft einen Projektnamen und liefert bei ungültigem Namen den Grund.
+func (e *Engine) Validate(ctx context.Context, name string) error {
+	if valid, msg := core.IsValidProjectName(name); !valid {
+		return errors.New(msg)
+	}
+	return ctx.Err()
+}
+
+// Create erstellt ein Projekt. progress darf nil sein. Wird ctx abgebrochen,
+// endet der laufende Befehl und keine weitere Phase beginnt; die Erstellung
+// lässt sich mit `newpipi resume` fortsetzen.
+func (e *Engine) Create(ctx context.Context, opts Options, progress func(Step)) (*Result, error) {
+	pt, ok := core.ParseProjectType(opts.Type)
+	if !ok {
+		return nil, fmt.Errorf("unbekannter projekttyp: %q", opts.Type)
+	}
+	ps, _, err := core.CreateRequest{
+		Type:       pt,
+		Name:       opts.Name,
+		ParentPath: opts.ParentPath,
+		Template:   opts.Template,
+		Toolkit:    opts.Toolkit,
+		Profile:    opts.Profile,
+		Options:    opts.Values,
+		Snippets:   opts.Snippets,
+		Env:        opts.Env,
+		SkipGit:    opts.SkipGit,
+		VCS:        opts.VCS,
+		GitHooks:   opts.GitHooks,
+		Sign:       opts.Sign,
+		LFS:        opts.LFS,
+		Audit:      opts.Audit,
+		Licenses:   opts.Licenses,
+		SBOM:       opts.SBOM,
+	}.Prepare()
+	if err != nil {
+		return nil, err
+	}
+	ps.Context = ctx
+	ps.NewCommand = e.runner.Command
+	if progress != nil {
+		estimate := ps.LoadEstimate()
+		ps.Progress = func(step string) {
+			fraction, _, _ := estimate.At(step, 0)
+			progress(Step{Message: step, Fraction: fraction})
+		}
+	}
+	if err := ps.CreateProject(); err != nil {
+		return nil, err
+	}
+	return &Result{Path: filepath.Join(ps.ParentPath, ps.ProjectName)}, nil
+}
@@ -0,0 +1,113 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordRunner merkt sich die Befehle und führt stattdessen true aus.
+type recordRunner struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func (r *recordRunner) Command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	r.mu.Lock()
+	r.commands = append(r.commands, strings.Join(slices.Concat([]string{name}, args), " "))
+	r.mu.Unlock()
+	return exec.CommandContext(ctx, "true")
+}
+
+func TestEngineCreate(t *testing.T) {
+	t.Setenv("NEWPIPI_CONFIG_DIR", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		opts    Options
+		wantErr error
+		// command muss unter den ausgeführten Befehlen sein
+		command string
+	}{
+		{name: "go", ctx: context.Background(), opts: Options{Type: "Go", Name: "demo", Toolkit: "None", SkipGit: true}, command: "go mod init demo"},
+		{name: "abgebrochen", ctx: canceled, opts: Options{Type: "Go", Name: "demo", Toolkit: "None", SkipGit: true}, wantErr: context.Canceled},
+		{name: "unbekannter typ", ctx: context.Background(), opts: Options{Type: "Cobol", Name: "demo"}},
+		{name: "ungültiger name", ctx: context.Background(), opts: Options{Type: "Go", Name: "de mo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &recordRunner{}
+			engine := NewWithRunner(runner)
+			tt.opts.ParentPath = t.TempDir()
+			var steps []Step
+			result, err := engine.Create(tt.ctx, tt.opts, func(s Step) { steps = append(steps, s) })
+			if tt.command == "" {
+				if err == nil || tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Create() err = %v, erwartet %v", err, tt.wantErr)
+				}
+				if len(runner.commands) > 0 {
+					t.Errorf("Befehle trotz Fehler: %q", runner.commands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(tt.opts.ParentPath, tt.opts.Name); result.Path != want {
+				t.Errorf("Path = %q, erwartet %q", result.Path, want)
+			}
+			if _, err := os.Stat(result.Path); err != nil {
+				t.Error(err)
+			}
+			if !slices.Contains(runner.commands, tt.command) {
+				t.Errorf("%q nicht ausgeführt: %q", tt.command, runner.commands)
+			}
+			if len(steps) == 0 {
+				t.Error("kein Fortschritt gemeldet")
+			}
+		})
+	}
+}
+
+func TestEngineQueries(t *testing.T) {
+	t.Setenv("NEWPIPI_CONFIG_DIR", t.TempDir())
+	ctx := context.Background()
+	engine := NewWithRunner(&recordRunner{})
+	types, err := engine.Types(ctx)
+	if err != nil || !slices.Contains(types, "Go") {
+		t.Errorf("Types() = %q, %v", types, err)
+	}
+	templates, err := engine.Templates(ctx, "Python")
+	if err != nil || !slices.ContainsFunc(templates, func(tmpl Template) bool { return tmpl.Name == "CLI App" }) {
+		t.Fatalf("Templates(Python) = %v, %v", templates, err)
+	}
+	for _, tmpl := range templates {
+		if tmpl.Type != "Python" {
+			t.Errorf("Template %s hat Typ %s", tmpl.Name, tmpl.Type)
+		}
+	}
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"demo", true},
+		{"my-app_2", true},
+		{"", false},
+		{"de mo", false},
+		{"a/b", false},
+	}
+	for _, tt := range tests {
+		if err := engine.Validate(ctx, tt.name); (err == nil) != tt.valid {
+			t.Errorf("Validate(%q) = %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver"
+	"github.com/alexander-graf/go_pipi/internal/core"
+)
+
+// Fyne kennt keine Fensterposition. Unter X11 werden Position und Bildschirm
+// daher über xdotool gelesen und gesetzt; unter Wayland entscheidet der
+// Compositor, dort bleibt nur die Größe erhalten.
+
+// windowPlacement ist Position und Größe des Hauptfensters.
+type windowPlacement struct {
+	// X und Y sind Bildschirmkoordinaten; bei mehreren Monitoren legen sie auch die Anzeige fest.
+	X      int     `json:"x"`
+	Y      int     `json:"y"`
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+// x11Window liefert die X11-Fenster-ID oder 0 außerhalb von X11.
+func x11Window(w fyne.Window) uintptr {
+	native, ok := w.(driver.NativeWindow)
+	if !ok {
+		return 0
+	}
+	var handle uintptr
+	native.RunNative(func(ctx any) {
+		if x11, ok := ctx.(driver.X11WindowContext); ok {
+			handle = x11.WindowHandle
+		}
+	})
+	return handle
+}
+
+// xdotool führt xdotool aus; fehlt es, gibt es keine Positionierung.
+func xdotool(args ...string) (string, error) {
+	if _, err := exec.LookPath("xdotool"); err != nil {
+		return "", err
+	}
+	out, err := exec.Command("xdotool", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("xdotool %s fehlgeschlagen: %v", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// currentPlacement ermittelt die Lage des Fensters.
+func currentPlacement(w fyne.Window) windowPlacement {
+	size := w.Canvas().Size()
+	p := windowPlacement{Width: size.Width, Height: size.Height, X: -1, Y: -1}
+	id := x11Window(w)
+	if id == 0 {
+		return p
+	}
+	out, err := xdotool("getwindowgeometry", "--shell", fmt.Sprint(id))
+	if err != nil {
+		return p
+	}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, _ := strings.Cut(line, "=")
+		n, _ := strconv.Atoi(value)
+		switch key {
+		case "X":
+			p.X = n
+		case "Y":
+			p.Y = n
+		}
+	}
+	return p
+}
+
+// restorePlacement stellt die Größe sofort und, unter X11, die Position nach
+// dem Start der Anwendung wieder her. Liegt die Position außerhalb der
+// aktuellen Bildschirme (Monitor abgezogen), bleibt das Fenster, wo der
+// Fenstermanager es hinsetzt.
+func restorePlacement(a fyne.App, w fyne.Window, p *windowPlacement) {
+	if p == nil {
+		return
+	}
+	if p.Width > 0 && p.Height > 0 {
+		w.Resize(fyne.NewSize(p.Width, p.Height))
+	}
+	if p.X < 0 || p.Y < 0 {
+		return
+	}
+	// Die Fenster-ID gibt es erst, wenn das Fenster angezeigt wird
+	a.Lifecycle().SetOnStarted(func() {
+		go moveWindow(w, p)
+	})
+}
+
+func moveWindow(w fyne.Window, p *windowPlacement) {
+	id := x11Window(w)
+	if id == 0 {
+		return
+	}
+	out, err := xdotool("getdisplaygeometry")
+	if err != nil {
+		return
+	}
+	var width, height int
+	fmt.Sscan(out, &width, &height)
+	if p.X >= width || p.Y >= height {
+		return
+	}
+	if _, err := xdotool("windowmove", fmt.Sprint(id), fmt.Sprint(p.X), fmt.Sprint(p.Y)); err != nil {
+		log.Printf("Fensterposition setzen fehlgeschlagen: %v", err)
+	}
+}
+
+// placementPath liegt im Zustandsverzeichnis: Monitore gehören zum Rechner,
+// nicht in die synchronisierte Konfiguration.
+func placementPath() string {
+	dir, err := core.StateDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "window.json")
+}
+
+func readPlacement() *windowPlacement {
+	content, err := os.ReadFile(placementPath())
+	if err != nil {
+		return nil
+	}
+	var p windowPlacement
+	if err := json.Unmarshal(content, &p); err != nil {
+		return nil
+	}
+	return &p
+}
+
+// savePlacement merkt sich die Lage des Fensters für den nächsten Start.
+func savePlacement(w fyne.Window) {
+	content, err := json.Marshal(currentPlacement(w))
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(placementPath()), 0755); err == nil {
+			err = os.WriteFile(placementPath(), content, 0644)
+		}
+	}
+	if err != nil {
+		log.Printf("Fensterposition speichern fehlgeschlagen: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/alexander-graf/go_pipi/internal/core"
+)
+
+// showPreflight zeigt die Checkliste. Ohne blockierende Fehler führt "Create"
+// zu onCreate, sonst lässt sich der Dialog nur schließen.
+func showPreflight(window fyne.Window, checks []core.PreflightCheck, skips core.StepSkips, onCreate func(skips core.StepSkips)) {
+	rows := container.NewVBox()
+	for _, c := range checks {
+		icon, text := theme.ConfirmIcon(), c.Detail
+		switch {
+		case c.Blocking():
+			icon, text = theme.ErrorIcon(), c.Err.Error()
+		case c.Err != nil:
+			icon, text = theme.WarningIcon(), c.Err.Error()
+		}
+		name := widget.NewLabelWithStyle(c.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		detail := widget.NewLabel(text)
+		detail.Wrapping = fyne.TextWrapWord
+		rows.Add(container.NewBorder(nil, nil, container.NewHBox(widget.NewIcon(icon), name), nil, detail))
+	}
+	if core.PreflightError(checks) != nil {
+		d := dialog.NewCustom("Pre-flight checks", "Close", rows, window)
+		d.Resize(fyne.NewSize(560, 0))
+		d.Show()
+		return
+	}
+	// Abgewählte Schritte gelten nur für diesen Lauf (siehe internal/core/skipsteps.go)
+	installCheck := widget.NewCheck("Skip dependency install", nil)
+	installCheck.SetChecked(skips.Install)
+	gitCheck := widget.NewCheck("Skip Git", nil)
+	gitCheck.SetChecked(skips.Git)
+	if skips.Git {
+		// Ohne Versionskontrolle gibt es nichts abzuwählen
+		gitCheck.Disable()
+	}
+	terminalCheck := widget.NewCheck("Skip terminal", nil)
+	terminalCheck.SetChecked(skips.Terminal)
+	ciCheck := widget.NewCheck("Skip CI files", nil)
+	ciCheck.SetChecked(skips.CI)
+	rows.Add(widget.NewSeparator())
+	rows.Add(widget.NewLabelWithStyle("This run only", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+	rows.Add(container.NewGridWithColumns(2, installCheck, gitCheck, terminalCheck, ciCheck))
+
+	d := dialog.NewCustomConfirm("Pre-flight checks", "Create", "Cancel", rows, func(ok bool) {
+		if ok {
+			onCreate(core.StepSkips{Install: installCheck.Checked, Git: gitCheck.Checked, Terminal: terminalCheck.Checked, CI: ciCheck.Checked})
+		}
+	}, window)
+	d.Resize(fyne.NewSize(560, 0))
+	d.Show()
+}
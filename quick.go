@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/app"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/alexander-graf/go_pipi/internal/core"
+)
+
+// quickEntry schließt das Schnellstart-Fenster mit Escape.
+type quickEntry struct {
+	widget.Entry
+	onEscape func()
+}
+
+func newQuickEntry() *quickEntry {
+	e := &quickEntry{}
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+func (e *quickEntry) TypedKey(key *fyne.KeyEvent) {
+	if key.Name == fyne.KeyEscape {
+		e.onEscape()
+		return
+	}
+	e.Entry.TypedKey(key)
+}
+
+// runQuickWindow zeigt ein kleines Fenster mit Auswahl und Namensfeld. Unter
+// X11 bleibt es per xdotool im Vordergrund.
+func runQuickWindow() int {
+	a := app.New()
+	if s, err := core.LoadSettings(); err == nil {
+		a.Settings().SetTheme(newAppTheme(s))
+	}
+	a.SetIcon(fyne.NewStaticResource("icon", iconData))
+	window := a.NewWindow("newpipi quick")
+
+	choices := core.QuickChoices()
+	choiceSelect := widget.NewSelect(choices, nil)
+	choiceSelect.SetSelected(choices[0])
+	status := widget.NewLabel("Enter: erstellen, Escape: abbrechen")
+	status.Wrapping = fyne.TextWrapWord
+	name := newQuickEntry()
+	name.SetPlaceHolder("Project name")
+	name.onEscape = a.Quit
+
+	code := core.ExitOK
+	name.OnSubmitted = func(text string) {
+		ps, err := core.QuickSetup(choiceSelect.Selected, text)
+		if err != nil {
+			status.SetText(err.Error())
+			return
+		}
+		name.Disable()
+		choiceSelect.Disable()
+		ps.Progress = status.SetText
+		go func() {
+			if err := ps.CreateProject(); err != nil {
+				log.Printf("Fehler bei Projekterstellung: %v", err)
+				status.SetText("Fehler: " + err.Error())
+				name.Enable()
+				choiceSelect.Enable()
+				code = core.ExitCodeFor(err)
+				return
+			}
+			code = core.ExitOK
+			a.Quit()
+		}()
+	}
+
+	window.SetContent(container.NewVBox(choiceSelect, name, status))
+	window.Resize(fyne.NewSize(420, 0))
+	window.SetFixedSize(true)
+	window.CenterOnScreen()
+	window.Canvas().Focus(name)
+	a.Lifecycle().SetOnStarted(func() {
+		go func() {
+			if id := x11Window(window); id != 0 {
+				if _, err := xdotool("windowstate", "--add", "ABOVE", fmt.Sprint(id)); err != nil {
+					log.Printf("Fenster im Vordergrund halten fehlgeschlagen: %v", err)
+				}
+			}
+		}()
+	})
+	window.ShowAndRun()
+	return code
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"github.com/alexander-graf/go_pipi/internal/core"
+)
+
+// densityFactors skalieren Abstände und Innenränder der Widgets.
+var densityFactors = map[string]float32{
+	"compact":     0.5,
+	"normal":      1,
+	"comfortable": 1.5,
+}
+
+// appTheme ist das Standard-Theme mit wählbarer Schriftgröße und Dichte.
+type appTheme struct {
+	fyne.Theme
+	fontSize float32
+	density  float32
+}
+
+// newAppTheme baut das Theme aus den Einstellungen; ohne Angaben entspricht
+// es dem Standard-Theme.
+func newAppTheme(s core.Settings) fyne.Theme {
+	density, ok := densityFactors[s.Density]
+	if !ok {
+		density = 1
+	}
+	return &appTheme{Theme: theme.DefaultTheme(), fontSize: s.FontSize, density: density}
+}
+
+func (t *appTheme) Size(name fyne.ThemeSizeName) float32 {
+	size := t.Theme.Size(name)
+	switch name {
+	case theme.SizeNameText:
+		if t.fontSize > 0 {
+			return t.fontSize
+		}
+	case theme.SizeNameHeadingText, theme.SizeNameSubHeadingText, theme.SizeNameCaptionText:
+		// Überschriften und Beschriftungen wachsen mit der Grundschrift
+		if t.fontSize > 0 {
+			return size * t.fontSize / t.Theme.Size(theme.SizeNameText)
+		}
+	case theme.SizeNamePadding, theme.SizeNameInnerPadding, theme.SizeNameLineSpacing:
+		return size * t.density
+	}
+	return size
+}